@@ -0,0 +1,86 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The quota annotations of a namespace limit the objects in the namespace like a ClusterQuota, without creating one.
+const (
+	GardenerClustersQuotaAnnotation = "infrastructuremanager.kyma-project.io/quota-gardenerclusters"
+	RuntimesQuotaAnnotation         = "infrastructuremanager.kyma-project.io/quota-runtimes"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="GARDENERCLUSTERS",type=integer,JSONPath=`.spec.gardenerClusters`
+//+kubebuilder:printcolumn:name="RUNTIMES",type=integer,JSONPath=`.spec.runtimes`
+//+kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterQuota is the Schema for the clusterquotas API.
+// It limits the number of GardenerClusters, and Runtimes in its namespace, the limits are enforced when the objects are
+// created. With several quotas in a namespace the lowest limit applies.
+type ClusterQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterQuotaSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterQuotaList contains a list of ClusterQuota
+type ClusterQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterQuota `json:"items"`
+}
+
+// ClusterQuotaSpec defines the limits of the quota, a limit which is not set is not enforced.
+type ClusterQuotaSpec struct {
+	// GardenerClusters is the maximal number of GardenerClusters in the namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GardenerClusters *int32 `json:"gardenerClusters,omitempty"`
+
+	// Runtimes is the maximal number of Runtimes in the namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Runtimes *int32 `json:"runtimes,omitempty"`
+}
+
+// Limit returns the maximal number of objects of the kind, and whether the quota limits the kind.
+func (quota *ClusterQuota) Limit(kind string) (int32, bool) {
+	var limit *int32
+
+	switch kind {
+	case "GardenerCluster":
+		limit = quota.Spec.GardenerClusters
+	case "Runtime":
+		limit = quota.Spec.Runtimes
+	}
+
+	if limit == nil {
+		return 0, false
+	}
+
+	return *limit, true
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterQuota{}, &ClusterQuotaList{})
+}
@@ -0,0 +1,189 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ExternalStoreType string
+
+const (
+	ExternalStoreTypeVault             ExternalStoreType = "Vault"
+	ExternalStoreTypeAWSSecretsManager ExternalStoreType = "AWSSecretsManager"
+	ExternalStoreTypeGCPSecretManager  ExternalStoreType = "GCPSecretManager"
+	ExternalStoreTypeAzureKeyVault     ExternalStoreType = "AzureKeyVault"
+	ExternalStoreTypePushSecret        ExternalStoreType = "PushSecret"
+)
+
+// ExternalStores defines secret management systems outside of the cluster the kubeconfig is written to,
+// in addition to the secrets. The entries are rotated together with the secrets, and deleted together with them
+// unless the secrets are retained. Entries of stores removed from the spec are not deleted.
+type ExternalStores struct {
+	// Vault writes the kubeconfig to a HashiCorp Vault KV version 2 secrets engine.
+	// +optional
+	Vault *VaultTarget `json:"vault,omitempty"`
+
+	// AWSSecretsManager writes the kubeconfig to AWS Secrets Manager.
+	// +optional
+	AWSSecretsManager *AWSSecretsManagerTarget `json:"awsSecretsManager,omitempty"`
+
+	// GCPSecretManager writes the kubeconfig to Google Secret Manager.
+	// +optional
+	GCPSecretManager *GCPSecretManagerTarget `json:"gcpSecretManager,omitempty"`
+
+	// AzureKeyVault writes the kubeconfig to Azure Key Vault.
+	// +optional
+	AzureKeyVault *AzureKeyVaultTarget `json:"azureKeyVault,omitempty"`
+
+	// PushSecret hands the kubeconfig over to the External Secrets Operator with a PushSecret,
+	// which pushes it to the provider of the referenced secret store.
+	// +optional
+	PushSecret *PushSecretTarget `json:"pushSecret,omitempty"`
+}
+
+// VaultTarget defines the location of the kubeconfig in a Vault KV version 2 secrets engine
+type VaultTarget struct {
+	// Mount is the path the KV secrets engine is mounted at, it must be the mount the controller is configured with.
+	// +kubebuilder:default=secret
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// Path of the secret relative to the path of the GardenerCluster in the secrets engine, `<prefix>/<namespace>/<name>/`
+	// with the prefix the controller is configured with. Every rotation creates a new version of the secret.
+	Path string `json:"path"`
+
+	// Key of the kubeconfig in the secret data.
+	// +kubebuilder:default=config
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// AWSSecretsManagerTarget defines the AWS Secrets Manager secret holding the kubeconfig
+type AWSSecretsManagerTarget struct {
+	// SecretName is the name, or the ARN of the secret. The secret is created in the region of the controller when it does not exist,
+	// every rotation stores a new version of it.
+	SecretName string `json:"secretName"`
+}
+
+// GCPSecretManagerTarget defines the Google Secret Manager secret holding the kubeconfig
+type GCPSecretManagerTarget struct {
+	// Project is the ID of the Google Cloud project of the secret.
+	// +kubebuilder:validation:Pattern=`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`
+	Project string `json:"project"`
+
+	// SecretID is the ID of the secret, it is created with automatic replication when it does not exist.
+	// Every rotation adds a new version of the secret.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]{1,255}$`
+	SecretID string `json:"secretID"`
+}
+
+// AzureKeyVaultTarget defines the Azure Key Vault secret holding the kubeconfig
+type AzureKeyVaultTarget struct {
+	// VaultName is the name of the key vault, the managed identity of the controller must be allowed to set, and delete its secrets.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9-]{1,22}[a-zA-Z0-9]$`
+	VaultName string `json:"vaultName"`
+
+	// SecretName is the name of the secret, every rotation creates a new version of it.
+	// A soft-deleted secret with the same name is recovered before it is updated.
+	// +kubebuilder:validation:Pattern=`^[0-9a-zA-Z-]{1,127}$`
+	SecretName string `json:"secretName"`
+}
+
+// PushSecretTarget defines the External Secrets Operator secret store, and the remote key the kubeconfig is pushed to.
+// The PushSecret is created in the namespace of the GardenerCluster.
+type PushSecretTarget struct {
+	SecretStoreRef SecretStoreRef `json:"secretStoreRef"`
+
+	// RemoteKey is the name of the secret in the provider.
+	RemoteKey string `json:"remoteKey"`
+
+	// Property of the provider secret the kubeconfig is stored under, the whole secret is used when empty.
+	// +optional
+	Property string `json:"property,omitempty"`
+}
+
+// SecretStoreRef references an External Secrets Operator secret store
+type SecretStoreRef struct {
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	// +kubebuilder:default=SecretStore
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// ExternalStoreStatus defines the observed state of the kubeconfig in an external store
+type ExternalStoreStatus struct {
+	Type ExternalStoreType `json:"type"`
+
+	// Location identifies the entry holding the kubeconfig in the store.
+	Location string `json:"location"`
+
+	// Version of the entry holding the current kubeconfig, if the store keeps versions.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// LastWriteTime is the time the current kubeconfig was written to the store.
+	// +optional
+	LastWriteTime *metav1.Time `json:"lastWriteTime,omitempty"`
+}
+
+// ExternalStoreTypes returns the external stores requested in the spec.
+func (kubeconfig Kubeconfig) ExternalStoreTypes() []ExternalStoreType {
+	if kubeconfig.ExternalStores == nil {
+		return nil
+	}
+
+	var storeTypes []ExternalStoreType
+
+	if kubeconfig.ExternalStores.Vault != nil {
+		storeTypes = append(storeTypes, ExternalStoreTypeVault)
+	}
+
+	if kubeconfig.ExternalStores.AWSSecretsManager != nil {
+		storeTypes = append(storeTypes, ExternalStoreTypeAWSSecretsManager)
+	}
+
+	if kubeconfig.ExternalStores.GCPSecretManager != nil {
+		storeTypes = append(storeTypes, ExternalStoreTypeGCPSecretManager)
+	}
+
+	if kubeconfig.ExternalStores.AzureKeyVault != nil {
+		storeTypes = append(storeTypes, ExternalStoreTypeAzureKeyVault)
+	}
+
+	if kubeconfig.ExternalStores.PushSecret != nil {
+		storeTypes = append(storeTypes, ExternalStoreTypePushSecret)
+	}
+
+	return storeTypes
+}
+
+// ExternalStoreSynced reports whether the current kubeconfig has been written to the given location of the store.
+func (cluster *GardenerCluster) ExternalStoreSynced(storeType ExternalStoreType, location string) bool {
+	for _, storeStatus := range cluster.Status.ExternalStores {
+		if storeStatus.Type == storeType {
+			return storeStatus.Location == location
+		}
+	}
+
+	return false
+}
+
+// SetExternalStoreWritten records that the current kubeconfig has been written to the store.
+func (cluster *GardenerCluster) SetExternalStoreWritten(storeType ExternalStoreType, location, version string, writeTime metav1.Time) {
+	storeStatus := ExternalStoreStatus{
+		Type:          storeType,
+		Location:      location,
+		Version:       version,
+		LastWriteTime: &writeTime,
+	}
+
+	for i := range cluster.Status.ExternalStores {
+		if cluster.Status.ExternalStores[i].Type == storeType {
+			cluster.Status.ExternalStores[i] = storeStatus
+			return
+		}
+	}
+
+	cluster.Status.ExternalStores = append(cluster.Status.ExternalStores, storeStatus)
+}
@@ -0,0 +1,4 @@
+package v1
+
+// Hub marks v1 as the hub version all other GardenerCluster versions are converted through.
+func (*GardenerCluster) Hub() {}
@@ -18,13 +18,23 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:resource:shortName=gcl,categories={kyma,infrastructure}
+//+kubebuilder:printcolumn:name="STATE",type=string,JSONPath=`.status.state`
+//+kubebuilder:printcolumn:name="SHOOT",type=string,JSONPath=`.spec.shoot.name`
+//+kubebuilder:printcolumn:name="SECRET",type=string,JSONPath=`.spec.kubeconfig.secret.name`
+//+kubebuilder:printcolumn:name="LAST-ROTATION",type=date,JSONPath=`.status.lastRotationTime`
+//+kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // GardenerCluster is the Schema for the clusters API
 type GardenerCluster struct {
@@ -48,8 +58,63 @@ type GardenerClusterList struct {
 type GardenerClusterSpec struct {
 	Kubeconfig Kubeconfig `json:"kubeconfig"`
 	Shoot      Shoot      `json:"shoot"`
+
+	// Purpose of the shoot, it selects the default kubeconfig rotation interval, and labels the metrics of the cluster.
+	// The purpose of the shoot in Gardener is used when not specified.
+	// +optional
+	Purpose Purpose `json:"purpose,omitempty"`
+
+	// Gardener selects the credentials of the Gardener project of the shoot, the credentials of the controller are used
+	// when not specified.
+	// +optional
+	Gardener *GardenerCredentials `json:"gardener,omitempty"`
+
+	// Priority orders the reconciliation of the clusters when the work queue is deep, the clusters with a higher
+	// priority are rotated, and repaired first. Only applied with the priority queue enabled on the controller.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// GardenerCredentials references the kubeconfig of a Gardener project, e.g. of another landscape.
+type GardenerCredentials struct {
+	// SecretRef references the secret with the kubeconfig in the namespace of the GardenerCluster, so that clusters
+	// can't use the credentials of other namespaces. The namespace of the current context of the kubeconfig is the
+	// namespace of the Gardener project, the project of the controller is used when it has none.
+	SecretRef GardenerCredentialsSecretRef `json:"secretRef"`
 }
 
+// GardenerCredentialsSecretRef references a kubeconfig in a secret in the namespace of the GardenerCluster.
+type GardenerCredentialsSecretRef struct {
+	Name string `json:"name"`
+
+	// Key defaults to `kubeconfig`.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DefaultGardenerCredentialsKey is the key of the kubeconfig in the secrets with the Gardener credentials.
+const DefaultGardenerCredentialsKey = "kubeconfig"
+
+// KeyOrDefault returns the key of the kubeconfig in the secret.
+func (ref GardenerCredentialsSecretRef) KeyOrDefault() string {
+	if ref.Key == "" {
+		return DefaultGardenerCredentialsKey
+	}
+
+	return ref.Key
+}
+
+// Purpose is the purpose of a shoot in Gardener, it changes the defaults applied to the shoot.
+// +kubebuilder:validation:Enum=evaluation;testing;development;production
+type Purpose string
+
+const (
+	PurposeEvaluation  Purpose = "evaluation"
+	PurposeTesting     Purpose = "testing"
+	PurposeDevelopment Purpose = "development"
+	PurposeProduction  Purpose = "production"
+)
+
 // Shoot defines the name of the Shoot resource
 type Shoot struct {
 	Name string `json:"name"`
@@ -58,45 +123,359 @@ type Shoot struct {
 // Kubeconfig defines the desired kubeconfig location
 type Kubeconfig struct {
 	Secret Secret `json:"secret"`
+
+	// SecretTemplate overrides the controller-wide templates of the secret name, and key for this cluster, each template
+	// is rendered on admission when the name, or the key of the secret is not specified.
+	// +optional
+	SecretTemplate *SecretTemplate `json:"secretTemplate,omitempty"`
+
+	// Immutable makes the controller create immutable secrets. Every rotation creates a new secret version
+	// named `<name>-v<version>` instead of updating the secret in place, see status.secrets for the current one.
+	// +optional
+	Immutable bool `json:"immutable,omitempty"`
+
+	// RetainOnDelete keeps the kubeconfig secrets when the GardenerCluster is deleted.
+	// +optional
+	RetainOnDelete bool `json:"retainOnDelete,omitempty"`
+
+	// AdditionalSecrets defines further secrets the same kubeconfig is replicated to, e.g. for consumers in other namespaces.
+	// +optional
+	AdditionalSecrets []Secret `json:"additionalSecrets,omitempty"`
+
+	// RotationInterval overrides the controller-wide kubeconfig rotation period for this cluster.
+	// It must be positive and must not exceed the controller-wide rotation period.
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+
+	// ExpirationSeconds overrides the controller-wide validity of the admin kubeconfig requested from Gardener for this cluster.
+	// It must be within the expiration limits of the controller, the rotation period is adjusted to the expiration.
+	// +kubebuilder:validation:Minimum=600
+	// +optional
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+
+	// Auth defines how the kubeconfig authenticates against the shoot, the admin kubeconfig is used by default.
+	// +optional
+	Auth *Auth `json:"auth,omitempty"`
+
+	// AccessLevel defines the permissions granted by the kubeconfig. The Viewer kubeconfig uses the token of a service account
+	// created in the shoot, that is bound to the `view` cluster role.
+	// +kubebuilder:validation:Enum=Admin;Viewer
+	// +kubebuilder:default=Admin
+	// +optional
+	AccessLevel AccessLevel `json:"accessLevel,omitempty"`
+
+	// EndpointType defines which API server endpoint of the shoot the kubeconfig targets. The Internal endpoint is only
+	// reachable from within the Gardener landscape, and keeps the traffic of consumers there off the public endpoint.
+	// +kubebuilder:validation:Enum=External;Internal
+	// +kubebuilder:default=External
+	// +optional
+	EndpointType EndpointType `json:"endpointType,omitempty"`
+
+	// CABundle references additional PEM encoded certificate authorities appended to the certificate authority data
+	// of the kubeconfig, e.g. of a TLS-intercepting proxy between the consumers and the shoot.
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+
+	// ExternalStores defines secret management systems outside of the cluster the kubeconfig is also written to.
+	// +optional
+	ExternalStores *ExternalStores `json:"externalStores,omitempty"`
+
+	// Encrypted stores the kubeconfig in the secrets envelope-encrypted with the key configured on the controller,
+	// the encrypted data key is recorded in the annotations of the secrets. The external stores receive the plain kubeconfig.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// SecretFormat defines the resource the kubeconfig is written to. SealedSecret writes Bitnami SealedSecrets sealed with
+	// the certificate configured on the controller instead of secrets, so that they can be committed to Git.
+	// The SealedSecrets are sealed with the strict scope, and are not supported in the immutable mode.
+	// +kubebuilder:validation:Enum=Secret;SealedSecret
+	// +kubebuilder:default=Secret
+	// +optional
+	SecretFormat SecretFormat `json:"secretFormat,omitempty"`
+}
+
+// CABundleSource references a CA bundle in the namespace of the GardenerCluster, exactly one of the references must be set
+type CABundleSource struct {
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+type EndpointType string
+
+const (
+	EndpointTypeExternal EndpointType = "External"
+	EndpointTypeInternal EndpointType = "Internal"
+)
+
+type SecretFormat string
+
+const (
+	SecretFormatSecret       SecretFormat = "Secret"
+	SecretFormatSealedSecret SecretFormat = "SealedSecret"
+)
+
+type AccessLevel string
+
+const (
+	AccessLevelAdmin  AccessLevel = "Admin"
+	AccessLevelViewer AccessLevel = "Viewer"
+)
+
+type AuthType string
+
+const (
+	// AuthTypeAdminKubeconfig authenticates with a short-lived client certificate requested from Gardener.
+	AuthTypeAdminKubeconfig AuthType = "AdminKubeconfig"
+	// AuthTypeOIDC authenticates the user with the OIDC provider using the kubelogin exec plugin.
+	AuthTypeOIDC AuthType = "OIDC"
+	// AuthTypeServiceAccountToken authenticates with the token of a service account created in the shoot. The admin kubeconfig
+	// is only used to set up the service account, so the consumer credentials do not depend on the admin credentials.
+	AuthTypeServiceAccountToken AuthType = "ServiceAccountToken"
+	// AuthTypeGardenlogin authenticates the operator with the gardenlogin exec plugin, which requests a client certificate
+	// from Gardener using the operator's own garden credentials.
+	AuthTypeGardenlogin AuthType = "Gardenlogin"
+)
+
+// Auth defines the authentication method used in the kubeconfig
+type Auth struct {
+	// +kubebuilder:validation:Enum=AdminKubeconfig;OIDC;ServiceAccountToken;Gardenlogin
+	// +kubebuilder:default=AdminKubeconfig
+	// +optional
+	Type AuthType `json:"type,omitempty"`
+
+	// OIDC configures the OIDC provider, it is required for the OIDC authentication type.
+	// The shoot API server must be configured to trust the issuer.
+	// +optional
+	OIDC *OIDCConfig `json:"oidc,omitempty"`
+}
+
+// OIDCConfig defines the OIDC provider the kubeconfig users authenticate with
+type OIDCConfig struct {
+	IssuerURL string `json:"issuerURL"`
+	ClientID  string `json:"clientID"`
+
+	// +optional
+	ExtraScopes []string `json:"extraScopes,omitempty"`
+}
+
+// AuthType returns the authentication method used in the kubeconfig.
+func (kubeconfig Kubeconfig) AuthType() AuthType {
+	if kubeconfig.Auth == nil || kubeconfig.Auth.Type == "" {
+		return AuthTypeAdminKubeconfig
+	}
+
+	return kubeconfig.Auth.Type
 }
 
 // SecretKeyRef defines the location, and structure of the secret containing kubeconfig
 type Secret struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	Key       string `json:"key"`
+	// Name is rendered from the secret name template when not specified.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace defaults to the namespace of the GardenerCluster.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key defaults to `config`.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Labels are applied to the secret, and restored when changed by other actors.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are applied to the secret, and restored when changed by other actors.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ClusterRef selects the cluster the secret is written to, the secret is written to the cluster of the
+	// GardenerCluster when not set.
+	// +optional
+	ClusterRef *SecretClusterRef `json:"clusterRef,omitempty"`
+}
+
+// SecretTemplate defines Go templates the name, and the key of the kubeconfig secret are rendered from, e.g.
+// `kubeconfig-{{ .Shoot.Name }}`. The templates are executed on the GardenerCluster, with the fields Name, Namespace,
+// Labels, Annotations, and Shoot.
+type SecretTemplate struct {
+	// Name is the template of the name of the secret, it applies to the secret only, the additional secrets must be named.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Key is the template of the key the kubeconfig is stored under, it applies to all the secrets.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DefaultClusterRefKey is the key of the kubeconfig of the target cluster when not specified otherwise.
+const DefaultClusterRefKey = "kubeconfig"
+
+// SecretClusterRef references the secret with the kubeconfig of the cluster a kubeconfig secret is delivered to. The
+// secret is read from the namespace of the GardenerCluster.
+type SecretClusterRef struct {
+	// Name of the secret with the kubeconfig of the target cluster.
+	Name string `json:"name"`
+
+	// Key defaults to `kubeconfig`.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// KeyOrDefault returns the key of the kubeconfig of the target cluster.
+func (ref SecretClusterRef) KeyOrDefault() string {
+	if ref.Key == "" {
+		return DefaultClusterRefKey
+	}
+
+	return ref.Key
+}
+
+// Remote reports whether the secret is written to another cluster than the one of the GardenerCluster.
+func (secret Secret) Remote() bool {
+	return secret.ClusterRef != nil
 }
 
+// Targets returns all secrets the kubeconfig is written to, starting with the primary secret.
+func (kubeconfig Kubeconfig) Targets() []Secret {
+	return append([]Secret{kubeconfig.Secret}, kubeconfig.AdditionalSecrets...)
+}
+
+// ForceKubeconfigRotationAnnotation requests the rotation of the kubeconfig of a GardenerCluster, the annotation is
+// removed once the kubeconfig is rotated.
+const ForceKubeconfigRotationAnnotation = "operator.kyma-project.io/force-kubeconfig-rotation"
+
+// AdoptSecretAnnotation set to "true" on an existing secret not managed by the controller allows the controller to
+// take it over as the target of a GardenerCluster, overwriting its kubeconfig.
+const AdoptSecretAnnotation = "operator.kyma-project.io/adopt"
+
 type State string
 
 const (
-	ReadyState State = "Ready"
-	ErrorState State = "Error"
+	ReadyState      State = "Ready"
+	ProcessingState State = "Processing"
+	ErrorState      State = "Error"
+	DeletingState   State = "Deleting"
+	// DegradedState is a GardenerCluster whose kubeconfig can't be rotated, while its secret still serves a kubeconfig
+	// which has not expired yet.
+	DegradedState State = "Degraded"
 )
 
 type ConditionReason string
 
 const (
-	ConditionReasonKubeconfigSecretCreated ConditionReason = "KubeconfigSecretCreated"
-	ConditionReasonKubeconfigSecretRotated ConditionReason = "KubeconfigSecretRotated"
-	ConditionReasonFailedToGetSecret       ConditionReason = "FailedToCheckSecret"
-	ConditionReasonFailedToCreateSecret    ConditionReason = "ConditionReasonFailedToCreateSecret"
-	ConditionReasonFailedToUpdateSecret    ConditionReason = "FailedToUpdateSecret"
-	ConditionReasonFailedToGetKubeconfig   ConditionReason = "FailedToGetKubeconfig"
+	ConditionReasonKubeconfigSecretCreating    ConditionReason = "KubeconfigSecretCreating"
+	ConditionReasonKubeconfigSecretRotating    ConditionReason = "KubeconfigSecretRotating"
+	ConditionReasonKubeconfigSecretDeleting    ConditionReason = "KubeconfigSecretDeleting"
+	ConditionReasonKubeconfigSecretCreated     ConditionReason = "KubeconfigSecretCreated"
+	ConditionReasonKubeconfigSecretRotated     ConditionReason = "KubeconfigSecretRotated"
+	ConditionReasonFailedToGetSecret           ConditionReason = "FailedToCheckSecret"
+	ConditionReasonFailedToCreateSecret        ConditionReason = "ConditionReasonFailedToCreateSecret"
+	ConditionReasonFailedToUpdateSecret        ConditionReason = "FailedToUpdateSecret"
+	ConditionReasonFailedToGetKubeconfig       ConditionReason = "FailedToGetKubeconfig"
+	ConditionReasonInvalidRotationInterval     ConditionReason = "InvalidRotationInterval"
+	ConditionReasonInvalidExpiration           ConditionReason = "InvalidExpiration"
+	ConditionReasonSecretDriftDetected         ConditionReason = "SecretDriftDetected"
+	ConditionReasonGardenerAccessible          ConditionReason = "GardenerAccessible"
+	ConditionReasonShootFound                  ConditionReason = "ShootFound"
+	ConditionReasonShootNotFound               ConditionReason = "ShootNotFound"
+	ConditionReasonDryRun                      ConditionReason = "DryRun"
+	ConditionReasonFailedToGenerateKubeconfig  ConditionReason = "FailedToGenerateKubeconfig"
+	ConditionReasonInvalidKubeconfig           ConditionReason = "InvalidKubeconfig"
+	ConditionReasonFailedToWriteExternalStore  ConditionReason = "FailedToWriteExternalStore"
+	ConditionReasonFailedToDeleteExternalStore ConditionReason = "FailedToDeleteExternalStore"
+	ConditionReasonReconciliationSuspended     ConditionReason = "ReconciliationSuspended"
+	ConditionReasonFailedToEncryptKubeconfig   ConditionReason = "FailedToEncryptKubeconfig"
+	ConditionReasonFailedToSealKubeconfig      ConditionReason = "FailedToSealKubeconfig"
+	ConditionReasonGardenerUnauthorized        ConditionReason = "GardenerUnauthorized"
+	ConditionReasonGardenerThrottled           ConditionReason = "GardenerThrottled"
+	ConditionReasonGardenerUnavailable         ConditionReason = "GardenerUnavailable"
+	ConditionReasonInvalidGardenerCredentials  ConditionReason = "InvalidGardenerCredentials"
+	ConditionReasonNamespaceRotationLimit      ConditionReason = "NamespaceRotationLimitExceeded"
+	ConditionReasonUnmanagedSecretExists       ConditionReason = "UnmanagedSecretExists"
+	ConditionReasonSecretOwnedByAnotherCluster ConditionReason = "SecretOwnedByAnotherCluster"
+	ConditionReasonSecretRelocationPending     ConditionReason = "SecretRelocationPending"
+	ConditionReasonSecretRelocated             ConditionReason = "SecretRelocated"
+	ConditionReasonFailedToRelocateSecret      ConditionReason = "FailedToRelocateSecret"
+	ConditionReasonShootRecreated              ConditionReason = "ShootRecreated"
 )
 
 type ConditionType string
 
 const (
+	// ConditionTypeKubeconfigManagement summarizes the state of the kubeconfig management.
 	ConditionTypeKubeconfigManagement ConditionType = "KubeconfigManagement"
+	// ConditionTypeGardenerAccess indicates whether the kubeconfig can be requested from Gardener.
+	ConditionTypeGardenerAccess ConditionType = "GardenerAccess"
+	// ConditionTypeShootAvailable indicates whether the referenced shoot exists in Gardener.
+	ConditionTypeShootAvailable ConditionType = "ShootAvailable"
+	// ConditionTypeSecretSynced indicates whether all target secrets hold the current kubeconfig.
+	ConditionTypeSecretSynced ConditionType = "SecretSynced"
+	// ConditionTypeSuspended indicates that the kubeconfig management is paused for the cluster.
+	ConditionTypeSuspended ConditionType = "Suspended"
+	// ConditionTypeDryRun reports the secret changes not applied because the controller runs in dry-run mode.
+	ConditionTypeDryRun ConditionType = "DryRun"
+	// ConditionTypeThrottled indicates that the rotation is delayed because the namespace exceeded its rotation limit.
+	ConditionTypeThrottled ConditionType = "Throttled"
+	// ConditionTypeSecretConflict indicates that a target secret exists, but the controller may not take it over.
+	ConditionTypeSecretConflict ConditionType = "SecretConflict"
+	// ConditionTypeSecretRelocated reports the move of the secrets to the new locations of the changed targets.
+	ConditionTypeSecretRelocated ConditionType = "SecretRelocated"
+	// ConditionTypeShootRecreated indicates that the shoot was deleted, and recreated with the same name, and the
+	// kubeconfig of the previous shoot was replaced.
+	ConditionTypeShootRecreated ConditionType = "ShootRecreated"
 )
 
 // GardenerClusterStatus defines the observed state of GardenerCluster
 type GardenerClusterStatus struct {
 	// State signifies current state of Gardener Cluster.
-	// Value can be one of ("Ready", "Processing", "Error", "Deleting").
+	// Value can be one of ("Ready", "Processing", "Error", "Degraded", "Deleting"). Degraded replaces Error while the
+	// rotation fails, but the secret still serves a kubeconfig which expires at status.kubeconfigExpirationTime.
 	State State `json:"state,omitempty"`
 
+	// ObservedGeneration is the most recent generation of the spec processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the time when the kubeconfig was last written to the secrets, including the initial creation.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastRotationTime is the time when the kubeconfig stored in the secrets was last replaced by a new one.
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// RotationCount is the number of kubeconfig rotations performed for the cluster.
+	// +optional
+	RotationCount int64 `json:"rotationCount,omitempty"`
+
+	// KubeconfigExpirationTime is the time when the kubeconfig stored in the secret becomes invalid.
+	// +optional
+	KubeconfigExpirationTime *metav1.Time `json:"kubeconfigExpirationTime,omitempty"`
+
+	// KubeconfigFetchFailures is the number of consecutive failures of requesting the kubeconfig from Gardener.
+	// +optional
+	KubeconfigFetchFailures int32 `json:"kubeconfigFetchFailures,omitempty"`
+
+	// NextRetryTime is the time of the next attempt to request the kubeconfig from Gardener after a failure.
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// Shoot reports the metadata of the Gardener shoot, refreshed whenever the kubeconfig is synchronized.
+	// +optional
+	Shoot *ShootInfo `json:"shoot,omitempty"`
+
+	// Secrets reports the synchronization status of every target secret.
+	// +optional
+	Secrets []SecretStatus `json:"secrets,omitempty"`
+
+	// ExternalStores reports the kubeconfig entries in the external stores.
+	// +optional
+	ExternalStores []ExternalStoreStatus `json:"externalStores,omitempty"`
+
 	// List of status conditions to indicate the status of a ServiceInstance.
 	// +optional
 	// +listType=map
@@ -104,6 +483,121 @@ type GardenerClusterStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ShootInfo defines the metadata of the Gardener shoot
+type ShootInfo struct {
+	// UID identifies the shoot in Gardener, it changes when the shoot is deleted, and recreated with the same name.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+
+	// ProviderType is the infrastructure provider of the shoot, e.g. aws, azure, gcp.
+	ProviderType string `json:"providerType,omitempty"`
+
+	Region string `json:"region,omitempty"`
+
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Purpose of the shoot in Gardener.
+	// +optional
+	Purpose Purpose `json:"purpose,omitempty"`
+
+	// Domain is the external domain of the shoot API server.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// ExternalEndpoint is the URL of the public shoot API server endpoint.
+	// +optional
+	ExternalEndpoint string `json:"externalEndpoint,omitempty"`
+
+	// InternalEndpoint is the URL of the shoot API server endpoint reachable from within the Gardener landscape.
+	// +optional
+	InternalEndpoint string `json:"internalEndpoint,omitempty"`
+}
+
+// SecretStatus defines the observed state of a single target secret
+type SecretStatus struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Status is True when the secret holds the current kubeconfig.
+	Status metav1.ConditionStatus `json:"status"`
+
+	Reason  ConditionReason `json:"reason,omitempty"`
+	Message string          `json:"message,omitempty"`
+
+	// CurrentSecret is the name of the secret holding the current kubeconfig, it differs from Name in immutable mode.
+	// +optional
+	CurrentSecret string `json:"currentSecret,omitempty"`
+
+	// ClusterRef is the cluster the secret is written to, it is recorded to delete the secret once the target moves.
+	// +optional
+	ClusterRef *SecretClusterRef `json:"clusterRef,omitempty"`
+}
+
+func (cluster *GardenerCluster) UpdateSecretStatus(secret Secret, reason ConditionReason, err error) {
+	secretStatus := SecretStatus{
+		Name:       secret.Name,
+		Namespace:  secret.Namespace,
+		Status:     metav1.ConditionTrue,
+		Reason:     reason,
+		Message:    getMessage(reason),
+		ClusterRef: secret.ClusterRef,
+	}
+
+	if err != nil {
+		secretStatus.Status = metav1.ConditionFalse
+		secretStatus.Message = fmt.Sprintf("%s Error: %s", getMessage(reason), err.Error())
+	}
+
+	for i := range cluster.Status.Secrets {
+		if cluster.Status.Secrets[i].Name == secret.Name && cluster.Status.Secrets[i].Namespace == secret.Namespace {
+			cluster.Status.Secrets[i] = secretStatus
+			return
+		}
+	}
+
+	cluster.Status.Secrets = append(cluster.Status.Secrets, secretStatus)
+}
+
+// AddPendingRelocation records the previous location of a moved target secret, the secret there is deleted once the
+// secrets at the new locations are verified.
+func (cluster *GardenerCluster) AddPendingRelocation(secretStatus SecretStatus) {
+	secretStatus.Status = metav1.ConditionFalse
+	secretStatus.Reason = ConditionReasonSecretRelocationPending
+	secretStatus.Message = getMessage(ConditionReasonSecretRelocationPending)
+	secretStatus.CurrentSecret = ""
+
+	cluster.Status.Secrets = append(cluster.Status.Secrets, secretStatus)
+}
+
+// ShootPurpose returns the purpose of the spec, or the purpose of the shoot in Gardener when not specified.
+func (cluster *GardenerCluster) ShootPurpose() Purpose {
+	if cluster.Spec.Purpose != "" || cluster.Status.Shoot == nil {
+		return cluster.Spec.Purpose
+	}
+
+	return cluster.Status.Shoot.Purpose
+}
+
+// SecretSynced reports whether the kubeconfig has been successfully written to the secret before.
+func (cluster *GardenerCluster) SecretSynced(secret Secret) bool {
+	for _, secretStatus := range cluster.Status.Secrets {
+		if secretStatus.Name == secret.Name && secretStatus.Namespace == secret.Namespace {
+			return secretStatus.Status == metav1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func (cluster *GardenerCluster) SetCurrentSecretName(secret Secret, currentSecretName string) {
+	for i := range cluster.Status.Secrets {
+		if cluster.Status.Secrets[i].Name == secret.Name && cluster.Status.Secrets[i].Namespace == secret.Namespace {
+			cluster.Status.Secrets[i].CurrentSecret = currentSecretName
+			return
+		}
+	}
+}
+
 func (cluster *GardenerCluster) UpdateConditionForReadyState(conditionType ConditionType, reason ConditionReason, conditionStatus metav1.ConditionStatus) {
 	cluster.Status.State = ReadyState
 
@@ -118,6 +612,34 @@ func (cluster *GardenerCluster) UpdateConditionForReadyState(conditionType Condi
 	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
 }
 
+func (cluster *GardenerCluster) UpdateConditionForProcessingState(conditionType ConditionType, reason ConditionReason, conditionStatus metav1.ConditionStatus) {
+	cluster.Status.State = ProcessingState
+
+	condition := metav1.Condition{
+		Type:               string(conditionType),
+		Status:             conditionStatus,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(reason),
+		Message:            getMessage(reason),
+	}
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, condition.Type)
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+func (cluster *GardenerCluster) UpdateConditionForDeletingState(conditionType ConditionType, reason ConditionReason, conditionStatus metav1.ConditionStatus) {
+	cluster.Status.State = DeletingState
+
+	condition := metav1.Condition{
+		Type:               string(conditionType),
+		Status:             conditionStatus,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(reason),
+		Message:            getMessage(reason),
+	}
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, condition.Type)
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
 func (cluster *GardenerCluster) UpdateConditionForErrorState(conditionType ConditionType, reason ConditionReason, conditionStatus metav1.ConditionStatus, error error) {
 	cluster.Status.State = ErrorState
 
@@ -132,8 +654,54 @@ func (cluster *GardenerCluster) UpdateConditionForErrorState(conditionType Condi
 	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
 }
 
+// SetCondition sets a condition without changing the state of the cluster, the transition time is kept if the status doesn't change.
+func (cluster *GardenerCluster) SetCondition(conditionType ConditionType, conditionStatus metav1.ConditionStatus, reason ConditionReason, err error) {
+	message := getMessage(reason)
+	if err != nil {
+		message = fmt.Sprintf("%s Error: %s", message, err.Error())
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(conditionType),
+		Status:  conditionStatus,
+		Reason:  string(reason),
+		Message: message,
+	})
+}
+
+// SetSecretRelocatedCondition reports the previous locations of the secrets deleted after their targets moved.
+func (cluster *GardenerCluster) SetSecretRelocatedCondition(previousLocations []string) {
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(ConditionTypeSecretRelocated),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonSecretRelocated),
+		Message: fmt.Sprintf("%s %s", getMessage(ConditionReasonSecretRelocated), strings.Join(previousLocations, ", ")),
+	})
+}
+
+// SetDryRunCondition reports the actions skipped in dry-run mode, the condition is removed when there are none.
+func (cluster *GardenerCluster) SetDryRunCondition(actions []string) {
+	if len(actions) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(ConditionTypeDryRun))
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(ConditionTypeDryRun),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonDryRun),
+		Message: fmt.Sprintf("%s %s", getMessage(ConditionReasonDryRun), strings.Join(actions, " ")),
+	})
+}
+
 func getMessage(reason ConditionReason) string {
 	switch reason {
+	case ConditionReasonKubeconfigSecretCreating:
+		return "Secret is being created."
+	case ConditionReasonKubeconfigSecretRotating:
+		return "Secret is being rotated."
+	case ConditionReasonKubeconfigSecretDeleting:
+		return "Secret is being deleted."
 	case ConditionReasonKubeconfigSecretCreated:
 		return "Secret created successfully."
 	case ConditionReasonKubeconfigSecretRotated:
@@ -146,6 +714,92 @@ func getMessage(reason ConditionReason) string {
 		return "Failed to get secret."
 	case ConditionReasonFailedToGetKubeconfig:
 		return "Failed to get kubeconfig."
+	case ConditionReasonInvalidRotationInterval:
+		return "Invalid kubeconfig rotation interval."
+	case ConditionReasonInvalidExpiration:
+		return "Invalid kubeconfig expiration."
+	case ConditionReasonSecretDriftDetected:
+		return "Secret modified by another actor has been restored."
+	case ConditionReasonGardenerAccessible:
+		return "Gardener is accessible."
+	case ConditionReasonShootFound:
+		return "Shoot found in Gardener."
+	case ConditionReasonShootNotFound:
+		return "Shoot not found in Gardener."
+	case ConditionReasonGardenerUnauthorized:
+		return "Gardener rejected the credentials of the controller."
+	case ConditionReasonGardenerThrottled:
+		return "Gardener rate limited the request for the kubeconfig."
+	case ConditionReasonGardenerUnavailable:
+		return "Gardener failed repeatedly, the requests for kubeconfigs are paused until it recovers."
+	case ConditionReasonInvalidGardenerCredentials:
+		return "Failed to read the Gardener credentials selected by the cluster."
+	case ConditionReasonNamespaceRotationLimit:
+		return "The namespace exceeded its rotation limit, the rotation is delayed."
+	case ConditionReasonUnmanagedSecretExists:
+		return "A secret not managed by the controller exists, annotate it with " + AdoptSecretAnnotation + "=true to adopt it."
+	case ConditionReasonSecretOwnedByAnotherCluster:
+		return "The secret is managed for another GardenerCluster."
+	case ConditionReasonSecretRelocationPending:
+		return "The secret is no longer a target, it is deleted once the secrets at the new locations are verified."
+	case ConditionReasonSecretRelocated:
+		return "Secrets moved to the new locations, deleted the secrets at the previous locations:"
+	case ConditionReasonFailedToRelocateSecret:
+		return "Failed to move the secrets to the new locations, the secrets at the previous locations have been kept."
+	case ConditionReasonShootRecreated:
+		return "The shoot was recreated with the same name, the kubeconfig of the previous shoot is replaced."
+	case ConditionReasonReconciliationSuspended:
+		return "Kubeconfig management is paused with the reconciler disabled annotation."
+	case ConditionReasonFailedToGenerateKubeconfig:
+		return "Failed to generate kubeconfig for the authentication type."
+	case ConditionReasonFailedToWriteExternalStore:
+		return "Failed to write kubeconfig to external store."
+	case ConditionReasonFailedToDeleteExternalStore:
+		return "Failed to delete kubeconfig from external store."
+	case ConditionReasonInvalidKubeconfig:
+		return "Kubeconfig failed validation, the secret has not been updated."
+	case ConditionReasonFailedToEncryptKubeconfig:
+		return "Failed to encrypt kubeconfig, the secret has not been updated."
+	case ConditionReasonFailedToSealKubeconfig:
+		return "Failed to seal kubeconfig, the SealedSecret has not been updated."
+	case ConditionReasonDryRun:
+		return "Dry run, changes not applied:"
+	case ConditionReasonKubeconfigIssued:
+		return "Kubeconfig issued successfully."
+	case ConditionReasonKubeconfigExpired:
+		return "Kubeconfig expired, and the secret has been deleted."
+	case ConditionReasonInvalidTTL:
+		return "Invalid kubeconfig TTL."
+	case ConditionReasonShootNotReferenced:
+		return "Shoot is not referenced in the namespace of the request, the kubeconfig has not been issued."
+	case ConditionReasonShootCreating:
+		return "Shoot is being created."
+	case ConditionReasonShootUpdating:
+		return "Shoot is being updated."
+	case ConditionReasonShootReady:
+		return "Shoot is ready."
+	case ConditionReasonShootOperationFailed:
+		return "Gardener failed to reconcile the shoot."
+	case ConditionReasonShootDeleting:
+		return "Shoot is being deleted."
+	case ConditionReasonFailedToCreateShoot:
+		return "Failed to create shoot."
+	case ConditionReasonFailedToUpdateShoot:
+		return "Failed to update shoot."
+	case ConditionReasonFailedToDeleteShoot:
+		return "Failed to delete shoot."
+	case ConditionReasonFailedToGetShoot:
+		return "Failed to get shoot."
+	case ConditionReasonShootHibernated:
+		return "Shoot is hibernated."
+	case ConditionReasonShootHibernating:
+		return "Shoot is being hibernated."
+	case ConditionReasonShootWakingUp:
+		return "Shoot is being woken up."
+	case ConditionReasonShootAwake:
+		return "Shoot is awake."
+	case ConditionReasonDeletionNotConfirmed:
+		return "Shoot is protected, the deletion has to be confirmed with the " + DeletionConfirmationAnnotation + " annotation."
 
 	default:
 		return "Unknown condition"
@@ -0,0 +1,474 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the GardenerCluster webhooks, including the conversion webhook serving all API versions.
+// The secret template is the controller-wide default of the templates the secret name, and key are rendered from, the
+// shoot admission checks the shoots of the created clusters exist.
+func (cluster *GardenerCluster) SetupWebhookWithManager(mgr ctrl.Manager, secretTemplate SecretTemplate, shootAdmission ShootAdmission) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(cluster).
+		WithDefaulter(&gardenerClusterDefaulter{secretTemplate: secretTemplate}).
+		WithValidator(&gardenerClusterValidator{shootAdmission: shootAdmission}).
+		Complete()
+}
+
+// ShootLookup checks the shoots of the Gardener project, the lookup fails with a NotFound error for shoots which don't exist.
+// +kubebuilder:object:generate=false
+type ShootLookup interface {
+	LookupShoot(ctx context.Context, name string) error
+}
+
+// ShootAdmission configures the check of the shoot referenced by the GardenerClusters on creation, so that a typo in
+// the shoot name is rejected instead of failing every reconciliation.
+// +kubebuilder:object:generate=false
+type ShootAdmission struct {
+	// Shoots looks the shoots up, the check is skipped without it.
+	Shoots ShootLookup
+	// Enabled reports whether the check is enabled, it is read on every request so that it can be toggled at runtime.
+	Enabled func() bool
+	// Timeout bounds the lookup, the cluster is admitted when Gardener doesn't answer in time.
+	Timeout time.Duration
+}
+
+func (shootAdmission ShootAdmission) enabled() bool {
+	return shootAdmission.Shoots != nil && shootAdmission.Enabled != nil && shootAdmission.Enabled()
+}
+
+// DefaultKubeconfigSecretKey is the secret key the kubeconfig is stored under when not specified otherwise.
+const DefaultKubeconfigSecretKey = "config"
+
+//+kubebuilder:webhook:path=/mutate-infrastructuremanager-kyma-project-io-v1-gardenercluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructuremanager.kyma-project.io,resources=gardenerclusters,verbs=create;update,versions=v1,name=mgardenercluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &GardenerCluster{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (cluster *GardenerCluster) Default() {
+	cluster.defaultSecret(&cluster.Spec.Kubeconfig.Secret)
+
+	for i := range cluster.Spec.Kubeconfig.AdditionalSecrets {
+		cluster.defaultSecret(&cluster.Spec.Kubeconfig.AdditionalSecrets[i])
+	}
+}
+
+func (cluster *GardenerCluster) defaultSecret(secret *Secret) {
+	if secret.Key == "" {
+		secret.Key = DefaultKubeconfigSecretKey
+	}
+
+	if secret.Namespace == "" {
+		secret.Namespace = cluster.Namespace
+	}
+}
+
+// gardenerClusterDefaulter extends the defaulting with the secret names, and keys rendered from the templates.
+// +kubebuilder:object:generate=false
+type gardenerClusterDefaulter struct {
+	secretTemplate SecretTemplate
+}
+
+var _ webhook.CustomDefaulter = &gardenerClusterDefaulter{}
+
+func (defaulter *gardenerClusterDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*GardenerCluster)
+	if !ok {
+		return errors.Errorf("unexpected object %T", obj)
+	}
+
+	// the templates are rendered before the defaults, so that the key template takes precedence over the default key
+	err := cluster.ApplySecretTemplate(defaulter.secretTemplate)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+
+	cluster.Default()
+
+	return nil
+}
+
+// ApplySecretTemplate renders the name of the secret, and the keys of all the secrets which are not specified. The
+// templates of the cluster take precedence over the given controller-wide ones.
+func (cluster *GardenerCluster) ApplySecretTemplate(defaults SecretTemplate) error {
+	secretTemplate := defaults
+	if override := cluster.Spec.Kubeconfig.SecretTemplate; override != nil {
+		if override.Name != "" {
+			secretTemplate.Name = override.Name
+		}
+
+		if override.Key != "" {
+			secretTemplate.Key = override.Key
+		}
+	}
+
+	secret := &cluster.Spec.Kubeconfig.Secret
+	if secret.Name == "" && secretTemplate.Name != "" {
+		name, err := cluster.renderSecretTemplate(secretTemplate.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to render secret name template")
+		}
+
+		secret.Name = name
+	}
+
+	if secretTemplate.Key == "" {
+		return nil
+	}
+
+	key, err := cluster.renderSecretTemplate(secretTemplate.Key)
+	if err != nil {
+		return errors.Wrap(err, "failed to render secret key template")
+	}
+
+	if secret.Key == "" {
+		secret.Key = key
+	}
+
+	for i := range cluster.Spec.Kubeconfig.AdditionalSecrets {
+		if cluster.Spec.Kubeconfig.AdditionalSecrets[i].Key == "" {
+			cluster.Spec.Kubeconfig.AdditionalSecrets[i].Key = key
+		}
+	}
+
+	return nil
+}
+
+// secretTemplateData is the data the secret templates are executed on
+type secretTemplateData struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+	Shoot       Shoot
+}
+
+func (cluster *GardenerCluster) renderSecretTemplate(text string) (string, error) {
+	tmpl, err := ParseSecretTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered bytes.Buffer
+
+	err = tmpl.Execute(&rendered, secretTemplateData{
+		Name:        cluster.Name,
+		Namespace:   cluster.Namespace,
+		Labels:      cluster.Labels,
+		Annotations: cluster.Annotations,
+		Shoot:       cluster.Spec.Shoot,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return rendered.String(), nil
+}
+
+// ParseSecretTemplate parses a template of the secret name, or key, referencing missing labels, or annotations fails
+// the rendering.
+func ParseSecretTemplate(text string) (*template.Template, error) {
+	return template.New("secret").Option("missingkey=error").Parse(text)
+}
+
+//+kubebuilder:webhook:path=/validate-infrastructuremanager-kyma-project-io-v1-gardenercluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructuremanager.kyma-project.io,resources=gardenerclusters,verbs=create;update,versions=v1,name=vgardenercluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &GardenerCluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (cluster *GardenerCluster) ValidateCreate() (admission.Warnings, error) {
+	return nil, cluster.toInvalidError(cluster.validateSpec())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (cluster *GardenerCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	oldCluster, ok := old.(*GardenerCluster)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", old)
+	}
+
+	// the finalizer has to be removable from clusters created before the validation was introduced
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	allErrs := cluster.validateSpec()
+	allErrs = append(allErrs, cluster.validateImmutableFields(oldCluster)...)
+
+	return nil, cluster.toInvalidError(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (cluster *GardenerCluster) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+// gardenerClusterValidator extends the validation of the spec with the check of the shoot in Gardener.
+// +kubebuilder:object:generate=false
+type gardenerClusterValidator struct {
+	shootAdmission ShootAdmission
+}
+
+var _ webhook.CustomValidator = &gardenerClusterValidator{}
+
+func (validator *gardenerClusterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*GardenerCluster)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", obj)
+	}
+
+	warnings, err := cluster.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	// the shoot name is immutable, it is checked on creation only
+	shootWarnings, err := validator.validateShoot(ctx, cluster)
+
+	return append(warnings, shootWarnings...), err
+}
+
+func (validator *gardenerClusterValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := newObj.(*GardenerCluster)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", newObj)
+	}
+
+	return cluster.ValidateUpdate(oldObj)
+}
+
+func (validator *gardenerClusterValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*GardenerCluster)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", obj)
+	}
+
+	return cluster.ValidateDelete()
+}
+
+// validateShoot rejects the cluster when its shoot doesn't exist in the Gardener project. Gardener not answering in
+// time doesn't block the creation, the cluster is admitted with a warning then. The clusters with their own Gardener
+// credentials reference shoots of other projects, they are not checked.
+func (validator *gardenerClusterValidator) validateShoot(ctx context.Context, cluster *GardenerCluster) (admission.Warnings, error) {
+	if !validator.shootAdmission.enabled() || cluster.Spec.Gardener != nil {
+		return nil, nil
+	}
+
+	if validator.shootAdmission.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, validator.shootAdmission.Timeout)
+		defer cancel()
+	}
+
+	err := validator.shootAdmission.Shoots.LookupShoot(ctx, cluster.Spec.Shoot.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, cluster.toInvalidError(field.ErrorList{field.NotFound(field.NewPath("spec", "shoot", "name"), cluster.Spec.Shoot.Name)})
+	}
+
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("the existence of the shoot %s could not be checked: %s", cluster.Spec.Shoot.Name, err)}, nil
+	}
+
+	return nil, nil
+}
+
+func (cluster *GardenerCluster) validateSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if cluster.Spec.Shoot.Name == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("shoot", "name"), "shoot name must not be empty"))
+	}
+
+	kubeconfigPath := specPath.Child("kubeconfig")
+	allErrs = append(allErrs, validateSecret(kubeconfigPath.Child("secret"), cluster.Spec.Kubeconfig.Secret)...)
+
+	for i, secret := range cluster.Spec.Kubeconfig.AdditionalSecrets {
+		allErrs = append(allErrs, validateSecret(kubeconfigPath.Child("additionalSecrets").Index(i), secret)...)
+	}
+
+	allErrs = append(allErrs, validateSecretTemplate(kubeconfigPath.Child("secretTemplate"), cluster.Spec.Kubeconfig.SecretTemplate)...)
+	allErrs = append(allErrs, validateAuth(kubeconfigPath.Child("auth"), cluster.Spec.Kubeconfig.Auth)...)
+
+	allErrs = append(allErrs, validateCABundle(kubeconfigPath.Child("caBundle"), cluster.Spec.Kubeconfig.CABundle)...)
+	allErrs = append(allErrs, validateExternalStores(kubeconfigPath.Child("externalStores"), cluster.Spec.Kubeconfig.ExternalStores)...)
+
+	authType := cluster.Spec.Kubeconfig.AuthType()
+	if cluster.Spec.Kubeconfig.AccessLevel == AccessLevelViewer && (authType == AuthTypeOIDC || authType == AuthTypeGardenlogin) {
+		allErrs = append(allErrs, field.Invalid(kubeconfigPath.Child("accessLevel"), cluster.Spec.Kubeconfig.AccessLevel, fmt.Sprintf("viewer access level is not supported with the %s authentication type", authType)))
+	}
+
+	if cluster.Spec.Kubeconfig.SecretFormat == SecretFormatSealedSecret && cluster.Spec.Kubeconfig.Immutable {
+		allErrs = append(allErrs, field.Invalid(kubeconfigPath.Child("secretFormat"), cluster.Spec.Kubeconfig.SecretFormat, "SealedSecrets are not supported in the immutable mode"))
+	}
+
+	return allErrs
+}
+
+func validateSecretTemplate(path *field.Path, secretTemplate *SecretTemplate) field.ErrorList {
+	if secretTemplate == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	if _, err := ParseSecretTemplate(secretTemplate.Name); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("name"), secretTemplate.Name, err.Error()))
+	}
+
+	if _, err := ParseSecretTemplate(secretTemplate.Key); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("key"), secretTemplate.Key, err.Error()))
+	}
+
+	return allErrs
+}
+
+func validateAuth(path *field.Path, auth *Auth) field.ErrorList {
+	if auth == nil || auth.Type != AuthTypeOIDC {
+		return nil
+	}
+
+	if auth.OIDC == nil {
+		return field.ErrorList{field.Required(path.Child("oidc"), "OIDC configuration is required for the OIDC authentication type")}
+	}
+
+	var allErrs field.ErrorList
+
+	if auth.OIDC.IssuerURL == "" {
+		allErrs = append(allErrs, field.Required(path.Child("oidc", "issuerURL"), "issuer URL must not be empty"))
+	}
+
+	if auth.OIDC.ClientID == "" {
+		allErrs = append(allErrs, field.Required(path.Child("oidc", "clientID"), "client ID must not be empty"))
+	}
+
+	return allErrs
+}
+
+func validateCABundle(path *field.Path, caBundle *CABundleSource) field.ErrorList {
+	if caBundle == nil {
+		return nil
+	}
+
+	if (caBundle.ConfigMapKeyRef == nil) == (caBundle.SecretKeyRef == nil) {
+		return field.ErrorList{field.Invalid(path, caBundle, "exactly one of configMapKeyRef, and secretKeyRef must be set")}
+	}
+
+	return nil
+}
+
+func validateExternalStores(path *field.Path, stores *ExternalStores) field.ErrorList {
+	if stores == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	if stores.Vault != nil && strings.Trim(stores.Vault.Path, "/") == "" {
+		allErrs = append(allErrs, field.Required(path.Child("vault", "path"), "Vault secret path must not be empty"))
+	}
+
+	if stores.AWSSecretsManager != nil && stores.AWSSecretsManager.SecretName == "" {
+		allErrs = append(allErrs, field.Required(path.Child("awsSecretsManager", "secretName"), "AWS secret name must not be empty"))
+	}
+
+	if stores.GCPSecretManager != nil {
+		if stores.GCPSecretManager.Project == "" {
+			allErrs = append(allErrs, field.Required(path.Child("gcpSecretManager", "project"), "GCP project must not be empty"))
+		}
+
+		if stores.GCPSecretManager.SecretID == "" {
+			allErrs = append(allErrs, field.Required(path.Child("gcpSecretManager", "secretID"), "GCP secret ID must not be empty"))
+		}
+	}
+
+	if stores.AzureKeyVault != nil {
+		if stores.AzureKeyVault.VaultName == "" {
+			allErrs = append(allErrs, field.Required(path.Child("azureKeyVault", "vaultName"), "Azure key vault name must not be empty"))
+		}
+
+		if stores.AzureKeyVault.SecretName == "" {
+			allErrs = append(allErrs, field.Required(path.Child("azureKeyVault", "secretName"), "Azure secret name must not be empty"))
+		}
+	}
+
+	if stores.PushSecret != nil {
+		if stores.PushSecret.SecretStoreRef.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("pushSecret", "secretStoreRef", "name"), "secret store name must not be empty"))
+		}
+
+		if stores.PushSecret.RemoteKey == "" {
+			allErrs = append(allErrs, field.Required(path.Child("pushSecret", "remoteKey"), "remote key must not be empty"))
+		}
+	}
+
+	return allErrs
+}
+
+func (cluster *GardenerCluster) validateImmutableFields(old *GardenerCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if cluster.Spec.Shoot.Name != old.Spec.Shoot.Name {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("shoot", "name"), "field is immutable"))
+	}
+
+	// the secret can be moved to another name, or namespace, the controller deletes the secret at the previous location
+	secretPath := specPath.Child("kubeconfig", "secret")
+	if !equality.Semantic.DeepEqual(cluster.Spec.Kubeconfig.Secret.ClusterRef, old.Spec.Kubeconfig.Secret.ClusterRef) {
+		allErrs = append(allErrs, field.Forbidden(secretPath.Child("clusterRef"), "field is immutable"))
+	}
+
+	return allErrs
+}
+
+func validateSecret(path *field.Path, secret Secret) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, msg := range validation.IsDNS1123Subdomain(secret.Name) {
+		allErrs = append(allErrs, field.Invalid(path.Child("name"), secret.Name, msg))
+	}
+
+	for _, msg := range validation.IsDNS1123Label(secret.Namespace) {
+		allErrs = append(allErrs, field.Invalid(path.Child("namespace"), secret.Namespace, msg))
+	}
+
+	if secret.ClusterRef != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(secret.ClusterRef.Name) {
+			allErrs = append(allErrs, field.Invalid(path.Child("clusterRef", "name"), secret.ClusterRef.Name, msg))
+		}
+	}
+
+	if secret.Key == "" {
+		return append(allErrs, field.Required(path.Child("key"), "secret key must not be empty"))
+	}
+
+	for _, msg := range validation.IsConfigMapKey(secret.Key) {
+		allErrs = append(allErrs, field.Invalid(path.Child("key"), secret.Key, msg))
+	}
+
+	return allErrs
+}
+
+func (cluster *GardenerCluster) toInvalidError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("GardenerCluster").GroupKind(), cluster.Name, allErrs)
+}
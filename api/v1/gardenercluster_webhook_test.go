@@ -0,0 +1,355 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGardenerClusterValidation(t *testing.T) {
+	t.Run("should accept valid cluster", func(t *testing.T) {
+		// given
+		cluster := fixGardenerCluster()
+
+		// when
+		_, err := cluster.ValidateCreate()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		modify func(cluster *GardenerCluster)
+		field  string
+	}{
+		{
+			name:   "empty shoot name",
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Shoot.Name = "" },
+			field:  "spec.shoot.name",
+		},
+		{
+			name:   "invalid secret name",
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Kubeconfig.Secret.Name = "Kubeconfig_Secret" },
+			field:  "spec.kubeconfig.secret.name",
+		},
+		{
+			name:   "invalid secret namespace",
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Kubeconfig.Secret.Namespace = "kcp.system" },
+			field:  "spec.kubeconfig.secret.namespace",
+		},
+		{
+			name:   "missing secret key",
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Kubeconfig.Secret.Key = "" },
+			field:  "spec.kubeconfig.secret.key",
+		},
+		{
+			name: "invalid additional secret",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.AdditionalSecrets = []Secret{{Name: "copy", Namespace: "", Key: "config"}}
+			},
+			field: "spec.kubeconfig.additionalSecrets[0].namespace",
+		},
+		{
+			name: "invalid target cluster reference",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.Secret.ClusterRef = &SecretClusterRef{Name: "Target_Cluster"}
+			},
+			field: "spec.kubeconfig.secret.clusterRef.name",
+		},
+		{
+			name: "missing OIDC configuration",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.Auth = &Auth{Type: AuthTypeOIDC}
+			},
+			field: "spec.kubeconfig.auth.oidc",
+		},
+		{
+			name: "missing OIDC client ID",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.Auth = &Auth{Type: AuthTypeOIDC, OIDC: &OIDCConfig{IssuerURL: "https://issuer.example.com"}}
+			},
+			field: "spec.kubeconfig.auth.oidc.clientID",
+		},
+		{
+			name: "viewer access level with OIDC",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.AccessLevel = AccessLevelViewer
+				cluster.Spec.Kubeconfig.Auth = &Auth{Type: AuthTypeOIDC, OIDC: &OIDCConfig{IssuerURL: "https://issuer.example.com", ClientID: "client"}}
+			},
+			field: "spec.kubeconfig.accessLevel",
+		},
+		{
+			name: "CA bundle without reference",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.CABundle = &CABundleSource{}
+			},
+			field: "spec.kubeconfig.caBundle",
+		},
+		{
+			name: "empty Vault path",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.ExternalStores = &ExternalStores{Vault: &VaultTarget{Path: "/"}}
+			},
+			field: "spec.kubeconfig.externalStores.vault.path",
+		},
+		{
+			name: "viewer access level with gardenlogin",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.AccessLevel = AccessLevelViewer
+				cluster.Spec.Kubeconfig.Auth = &Auth{Type: AuthTypeGardenlogin}
+			},
+			field: "spec.kubeconfig.accessLevel",
+		},
+		{
+			name: "SealedSecret format in immutable mode",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.SecretFormat = SecretFormatSealedSecret
+				cluster.Spec.Kubeconfig.Immutable = true
+			},
+			field: "spec.kubeconfig.secretFormat",
+		},
+		{
+			name: "invalid secret name template",
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Kubeconfig.SecretTemplate = &SecretTemplate{Name: "kubeconfig-{{ .Shoot.Name"}
+			},
+			field: "spec.kubeconfig.secretTemplate.name",
+		},
+	} {
+		t.Run("should reject cluster with "+tc.name, func(t *testing.T) {
+			// given
+			cluster := fixGardenerCluster()
+			tc.modify(cluster)
+
+			// when
+			_, err := cluster.ValidateCreate()
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.field)
+		})
+	}
+
+	t.Run("should reject change of immutable fields", func(t *testing.T) {
+		// given
+		old := fixGardenerCluster()
+		cluster := fixGardenerCluster()
+		cluster.Spec.Shoot.Name = "other-shoot"
+		cluster.Spec.Kubeconfig.Secret.ClusterRef = &SecretClusterRef{Name: "target-cluster"}
+
+		// when
+		_, err := cluster.ValidateUpdate(old)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.shoot.name")
+		assert.Contains(t, err.Error(), "spec.kubeconfig.secret.clusterRef")
+	})
+
+	t.Run("should accept change of mutable fields", func(t *testing.T) {
+		// given
+		old := fixGardenerCluster()
+		cluster := fixGardenerCluster()
+		cluster.Spec.Kubeconfig.Secret.Key = "kubeconfig"
+		cluster.Spec.Kubeconfig.Secret.Name = "other-secret"
+		cluster.Spec.Kubeconfig.Secret.Namespace = "other-namespace"
+		cluster.Spec.Kubeconfig.RotationInterval = &metav1.Duration{Duration: time.Hour}
+
+		// when
+		_, err := cluster.ValidateUpdate(old)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept update of deleted cluster", func(t *testing.T) {
+		// given
+		old := fixGardenerCluster()
+		old.Spec.Shoot.Name = ""
+		cluster := old.DeepCopy()
+		cluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		cluster.Finalizers = nil
+
+		// when
+		_, err := cluster.ValidateUpdate(old)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestGardenerClusterDefaulting(t *testing.T) {
+	t.Run("should default secret key and namespace", func(t *testing.T) {
+		// given
+		cluster := fixGardenerCluster()
+		cluster.Spec.Kubeconfig.Secret = Secret{Name: "kubeconfig"}
+		cluster.Spec.Kubeconfig.AdditionalSecrets = []Secret{{Name: "copy", Namespace: "other"}}
+
+		// when
+		cluster.Default()
+
+		// then
+		assert.Equal(t, Secret{Name: "kubeconfig", Namespace: "kcp-system", Key: "config"}, cluster.Spec.Kubeconfig.Secret)
+		assert.Equal(t, []Secret{{Name: "copy", Namespace: "other", Key: "config"}}, cluster.Spec.Kubeconfig.AdditionalSecrets)
+	})
+
+	t.Run("should keep specified secret key and namespace", func(t *testing.T) {
+		// given
+		cluster := fixGardenerCluster()
+		cluster.Spec.Kubeconfig.Secret = Secret{Name: "kubeconfig", Namespace: "other", Key: "kubeconfig"}
+
+		// when
+		cluster.Default()
+
+		// then
+		assert.Equal(t, Secret{Name: "kubeconfig", Namespace: "other", Key: "kubeconfig"}, cluster.Spec.Kubeconfig.Secret)
+	})
+
+	t.Run("should render secret name and key from controller templates", func(t *testing.T) {
+		// given
+		defaulter := &gardenerClusterDefaulter{secretTemplate: SecretTemplate{Name: "kubeconfig-{{ .Shoot.Name }}", Key: "{{ .Labels.env }}"}}
+		cluster := fixGardenerCluster()
+		cluster.Labels = map[string]string{"env": "dev"}
+		cluster.Spec.Kubeconfig.Secret = Secret{}
+		cluster.Spec.Kubeconfig.AdditionalSecrets = []Secret{{Name: "copy", Namespace: "other"}}
+
+		// when
+		err := defaulter.Default(context.Background(), cluster)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Secret{Name: "kubeconfig-shoot", Namespace: "kcp-system", Key: "dev"}, cluster.Spec.Kubeconfig.Secret)
+		assert.Equal(t, []Secret{{Name: "copy", Namespace: "other", Key: "dev"}}, cluster.Spec.Kubeconfig.AdditionalSecrets)
+	})
+
+	t.Run("should prefer cluster templates and specified secret name", func(t *testing.T) {
+		// given
+		defaulter := &gardenerClusterDefaulter{secretTemplate: SecretTemplate{Name: "kubeconfig-{{ .Shoot.Name }}", Key: "kubeconfig"}}
+		cluster := fixGardenerCluster()
+		cluster.Spec.Kubeconfig.Secret = Secret{}
+		cluster.Spec.Kubeconfig.SecretTemplate = &SecretTemplate{Name: "{{ .Namespace }}-{{ .Name }}"}
+		cluster.Spec.Kubeconfig.AdditionalSecrets = []Secret{{Name: "copy", Namespace: "other", Key: "config"}}
+
+		// when
+		err := defaulter.Default(context.Background(), cluster)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Secret{Name: "kcp-system-cluster", Namespace: "kcp-system", Key: "kubeconfig"}, cluster.Spec.Kubeconfig.Secret)
+		assert.Equal(t, []Secret{{Name: "copy", Namespace: "other", Key: "config"}}, cluster.Spec.Kubeconfig.AdditionalSecrets)
+	})
+
+	t.Run("should reject template referencing missing label", func(t *testing.T) {
+		// given
+		defaulter := &gardenerClusterDefaulter{secretTemplate: SecretTemplate{Name: "kubeconfig-{{ .Labels.env }}"}}
+		cluster := fixGardenerCluster()
+		cluster.Spec.Kubeconfig.Secret.Name = ""
+
+		// when
+		err := defaulter.Default(context.Background(), cluster)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+type fixedShoots map[string]error
+
+func (shoots fixedShoots) LookupShoot(ctx context.Context, name string) error {
+	err, ok := shoots[name]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Group: "core.gardener.cloud", Resource: "shoots"}, name)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return err
+}
+
+func TestGardenerClusterShootValidation(t *testing.T) {
+	enabled := func() bool { return true }
+	shoots := fixedShoots{"shoot": nil, "slow": context.DeadlineExceeded, "failing": errors.New("connection refused")}
+
+	for _, tc := range []struct {
+		name      string
+		admission ShootAdmission
+		modify    func(cluster *GardenerCluster)
+		invalid   bool
+		warnings  int
+	}{
+		{name: "should accept existing shoot", admission: ShootAdmission{Shoots: shoots, Enabled: enabled}},
+		{name: "should reject missing shoot", admission: ShootAdmission{Shoots: shoots, Enabled: enabled},
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Shoot.Name = "shot" }, invalid: true},
+		{name: "should accept missing shoot with disabled check", admission: ShootAdmission{Shoots: shoots, Enabled: func() bool { return false }},
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Shoot.Name = "shot" }},
+		{name: "should accept missing shoot of other Gardener project", admission: ShootAdmission{Shoots: shoots, Enabled: enabled},
+			modify: func(cluster *GardenerCluster) {
+				cluster.Spec.Shoot.Name = "shot"
+				cluster.Spec.Gardener = &GardenerCredentials{}
+			}},
+		{name: "should accept with warning when lookup times out", admission: ShootAdmission{Shoots: shoots, Enabled: enabled, Timeout: time.Millisecond},
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Shoot.Name = "slow" }, warnings: 1},
+		{name: "should accept with warning when lookup fails", admission: ShootAdmission{Shoots: shoots, Enabled: enabled},
+			modify: func(cluster *GardenerCluster) { cluster.Spec.Shoot.Name = "failing" }, warnings: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			validator := &gardenerClusterValidator{shootAdmission: tc.admission}
+			cluster := fixGardenerCluster()
+			if tc.modify != nil {
+				tc.modify(cluster)
+			}
+
+			// when
+			warnings, err := validator.ValidateCreate(context.Background(), cluster)
+
+			// then
+			if tc.invalid {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+				assert.Contains(t, err.Error(), "spec.shoot.name")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, warnings, tc.warnings)
+		})
+	}
+
+	t.Run("should not check shoot on update", func(t *testing.T) {
+		// given
+		validator := &gardenerClusterValidator{shootAdmission: ShootAdmission{Shoots: fixedShoots{}, Enabled: enabled}}
+		cluster := fixGardenerCluster()
+
+		// when
+		_, err := validator.ValidateUpdate(context.Background(), cluster.DeepCopy(), cluster)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func fixGardenerCluster() *GardenerCluster {
+	return &GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: "kcp-system",
+		},
+		Spec: GardenerClusterSpec{
+			Shoot: Shoot{Name: "shoot"},
+			Kubeconfig: Kubeconfig{
+				Secret: Secret{Name: "kubeconfig", Namespace: "kcp-system", Key: "config"},
+			},
+		},
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultInfrastructureManagerConfigName is the name of the config read by the manager by default.
+const DefaultInfrastructureManagerConfigName = "default"
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="NAMESPACE",type=string,JSONPath=`.spec.gardener.projectNamespace`
+//+kubebuilder:printcolumn:name="ROTATION",type=string,JSONPath=`.spec.rotationPeriod`
+//+kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// InfrastructureManagerConfig is the Schema for the infrastructuremanagerconfigs API.
+// It overrides the startup flags of the manager, the changes are applied without restarting the manager. The manager
+// reads the config with the name set in its flags, the flags apply again when the config is deleted.
+type InfrastructureManagerConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InfrastructureManagerConfigSpec   `json:"spec,omitempty"`
+	Status InfrastructureManagerConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// InfrastructureManagerConfigList contains a list of InfrastructureManagerConfig
+type InfrastructureManagerConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InfrastructureManagerConfig `json:"items"`
+}
+
+// InfrastructureManagerConfigSpec defines the settings overriding the flags, the settings not specified keep the
+// values of the flags.
+type InfrastructureManagerConfigSpec struct {
+	// +optional
+	Gardener *GardenerConfig `json:"gardener,omitempty"`
+
+	// RotationPeriod is the default rotation period of the kubeconfigs, the expiration of the kubeconfigs follows it.
+	// +optional
+	RotationPeriod *metav1.Duration `json:"rotationPeriod,omitempty"`
+
+	// FeatureGates enable, or disable the features of the manager, e.g. KubeconfigValidation.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// GardenerConfig selects the Gardener project of the manager. Changing the project does not move the shoots, and the
+// Runtimes keep creating their shoots in the project of the flags.
+type GardenerConfig struct {
+	// Endpoint overrides the server of the Gardener kubeconfig.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ProjectNamespace is the namespace of the Gardener project, e.g. garden-kyma.
+	// +optional
+	ProjectNamespace string `json:"projectNamespace,omitempty"`
+
+	// CredentialsRef references the secret with the Gardener kubeconfig, replacing the mounted kubeconfig.
+	// +optional
+	CredentialsRef *GardenerConfigCredentialsRef `json:"credentialsRef,omitempty"`
+}
+
+// GardenerConfigCredentialsRef references a kubeconfig in a secret.
+type GardenerConfigCredentialsRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Key defaults to `kubeconfig`.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// KeyOrDefault returns the key of the kubeconfig in the secret.
+func (ref GardenerConfigCredentialsRef) KeyOrDefault() string {
+	if ref.Key == "" {
+		return DefaultGardenerCredentialsKey
+	}
+
+	return ref.Key
+}
+
+// InfrastructureManagerConfigStatus reports whether the config has been applied.
+type InfrastructureManagerConfigStatus struct {
+	// ObservedGeneration is the generation of the last applied config.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// List of status conditions to indicate the status of the config.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionTypeConfigApplied indicates whether the manager applied the config.
+	ConditionTypeConfigApplied ConditionType = "Applied"
+
+	ConditionReasonConfigApplied ConditionReason = "ConfigApplied"
+	ConditionReasonInvalidConfig ConditionReason = "InvalidConfig"
+)
+
+func init() {
+	SchemeBuilder.Register(&InfrastructureManagerConfig{}, &InfrastructureManagerConfigList{})
+}
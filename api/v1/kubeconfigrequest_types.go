@@ -0,0 +1,133 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="STATE",type=string,JSONPath=`.status.state`
+//+kubebuilder:printcolumn:name="SHOOT",type=string,JSONPath=`.spec.shoot.name`
+//+kubebuilder:printcolumn:name="SECRET",type=string,JSONPath=`.status.secretName`
+//+kubebuilder:printcolumn:name="EXPIRES",type=string,JSONPath=`.status.expirationTime`
+//+kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// KubeconfigRequest is the Schema for the kubeconfigrequests API.
+// It requests a short-lived admin kubeconfig for a shoot, the kubeconfig secret is deleted when it expires.
+type KubeconfigRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KubeconfigRequestSpec   `json:"spec"`
+	Status KubeconfigRequestStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KubeconfigRequestList contains a list of KubeconfigRequest
+type KubeconfigRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubeconfigRequest `json:"items"`
+}
+
+// KubeconfigRequestSpec defines the desired state of KubeconfigRequest.
+// The spec is not reconciled anymore once the kubeconfig has been issued.
+type KubeconfigRequestSpec struct {
+	Shoot Shoot `json:"shoot"`
+
+	// TTL is the validity period of the kubeconfig, it must not exceed the kubeconfig expiration time of the controller.
+	TTL metav1.Duration `json:"ttl"`
+
+	// SecretName is the name of the secret in the namespace of the request the kubeconfig is written to.
+	// Defaults to `<request name>-kubeconfig`.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+const (
+	// ExpiredState signifies that the kubeconfig issued for a KubeconfigRequest has expired, and its secret was deleted.
+	ExpiredState State = "Expired"
+)
+
+const (
+	ConditionReasonKubeconfigIssued   ConditionReason = "KubeconfigIssued"
+	ConditionReasonKubeconfigExpired  ConditionReason = "KubeconfigExpired"
+	ConditionReasonInvalidTTL         ConditionReason = "InvalidTTL"
+	ConditionReasonShootNotReferenced ConditionReason = "ShootNotReferenced"
+)
+
+const (
+	// ConditionTypeKubeconfigIssued indicates whether the requested kubeconfig has been written to the secret.
+	ConditionTypeKubeconfigIssued ConditionType = "KubeconfigIssued"
+)
+
+// KubeconfigRequestStatus defines the observed state of KubeconfigRequest
+type KubeconfigRequestStatus struct {
+	// State signifies current state of the request.
+	// Value can be one of ("Processing", "Ready", "Error", "Expired").
+	State State `json:"state,omitempty"`
+
+	// SecretName is the name of the secret holding the kubeconfig.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// ExpirationTime is the time when the kubeconfig becomes invalid, and the secret is deleted.
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// List of status conditions to indicate the status of the request.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// SecretName returns the name of the secret the kubeconfig is written to.
+func (request *KubeconfigRequest) SecretName() string {
+	if request.Spec.SecretName != "" {
+		return request.Spec.SecretName
+	}
+
+	return fmt.Sprintf("%s-kubeconfig", request.Name)
+}
+
+// UpdateCondition sets the state of the request together with the KubeconfigIssued condition.
+func (request *KubeconfigRequest) UpdateCondition(state State, conditionStatus metav1.ConditionStatus, reason ConditionReason, err error) {
+	request.Status.State = state
+
+	message := getMessage(reason)
+	if err != nil {
+		message = fmt.Sprintf("%s Error: %s", message, err.Error())
+	}
+
+	meta.SetStatusCondition(&request.Status.Conditions, metav1.Condition{
+		Type:    string(ConditionTypeKubeconfigIssued),
+		Status:  conditionStatus,
+		Reason:  string(reason),
+		Message: message,
+	})
+}
+
+func init() {
+	SchemeBuilder.Register(&KubeconfigRequest{}, &KubeconfigRequestList{})
+}
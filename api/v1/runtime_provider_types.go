@@ -0,0 +1,135 @@
+package v1
+
+const (
+	ProviderTypeAWS       = "aws"
+	ProviderTypeGCP       = "gcp"
+	ProviderTypeAzure     = "azure"
+	ProviderTypeOpenStack = "openstack"
+)
+
+// AWSProviderConfig defines the VPC of the shoot, and the subnets in its zones.
+type AWSProviderConfig struct {
+	VPC AWSVPC `json:"vpc"`
+
+	// Zones define the subnets of the availability zones, the worker pools can only use these zones.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Zones []AWSZone `json:"zones"`
+
+	// InstanceMetadataOptions restrict the access to the instance metadata service of the nodes.
+	// +optional
+	InstanceMetadataOptions *AWSInstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+}
+
+// AWSVPC either reuses an existing VPC, or defines the CIDR of a VPC created for the shoot.
+type AWSVPC struct {
+	// ID of an existing VPC.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// CIDR of the VPC created for the shoot.
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// AWSZone defines the subnets of an availability zone.
+type AWSZone struct {
+	Name string `json:"name"`
+
+	// Workers is the CIDR of the subnet of the nodes.
+	Workers string `json:"workers"`
+
+	// Public is the CIDR of the subnet of the public load balancers.
+	Public string `json:"public"`
+
+	// Internal is the CIDR of the subnet of the internal load balancers.
+	Internal string `json:"internal"`
+}
+
+// AWSInstanceMetadataOptions configure the instance metadata service of the nodes.
+type AWSInstanceMetadataOptions struct {
+	// HTTPTokens enforces IMDSv2 when required.
+	// +kubebuilder:validation:Enum=required;optional
+	// +optional
+	HTTPTokens string `json:"httpTokens,omitempty"`
+
+	// HTTPPutResponseHopLimit limits the network hops of the metadata responses, 1 blocks the access from pods.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	HTTPPutResponseHopLimit *int64 `json:"httpPutResponseHopLimit,omitempty"`
+}
+
+// GCPProviderConfig defines the VPC, and the subnets of the shoot.
+type GCPProviderConfig struct {
+	// VPC reuses an existing VPC, a VPC is created for the shoot when not set.
+	// +optional
+	VPC *GCPVPC `json:"vpc,omitempty"`
+
+	// Workers is the CIDR of the subnet of the nodes.
+	Workers string `json:"workers"`
+
+	// Internal is the CIDR of the subnet of the internal load balancers.
+	// +optional
+	Internal string `json:"internal,omitempty"`
+
+	// Zone of the control plane components, e.g. the persistent volumes of etcd.
+	Zone string `json:"zone"`
+}
+
+// GCPVPC references an existing VPC.
+type GCPVPC struct {
+	Name string `json:"name"`
+
+	// CloudRouterName references the cloud router of the VPC used for the NAT of the nodes.
+	// +optional
+	CloudRouterName string `json:"cloudRouterName,omitempty"`
+}
+
+// AzureProviderConfig defines the VNet, and the subnet of the shoot.
+type AzureProviderConfig struct {
+	VNet AzureVNet `json:"vnet"`
+
+	// Workers is the CIDR of the subnet of the nodes.
+	Workers string `json:"workers"`
+
+	// Zoned spreads the nodes across the availability zones of the region.
+	// +optional
+	Zoned bool `json:"zoned,omitempty"`
+}
+
+// AzureVNet either reuses an existing VNet, or defines the CIDR of a VNet created for the shoot.
+type AzureVNet struct {
+	// Name of an existing VNet.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ResourceGroup of the existing VNet.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+
+	// CIDR of the VNet created for the shoot.
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// OpenStackProviderConfig defines the networks, and the load balancers of the shoot.
+type OpenStackProviderConfig struct {
+	// FloatingPoolName is the network the floating IPs of the shoot are allocated from.
+	FloatingPoolName string `json:"floatingPoolName"`
+
+	// RouterID reuses an existing router, a router is created for the shoot when not set.
+	// +optional
+	RouterID string `json:"routerID,omitempty"`
+
+	// NetworkID reuses an existing network, a network is created for the shoot when not set.
+	// +optional
+	NetworkID string `json:"networkID,omitempty"`
+
+	// Workers is the CIDR of the subnet of the nodes.
+	Workers string `json:"workers"`
+
+	// LoadBalancerProvider of the Octavia load balancers, e.g. amphora, or f5.
+	LoadBalancerProvider string `json:"loadBalancerProvider"`
+}
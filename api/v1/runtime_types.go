@@ -0,0 +1,701 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="STATE",type=string,JSONPath=`.status.state`
+//+kubebuilder:printcolumn:name="SHOOT",type=string,JSONPath=`.spec.shoot.name`
+//+kubebuilder:printcolumn:name="PROVIDER",type=string,JSONPath=`.spec.shoot.provider.type`
+//+kubebuilder:printcolumn:name="REGION",type=string,JSONPath=`.spec.shoot.region`
+//+kubebuilder:printcolumn:name="HIBERNATED",type=boolean,JSONPath=`.status.hibernated`
+//+kubebuilder:printcolumn:name="HA",type=string,JSONPath=`.status.controlPlaneFailureTolerance`,priority=1
+//+kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Runtime is the Schema for the runtimes API.
+// It declares a Gardener shoot cluster the controller creates, updates, and deletes.
+type Runtime struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RuntimeSpec   `json:"spec"`
+	Status RuntimeStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RuntimeList contains a list of Runtime
+type RuntimeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Runtime `json:"items"`
+}
+
+// RuntimeSpec defines the desired state of Runtime
+type RuntimeSpec struct {
+	Shoot RuntimeShoot `json:"shoot"`
+
+	// DeletionProtection keeps the shoot, and the Runtime until the deletion is confirmed with the
+	// `operator.kyma-project.io/confirm-deletion: "true"` annotation. It defaults to true for production shoots.
+	// +optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+
+	// Maintenance defines when Gardener maintains the shoot, and which updates it applies automatically.
+	// The defaults of Gardener are kept for the settings not specified.
+	// +optional
+	Maintenance *RuntimeMaintenance `json:"maintenance,omitempty"`
+
+	// Hibernation scales the shoot down to zero nodes, and stops its control plane, on demand, or on schedules.
+	// +optional
+	Hibernation *RuntimeHibernation `json:"hibernation,omitempty"`
+}
+
+// RuntimeMaintenance defines the maintenance of the shoot, the time windows can be staggered across the fleet.
+type RuntimeMaintenance struct {
+	// +optional
+	TimeWindow *RuntimeMaintenanceTimeWindow `json:"timeWindow,omitempty"`
+
+	// +optional
+	AutoUpdate *RuntimeMaintenanceAutoUpdate `json:"autoUpdate,omitempty"`
+}
+
+// RuntimeMaintenanceTimeWindow defines the daily time window of the maintenance in the `HHMMSS+ZZZZ` format,
+// e.g. `220000+0100`.
+type RuntimeMaintenanceTimeWindow struct {
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3])[0-5][0-9][0-5][0-9][+-](0[0-9]|1[0-4])[0-5][0-9]$`
+	Begin string `json:"begin"`
+
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3])[0-5][0-9][0-5][0-9][+-](0[0-9]|1[0-4])[0-5][0-9]$`
+	End string `json:"end"`
+}
+
+// RuntimeMaintenanceAutoUpdate defines the updates Gardener applies in the maintenance time window.
+type RuntimeMaintenanceAutoUpdate struct {
+	// KubernetesVersion updates the shoot to the latest patch version of its minor version,
+	// the version in the spec is then the lowest version of the shoot.
+	KubernetesVersion bool `json:"kubernetesVersion"`
+
+	// MachineImageVersion updates the machine images of the nodes to the latest version.
+	MachineImageVersion bool `json:"machineImageVersion"`
+}
+
+// RuntimeHibernation defines when the shoot is hibernated.
+type RuntimeHibernation struct {
+	// Enabled hibernates the shoot when true, and wakes it up when false, overriding the schedules while it is set.
+	// When unset, the shoot is hibernated, and woken up by the schedules.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Schedules hibernate, and wake up the shoot periodically, e.g. nightly for development clusters.
+	// +optional
+	Schedules []HibernationSchedule `json:"schedules,omitempty"`
+}
+
+// HibernationSchedule defines a period of hibernation with cron expressions, at least one of start, and end is required.
+type HibernationSchedule struct {
+	// Start is the cron expression of the time the shoot is hibernated, e.g. `00 20 * * 1-5`.
+	// +optional
+	Start string `json:"start,omitempty"`
+
+	// End is the cron expression of the time the shoot is woken up, e.g. `00 07 * * 1-5`.
+	// +optional
+	End string `json:"end,omitempty"`
+
+	// Location is the time zone the cron expressions are evaluated in, e.g. `Europe/Berlin`, UTC by default.
+	// +optional
+	Location string `json:"location,omitempty"`
+}
+
+// RuntimeShoot defines the shoot provisioned in the Gardener project of the controller.
+type RuntimeShoot struct {
+	// Name of the shoot, Gardener limits the length of the project, and the shoot name together.
+	// +kubebuilder:validation:MaxLength=21
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Purpose of the shoot, e.g. evaluation, or production, it changes the defaults Gardener applies, and the deletion
+	// protection of the Runtime.
+	// +optional
+	Purpose Purpose `json:"purpose,omitempty"`
+
+	// Region the shoot is created in, it can't be changed afterwards.
+	Region string `json:"region"`
+
+	// CloudProfileName references the Gardener cloud profile of the provider, e.g. an alternative profile of a dedicated
+	// landscape. It can't be changed afterwards.
+	CloudProfileName string `json:"cloudProfileName"`
+
+	// SeedName pins the control plane of the shoot to a dedicated seed, Gardener schedules the shoot to a seed matching
+	// the selectors when not set. It can't be changed afterwards.
+	// +optional
+	SeedName string `json:"seedName,omitempty"`
+
+	// SeedSelector restricts the seeds the control plane of the shoot is scheduled to, it can't be changed afterwards.
+	// +optional
+	SeedSelector *RuntimeSeedSelector `json:"seedSelector,omitempty"`
+
+	// SecretBindingName references the infrastructure credentials in the Gardener project.
+	SecretBindingName string `json:"secretBindingName"`
+
+	Provider RuntimeProvider `json:"provider"`
+
+	// DNS configures a custom domain of the shoot, the default domain of the Gardener landscape is used when not set.
+	// +optional
+	DNS *RuntimeDNS `json:"dns,omitempty"`
+
+	// Networking of the shoot, it is applied on creation, and can't be changed afterwards.
+	// +optional
+	Networking *RuntimeNetworking `json:"networking,omitempty"`
+
+	Kubernetes RuntimeKubernetes `json:"kubernetes"`
+
+	// ControlPlane configures the high availability of the control plane, it can be enabled, but not changed, or
+	// disabled afterwards.
+	// +optional
+	ControlPlane *RuntimeControlPlane `json:"controlPlane,omitempty"`
+
+	// RegistryMirrors are pulled from instead of the upstream registries, by the nodes of all the worker pools. Gardener
+	// configures the mirrors with the shoot-wide registry-mirror extension, they can't differ between the pools.
+	// +listType=map
+	// +listMapKey=upstream
+	// +optional
+	RegistryMirrors []RuntimeRegistryMirror `json:"registryMirrors,omitempty"`
+
+	// Extensions are the Gardener extensions enabled, or disabled for the shoot, e.g. oidc-webhook-authenticator. The
+	// extensions managed by the other fields, e.g. the DNS extension of the custom domain, can't be listed. Extensions
+	// removed from the list are removed from the shoot.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Extensions []RuntimeExtension `json:"extensions,omitempty"`
+
+	// AuditLog configures the auditing of the requests to the kube-apiserver of the shoot.
+	// +optional
+	AuditLog *RuntimeAuditLog `json:"auditLog,omitempty"`
+
+	// Workers define the worker pools of the shoot, pools are added, resized, and removed by changing the list.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Workers []RuntimeWorker `json:"workers"`
+}
+
+// RuntimeSeedSelector selects the seeds by their labels, and provider types.
+type RuntimeSeedSelector struct {
+	metav1.LabelSelector `json:",inline"`
+
+	// ProviderTypes are the provider types of the selected seeds, `*` selects seeds of any provider type. Only seeds of
+	// the provider type of the shoot are selected when not set.
+	// +optional
+	ProviderTypes []string `json:"providerTypes,omitempty"`
+}
+
+// RuntimeRegistryMirror defines the mirrors of an upstream registry.
+type RuntimeRegistryMirror struct {
+	// Upstream is the host of the mirrored registry, e.g. `docker.io`.
+	Upstream string `json:"upstream"`
+
+	// Hosts are the URLs of the mirrors, they are tried in order before the upstream registry.
+	// +kubebuilder:validation:MinItems=1
+	Hosts []string `json:"hosts"`
+}
+
+// RuntimeExtension defines a Gardener extension of the shoot.
+type RuntimeExtension struct {
+	// Type of the extension, e.g. shoot-networking-filter.
+	Type string `json:"type"`
+
+	// Disabled disables an extension Gardener enables globally, e.g. by default for all shoots.
+	// +optional
+	Disabled *bool `json:"disabled,omitempty"`
+
+	// ProviderConfig is passed to the extension as is.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	ProviderConfig *runtime.RawExtension `json:"providerConfig,omitempty"`
+}
+
+// RuntimeControlPlane defines the high availability of the control plane of the shoot.
+type RuntimeControlPlane struct {
+	// FailureTolerance is the failure the control plane survives, `node` spreads the replicas of the control plane
+	// across the nodes of a zone, `zone` across the zones of a region with at least three zones.
+	FailureTolerance FailureToleranceType `json:"failureTolerance"`
+}
+
+// FailureToleranceType is the failure a highly available control plane survives.
+// +kubebuilder:validation:Enum=node;zone
+type FailureToleranceType string
+
+const (
+	FailureToleranceNode FailureToleranceType = "node"
+	FailureToleranceZone FailureToleranceType = "zone"
+)
+
+// RuntimeAuditLog defines which requests are audited, and where the audit events are shipped to.
+type RuntimeAuditLog struct {
+	// PolicyConfigMapName references the ConfigMap with the audit policy in the Gardener project of the controller,
+	// the policy is stored under the `policy` key.
+	// +optional
+	PolicyConfigMapName string `json:"policyConfigMapName,omitempty"`
+
+	// Backend ships the audit events of the shoot.
+	// +optional
+	Backend *RuntimeAuditLogBackend `json:"backend,omitempty"`
+}
+
+// RuntimeAuditLogBackend defines the Gardener extension shipping the audit events.
+type RuntimeAuditLogBackend struct {
+	// Type of the extension, e.g. shoot-auditlog-service.
+	Type string `json:"type"`
+
+	// ProviderConfig is passed to the extension as is, e.g. the tenant, and the URL of the audit log service.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	ProviderConfig *runtime.RawExtension `json:"providerConfig,omitempty"`
+}
+
+// RuntimeProvider defines the infrastructure provider of the shoot.
+type RuntimeProvider struct {
+	// Type of the provider, e.g. aws, gcp, azure, or openstack.
+	Type string `json:"type"`
+
+	// AWS configures the infrastructure of the aws provider type.
+	// +optional
+	AWS *AWSProviderConfig `json:"aws,omitempty"`
+
+	// GCP configures the infrastructure of the gcp provider type.
+	// +optional
+	GCP *GCPProviderConfig `json:"gcp,omitempty"`
+
+	// Azure configures the infrastructure of the azure provider type.
+	// +optional
+	Azure *AzureProviderConfig `json:"azure,omitempty"`
+
+	// OpenStack configures the infrastructure of the openstack provider type.
+	// +optional
+	OpenStack *OpenStackProviderConfig `json:"openstack,omitempty"`
+
+	// InfrastructureConfig is passed to the Gardener provider extension as is, for providers without a typed
+	// configuration. It can't be combined with the typed configuration.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	InfrastructureConfig *runtime.RawExtension `json:"infrastructureConfig,omitempty"`
+
+	// ControlPlaneConfig is passed to the Gardener provider extension as is, for providers without a typed
+	// configuration. It can't be combined with the typed configuration.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	ControlPlaneConfig *runtime.RawExtension `json:"controlPlaneConfig,omitempty"`
+}
+
+// RuntimeDNS defines the domain of the shoot, and the DNS provider managing its records.
+type RuntimeDNS struct {
+	// Domain of the shoot, e.g. `cluster.example.com`, it can't be changed afterwards.
+	// +kubebuilder:validation:MaxLength=253
+	Domain string `json:"domain"`
+
+	Provider RuntimeDNSProvider `json:"provider"`
+}
+
+// RuntimeDNSProvider defines the DNS service hosting the zone of the domain.
+type RuntimeDNSProvider struct {
+	// Type of the DNS provider, e.g. aws-route53, azure-dns, google-clouddns, or openstack-designate.
+	Type string `json:"type"`
+
+	// SecretName references the credentials of the DNS provider in the Gardener project of the controller.
+	SecretName string `json:"secretName"`
+}
+
+// RuntimeNetworking defines the CNI, and the IP ranges of the shoot, the ranges must not overlap.
+type RuntimeNetworking struct {
+	// Type of the CNI, calico by default.
+	// +kubebuilder:validation:Enum=calico;cilium
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Nodes is the CIDR of the node network, it has to be part of the network of the infrastructure.
+	// +optional
+	Nodes string `json:"nodes,omitempty"`
+
+	// Pods is the CIDR of the pod network.
+	// +optional
+	Pods string `json:"pods,omitempty"`
+
+	// Services is the CIDR of the service network.
+	// +optional
+	Services string `json:"services,omitempty"`
+}
+
+// RuntimeKubernetes defines the Kubernetes version of the shoot, it can be upgraded, but not downgraded.
+// Versions upgraded by the maintenance of Gardener are kept.
+type RuntimeKubernetes struct {
+	Version string `json:"version"`
+
+	// OIDC configures the kube-apiserver to authenticate the users with tokens of the OIDC provider.
+	// +optional
+	OIDC *RuntimeOIDC `json:"oidc,omitempty"`
+
+	// ClusterAutoscaler tunes the scaling of the worker pools, e.g. for bursty workloads.
+	// +optional
+	ClusterAutoscaler *RuntimeClusterAutoscaler `json:"clusterAutoscaler,omitempty"`
+}
+
+// RuntimeClusterAutoscaler tunes the cluster autoscaler of the shoot, the settings not specified keep the defaults of
+// Gardener. Gardener applies the settings to all worker pools, it doesn't support settings per pool.
+type RuntimeClusterAutoscaler struct {
+	// ScaleDownDelayAfterAdd is the time after a scale up before a scale down is evaluated, e.g. `1h`.
+	// +optional
+	ScaleDownDelayAfterAdd *metav1.Duration `json:"scaleDownDelayAfterAdd,omitempty"`
+
+	// ScaleDownUnneededTime is the time a node has to be unneeded before it is removed.
+	// +optional
+	ScaleDownUnneededTime *metav1.Duration `json:"scaleDownUnneededTime,omitempty"`
+
+	// ScaleDownUtilizationThreshold is the ratio of the requested resources to the capacity of a node, below which the
+	// node can be removed, e.g. `0.5`.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	ScaleDownUtilizationThreshold string `json:"scaleDownUtilizationThreshold,omitempty"`
+
+	// MaxNodeProvisionTime is the time the autoscaler waits for a new node to join, before it scales up another pool.
+	// +optional
+	MaxNodeProvisionTime *metav1.Duration `json:"maxNodeProvisionTime,omitempty"`
+}
+
+// RuntimeOIDC defines the OIDC provider the kube-apiserver of the shoot trusts.
+type RuntimeOIDC struct {
+	// IssuerURL of the OIDC provider, it has to use the https scheme.
+	IssuerURL string `json:"issuerURL"`
+
+	// ClientID the ID tokens are issued for.
+	ClientID string `json:"clientID"`
+
+	// UsernameClaim is the claim of the user name, `sub` by default.
+	// +optional
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+
+	// UsernamePrefix is prepended to the user names, use `-` to disable the prefix.
+	// +optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// GroupsClaim is the claim of the user groups.
+	// +optional
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// GroupsPrefix is prepended to the group names.
+	// +optional
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+
+	// SigningAlgs accepted for the ID tokens, RS256 by default.
+	// +optional
+	SigningAlgs []string `json:"signingAlgs,omitempty"`
+
+	// RequiredClaims the ID tokens must contain with the given values.
+	// +optional
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty"`
+}
+
+// RuntimeWorker defines a worker pool of the shoot.
+type RuntimeWorker struct {
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// MachineType is one of the machine types of the cloud profile.
+	MachineType string `json:"machineType"`
+
+	// +kubebuilder:validation:Minimum=0
+	Minimum int32 `json:"minimum"`
+
+	// +kubebuilder:validation:Minimum=0
+	Maximum int32 `json:"maximum"`
+
+	// Zones of the region the nodes are spread across, zones can be added to an existing pool, but not removed.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// Volume is the root disk of the nodes, the default of the machine type is used when not set.
+	// +optional
+	Volume *RuntimeWorkerVolume `json:"volume,omitempty"`
+
+	// Labels are added to the nodes of the pool.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are added to the nodes of the pool.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// Image is the operating system image of the nodes, the default image of the cloud profile is used when not set.
+	// +optional
+	Image *RuntimeWorkerImage `json:"image,omitempty"`
+
+	// CRI configures the container runtime of the nodes, containerd is used when not set.
+	// +optional
+	CRI *RuntimeWorkerCRI `json:"cri,omitempty"`
+
+	// Kubelet configures the kubelets of the pool, the settings not specified keep the values defaulted by Gardener.
+	// +optional
+	Kubelet *RuntimeKubelet `json:"kubelet,omitempty"`
+}
+
+// RuntimeWorkerImage defines the operating system image of the nodes.
+type RuntimeWorkerImage struct {
+	// Name of the image in the cloud profile, e.g. gardenlinux.
+	Name string `json:"name"`
+
+	// Version of the image, Gardener selects the latest version of the cloud profile when not set.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// AutoUpdate updates the image to its latest version in the maintenance time window, the version is then the lowest
+	// version of the pool. Without it the version is pinned. Gardener updates the images of all the pools together, the
+	// pools setting it must agree. The auto update of the maintenance applies when not set.
+	// +optional
+	AutoUpdate *bool `json:"autoUpdate,omitempty"`
+}
+
+// RuntimeWorkerCRI defines the container runtime of the nodes.
+type RuntimeWorkerCRI struct {
+	// Name of the container runtime interface.
+	// +kubebuilder:validation:Enum=containerd
+	// +kubebuilder:default=containerd
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ContainerRuntimes are the additional runtimes installed on the nodes, e.g. gvisor, the pods select them with
+	// their runtime class.
+	// +listType=set
+	// +optional
+	ContainerRuntimes []string `json:"containerRuntimes,omitempty"`
+}
+
+// RuntimeKubelet defines the kubelet settings of a worker pool.
+type RuntimeKubelet struct {
+	// MaxPods is the maximal number of pods on a node.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPods *int32 `json:"maxPods,omitempty"`
+
+	// EvictionHard are the thresholds evicting the pods immediately.
+	// +optional
+	EvictionHard *RuntimeKubeletEviction `json:"evictionHard,omitempty"`
+
+	// EvictionSoft are the thresholds evicting the pods once exceeded for the grace period.
+	// +optional
+	EvictionSoft *RuntimeKubeletEviction `json:"evictionSoft,omitempty"`
+
+	// EvictionSoftGracePeriod is the time a soft threshold has to be exceeded, it applies to all the soft thresholds,
+	// and is required with them.
+	// +optional
+	EvictionSoftGracePeriod *metav1.Duration `json:"evictionSoftGracePeriod,omitempty"`
+}
+
+// RuntimeKubeletEviction defines the eviction thresholds of the kubelet, as a quantity, e.g. `100Mi`, or a percentage,
+// e.g. `10%`.
+type RuntimeKubeletEviction struct {
+	// +optional
+	MemoryAvailable string `json:"memoryAvailable,omitempty"`
+
+	// +optional
+	ImageFSAvailable string `json:"imageFSAvailable,omitempty"`
+
+	// +optional
+	ImageFSInodesFree string `json:"imageFSInodesFree,omitempty"`
+
+	// +optional
+	NodeFSAvailable string `json:"nodeFSAvailable,omitempty"`
+
+	// +optional
+	NodeFSInodesFree string `json:"nodeFSInodesFree,omitempty"`
+}
+
+// RuntimeWorkerVolume defines the root disk of the nodes.
+type RuntimeWorkerVolume struct {
+	// Type of the volume, one of the volume types of the cloud profile, e.g. gp3.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Size of the volume, e.g. 50Gi.
+	Size string `json:"size"`
+}
+
+// RuntimeWorkerStatus reports a worker pool applied to the shoot.
+type RuntimeWorkerStatus struct {
+	Name string `json:"name"`
+
+	// State of the pool, Processing until Gardener has reconciled its last change.
+	State State `json:"state"`
+
+	MachineType string `json:"machineType"`
+	Minimum     int32  `json:"minimum"`
+	Maximum     int32  `json:"maximum"`
+
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// MachineImage is the name of the image running on the nodes of the pool.
+	// +optional
+	MachineImage string `json:"machineImage,omitempty"`
+
+	// MachineImageVersion is the version of the image running on the nodes of the pool.
+	// +optional
+	MachineImageVersion string `json:"machineImageVersion,omitempty"`
+}
+
+// The Gardener extensions managed by the fields of the Runtime, they can't be listed in its extensions.
+const (
+	// DNSExtensionType is the extension managing the DNS records of the custom domain.
+	DNSExtensionType = "shoot-dns-service"
+
+	// RegistryMirrorExtensionType is the extension configuring the registry mirrors in containerd on the nodes.
+	RegistryMirrorExtensionType = "registry-mirror"
+)
+
+// DeletionConfirmationAnnotation confirms the deletion of a Runtime with deletion protection.
+const DeletionConfirmationAnnotation = "operator.kyma-project.io/confirm-deletion"
+
+const (
+	ConditionTypeShootProvisioned ConditionType = "ShootProvisioned"
+	// ConditionTypeHibernated indicates whether the shoot is hibernated, it is unknown while the shoot is being
+	// hibernated, or woken up.
+	ConditionTypeHibernated ConditionType = "Hibernated"
+)
+
+const (
+	ConditionReasonShootCreating        ConditionReason = "ShootCreating"
+	ConditionReasonShootUpdating        ConditionReason = "ShootUpdating"
+	ConditionReasonShootReady           ConditionReason = "ShootReady"
+	ConditionReasonShootOperationFailed ConditionReason = "ShootOperationFailed"
+	ConditionReasonShootDeleting        ConditionReason = "ShootDeleting"
+	ConditionReasonFailedToCreateShoot  ConditionReason = "FailedToCreateShoot"
+	ConditionReasonFailedToUpdateShoot  ConditionReason = "FailedToUpdateShoot"
+	ConditionReasonFailedToDeleteShoot  ConditionReason = "FailedToDeleteShoot"
+	ConditionReasonFailedToGetShoot     ConditionReason = "FailedToGetShoot"
+	ConditionReasonShootHibernated      ConditionReason = "ShootHibernated"
+	ConditionReasonShootHibernating     ConditionReason = "ShootHibernating"
+	ConditionReasonShootWakingUp        ConditionReason = "ShootWakingUp"
+	ConditionReasonShootAwake           ConditionReason = "ShootAwake"
+	ConditionReasonDeletionNotConfirmed ConditionReason = "DeletionNotConfirmed"
+)
+
+// RuntimeStatus defines the observed state of Runtime
+type RuntimeStatus struct {
+	// State signifies current state of the Runtime.
+	// Value can be one of ("Processing", "Ready", "Error", "Deleting").
+	State State `json:"state,omitempty"`
+
+	// ObservedGeneration is the generation of the spec last applied to the shoot.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastOperation is the last operation Gardener performed on the shoot, e.g. `Reconcile Succeeded (100%)`.
+	// +optional
+	LastOperation string `json:"lastOperation,omitempty"`
+
+	// Hibernated indicates whether the shoot is currently hibernated.
+	// +optional
+	Hibernated bool `json:"hibernated,omitempty"`
+
+	// ControlPlaneFailureTolerance is the failure tolerance the control plane of the shoot has been rolled out with,
+	// empty for a control plane which is not highly available.
+	// +optional
+	ControlPlaneFailureTolerance FailureToleranceType `json:"controlPlaneFailureTolerance,omitempty"`
+
+	// Workers reports the worker pools of the shoot.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Workers []RuntimeWorkerStatus `json:"workers,omitempty"`
+
+	// List of status conditions to indicate the status of the Runtime.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// UpdateCondition sets the state of the Runtime together with the ShootProvisioned condition.
+func (rt *Runtime) UpdateCondition(state State, conditionStatus metav1.ConditionStatus, reason ConditionReason, message string, err error) {
+	rt.Status.State = state
+
+	if message == "" {
+		message = getMessage(reason)
+	}
+
+	if err != nil {
+		message = fmt.Sprintf("%s Error: %s", message, err.Error())
+	}
+
+	meta.SetStatusCondition(&rt.Status.Conditions, metav1.Condition{
+		Type:    string(ConditionTypeShootProvisioned),
+		Status:  conditionStatus,
+		Reason:  string(reason),
+		Message: message,
+	})
+}
+
+// DeletionProtected reports whether the deletion has to be confirmed, production shoots are protected unless the spec
+// disables the protection.
+func (rt *Runtime) DeletionProtected() bool {
+	if rt.Spec.DeletionProtection != nil {
+		return *rt.Spec.DeletionProtection
+	}
+
+	return rt.Spec.Shoot.Purpose == PurposeProduction
+}
+
+// DeletionAllowed reports whether the shoot may be deleted, the deletion of protected Runtimes has to be confirmed.
+func (rt *Runtime) DeletionAllowed() bool {
+	return !rt.DeletionProtected() || rt.Annotations[DeletionConfirmationAnnotation] == "true"
+}
+
+// SetHibernationCondition reports the hibernation of the shoot, it is in progress while the desired state differs from the current one.
+func (rt *Runtime) SetHibernationCondition(hibernationEnabled, hibernated bool) {
+	rt.Status.Hibernated = hibernated
+
+	status, reason := metav1.ConditionFalse, ConditionReasonShootAwake
+
+	switch {
+	case hibernationEnabled && hibernated:
+		status, reason = metav1.ConditionTrue, ConditionReasonShootHibernated
+	case hibernationEnabled:
+		status, reason = metav1.ConditionUnknown, ConditionReasonShootHibernating
+	case hibernated:
+		status, reason = metav1.ConditionUnknown, ConditionReasonShootWakingUp
+	}
+
+	meta.SetStatusCondition(&rt.Status.Conditions, metav1.Condition{
+		Type:    string(ConditionTypeHibernated),
+		Status:  status,
+		Reason:  string(reason),
+		Message: getMessage(reason),
+	})
+}
+
+func init() {
+	SchemeBuilder.Register(&Runtime{}, &RuntimeList{})
+}
@@ -0,0 +1,931 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// minZonesForZoneFailureTolerance is the number of zones Gardener spreads the replicas of a zone tolerant control
+// plane across.
+const minZonesForZoneFailureTolerance = 3
+
+// RegionZones lists the zones of the regions defined in the Gardener cloud profiles.
+// +kubebuilder:object:generate=false
+type RegionZones interface {
+	RegionZones(ctx context.Context, cloudProfileName, region string) ([]string, error)
+}
+
+// SchedulingTargets describes the cloud profiles, and the seeds of the Gardener landscape the shoots are scheduled to.
+// The lookups fail with a NotFound error for objects which don't exist.
+// +kubebuilder:object:generate=false
+type SchedulingTargets interface {
+	CloudProfile(ctx context.Context, name string) (CloudProfileInfo, error)
+	Seed(ctx context.Context, name string) (SeedInfo, error)
+}
+
+// CloudProfileInfo is the part of a Gardener cloud profile the Runtimes are validated against.
+// +kubebuilder:object:generate=false
+type CloudProfileInfo struct {
+	ProviderType string
+	Regions      []string
+	SeedSelector *RuntimeSeedSelector
+}
+
+// SeedInfo is the part of a Gardener seed the Runtimes are validated against.
+// +kubebuilder:object:generate=false
+type SeedInfo struct {
+	ProviderType string
+	Region       string
+	Labels       map[string]string
+}
+
+// Landscape is the Gardener landscape the Runtimes are validated against.
+// +kubebuilder:object:generate=false
+type Landscape interface {
+	RegionZones
+	SchedulingTargets
+}
+
+// SetupWebhookWithManager registers the Runtime webhooks. The cloud profiles, seeds, and the regions of zone tolerant
+// control planes are checked against the landscape when it is given.
+func (rt *Runtime) SetupWebhookWithManager(mgr ctrl.Manager, landscape Landscape) error {
+	validator := &runtimeValidator{}
+	if landscape != nil {
+		validator.zones = landscape
+		validator.targets = landscape
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(rt).
+		WithValidator(validator).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-infrastructuremanager-kyma-project-io-v1-runtime,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructuremanager.kyma-project.io,resources=runtimes,verbs=create;update;delete,versions=v1,name=vruntime.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Runtime{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (rt *Runtime) ValidateCreate() (admission.Warnings, error) {
+	return nil, rt.toInvalidError(rt.validateSpec())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (rt *Runtime) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	oldRuntime, ok := old.(*Runtime)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", old)
+	}
+
+	// the finalizer has to be removable while the shoot is deleted
+	if !rt.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	allErrs := rt.validateSpec()
+	allErrs = append(allErrs, rt.validateImmutableFields(oldRuntime)...)
+	allErrs = append(allErrs, rt.validateVersionUpgrade(oldRuntime)...)
+	allErrs = append(allErrs, rt.validateWorkerUpdates(oldRuntime)...)
+
+	return rt.workerUpdateWarnings(oldRuntime), rt.toInvalidError(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (rt *Runtime) ValidateDelete() (admission.Warnings, error) {
+	if rt.DeletionAllowed() {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewForbidden(GroupVersion.WithResource("runtimes").GroupResource(), rt.Name,
+		errors.Errorf("deletion protection is enabled, confirm the deletion with the %s: \"true\" annotation", DeletionConfirmationAnnotation))
+}
+
+func (rt *Runtime) validateSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	workersPath := specPath.Child("shoot", "workers")
+	names := sets.New[string]()
+
+	for i, worker := range rt.Spec.Shoot.Workers {
+		if names.Has(worker.Name) {
+			allErrs = append(allErrs, field.Duplicate(workersPath.Index(i).Child("name"), worker.Name))
+		}
+
+		names.Insert(worker.Name)
+		allErrs = append(allErrs, validateWorker(workersPath.Index(i), worker)...)
+	}
+
+	allErrs = append(allErrs, rt.validateImageAutoUpdate(workersPath)...)
+	allErrs = append(allErrs, validateHibernation(specPath.Child("hibernation"), rt.Spec.Hibernation)...)
+
+	versionPath := specPath.Child("shoot", "kubernetes", "version")
+	if _, err := version.ParseGeneric(rt.Spec.Shoot.Kubernetes.Version); err != nil {
+		allErrs = append(allErrs, field.Invalid(versionPath, rt.Spec.Shoot.Kubernetes.Version, err.Error()))
+	}
+
+	allErrs = append(allErrs, validateRuntimeOIDC(specPath.Child("shoot", "kubernetes", "oidc"), rt.Spec.Shoot.Kubernetes.OIDC)...)
+	allErrs = append(allErrs, validateClusterAutoscaler(specPath.Child("shoot", "kubernetes", "clusterAutoscaler"), rt.Spec.Shoot.Kubernetes.ClusterAutoscaler)...)
+
+	allErrs = append(allErrs, validateProvider(specPath.Child("shoot", "provider"), rt.Spec.Shoot.Provider, rt.Spec.Shoot.Workers)...)
+	allErrs = append(allErrs, validateSeedSelection(specPath.Child("shoot"), rt.Spec.Shoot)...)
+	allErrs = append(allErrs, validateNetworking(specPath.Child("shoot", "networking"), rt.Spec.Shoot.Networking)...)
+	allErrs = append(allErrs, validateDNS(specPath.Child("shoot", "dns"), rt.Spec.Shoot.DNS)...)
+	allErrs = append(allErrs, validateRegistryMirrors(specPath.Child("shoot", "registryMirrors"), rt.Spec.Shoot.RegistryMirrors)...)
+	allErrs = append(allErrs, validateAuditLog(specPath.Child("shoot", "auditLog"), rt.Spec.Shoot.AuditLog)...)
+	allErrs = append(allErrs, validateExtensions(specPath.Child("shoot", "extensions"), rt.Spec.Shoot)...)
+
+	if maintenance := rt.Spec.Maintenance; maintenance != nil && maintenance.TimeWindow != nil && maintenance.TimeWindow.Begin == maintenance.TimeWindow.End {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maintenance", "timeWindow", "end"), maintenance.TimeWindow.End, "end must differ from begin"))
+	}
+
+	return allErrs
+}
+
+func validateWorker(path *field.Path, worker RuntimeWorker) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if worker.Maximum < worker.Minimum {
+		allErrs = append(allErrs, field.Invalid(path.Child("maximum"), worker.Maximum, "maximum must not be lower than minimum"))
+	}
+
+	// Gardener spreads the nodes across the zones, each zone needs at least one node
+	if worker.Maximum > 0 && int(worker.Maximum) < len(worker.Zones) {
+		allErrs = append(allErrs, field.Invalid(path.Child("maximum"), worker.Maximum, "maximum must not be lower than the number of zones"))
+	}
+
+	zones := sets.New[string]()
+
+	for i, zone := range worker.Zones {
+		if zones.Has(zone) {
+			allErrs = append(allErrs, field.Duplicate(path.Child("zones").Index(i), zone))
+		}
+
+		zones.Insert(zone)
+	}
+
+	if worker.Volume != nil {
+		size, err := resource.ParseQuantity(worker.Volume.Size)
+		if err != nil || size.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("volume", "size"), worker.Volume.Size, "size must be a positive quantity, e.g. 50Gi"))
+		}
+	}
+
+	allErrs = append(allErrs, metav1validation.ValidateLabels(worker.Labels, path.Child("labels"))...)
+
+	for i, taint := range worker.Taints {
+		allErrs = append(allErrs, validateTaint(path.Child("taints").Index(i), taint)...)
+	}
+
+	if worker.Image != nil && worker.Image.Version != "" {
+		if _, err := version.ParseGeneric(worker.Image.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("image", "version"), worker.Image.Version, err.Error()))
+		}
+	}
+
+	return append(allErrs, validateKubelet(path.Child("kubelet"), worker.Kubelet)...)
+}
+
+// validateImageAutoUpdate rejects pools which don't agree on the auto update of the images, with each other, or the
+// maintenance, Gardener updates the images of all the pools together.
+func (rt *Runtime) validateImageAutoUpdate(workersPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	var expected *bool
+	expectedFrom := ""
+
+	if maintenance := rt.Spec.Maintenance; maintenance != nil && maintenance.AutoUpdate != nil {
+		expected = &maintenance.AutoUpdate.MachineImageVersion
+		expectedFrom = "spec.maintenance.autoUpdate.machineImageVersion"
+	}
+
+	for i, worker := range rt.Spec.Shoot.Workers {
+		if worker.Image == nil || worker.Image.AutoUpdate == nil {
+			continue
+		}
+
+		path := workersPath.Index(i).Child("image", "autoUpdate")
+
+		if expected != nil && *expected != *worker.Image.AutoUpdate {
+			allErrs = append(allErrs, field.Invalid(path, *worker.Image.AutoUpdate, fmt.Sprintf("must agree with %s, Gardener updates the images of all pools together", expectedFrom)))
+
+			continue
+		}
+
+		expected = worker.Image.AutoUpdate
+		expectedFrom = path.String()
+	}
+
+	return allErrs
+}
+
+func validateKubelet(path *field.Path, kubelet *RuntimeKubelet) field.ErrorList {
+	if kubelet == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateEviction(path.Child("evictionHard"), kubelet.EvictionHard)...)
+	allErrs = append(allErrs, validateEviction(path.Child("evictionSoft"), kubelet.EvictionSoft)...)
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("evictionSoftGracePeriod"), kubelet.EvictionSoftGracePeriod)...)
+
+	if kubelet.EvictionSoft != nil && kubelet.EvictionSoftGracePeriod == nil {
+		allErrs = append(allErrs, field.Required(path.Child("evictionSoftGracePeriod"), "grace period is required with soft eviction thresholds"))
+	}
+
+	return allErrs
+}
+
+func validateEviction(path *field.Path, eviction *RuntimeKubeletEviction) field.ErrorList {
+	if eviction == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	for _, threshold := range []struct {
+		name  string
+		value string
+	}{
+		{"memoryAvailable", eviction.MemoryAvailable},
+		{"imageFSAvailable", eviction.ImageFSAvailable},
+		{"imageFSInodesFree", eviction.ImageFSInodesFree},
+		{"nodeFSAvailable", eviction.NodeFSAvailable},
+		{"nodeFSInodesFree", eviction.NodeFSInodesFree},
+	} {
+		if threshold.value != "" && !validEvictionThreshold(threshold.value) {
+			allErrs = append(allErrs, field.Invalid(path.Child(threshold.name), threshold.value, "threshold must be a quantity, e.g. 100Mi, or a percentage, e.g. 10%"))
+		}
+	}
+
+	return allErrs
+}
+
+func validEvictionThreshold(threshold string) bool {
+	if percentage, found := strings.CutSuffix(threshold, "%"); found {
+		value, err := strconv.ParseFloat(percentage, 64)
+
+		return err == nil && value >= 0 && value <= 100
+	}
+
+	quantity, err := resource.ParseQuantity(threshold)
+
+	return err == nil && quantity.Sign() >= 0
+}
+
+func validateTaint(path *field.Path, taint corev1.Taint) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, msg := range validation.IsQualifiedName(taint.Key) {
+		allErrs = append(allErrs, field.Invalid(path.Child("key"), taint.Key, msg))
+	}
+
+	if taint.Value != "" {
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			allErrs = append(allErrs, field.Invalid(path.Child("value"), taint.Value, msg))
+		}
+	}
+
+	switch taint.Effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("effect"), taint.Effect,
+			[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+	}
+
+	return allErrs
+}
+
+func validateRuntimeOIDC(path *field.Path, oidc *RuntimeOIDC) field.ErrorList {
+	if oidc == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	issuerURL, err := url.Parse(oidc.IssuerURL)
+	if err != nil || issuerURL.Scheme != "https" || issuerURL.Host == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("issuerURL"), oidc.IssuerURL, "issuer URL must be an https URL"))
+	}
+
+	if oidc.ClientID == "" {
+		allErrs = append(allErrs, field.Required(path.Child("clientID"), "client ID must not be empty"))
+	}
+
+	return allErrs
+}
+
+func validateClusterAutoscaler(path *field.Path, autoscaler *RuntimeClusterAutoscaler) field.ErrorList {
+	if autoscaler == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("scaleDownDelayAfterAdd"), autoscaler.ScaleDownDelayAfterAdd)...)
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("scaleDownUnneededTime"), autoscaler.ScaleDownUnneededTime)...)
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("maxNodeProvisionTime"), autoscaler.MaxNodeProvisionTime)...)
+
+	if autoscaler.ScaleDownUtilizationThreshold != "" {
+		threshold, err := strconv.ParseFloat(autoscaler.ScaleDownUtilizationThreshold, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			allErrs = append(allErrs, field.Invalid(path.Child("scaleDownUtilizationThreshold"), autoscaler.ScaleDownUtilizationThreshold, "must be a number between 0, and 1"))
+		}
+	}
+
+	return allErrs
+}
+
+func validatePositiveDuration(path *field.Path, duration *metav1.Duration) field.ErrorList {
+	if duration == nil || duration.Duration > 0 {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(path, duration.Duration.String(), "must be positive")}
+}
+
+func validateProvider(path *field.Path, provider RuntimeProvider, workers []RuntimeWorker) field.ErrorList {
+	var allErrs field.ErrorList
+	var typedConfigs []string
+
+	for _, typedConfig := range []struct {
+		providerType string
+		set          bool
+	}{
+		{ProviderTypeAWS, provider.AWS != nil},
+		{ProviderTypeGCP, provider.GCP != nil},
+		{ProviderTypeAzure, provider.Azure != nil},
+		{ProviderTypeOpenStack, provider.OpenStack != nil},
+	} {
+		if !typedConfig.set {
+			continue
+		}
+
+		typedConfigs = append(typedConfigs, typedConfig.providerType)
+
+		if typedConfig.providerType != provider.Type {
+			allErrs = append(allErrs, field.Forbidden(path.Child(typedConfig.providerType), fmt.Sprintf("configuration doesn't match the %s provider type", provider.Type)))
+		}
+	}
+
+	if len(typedConfigs) == 0 {
+		return allErrs
+	}
+
+	if provider.InfrastructureConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("infrastructureConfig"), "must not be combined with the typed configuration"))
+	}
+
+	if provider.ControlPlaneConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("controlPlaneConfig"), "must not be combined with the typed configuration"))
+	}
+
+	switch {
+	case provider.AWS != nil:
+		allErrs = append(allErrs, validateAWSProviderConfig(path.Child(ProviderTypeAWS), provider.AWS, workers)...)
+	case provider.GCP != nil:
+		allErrs = append(allErrs, validateCIDR(path.Child(ProviderTypeGCP, "workers"), provider.GCP.Workers)...)
+		if provider.GCP.Internal != "" {
+			allErrs = append(allErrs, validateCIDR(path.Child(ProviderTypeGCP, "internal"), provider.GCP.Internal)...)
+		}
+	case provider.Azure != nil:
+		allErrs = append(allErrs, validateAzureProviderConfig(path.Child(ProviderTypeAzure), provider.Azure)...)
+	case provider.OpenStack != nil:
+		allErrs = append(allErrs, validateCIDR(path.Child(ProviderTypeOpenStack, "workers"), provider.OpenStack.Workers)...)
+	}
+
+	return allErrs
+}
+
+func validateAWSProviderConfig(path *field.Path, config *AWSProviderConfig, workers []RuntimeWorker) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if (config.VPC.ID == "") == (config.VPC.CIDR == "") {
+		allErrs = append(allErrs, field.Invalid(path.Child("vpc"), config.VPC, "exactly one of id, and cidr must be set"))
+	} else if config.VPC.CIDR != "" {
+		allErrs = append(allErrs, validateCIDR(path.Child("vpc", "cidr"), config.VPC.CIDR)...)
+	}
+
+	zones := sets.New[string]()
+
+	for i, zone := range config.Zones {
+		zones.Insert(zone.Name)
+
+		zonePath := path.Child("zones").Index(i)
+		allErrs = append(allErrs, validateCIDR(zonePath.Child("workers"), zone.Workers)...)
+		allErrs = append(allErrs, validateCIDR(zonePath.Child("public"), zone.Public)...)
+		allErrs = append(allErrs, validateCIDR(zonePath.Child("internal"), zone.Internal)...)
+	}
+
+	workersPath := field.NewPath("spec", "shoot", "workers")
+
+	for i, worker := range workers {
+		for j, zone := range worker.Zones {
+			if !zones.Has(zone) {
+				allErrs = append(allErrs, field.Invalid(workersPath.Index(i).Child("zones").Index(j), zone, "zone has no subnets in the aws configuration"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateAzureProviderConfig(path *field.Path, config *AzureProviderConfig) field.ErrorList {
+	var allErrs field.ErrorList
+	vnet := config.VNet
+
+	existingVNet := vnet.Name != "" || vnet.ResourceGroup != ""
+
+	switch {
+	case existingVNet && (vnet.Name == "" || vnet.ResourceGroup == ""):
+		allErrs = append(allErrs, field.Invalid(path.Child("vnet"), vnet, "name, and resourceGroup of an existing VNet must be set together"))
+	case existingVNet == (vnet.CIDR != ""):
+		allErrs = append(allErrs, field.Invalid(path.Child("vnet"), vnet, "exactly one of an existing VNet, and cidr must be set"))
+	case vnet.CIDR != "":
+		allErrs = append(allErrs, validateCIDR(path.Child("vnet", "cidr"), vnet.CIDR)...)
+	}
+
+	return append(allErrs, validateCIDR(path.Child("workers"), config.Workers)...)
+}
+
+func validateCIDR(path *field.Path, cidr string) field.ErrorList {
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return field.ErrorList{field.Invalid(path, cidr, "must be a valid CIDR, e.g. 10.250.0.0/16")}
+	}
+
+	return nil
+}
+
+func validateSeedSelection(path *field.Path, shoot RuntimeShoot) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if shoot.SeedName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(shoot.SeedName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("seedName"), shoot.SeedName, msg))
+		}
+	}
+
+	if shoot.SeedSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&shoot.SeedSelector.LabelSelector,
+			metav1validation.LabelSelectorValidationOptions{}, path.Child("seedSelector"))...)
+	}
+
+	return allErrs
+}
+
+func validateDNS(path *field.Path, dns *RuntimeDNS) field.ErrorList {
+	if dns == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	for _, msg := range validation.IsDNS1123Subdomain(dns.Domain) {
+		allErrs = append(allErrs, field.Invalid(path.Child("domain"), dns.Domain, msg))
+	}
+
+	if dns.Provider.Type == "" {
+		allErrs = append(allErrs, field.Required(path.Child("provider", "type"), "DNS provider type must not be empty"))
+	}
+
+	for _, msg := range validation.IsDNS1123Subdomain(dns.Provider.SecretName) {
+		allErrs = append(allErrs, field.Invalid(path.Child("provider", "secretName"), dns.Provider.SecretName, msg))
+	}
+
+	return allErrs
+}
+
+func validateRegistryMirrors(path *field.Path, mirrors []RuntimeRegistryMirror) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, mirror := range mirrors {
+		host, port, err := net.SplitHostPort(mirror.Upstream)
+		if err != nil {
+			host, port = mirror.Upstream, ""
+		}
+
+		if len(validation.IsDNS1123Subdomain(host)) > 0 || (port != "" && len(validation.IsValidPortNum(portNumber(port))) > 0) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("upstream"), mirror.Upstream, "upstream must be a registry host, e.g. docker.io"))
+		}
+
+		for j, host := range mirror.Hosts {
+			hostURL, err := url.Parse(host)
+			if err != nil || (hostURL.Scheme != "https" && hostURL.Scheme != "http") || hostURL.Host == "" {
+				allErrs = append(allErrs, field.Invalid(path.Index(i).Child("hosts").Index(j), host, "host must be an http, or https URL"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// portNumber returns the port, or -1 when it is not a number.
+func portNumber(port string) int {
+	number, err := strconv.Atoi(port)
+	if err != nil {
+		return -1
+	}
+
+	return number
+}
+
+func validateNetworking(path *field.Path, networking *RuntimeNetworking) field.ErrorList {
+	if networking == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	var networks []*net.IPNet
+	var networkPaths []*field.Path
+
+	for _, cidr := range []struct {
+		name  string
+		value string
+	}{
+		{"nodes", networking.Nodes},
+		{"pods", networking.Pods},
+		{"services", networking.Services},
+	} {
+		if cidr.value == "" {
+			continue
+		}
+
+		cidrPath := path.Child(cidr.name)
+
+		_, network, err := net.ParseCIDR(cidr.value)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(cidrPath, cidr.value, "must be a valid CIDR, e.g. 10.250.0.0/16"))
+
+			continue
+		}
+
+		for i, other := range networks {
+			if network.Contains(other.IP) || other.Contains(network.IP) {
+				allErrs = append(allErrs, field.Invalid(cidrPath, cidr.value, fmt.Sprintf("must not overlap with %s %s", networkPaths[i], other)))
+			}
+		}
+
+		networks = append(networks, network)
+		networkPaths = append(networkPaths, cidrPath)
+	}
+
+	return allErrs
+}
+
+func validateAuditLog(path *field.Path, auditLog *RuntimeAuditLog) field.ErrorList {
+	if auditLog == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	if auditLog.PolicyConfigMapName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(auditLog.PolicyConfigMapName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("policyConfigMapName"), auditLog.PolicyConfigMapName, msg))
+		}
+	}
+
+	if auditLog.Backend != nil && auditLog.Backend.Type == "" {
+		allErrs = append(allErrs, field.Required(path.Child("backend", "type"), "extension type must not be empty"))
+	}
+
+	return allErrs
+}
+
+// validateExtensions rejects the listed extensions managed by the other fields of the shoot.
+func validateExtensions(path *field.Path, shoot RuntimeShoot) field.ErrorList {
+	managed := map[string]string{}
+
+	if shoot.DNS != nil {
+		managed[DNSExtensionType] = "spec.shoot.dns"
+	}
+
+	if shoot.AuditLog != nil && shoot.AuditLog.Backend != nil {
+		managed[shoot.AuditLog.Backend.Type] = "spec.shoot.auditLog.backend"
+	}
+
+	if len(shoot.RegistryMirrors) > 0 {
+		managed[RegistryMirrorExtensionType] = "spec.shoot.registryMirrors"
+	}
+
+	var allErrs field.ErrorList
+
+	for i, extension := range shoot.Extensions {
+		if managedBy, found := managed[extension.Type]; found {
+			allErrs = append(allErrs, field.Forbidden(path.Index(i).Child("type"), fmt.Sprintf("extension is managed by %s", managedBy)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateHibernation(path *field.Path, hibernation *RuntimeHibernation) field.ErrorList {
+	if hibernation == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+
+	for i, schedule := range hibernation.Schedules {
+		if schedule.Start == "" && schedule.End == "" {
+			allErrs = append(allErrs, field.Required(path.Child("schedules").Index(i), "at least one of start, and end must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+func (rt *Runtime) validateImmutableFields(old *Runtime) field.ErrorList {
+	var allErrs field.ErrorList
+	shootPath := field.NewPath("spec", "shoot")
+
+	if rt.Spec.Shoot.Name != old.Spec.Shoot.Name {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("name"), "field is immutable"))
+	}
+
+	if rt.Spec.Shoot.Region != old.Spec.Shoot.Region {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("region"), "field is immutable"))
+	}
+
+	if rt.Spec.Shoot.CloudProfileName != old.Spec.Shoot.CloudProfileName {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("cloudProfileName"), "field is immutable"))
+	}
+
+	if rt.Spec.Shoot.SeedName != old.Spec.Shoot.SeedName {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("seedName"), "field is immutable"))
+	}
+
+	if !equality.Semantic.DeepEqual(rt.Spec.Shoot.SeedSelector, old.Spec.Shoot.SeedSelector) {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("seedSelector"), "field is immutable"))
+	}
+
+	if !equality.Semantic.DeepEqual(rt.Spec.Shoot.Networking, old.Spec.Shoot.Networking) {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("networking"), "field is immutable"))
+	}
+
+	if old.Spec.Shoot.DNS != nil && (rt.Spec.Shoot.DNS == nil || rt.Spec.Shoot.DNS.Domain != old.Spec.Shoot.DNS.Domain) {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("dns", "domain"), "field is immutable"))
+	}
+
+	if old.Spec.Shoot.ControlPlane != nil && !equality.Semantic.DeepEqual(rt.Spec.Shoot.ControlPlane, old.Spec.Shoot.ControlPlane) {
+		allErrs = append(allErrs, field.Forbidden(shootPath.Child("controlPlane"), "high availability of the control plane can't be changed, or disabled"))
+	}
+
+	return allErrs
+}
+
+func (rt *Runtime) validateVersionUpgrade(old *Runtime) field.ErrorList {
+	newVersion, newErr := version.ParseGeneric(rt.Spec.Shoot.Kubernetes.Version)
+	oldVersion, oldErr := version.ParseGeneric(old.Spec.Shoot.Kubernetes.Version)
+
+	if newErr == nil && oldErr == nil && newVersion.LessThan(oldVersion) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "shoot", "kubernetes", "version"), "Kubernetes version can't be downgraded")}
+	}
+
+	return nil
+}
+
+// validateWorkerUpdates rejects the changes of existing pools Gardener can't apply.
+func (rt *Runtime) validateWorkerUpdates(old *Runtime) field.ErrorList {
+	var allErrs field.ErrorList
+	workersPath := field.NewPath("spec", "shoot", "workers")
+	oldWorkers := workersByName(old.Spec.Shoot.Workers)
+
+	for i, worker := range rt.Spec.Shoot.Workers {
+		oldWorker, found := oldWorkers[worker.Name]
+		if !found {
+			continue
+		}
+
+		removedZones := sets.List(sets.New(oldWorker.Zones...).Difference(sets.New(worker.Zones...)))
+		if len(removedZones) > 0 {
+			allErrs = append(allErrs, field.Forbidden(workersPath.Index(i).Child("zones"), fmt.Sprintf("zones of an existing pool can't be removed: %v", removedZones)))
+		}
+	}
+
+	return allErrs
+}
+
+// workerUpdateWarnings points out the changes replacing nodes of the shoot.
+func (rt *Runtime) workerUpdateWarnings(old *Runtime) admission.Warnings {
+	var warnings admission.Warnings
+	workers := workersByName(rt.Spec.Shoot.Workers)
+
+	for _, oldWorker := range old.Spec.Shoot.Workers {
+		worker, found := workers[oldWorker.Name]
+		if !found {
+			warnings = append(warnings, fmt.Sprintf("the nodes of the removed worker pool %s are drained, and deleted", oldWorker.Name))
+
+			continue
+		}
+
+		if worker.MachineType != oldWorker.MachineType {
+			warnings = append(warnings, fmt.Sprintf("the nodes of the worker pool %s are replaced with %s machines", worker.Name, worker.MachineType))
+		}
+	}
+
+	return warnings
+}
+
+func workersByName(workers []RuntimeWorker) map[string]RuntimeWorker {
+	result := make(map[string]RuntimeWorker, len(workers))
+	for _, worker := range workers {
+		result[worker.Name] = worker
+	}
+
+	return result
+}
+
+// runtimeValidator extends the validation of the spec with the checks requiring the cloud profiles of Gardener.
+// +kubebuilder:object:generate=false
+type runtimeValidator struct {
+	zones   RegionZones
+	targets SchedulingTargets
+}
+
+var _ webhook.CustomValidator = &runtimeValidator{}
+
+func (validator *runtimeValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	rt, ok := obj.(*Runtime)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", obj)
+	}
+
+	warnings, err := rt.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	// the cloud profile, and the seed can't be changed, they are checked on creation only
+	err = validator.validateScheduling(ctx, rt)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, validator.validateRegion(ctx, rt)
+}
+
+func (validator *runtimeValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	rt, ok := newObj.(*Runtime)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", newObj)
+	}
+
+	warnings, err := rt.ValidateUpdate(oldObj)
+	if err != nil || !rt.DeletionTimestamp.IsZero() {
+		return warnings, err
+	}
+
+	// the high availability can't be changed once enabled, the region is checked when it is enabled
+	if oldRuntime, ok := oldObj.(*Runtime); ok && oldRuntime.Spec.Shoot.ControlPlane != nil {
+		return warnings, nil
+	}
+
+	return warnings, validator.validateRegion(ctx, rt)
+}
+
+func (validator *runtimeValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	rt, ok := obj.(*Runtime)
+	if !ok {
+		return nil, errors.Errorf("unexpected object %T", obj)
+	}
+
+	return rt.ValidateDelete()
+}
+
+// validateRegion checks the region has enough zones for a zone tolerant control plane.
+func (validator *runtimeValidator) validateRegion(ctx context.Context, rt *Runtime) error {
+	controlPlane := rt.Spec.Shoot.ControlPlane
+	if validator.zones == nil || controlPlane == nil || controlPlane.FailureTolerance != FailureToleranceZone {
+		return nil
+	}
+
+	zones, err := validator.zones.RegionZones(ctx, rt.Spec.Shoot.CloudProfileName, rt.Spec.Shoot.Region)
+	if err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "failed to get the zones of the region"))
+	}
+
+	if len(zones) < minZonesForZoneFailureTolerance {
+		return rt.toInvalidError(field.ErrorList{field.Invalid(field.NewPath("spec", "shoot", "controlPlane", "failureTolerance"), controlPlane.FailureTolerance,
+			fmt.Sprintf("region %s has %d zones, the zone failure tolerance requires at least %d", rt.Spec.Shoot.Region, len(zones), minZonesForZoneFailureTolerance))})
+	}
+
+	return nil
+}
+
+// validateScheduling checks the cloud profile is of the provider type, and defines the region, and the seed the shoot is
+// pinned to is selected by the seed selectors of the Runtime, and the cloud profile.
+func (validator *runtimeValidator) validateScheduling(ctx context.Context, rt *Runtime) error {
+	if validator.targets == nil {
+		return nil
+	}
+
+	shootPath := field.NewPath("spec", "shoot")
+	shoot := rt.Spec.Shoot
+
+	cloudProfile, err := validator.targets.CloudProfile(ctx, shoot.CloudProfileName)
+	if apierrors.IsNotFound(err) {
+		return rt.toInvalidError(field.ErrorList{field.NotFound(shootPath.Child("cloudProfileName"), shoot.CloudProfileName)})
+	}
+
+	if err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "failed to get the cloud profile"))
+	}
+
+	var allErrs field.ErrorList
+
+	if cloudProfile.ProviderType != shoot.Provider.Type {
+		allErrs = append(allErrs, field.Invalid(shootPath.Child("cloudProfileName"), shoot.CloudProfileName,
+			fmt.Sprintf("cloud profile is of the %s provider type", cloudProfile.ProviderType)))
+	}
+
+	if !sets.New(cloudProfile.Regions...).Has(shoot.Region) {
+		allErrs = append(allErrs, field.Invalid(shootPath.Child("region"), shoot.Region,
+			fmt.Sprintf("region is not defined in the cloud profile %s", shoot.CloudProfileName)))
+	}
+
+	if shoot.SeedName == "" {
+		return rt.toInvalidError(allErrs)
+	}
+
+	seed, err := validator.targets.Seed(ctx, shoot.SeedName)
+	if apierrors.IsNotFound(err) {
+		return rt.toInvalidError(append(allErrs, field.NotFound(shootPath.Child("seedName"), shoot.SeedName)))
+	}
+
+	if err != nil {
+		return apierrors.NewInternalError(errors.Wrap(err, "failed to get the seed"))
+	}
+
+	for _, selector := range []struct {
+		name     string
+		selector *RuntimeSeedSelector
+	}{
+		{"seed selector", shoot.SeedSelector},
+		{"seed selector of the cloud profile", cloudProfile.SeedSelector},
+	} {
+		selected, err := seedSelected(selector.selector, seed, shoot.Provider.Type)
+		if err != nil {
+			return apierrors.NewInternalError(errors.Wrapf(err, "failed to evaluate the %s", selector.name))
+		}
+
+		if !selected {
+			allErrs = append(allErrs, field.Invalid(shootPath.Child("seedName"), shoot.SeedName, fmt.Sprintf("seed is not selected by the %s", selector.name)))
+		}
+	}
+
+	return rt.toInvalidError(allErrs)
+}
+
+// seedSelected reports whether the selector selects the seed for a shoot of the provider type, a nil selector selects
+// every seed.
+func seedSelected(selector *RuntimeSeedSelector, seed SeedInfo, shootProviderType string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+	if err != nil {
+		return false, err
+	}
+
+	if !labelSelector.Matches(labels.Set(seed.Labels)) {
+		return false, nil
+	}
+
+	providerTypes := sets.New(selector.ProviderTypes...)
+	if providerTypes.Len() == 0 {
+		providerTypes.Insert(shootProviderType)
+	}
+
+	return providerTypes.Has("*") || providerTypes.Has(seed.ProviderType), nil
+}
+
+func (rt *Runtime) toInvalidError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("Runtime").GroupKind(), rt.Name, allErrs)
+}
@@ -0,0 +1,643 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRuntimeValidation(t *testing.T) {
+	t.Run("should accept valid runtime", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+
+		// when
+		_, err := rt.ValidateCreate()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept valid runtime with typed provider configuration", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		rt.Spec.Shoot.Provider.AWS = fixAWSProviderConfig()
+
+		// when
+		_, err := rt.ValidateCreate()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept valid runtime with node configuration", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		rt.Spec.Shoot.RegistryMirrors = []RuntimeRegistryMirror{{Upstream: "registry.example.com:5000", Hosts: []string{"https://mirror.example.com"}}}
+		rt.Spec.Shoot.Workers[0].Kubelet = &RuntimeKubelet{
+			EvictionHard:            &RuntimeKubeletEviction{MemoryAvailable: "100Mi", NodeFSAvailable: "5%"},
+			EvictionSoft:            &RuntimeKubeletEviction{MemoryAvailable: "200Mi"},
+			EvictionSoftGracePeriod: &metav1.Duration{Duration: time.Minute},
+		}
+
+		// when
+		_, err := rt.ValidateCreate()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		modify func(rt *Runtime)
+		field  string
+	}{
+		{
+			name:   "maximum lower than minimum",
+			modify: func(rt *Runtime) { rt.Spec.Shoot.Workers[0].Maximum = 0 },
+			field:  "spec.shoot.workers[0].maximum",
+		},
+		{
+			name: "maximum lower than the number of zones",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Workers[0].Zones = []string{"eu-central-1a", "eu-central-1b", "eu-central-1c", "eu-central-1d"}
+			},
+			field: "spec.shoot.workers[0].maximum",
+		},
+		{
+			name:   "duplicated zone",
+			modify: func(rt *Runtime) { rt.Spec.Shoot.Workers[0].Zones = []string{"eu-central-1a", "eu-central-1a"} },
+			field:  "spec.shoot.workers[0].zones[1]",
+		},
+		{
+			name: "duplicated worker pool",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Workers = append(rt.Spec.Shoot.Workers, rt.Spec.Shoot.Workers[0])
+			},
+			field: "spec.shoot.workers[1].name",
+		},
+		{
+			name:   "invalid volume size",
+			modify: func(rt *Runtime) { rt.Spec.Shoot.Workers[0].Volume = &RuntimeWorkerVolume{Size: "fifty"} },
+			field:  "spec.shoot.workers[0].volume.size",
+		},
+		{
+			name:   "invalid node label",
+			modify: func(rt *Runtime) { rt.Spec.Shoot.Workers[0].Labels = map[string]string{"pool/": "gpu"} },
+			field:  "spec.shoot.workers[0].labels",
+		},
+		{
+			name: "invalid taint effect",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Workers[0].Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: "Never"}}
+			},
+			field: "spec.shoot.workers[0].taints[0].effect",
+		},
+		{
+			name:   "invalid Kubernetes version",
+			modify: func(rt *Runtime) { rt.Spec.Shoot.Kubernetes.Version = "latest" },
+			field:  "spec.shoot.kubernetes.version",
+		},
+		{
+			name: "OIDC issuer without https",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Kubernetes.OIDC = &RuntimeOIDC{IssuerURL: "http://issuer.example.com", ClientID: "client"}
+			},
+			field: "spec.shoot.kubernetes.oidc.issuerURL",
+		},
+		{
+			name: "OIDC without client ID",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Kubernetes.OIDC = &RuntimeOIDC{IssuerURL: "https://issuer.example.com"}
+			},
+			field: "spec.shoot.kubernetes.oidc.clientID",
+		},
+		{
+			name: "typed configuration of another provider",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Provider.GCP = &GCPProviderConfig{Workers: "10.250.0.0/16", Zone: "europe-west3-a"}
+			},
+			field: "spec.shoot.provider.gcp",
+		},
+		{
+			name: "typed, and raw configuration",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Provider.AWS = fixAWSProviderConfig()
+				rt.Spec.Shoot.Provider.InfrastructureConfig = &runtime.RawExtension{Raw: []byte(`{}`)}
+			},
+			field: "spec.shoot.provider.infrastructureConfig",
+		},
+		{
+			name: "AWS VPC with id, and cidr",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Provider.AWS = fixAWSProviderConfig()
+				rt.Spec.Shoot.Provider.AWS.VPC.ID = "vpc-0a1b2c3d"
+			},
+			field: "spec.shoot.provider.aws.vpc",
+		},
+		{
+			name: "AWS zone with invalid CIDR",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Provider.AWS = fixAWSProviderConfig()
+				rt.Spec.Shoot.Provider.AWS.Zones[0].Public = "10.250.32.0"
+			},
+			field: "spec.shoot.provider.aws.zones[0].public",
+		},
+		{
+			name: "worker zone without AWS subnets",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Provider.AWS = fixAWSProviderConfig()
+				rt.Spec.Shoot.Workers[0].Zones = []string{"eu-central-1b"}
+			},
+			field: "spec.shoot.workers[0].zones[0]",
+		},
+		{
+			name: "Azure VNet without resource group",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Provider.Type = ProviderTypeAzure
+				rt.Spec.Shoot.Provider.Azure = &AzureProviderConfig{VNet: AzureVNet{Name: "shared"}, Workers: "10.250.0.0/16"}
+			},
+			field: "spec.shoot.provider.azure.vnet",
+		},
+		{
+			name: "invalid DNS domain",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.DNS = &RuntimeDNS{Domain: "Cluster.Example.com", Provider: RuntimeDNSProvider{Type: "aws-route53", SecretName: "route53"}}
+			},
+			field: "spec.shoot.dns.domain",
+		},
+		{
+			name: "invalid CIDR",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Networking = &RuntimeNetworking{Nodes: "10.250.0.0"}
+			},
+			field: "spec.shoot.networking.nodes",
+		},
+		{
+			name: "overlapping CIDRs",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Networking = &RuntimeNetworking{Nodes: "10.250.0.0/16", Pods: "100.64.0.0/12", Services: "10.250.128.0/17"}
+			},
+			field: "spec.shoot.networking.services",
+		},
+		{
+			name: "invalid audit policy ConfigMap name",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.AuditLog = &RuntimeAuditLog{PolicyConfigMapName: "Audit_Policy"}
+			},
+			field: "spec.shoot.auditLog.policyConfigMapName",
+		},
+		{
+			name: "negative scale down delay",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Kubernetes.ClusterAutoscaler = &RuntimeClusterAutoscaler{ScaleDownDelayAfterAdd: &metav1.Duration{Duration: -time.Minute}}
+			},
+			field: "spec.shoot.kubernetes.clusterAutoscaler.scaleDownDelayAfterAdd",
+		},
+		{
+			name: "scale down utilization threshold above one",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Kubernetes.ClusterAutoscaler = &RuntimeClusterAutoscaler{ScaleDownUtilizationThreshold: "1.5"}
+			},
+			field: "spec.shoot.kubernetes.clusterAutoscaler.scaleDownUtilizationThreshold",
+		},
+		{
+			name: "empty maintenance time window",
+			modify: func(rt *Runtime) {
+				rt.Spec.Maintenance = &RuntimeMaintenance{TimeWindow: &RuntimeMaintenanceTimeWindow{Begin: "220000+0100", End: "220000+0100"}}
+			},
+			field: "spec.maintenance.timeWindow.end",
+		},
+		{
+			name: "pools disagreeing on the image auto update",
+			modify: func(rt *Runtime) {
+				enabled, disabled := true, false
+				rt.Spec.Shoot.Workers[0].Image = &RuntimeWorkerImage{Name: "gardenlinux", AutoUpdate: &enabled}
+				rt.Spec.Shoot.Workers = append(rt.Spec.Shoot.Workers, RuntimeWorker{
+					Name: "gpu-worker", MachineType: "g4dn.xlarge", Minimum: 1, Maximum: 1,
+					Image: &RuntimeWorkerImage{Name: "gardenlinux", Version: "1312.3.0", AutoUpdate: &disabled},
+				})
+			},
+			field: "spec.shoot.workers[1].image.autoUpdate",
+		},
+		{
+			name: "image auto update disagreeing with the maintenance",
+			modify: func(rt *Runtime) {
+				enabled := true
+				rt.Spec.Maintenance = &RuntimeMaintenance{AutoUpdate: &RuntimeMaintenanceAutoUpdate{MachineImageVersion: false}}
+				rt.Spec.Shoot.Workers[0].Image = &RuntimeWorkerImage{Name: "gardenlinux", AutoUpdate: &enabled}
+			},
+			field: "spec.shoot.workers[0].image.autoUpdate",
+		},
+		{
+			name: "listed extension managed by the custom domain",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.DNS = &RuntimeDNS{Domain: "cluster.example.com", Provider: RuntimeDNSProvider{Type: "aws-route53", SecretName: "route53-credentials"}}
+				rt.Spec.Shoot.Extensions = []RuntimeExtension{{Type: "shoot-networking-filter"}, {Type: DNSExtensionType}}
+			},
+			field: "spec.shoot.extensions[1].type",
+		},
+		{
+			name: "invalid eviction threshold",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Workers[0].Kubelet = &RuntimeKubelet{EvictionHard: &RuntimeKubeletEviction{MemoryAvailable: "120%"}}
+			},
+			field: "spec.shoot.workers[0].kubelet.evictionHard.memoryAvailable",
+		},
+		{
+			name: "soft eviction thresholds without grace period",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.Workers[0].Kubelet = &RuntimeKubelet{EvictionSoft: &RuntimeKubeletEviction{MemoryAvailable: "200Mi"}}
+			},
+			field: "spec.shoot.workers[0].kubelet.evictionSoftGracePeriod",
+		},
+		{
+			name: "invalid registry mirror host",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.RegistryMirrors = []RuntimeRegistryMirror{{Upstream: "docker.io", Hosts: []string{"mirror.example.com"}}}
+			},
+			field: "spec.shoot.registryMirrors[0].hosts[0]",
+		},
+		{
+			name: "invalid registry mirror upstream",
+			modify: func(rt *Runtime) {
+				rt.Spec.Shoot.RegistryMirrors = []RuntimeRegistryMirror{{Upstream: "https://docker.io", Hosts: []string{"https://mirror.example.com"}}}
+			},
+			field: "spec.shoot.registryMirrors[0].upstream",
+		},
+		{
+			name: "empty hibernation schedule",
+			modify: func(rt *Runtime) {
+				rt.Spec.Hibernation = &RuntimeHibernation{Schedules: []HibernationSchedule{{Location: "Europe/Berlin"}}}
+			},
+			field: "spec.hibernation.schedules[0]",
+		},
+	} {
+		t.Run("should reject runtime with "+tc.name, func(t *testing.T) {
+			// given
+			rt := fixRuntime()
+			tc.modify(rt)
+
+			// when
+			_, err := rt.ValidateCreate()
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.field)
+		})
+	}
+
+	t.Run("should reject change of immutable fields", func(t *testing.T) {
+		// given
+		old := fixRuntime()
+		rt := fixRuntime()
+		rt.Spec.Shoot.Name = "other-shoot"
+		rt.Spec.Shoot.Region = "eu-west-1"
+		rt.Spec.Shoot.Networking = &RuntimeNetworking{Type: "cilium"}
+		rt.Spec.Shoot.CloudProfileName = "aws-eu"
+		rt.Spec.Shoot.SeedName = "aws-eu1"
+		rt.Spec.Shoot.SeedSelector = &RuntimeSeedSelector{ProviderTypes: []string{"aws"}}
+
+		// when
+		_, err := rt.ValidateUpdate(old)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.shoot.name")
+		assert.Contains(t, err.Error(), "spec.shoot.region")
+		assert.Contains(t, err.Error(), "spec.shoot.networking")
+		assert.Contains(t, err.Error(), "spec.shoot.cloudProfileName")
+		assert.Contains(t, err.Error(), "spec.shoot.seedName")
+		assert.Contains(t, err.Error(), "spec.shoot.seedSelector")
+	})
+
+	t.Run("should reject disabling the high availability of the control plane", func(t *testing.T) {
+		// given
+		old := fixRuntime()
+		old.Spec.Shoot.ControlPlane = &RuntimeControlPlane{FailureTolerance: FailureToleranceNode}
+		rt := fixRuntime()
+
+		// when
+		_, err := rt.ValidateUpdate(old)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.shoot.controlPlane")
+	})
+
+	t.Run("should reject change of the DNS domain", func(t *testing.T) {
+		// given
+		old := fixRuntime()
+		old.Spec.Shoot.DNS = &RuntimeDNS{Domain: "cluster.example.com", Provider: RuntimeDNSProvider{Type: "aws-route53", SecretName: "route53"}}
+		rt := fixRuntime()
+		rt.Spec.Shoot.DNS = &RuntimeDNS{Domain: "other.example.com", Provider: RuntimeDNSProvider{Type: "aws-route53", SecretName: "route53"}}
+
+		// when
+		_, err := rt.ValidateUpdate(old)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.shoot.dns.domain")
+	})
+
+	t.Run("should reject downgrade of the Kubernetes version", func(t *testing.T) {
+		// given
+		old := fixRuntime()
+		old.Spec.Shoot.Kubernetes.Version = "1.28.2"
+		rt := fixRuntime()
+
+		// when
+		_, err := rt.ValidateUpdate(old)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.shoot.kubernetes.version")
+	})
+
+	t.Run("should reject removal of zones from an existing pool", func(t *testing.T) {
+		// given
+		old := fixRuntime()
+		old.Spec.Shoot.Workers[0].Zones = []string{"eu-central-1a", "eu-central-1b"}
+		rt := fixRuntime()
+
+		// when
+		_, err := rt.ValidateUpdate(old)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.shoot.workers[0].zones")
+	})
+
+	t.Run("should warn about replaced nodes when pools are resized, and removed", func(t *testing.T) {
+		// given
+		old := fixRuntime()
+		old.Spec.Shoot.Workers = append(old.Spec.Shoot.Workers, RuntimeWorker{Name: "gpu-worker", MachineType: "p3.2xlarge", Minimum: 1, Maximum: 1})
+		rt := fixRuntime()
+		rt.Spec.Shoot.Workers[0].MachineType = "m5.2xlarge"
+		rt.Spec.Shoot.Workers[0].Maximum = 5
+		rt.Spec.Shoot.Workers[0].Zones = append(rt.Spec.Shoot.Workers[0].Zones, "eu-central-1b")
+
+		// when
+		warnings, err := rt.ValidateUpdate(old)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "m5.2xlarge")
+		assert.Contains(t, warnings[1], "gpu-worker")
+	})
+
+	t.Run("should accept update of deleted runtime", func(t *testing.T) {
+		// given
+		old := fixRuntime()
+		rt := old.DeepCopy()
+		rt.Spec.Shoot.Workers[0].Maximum = 0
+		rt.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+		// when
+		_, err := rt.ValidateUpdate(old)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestRuntimeDeletionProtection(t *testing.T) {
+	t.Run("should accept deletion of unprotected runtime", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+
+		// when
+		_, err := rt.ValidateDelete()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject deletion of protected runtime", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		protected := true
+		rt.Spec.DeletionProtection = &protected
+
+		// when
+		_, err := rt.ValidateDelete()
+
+		// then
+		require.Error(t, err)
+		assert.True(t, apierrors.IsForbidden(err))
+		assert.Contains(t, err.Error(), DeletionConfirmationAnnotation)
+	})
+
+	t.Run("should accept confirmed deletion of protected runtime", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		protected := true
+		rt.Spec.DeletionProtection = &protected
+		rt.Annotations = map[string]string{DeletionConfirmationAnnotation: "true"}
+
+		// when
+		_, err := rt.ValidateDelete()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject deletion of production runtime by default", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		rt.Spec.Shoot.Purpose = PurposeProduction
+
+		// when
+		_, err := rt.ValidateDelete()
+
+		// then
+		require.Error(t, err)
+		assert.True(t, apierrors.IsForbidden(err))
+	})
+
+	t.Run("should accept deletion of production runtime with disabled protection", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		rt.Spec.Shoot.Purpose = PurposeProduction
+		protected := false
+		rt.Spec.DeletionProtection = &protected
+
+		// when
+		_, err := rt.ValidateDelete()
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+type fixedRegionZones []string
+
+func (zones fixedRegionZones) RegionZones(_ context.Context, _, _ string) ([]string, error) {
+	return zones, nil
+}
+
+func TestRuntimeRegionValidation(t *testing.T) {
+	t.Run("should accept zone failure tolerance in region with three zones", func(t *testing.T) {
+		// given
+		validator := &runtimeValidator{zones: fixedRegionZones{"eu-central-1a", "eu-central-1b", "eu-central-1c"}}
+		rt := fixRuntime()
+		rt.Spec.Shoot.ControlPlane = &RuntimeControlPlane{FailureTolerance: FailureToleranceZone}
+
+		// when
+		_, err := validator.ValidateCreate(context.Background(), rt)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject zone failure tolerance in region with fewer zones", func(t *testing.T) {
+		// given
+		validator := &runtimeValidator{zones: fixedRegionZones{"eu-central-1a", "eu-central-1b"}}
+		old := fixRuntime()
+		rt := fixRuntime()
+		rt.Spec.Shoot.ControlPlane = &RuntimeControlPlane{FailureTolerance: FailureToleranceZone}
+
+		// when
+		_, err := validator.ValidateUpdate(context.Background(), old, rt)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.shoot.controlPlane.failureTolerance")
+	})
+
+	t.Run("should accept node failure tolerance in single zone region", func(t *testing.T) {
+		// given
+		validator := &runtimeValidator{zones: fixedRegionZones{"eu-central-1a"}}
+		rt := fixRuntime()
+		rt.Spec.Shoot.ControlPlane = &RuntimeControlPlane{FailureTolerance: FailureToleranceNode}
+
+		// when
+		_, err := validator.ValidateCreate(context.Background(), rt)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+type fixedSchedulingTargets struct {
+	cloudProfiles map[string]CloudProfileInfo
+	seeds         map[string]SeedInfo
+}
+
+func (targets fixedSchedulingTargets) CloudProfile(_ context.Context, name string) (CloudProfileInfo, error) {
+	cloudProfile, ok := targets.cloudProfiles[name]
+	if !ok {
+		return CloudProfileInfo{}, apierrors.NewNotFound(schema.GroupResource{Group: "core.gardener.cloud", Resource: "cloudprofiles"}, name)
+	}
+
+	return cloudProfile, nil
+}
+
+func (targets fixedSchedulingTargets) Seed(_ context.Context, name string) (SeedInfo, error) {
+	seed, ok := targets.seeds[name]
+	if !ok {
+		return SeedInfo{}, apierrors.NewNotFound(schema.GroupResource{Group: "core.gardener.cloud", Resource: "seeds"}, name)
+	}
+
+	return seed, nil
+}
+
+func TestRuntimeSchedulingValidation(t *testing.T) {
+	targets := fixedSchedulingTargets{
+		cloudProfiles: map[string]CloudProfileInfo{
+			"aws": {ProviderType: "aws", Regions: []string{"eu-central-1"}},
+			"aws-eu": {
+				ProviderType: "aws",
+				Regions:      []string{"eu-central-1"},
+				SeedSelector: &RuntimeSeedSelector{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"eu-access": "true"}}},
+			},
+			"gcp": {ProviderType: "gcp", Regions: []string{"europe-west3"}},
+		},
+		seeds: map[string]SeedInfo{
+			"aws-eu1": {ProviderType: "aws", Region: "eu-central-1", Labels: map[string]string{"eu-access": "true"}},
+			"aws-us1": {ProviderType: "aws", Region: "us-east-1"},
+			"gcp-eu1": {ProviderType: "gcp", Region: "europe-west3", Labels: map[string]string{"eu-access": "true"}},
+		},
+	}
+
+	for _, testCase := range []struct {
+		name         string
+		cloudProfile string
+		seedName     string
+		seedSelector *RuntimeSeedSelector
+		invalidField string
+	}{
+		{name: "should accept the seed allowed by the cloud profile", cloudProfile: "aws-eu", seedName: "aws-eu1"},
+		{name: "should accept a seed of another provider type selected by the seed selector", cloudProfile: "aws", seedName: "gcp-eu1",
+			seedSelector: &RuntimeSeedSelector{ProviderTypes: []string{"*"}}},
+		{name: "should reject a missing cloud profile", cloudProfile: "azure", invalidField: "spec.shoot.cloudProfileName"},
+		{name: "should reject a cloud profile of another provider type", cloudProfile: "gcp", invalidField: "spec.shoot.cloudProfileName"},
+		{name: "should reject a missing seed", cloudProfile: "aws", seedName: "aws-eu2", invalidField: "spec.shoot.seedName"},
+		{name: "should reject a seed not selected by the cloud profile", cloudProfile: "aws-eu", seedName: "aws-us1", invalidField: "spec.shoot.seedName"},
+		{name: "should reject a seed not selected by the seed selector", cloudProfile: "aws", seedName: "aws-us1",
+			seedSelector: &RuntimeSeedSelector{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"eu-access": "true"}}},
+			invalidField: "spec.shoot.seedName"},
+		{name: "should reject a seed of another provider type", cloudProfile: "aws", seedName: "gcp-eu1",
+			seedSelector: &RuntimeSeedSelector{}, invalidField: "spec.shoot.seedName"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			validator := &runtimeValidator{targets: targets}
+			rt := fixRuntime()
+			rt.Spec.Shoot.CloudProfileName = testCase.cloudProfile
+			rt.Spec.Shoot.SeedName = testCase.seedName
+			rt.Spec.Shoot.SeedSelector = testCase.seedSelector
+
+			// when
+			_, err := validator.ValidateCreate(context.Background(), rt)
+
+			// then
+			if testCase.invalidField == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, apierrors.IsInvalid(err))
+			assert.Contains(t, err.Error(), testCase.invalidField)
+		})
+	}
+}
+
+func fixAWSProviderConfig() *AWSProviderConfig {
+	return &AWSProviderConfig{
+		VPC: AWSVPC{CIDR: "10.250.0.0/16"},
+		Zones: []AWSZone{
+			{Name: "eu-central-1a", Workers: "10.250.0.0/19", Public: "10.250.32.0/20", Internal: "10.250.48.0/20"},
+		},
+	}
+}
+
+func fixRuntime() *Runtime {
+	return &Runtime{
+		ObjectMeta: metav1.ObjectMeta{Name: "runtime", Namespace: "kcp-system"},
+		Spec: RuntimeSpec{
+			Shoot: RuntimeShoot{
+				Name:              "shoot",
+				Region:            "eu-central-1",
+				CloudProfileName:  "aws",
+				SecretBindingName: "aws-credentials",
+				Provider:          RuntimeProvider{Type: "aws"},
+				Kubernetes:        RuntimeKubernetes{Version: "1.27.5"},
+				Workers: []RuntimeWorker{
+					{Name: "cpu-worker", MachineType: "m5.xlarge", Minimum: 1, Maximum: 3, Zones: []string{"eu-central-1a"}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultSecretMigrationBatchSize is the number of secrets migrated between the updates of the progress by default.
+const DefaultSecretMigrationBatchSize = 20
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="PHASE",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="MIGRATED",type=integer,JSONPath=`.status.migrated`
+//+kubebuilder:printcolumn:name="REMAINING",type=integer,JSONPath=`.status.remaining`
+//+kubebuilder:printcolumn:name="FAILED",type=integer,JSONPath=`.status.failed`
+//+kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretMigration is the Schema for the secretmigrations API.
+// It moves the existing kubeconfig secrets to the naming convention, and the label schema of a new version of the
+// manager, the secrets are migrated in batches, and the progress is reported in the status. Changing the spec restarts
+// the migration.
+type SecretMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecretMigrationSpec   `json:"spec"`
+	Status SecretMigrationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SecretMigrationList contains a list of SecretMigration
+type SecretMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretMigration `json:"items"`
+}
+
+// SecretMigrationSpec defines the secrets to migrate, and the changes applied to them.
+type SecretMigrationSpec struct {
+	// Namespaces limits the migration to the secrets in the namespaces, the secrets in all namespaces are migrated by default.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Selector selects the secrets to migrate, defaults to the secrets managed by the manager.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// LabelRenames move the values of the labels to new keys.
+	// +optional
+	LabelRenames []MetadataRename `json:"labelRenames,omitempty"`
+
+	// AnnotationRenames move the values of the annotations to new keys.
+	// +optional
+	AnnotationRenames []MetadataRename `json:"annotationRenames,omitempty"`
+
+	// NamePrefix renames the secrets starting with a prefix, the GardenerClusters writing to the secrets are updated.
+	// +optional
+	NamePrefix *NamePrefixRename `json:"namePrefix,omitempty"`
+
+	// BatchSize is the number of secrets migrated between the updates of the progress, defaults to 20.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+}
+
+// MetadataRename moves the value of a label, or an annotation to a new key.
+type MetadataRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NamePrefixRename replaces the prefix of the secret names.
+type NamePrefixRename struct {
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// BatchSizeOrDefault returns the number of secrets migrated between the updates of the progress.
+func (spec SecretMigrationSpec) BatchSizeOrDefault() int {
+	if spec.BatchSize <= 0 {
+		return DefaultSecretMigrationBatchSize
+	}
+
+	return int(spec.BatchSize)
+}
+
+type MigrationPhase string
+
+const (
+	MigrationPhaseRunning   MigrationPhase = "Running"
+	MigrationPhaseCompleted MigrationPhase = "Completed"
+	MigrationPhaseFailed    MigrationPhase = "Failed"
+)
+
+// SecretMigrationStatus reports the progress of the migration.
+type SecretMigrationStatus struct {
+	// Phase is one of ("Running", "Completed", "Failed"), the migration fails when any secret can't be migrated.
+	Phase MigrationPhase `json:"phase,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the progress is reported for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Migrated is the number of migrated secrets.
+	Migrated int32 `json:"migrated"`
+
+	// Remaining is the number of secrets still to be migrated.
+	Remaining int32 `json:"remaining"`
+
+	// Failed is the number of secrets which could not be migrated.
+	Failed int32 `json:"failed"`
+
+	// FailedSecrets are the secrets which could not be migrated, as namespace/name, they are not retried until the spec
+	// changes.
+	// +optional
+	FailedSecrets []string `json:"failedSecrets,omitempty"`
+
+	// CompletionTime is the time when the last secret was processed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// List of status conditions to indicate the status of the migration.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionTypeMigrationCompleted indicates whether all the secrets have been processed.
+	ConditionTypeMigrationCompleted ConditionType = "Completed"
+
+	ConditionReasonMigrationInProgress   ConditionReason = "MigrationInProgress"
+	ConditionReasonMigrationSucceeded    ConditionReason = "MigrationSucceeded"
+	ConditionReasonSecretMigrationFailed ConditionReason = "SecretMigrationFailed"
+	ConditionReasonInvalidMigration      ConditionReason = "InvalidMigration"
+)
+
+func init() {
+	SchemeBuilder.Register(&SecretMigration{}, &SecretMigrationList{})
+}
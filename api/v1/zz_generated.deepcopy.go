@@ -22,31 +22,1758 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSInstanceMetadataOptions) DeepCopyInto(out *AWSInstanceMetadataOptions) {
+	*out = *in
+	if in.HTTPPutResponseHopLimit != nil {
+		in, out := &in.HTTPPutResponseHopLimit, &out.HTTPPutResponseHopLimit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSInstanceMetadataOptions.
+func (in *AWSInstanceMetadataOptions) DeepCopy() *AWSInstanceMetadataOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSInstanceMetadataOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSProviderConfig) DeepCopyInto(out *AWSProviderConfig) {
+	*out = *in
+	out.VPC = in.VPC
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]AWSZone, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceMetadataOptions != nil {
+		in, out := &in.InstanceMetadataOptions, &out.InstanceMetadataOptions
+		*out = new(AWSInstanceMetadataOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSProviderConfig.
+func (in *AWSProviderConfig) DeepCopy() *AWSProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSecretsManagerTarget) DeepCopyInto(out *AWSSecretsManagerTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSecretsManagerTarget.
+func (in *AWSSecretsManagerTarget) DeepCopy() *AWSSecretsManagerTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSecretsManagerTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSVPC) DeepCopyInto(out *AWSVPC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSVPC.
+func (in *AWSVPC) DeepCopy() *AWSVPC {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSVPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSZone) DeepCopyInto(out *AWSZone) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSZone.
+func (in *AWSZone) DeepCopy() *AWSZone {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Auth) DeepCopyInto(out *Auth) {
+	*out = *in
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth.
+func (in *Auth) DeepCopy() *Auth {
+	if in == nil {
+		return nil
+	}
+	out := new(Auth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureKeyVaultTarget) DeepCopyInto(out *AzureKeyVaultTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKeyVaultTarget.
+func (in *AzureKeyVaultTarget) DeepCopy() *AzureKeyVaultTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureKeyVaultTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureProviderConfig) DeepCopyInto(out *AzureProviderConfig) {
+	*out = *in
+	out.VNet = in.VNet
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureProviderConfig.
+func (in *AzureProviderConfig) DeepCopy() *AzureProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVNet) DeepCopyInto(out *AzureVNet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVNet.
+func (in *AzureVNet) DeepCopy() *AzureVNet {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVNet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleSource) DeepCopyInto(out *CABundleSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleSource.
+func (in *CABundleSource) DeepCopy() *CABundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuota) DeepCopyInto(out *ClusterQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuota.
+func (in *ClusterQuota) DeepCopy() *ClusterQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuotaList) DeepCopyInto(out *ClusterQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaList.
+func (in *ClusterQuotaList) DeepCopy() *ClusterQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuotaSpec) DeepCopyInto(out *ClusterQuotaSpec) {
+	*out = *in
+	if in.GardenerClusters != nil {
+		in, out := &in.GardenerClusters, &out.GardenerClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaSpec.
+func (in *ClusterQuotaSpec) DeepCopy() *ClusterQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalStoreStatus) DeepCopyInto(out *ExternalStoreStatus) {
+	*out = *in
+	if in.LastWriteTime != nil {
+		in, out := &in.LastWriteTime, &out.LastWriteTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStoreStatus.
+func (in *ExternalStoreStatus) DeepCopy() *ExternalStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalStores) DeepCopyInto(out *ExternalStores) {
+	*out = *in
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultTarget)
+		**out = **in
+	}
+	if in.AWSSecretsManager != nil {
+		in, out := &in.AWSSecretsManager, &out.AWSSecretsManager
+		*out = new(AWSSecretsManagerTarget)
+		**out = **in
+	}
+	if in.GCPSecretManager != nil {
+		in, out := &in.GCPSecretManager, &out.GCPSecretManager
+		*out = new(GCPSecretManagerTarget)
+		**out = **in
+	}
+	if in.AzureKeyVault != nil {
+		in, out := &in.AzureKeyVault, &out.AzureKeyVault
+		*out = new(AzureKeyVaultTarget)
+		**out = **in
+	}
+	if in.PushSecret != nil {
+		in, out := &in.PushSecret, &out.PushSecret
+		*out = new(PushSecretTarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStores.
+func (in *ExternalStores) DeepCopy() *ExternalStores {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStores)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPProviderConfig) DeepCopyInto(out *GCPProviderConfig) {
+	*out = *in
+	if in.VPC != nil {
+		in, out := &in.VPC, &out.VPC
+		*out = new(GCPVPC)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPProviderConfig.
+func (in *GCPProviderConfig) DeepCopy() *GCPProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPSecretManagerTarget) DeepCopyInto(out *GCPSecretManagerTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPSecretManagerTarget.
+func (in *GCPSecretManagerTarget) DeepCopy() *GCPSecretManagerTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPSecretManagerTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPVPC) DeepCopyInto(out *GCPVPC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPVPC.
+func (in *GCPVPC) DeepCopy() *GCPVPC {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPVPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GardenerCluster) DeepCopyInto(out *GardenerCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerCluster.
+func (in *GardenerCluster) DeepCopy() *GardenerCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GardenerCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GardenerClusterList) DeepCopyInto(out *GardenerClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GardenerCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerClusterList.
+func (in *GardenerClusterList) DeepCopy() *GardenerClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GardenerClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GardenerClusterSpec) DeepCopyInto(out *GardenerClusterSpec) {
+	*out = *in
+	in.Kubeconfig.DeepCopyInto(&out.Kubeconfig)
+	out.Shoot = in.Shoot
+	if in.Gardener != nil {
+		in, out := &in.Gardener, &out.Gardener
+		*out = new(GardenerCredentials)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerClusterSpec.
+func (in *GardenerClusterSpec) DeepCopy() *GardenerClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GardenerClusterStatus) DeepCopyInto(out *GardenerClusterStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.KubeconfigExpirationTime != nil {
+		in, out := &in.KubeconfigExpirationTime, &out.KubeconfigExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Shoot != nil {
+		in, out := &in.Shoot, &out.Shoot
+		*out = new(ShootInfo)
+		**out = **in
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]SecretStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalStores != nil {
+		in, out := &in.ExternalStores, &out.ExternalStores
+		*out = make([]ExternalStoreStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerClusterStatus.
+func (in *GardenerClusterStatus) DeepCopy() *GardenerClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GardenerConfig) DeepCopyInto(out *GardenerConfig) {
+	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(GardenerConfigCredentialsRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerConfig.
+func (in *GardenerConfig) DeepCopy() *GardenerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GardenerConfigCredentialsRef) DeepCopyInto(out *GardenerConfigCredentialsRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerConfigCredentialsRef.
+func (in *GardenerConfigCredentialsRef) DeepCopy() *GardenerConfigCredentialsRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerConfigCredentialsRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GardenerCredentials) DeepCopyInto(out *GardenerCredentials) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerCredentials.
+func (in *GardenerCredentials) DeepCopy() *GardenerCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GardenerCredentialsSecretRef) DeepCopyInto(out *GardenerCredentialsSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerCredentialsSecretRef.
+func (in *GardenerCredentialsSecretRef) DeepCopy() *GardenerCredentialsSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GardenerCredentialsSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HibernationSchedule) DeepCopyInto(out *HibernationSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HibernationSchedule.
+func (in *HibernationSchedule) DeepCopy() *HibernationSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(HibernationSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureManagerConfig) DeepCopyInto(out *InfrastructureManagerConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureManagerConfig.
+func (in *InfrastructureManagerConfig) DeepCopy() *InfrastructureManagerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureManagerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InfrastructureManagerConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureManagerConfigList) DeepCopyInto(out *InfrastructureManagerConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InfrastructureManagerConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureManagerConfigList.
+func (in *InfrastructureManagerConfigList) DeepCopy() *InfrastructureManagerConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureManagerConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InfrastructureManagerConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureManagerConfigSpec) DeepCopyInto(out *InfrastructureManagerConfigSpec) {
+	*out = *in
+	if in.Gardener != nil {
+		in, out := &in.Gardener, &out.Gardener
+		*out = new(GardenerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RotationPeriod != nil {
+		in, out := &in.RotationPeriod, &out.RotationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureManagerConfigSpec.
+func (in *InfrastructureManagerConfigSpec) DeepCopy() *InfrastructureManagerConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureManagerConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureManagerConfigStatus) DeepCopyInto(out *InfrastructureManagerConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureManagerConfigStatus.
+func (in *InfrastructureManagerConfigStatus) DeepCopy() *InfrastructureManagerConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureManagerConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kubeconfig) DeepCopyInto(out *Kubeconfig) {
+	*out = *in
+	in.Secret.DeepCopyInto(&out.Secret)
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = new(SecretTemplate)
+		**out = **in
+	}
+	if in.AdditionalSecrets != nil {
+		in, out := &in.AdditionalSecrets, &out.AdditionalSecrets
+		*out = make([]Secret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(Auth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(CABundleSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalStores != nil {
+		in, out := &in.ExternalStores, &out.ExternalStores
+		*out = new(ExternalStores)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubeconfig.
+func (in *Kubeconfig) DeepCopy() *Kubeconfig {
+	if in == nil {
+		return nil
+	}
+	out := new(Kubeconfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigRequest) DeepCopyInto(out *KubeconfigRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigRequest.
+func (in *KubeconfigRequest) DeepCopy() *KubeconfigRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeconfigRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigRequestList) DeepCopyInto(out *KubeconfigRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeconfigRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigRequestList.
+func (in *KubeconfigRequestList) DeepCopy() *KubeconfigRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeconfigRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigRequestSpec) DeepCopyInto(out *KubeconfigRequestSpec) {
+	*out = *in
+	out.Shoot = in.Shoot
+	out.TTL = in.TTL
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigRequestSpec.
+func (in *KubeconfigRequestSpec) DeepCopy() *KubeconfigRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigRequestStatus) DeepCopyInto(out *KubeconfigRequestStatus) {
+	*out = *in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigRequestStatus.
+func (in *KubeconfigRequestStatus) DeepCopy() *KubeconfigRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataRename) DeepCopyInto(out *MetadataRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataRename.
+func (in *MetadataRename) DeepCopy() *MetadataRename {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamePrefixRename) DeepCopyInto(out *NamePrefixRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamePrefixRename.
+func (in *NamePrefixRename) DeepCopy() *NamePrefixRename {
+	if in == nil {
+		return nil
+	}
+	out := new(NamePrefixRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCConfig) DeepCopyInto(out *OIDCConfig) {
+	*out = *in
+	if in.ExtraScopes != nil {
+		in, out := &in.ExtraScopes, &out.ExtraScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfig.
+func (in *OIDCConfig) DeepCopy() *OIDCConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackProviderConfig) DeepCopyInto(out *OpenStackProviderConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackProviderConfig.
+func (in *OpenStackProviderConfig) DeepCopy() *OpenStackProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretTarget) DeepCopyInto(out *PushSecretTarget) {
+	*out = *in
+	out.SecretStoreRef = in.SecretStoreRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretTarget.
+func (in *PushSecretTarget) DeepCopy() *PushSecretTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Runtime) DeepCopyInto(out *Runtime) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Runtime.
+func (in *Runtime) DeepCopy() *Runtime {
+	if in == nil {
+		return nil
+	}
+	out := new(Runtime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Runtime) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeAuditLog) DeepCopyInto(out *RuntimeAuditLog) {
+	*out = *in
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(RuntimeAuditLogBackend)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeAuditLog.
+func (in *RuntimeAuditLog) DeepCopy() *RuntimeAuditLog {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeAuditLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeAuditLogBackend) DeepCopyInto(out *RuntimeAuditLogBackend) {
+	*out = *in
+	if in.ProviderConfig != nil {
+		in, out := &in.ProviderConfig, &out.ProviderConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeAuditLogBackend.
+func (in *RuntimeAuditLogBackend) DeepCopy() *RuntimeAuditLogBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeAuditLogBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeClusterAutoscaler) DeepCopyInto(out *RuntimeClusterAutoscaler) {
+	*out = *in
+	if in.ScaleDownDelayAfterAdd != nil {
+		in, out := &in.ScaleDownDelayAfterAdd, &out.ScaleDownDelayAfterAdd
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownUnneededTime != nil {
+		in, out := &in.ScaleDownUnneededTime, &out.ScaleDownUnneededTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxNodeProvisionTime != nil {
+		in, out := &in.MaxNodeProvisionTime, &out.MaxNodeProvisionTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeClusterAutoscaler.
+func (in *RuntimeClusterAutoscaler) DeepCopy() *RuntimeClusterAutoscaler {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeClusterAutoscaler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeControlPlane) DeepCopyInto(out *RuntimeControlPlane) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeControlPlane.
+func (in *RuntimeControlPlane) DeepCopy() *RuntimeControlPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeControlPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeDNS) DeepCopyInto(out *RuntimeDNS) {
+	*out = *in
+	out.Provider = in.Provider
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeDNS.
+func (in *RuntimeDNS) DeepCopy() *RuntimeDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeDNSProvider) DeepCopyInto(out *RuntimeDNSProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeDNSProvider.
+func (in *RuntimeDNSProvider) DeepCopy() *RuntimeDNSProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeDNSProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeExtension) DeepCopyInto(out *RuntimeExtension) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProviderConfig != nil {
+		in, out := &in.ProviderConfig, &out.ProviderConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeExtension.
+func (in *RuntimeExtension) DeepCopy() *RuntimeExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeHibernation) DeepCopyInto(out *RuntimeHibernation) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]HibernationSchedule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeHibernation.
+func (in *RuntimeHibernation) DeepCopy() *RuntimeHibernation {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeHibernation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeKubelet) DeepCopyInto(out *RuntimeKubelet) {
+	*out = *in
+	if in.MaxPods != nil {
+		in, out := &in.MaxPods, &out.MaxPods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EvictionHard != nil {
+		in, out := &in.EvictionHard, &out.EvictionHard
+		*out = new(RuntimeKubeletEviction)
+		**out = **in
+	}
+	if in.EvictionSoft != nil {
+		in, out := &in.EvictionSoft, &out.EvictionSoft
+		*out = new(RuntimeKubeletEviction)
+		**out = **in
+	}
+	if in.EvictionSoftGracePeriod != nil {
+		in, out := &in.EvictionSoftGracePeriod, &out.EvictionSoftGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeKubelet.
+func (in *RuntimeKubelet) DeepCopy() *RuntimeKubelet {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeKubelet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeKubeletEviction) DeepCopyInto(out *RuntimeKubeletEviction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeKubeletEviction.
+func (in *RuntimeKubeletEviction) DeepCopy() *RuntimeKubeletEviction {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeKubeletEviction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeKubernetes) DeepCopyInto(out *RuntimeKubernetes) {
+	*out = *in
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(RuntimeOIDC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterAutoscaler != nil {
+		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
+		*out = new(RuntimeClusterAutoscaler)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeKubernetes.
+func (in *RuntimeKubernetes) DeepCopy() *RuntimeKubernetes {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeKubernetes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeList) DeepCopyInto(out *RuntimeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Runtime, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeList.
+func (in *RuntimeList) DeepCopy() *RuntimeList {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuntimeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeMaintenance) DeepCopyInto(out *RuntimeMaintenance) {
+	*out = *in
+	if in.TimeWindow != nil {
+		in, out := &in.TimeWindow, &out.TimeWindow
+		*out = new(RuntimeMaintenanceTimeWindow)
+		**out = **in
+	}
+	if in.AutoUpdate != nil {
+		in, out := &in.AutoUpdate, &out.AutoUpdate
+		*out = new(RuntimeMaintenanceAutoUpdate)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeMaintenance.
+func (in *RuntimeMaintenance) DeepCopy() *RuntimeMaintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeMaintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeMaintenanceAutoUpdate) DeepCopyInto(out *RuntimeMaintenanceAutoUpdate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeMaintenanceAutoUpdate.
+func (in *RuntimeMaintenanceAutoUpdate) DeepCopy() *RuntimeMaintenanceAutoUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeMaintenanceAutoUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeMaintenanceTimeWindow) DeepCopyInto(out *RuntimeMaintenanceTimeWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeMaintenanceTimeWindow.
+func (in *RuntimeMaintenanceTimeWindow) DeepCopy() *RuntimeMaintenanceTimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeMaintenanceTimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeNetworking) DeepCopyInto(out *RuntimeNetworking) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeNetworking.
+func (in *RuntimeNetworking) DeepCopy() *RuntimeNetworking {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeNetworking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeOIDC) DeepCopyInto(out *RuntimeOIDC) {
+	*out = *in
+	if in.SigningAlgs != nil {
+		in, out := &in.SigningAlgs, &out.SigningAlgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredClaims != nil {
+		in, out := &in.RequiredClaims, &out.RequiredClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeOIDC.
+func (in *RuntimeOIDC) DeepCopy() *RuntimeOIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeOIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeProvider) DeepCopyInto(out *RuntimeProvider) {
+	*out = *in
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSProviderConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCP != nil {
+		in, out := &in.GCP, &out.GCP
+		*out = new(GCPProviderConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureProviderConfig)
+		**out = **in
+	}
+	if in.OpenStack != nil {
+		in, out := &in.OpenStack, &out.OpenStack
+		*out = new(OpenStackProviderConfig)
+		**out = **in
+	}
+	if in.InfrastructureConfig != nil {
+		in, out := &in.InfrastructureConfig, &out.InfrastructureConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneConfig != nil {
+		in, out := &in.ControlPlaneConfig, &out.ControlPlaneConfig
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeProvider.
+func (in *RuntimeProvider) DeepCopy() *RuntimeProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeRegistryMirror) DeepCopyInto(out *RuntimeRegistryMirror) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeRegistryMirror.
+func (in *RuntimeRegistryMirror) DeepCopy() *RuntimeRegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeRegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeSeedSelector) DeepCopyInto(out *RuntimeSeedSelector) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.ProviderTypes != nil {
+		in, out := &in.ProviderTypes, &out.ProviderTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeSeedSelector.
+func (in *RuntimeSeedSelector) DeepCopy() *RuntimeSeedSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeSeedSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeShoot) DeepCopyInto(out *RuntimeShoot) {
+	*out = *in
+	if in.SeedSelector != nil {
+		in, out := &in.SeedSelector, &out.SeedSelector
+		*out = new(RuntimeSeedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Provider.DeepCopyInto(&out.Provider)
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(RuntimeDNS)
+		**out = **in
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(RuntimeNetworking)
+		**out = **in
+	}
+	in.Kubernetes.DeepCopyInto(&out.Kubernetes)
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = new(RuntimeControlPlane)
+		**out = **in
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RuntimeRegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]RuntimeExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AuditLog != nil {
+		in, out := &in.AuditLog, &out.AuditLog
+		*out = new(RuntimeAuditLog)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = make([]RuntimeWorker, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeShoot.
+func (in *RuntimeShoot) DeepCopy() *RuntimeShoot {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeShoot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeSpec) DeepCopyInto(out *RuntimeSpec) {
+	*out = *in
+	in.Shoot.DeepCopyInto(&out.Shoot)
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(RuntimeMaintenance)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(RuntimeHibernation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeSpec.
+func (in *RuntimeSpec) DeepCopy() *RuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeStatus) DeepCopyInto(out *RuntimeStatus) {
+	*out = *in
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = make([]RuntimeWorkerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeStatus.
+func (in *RuntimeStatus) DeepCopy() *RuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeWorker) DeepCopyInto(out *RuntimeWorker) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(RuntimeWorkerVolume)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(RuntimeWorkerImage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CRI != nil {
+		in, out := &in.CRI, &out.CRI
+		*out = new(RuntimeWorkerCRI)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(RuntimeKubelet)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeWorker.
+func (in *RuntimeWorker) DeepCopy() *RuntimeWorker {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeWorker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeWorkerCRI) DeepCopyInto(out *RuntimeWorkerCRI) {
+	*out = *in
+	if in.ContainerRuntimes != nil {
+		in, out := &in.ContainerRuntimes, &out.ContainerRuntimes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeWorkerCRI.
+func (in *RuntimeWorkerCRI) DeepCopy() *RuntimeWorkerCRI {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeWorkerCRI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeWorkerImage) DeepCopyInto(out *RuntimeWorkerImage) {
+	*out = *in
+	if in.AutoUpdate != nil {
+		in, out := &in.AutoUpdate, &out.AutoUpdate
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeWorkerImage.
+func (in *RuntimeWorkerImage) DeepCopy() *RuntimeWorkerImage {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeWorkerImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeWorkerStatus) DeepCopyInto(out *RuntimeWorkerStatus) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeWorkerStatus.
+func (in *RuntimeWorkerStatus) DeepCopy() *RuntimeWorkerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeWorkerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeWorkerVolume) DeepCopyInto(out *RuntimeWorkerVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeWorkerVolume.
+func (in *RuntimeWorkerVolume) DeepCopy() *RuntimeWorkerVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeWorkerVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Secret) DeepCopyInto(out *Secret) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClusterRef != nil {
+		in, out := &in.ClusterRef, &out.ClusterRef
+		*out = new(SecretClusterRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Secret.
+func (in *Secret) DeepCopy() *Secret {
+	if in == nil {
+		return nil
+	}
+	out := new(Secret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretClusterRef) DeepCopyInto(out *SecretClusterRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretClusterRef.
+func (in *SecretClusterRef) DeepCopy() *SecretClusterRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretClusterRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretMigration) DeepCopyInto(out *SecretMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerCluster.
-func (in *GardenerCluster) DeepCopy() *GardenerCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretMigration.
+func (in *SecretMigration) DeepCopy() *SecretMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(GardenerCluster)
+	out := new(SecretMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GardenerCluster) DeepCopyObject() runtime.Object {
+func (in *SecretMigration) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -54,31 +1781,31 @@ func (in *GardenerCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GardenerClusterList) DeepCopyInto(out *GardenerClusterList) {
+func (in *SecretMigrationList) DeepCopyInto(out *SecretMigrationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GardenerCluster, len(*in))
+		*out = make([]SecretMigration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerClusterList.
-func (in *GardenerClusterList) DeepCopy() *GardenerClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretMigrationList.
+func (in *SecretMigrationList) DeepCopy() *SecretMigrationList {
 	if in == nil {
 		return nil
 	}
-	out := new(GardenerClusterList)
+	out := new(SecretMigrationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GardenerClusterList) DeepCopyObject() runtime.Object {
+func (in *SecretMigrationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -86,25 +1813,57 @@ func (in *GardenerClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GardenerClusterSpec) DeepCopyInto(out *GardenerClusterSpec) {
+func (in *SecretMigrationSpec) DeepCopyInto(out *SecretMigrationSpec) {
 	*out = *in
-	out.Kubeconfig = in.Kubeconfig
-	out.Shoot = in.Shoot
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LabelRenames != nil {
+		in, out := &in.LabelRenames, &out.LabelRenames
+		*out = make([]MetadataRename, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnnotationRenames != nil {
+		in, out := &in.AnnotationRenames, &out.AnnotationRenames
+		*out = make([]MetadataRename, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamePrefix != nil {
+		in, out := &in.NamePrefix, &out.NamePrefix
+		*out = new(NamePrefixRename)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerClusterSpec.
-func (in *GardenerClusterSpec) DeepCopy() *GardenerClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretMigrationSpec.
+func (in *SecretMigrationSpec) DeepCopy() *SecretMigrationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GardenerClusterSpec)
+	out := new(SecretMigrationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GardenerClusterStatus) DeepCopyInto(out *GardenerClusterStatus) {
+func (in *SecretMigrationStatus) DeepCopyInto(out *SecretMigrationStatus) {
 	*out = *in
+	if in.FailedSecrets != nil {
+		in, out := &in.FailedSecrets, &out.FailedSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -114,43 +1873,62 @@ func (in *GardenerClusterStatus) DeepCopyInto(out *GardenerClusterStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GardenerClusterStatus.
-func (in *GardenerClusterStatus) DeepCopy() *GardenerClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretMigrationStatus.
+func (in *SecretMigrationStatus) DeepCopy() *SecretMigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GardenerClusterStatus)
+	out := new(SecretMigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Kubeconfig) DeepCopyInto(out *Kubeconfig) {
+func (in *SecretStatus) DeepCopyInto(out *SecretStatus) {
 	*out = *in
-	out.Secret = in.Secret
+	if in.ClusterRef != nil {
+		in, out := &in.ClusterRef, &out.ClusterRef
+		*out = new(SecretClusterRef)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubeconfig.
-func (in *Kubeconfig) DeepCopy() *Kubeconfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStatus.
+func (in *SecretStatus) DeepCopy() *SecretStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Kubeconfig)
+	out := new(SecretStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Secret) DeepCopyInto(out *Secret) {
+func (in *SecretStoreRef) DeepCopyInto(out *SecretStoreRef) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Secret.
-func (in *Secret) DeepCopy() *Secret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRef.
+func (in *SecretStoreRef) DeepCopy() *SecretStoreRef {
 	if in == nil {
 		return nil
 	}
-	out := new(Secret)
+	out := new(SecretStoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretTemplate) DeepCopyInto(out *SecretTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretTemplate.
+func (in *SecretTemplate) DeepCopy() *SecretTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -169,3 +1947,33 @@ func (in *Shoot) DeepCopy() *Shoot {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShootInfo) DeepCopyInto(out *ShootInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShootInfo.
+func (in *ShootInfo) DeepCopy() *ShootInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ShootInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultTarget) DeepCopyInto(out *VaultTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultTarget.
+func (in *VaultTarget) DeepCopy() *VaultTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultTarget)
+	in.DeepCopyInto(out)
+	return out
+}
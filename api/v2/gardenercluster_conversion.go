@@ -0,0 +1,83 @@
+package v2
+
+import (
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this GardenerCluster to the hub version (v1).
+func (cluster *GardenerCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*imv1.GardenerCluster)
+	if !ok {
+		return errors.Errorf("unexpected conversion target %T", dstRaw)
+	}
+
+	if len(cluster.Spec.Kubeconfig.Targets) == 0 {
+		return errors.New("at least one kubeconfig target is required")
+	}
+
+	dst.ObjectMeta = cluster.ObjectMeta
+	dst.Spec.Shoot = cluster.Spec.Shoot
+	dst.Spec.Purpose = cluster.Spec.Purpose
+	dst.Spec.Gardener = cluster.Spec.Gardener
+	dst.Spec.Priority = cluster.Spec.Priority
+	dst.Spec.Kubeconfig = imv1.Kubeconfig{
+		Secret:            cluster.Spec.Kubeconfig.Targets[0],
+		AdditionalSecrets: cluster.Spec.Kubeconfig.Targets[1:],
+		RotationInterval:  cluster.Spec.Kubeconfig.RotationPolicy.Interval,
+		Immutable:         cluster.Spec.Kubeconfig.RotationPolicy.Immutable,
+		RetainOnDelete:    cluster.Spec.Kubeconfig.RetainOnDelete,
+		ExpirationSeconds: cluster.Spec.Kubeconfig.ExpirationSeconds,
+		Auth:              cluster.Spec.Kubeconfig.Auth,
+		AccessLevel:       cluster.Spec.Kubeconfig.AccessLevel,
+		EndpointType:      cluster.Spec.Kubeconfig.EndpointType,
+		CABundle:          cluster.Spec.Kubeconfig.CABundle,
+		ExternalStores:    cluster.Spec.Kubeconfig.ExternalStores,
+		Encrypted:         cluster.Spec.Kubeconfig.Encrypted,
+		SecretFormat:      cluster.Spec.Kubeconfig.SecretFormat,
+		SecretTemplate:    cluster.Spec.Kubeconfig.SecretTemplate,
+	}
+
+	if len(dst.Spec.Kubeconfig.AdditionalSecrets) == 0 {
+		dst.Spec.Kubeconfig.AdditionalSecrets = nil
+	}
+
+	dst.Status = cluster.Status
+
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1) to this version.
+func (cluster *GardenerCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*imv1.GardenerCluster)
+	if !ok {
+		return errors.Errorf("unexpected conversion source %T", srcRaw)
+	}
+
+	cluster.ObjectMeta = src.ObjectMeta
+	cluster.Spec.Shoot = src.Spec.Shoot
+	cluster.Spec.Purpose = src.Spec.Purpose
+	cluster.Spec.Gardener = src.Spec.Gardener
+	cluster.Spec.Priority = src.Spec.Priority
+	cluster.Spec.Kubeconfig = Kubeconfig{
+		Targets: src.Spec.Kubeconfig.Targets(),
+		RotationPolicy: RotationPolicy{
+			Interval:  src.Spec.Kubeconfig.RotationInterval,
+			Immutable: src.Spec.Kubeconfig.Immutable,
+		},
+		ExpirationSeconds: src.Spec.Kubeconfig.ExpirationSeconds,
+		Auth:              src.Spec.Kubeconfig.Auth,
+		AccessLevel:       src.Spec.Kubeconfig.AccessLevel,
+		EndpointType:      src.Spec.Kubeconfig.EndpointType,
+		CABundle:          src.Spec.Kubeconfig.CABundle,
+		ExternalStores:    src.Spec.Kubeconfig.ExternalStores,
+		Encrypted:         src.Spec.Kubeconfig.Encrypted,
+		SecretFormat:      src.Spec.Kubeconfig.SecretFormat,
+		SecretTemplate:    src.Spec.Kubeconfig.SecretTemplate,
+		RetainOnDelete:    src.Spec.Kubeconfig.RetainOnDelete,
+	}
+	cluster.Status = src.Status
+
+	return nil
+}
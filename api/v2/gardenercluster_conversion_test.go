@@ -0,0 +1,93 @@
+package v2
+
+import (
+	"testing"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGardenerClusterConversion(t *testing.T) {
+	t.Run("should convert v1 to v2 and back without losing data", func(t *testing.T) {
+		// given
+		hub := fixV1GardenerCluster()
+		hub.Spec.Kubeconfig.AdditionalSecrets = []imv1.Secret{
+			{Name: "kubeconfig-copy", Namespace: "other", Key: "config"},
+		}
+		hub.Spec.Kubeconfig.Auth = &imv1.Auth{
+			Type: imv1.AuthTypeOIDC,
+			OIDC: &imv1.OIDCConfig{IssuerURL: "https://issuer.example.com", ClientID: "client"},
+		}
+		hub.Spec.Kubeconfig.AccessLevel = imv1.AccessLevelViewer
+		hub.Spec.Kubeconfig.EndpointType = imv1.EndpointTypeInternal
+		hub.Spec.Purpose = imv1.PurposeProduction
+		hub.Spec.Gardener = &imv1.GardenerCredentials{SecretRef: imv1.GardenerCredentialsSecretRef{Name: "gardener-landscape-eu"}}
+		hub.Spec.Priority = 100
+		hub.Spec.Kubeconfig.SecretTemplate = &imv1.SecretTemplate{Name: "kubeconfig-{{ .Shoot.Name }}"}
+		spoke := &GardenerCluster{}
+		restored := &imv1.GardenerCluster{}
+
+		// when
+		require.NoError(t, spoke.ConvertFrom(hub))
+		require.NoError(t, spoke.ConvertTo(restored))
+
+		// then
+		assert.Equal(t, hub, restored)
+	})
+
+	t.Run("should use the first target as primary secret", func(t *testing.T) {
+		// given
+		hub := fixV1GardenerCluster()
+		spoke := &GardenerCluster{}
+
+		// when
+		require.NoError(t, spoke.ConvertFrom(hub))
+
+		// then
+		require.Len(t, spoke.Spec.Kubeconfig.Targets, 1)
+		assert.Equal(t, hub.Spec.Kubeconfig.Secret, spoke.Spec.Kubeconfig.Targets[0])
+		assert.Equal(t, hub.Spec.Kubeconfig.RotationInterval, spoke.Spec.Kubeconfig.RotationPolicy.Interval)
+		assert.True(t, spoke.Spec.Kubeconfig.RotationPolicy.Immutable)
+		assert.Nil(t, spoke.Spec.Kubeconfig.Auth)
+	})
+
+	t.Run("should fail to convert v2 without targets", func(t *testing.T) {
+		// given
+		spoke := &GardenerCluster{}
+
+		// when
+		err := spoke.ConvertTo(&imv1.GardenerCluster{})
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func fixV1GardenerCluster() *imv1.GardenerCluster {
+	return &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: "kcp-system",
+		},
+		Spec: imv1.GardenerClusterSpec{
+			Shoot: imv1.Shoot{Name: "shoot"},
+			Kubeconfig: imv1.Kubeconfig{
+				Secret:           imv1.Secret{Name: "kubeconfig", Namespace: "kcp-system", Key: "config"},
+				RotationInterval: &metav1.Duration{Duration: time.Hour},
+				Immutable:        true,
+				RetainOnDelete:   true,
+				ExpirationSeconds: func() *int64 {
+					expirationSeconds := int64(3600)
+					return &expirationSeconds
+				}(),
+			},
+		},
+		Status: imv1.GardenerClusterStatus{
+			State:         imv1.ReadyState,
+			RotationCount: 2,
+		},
+	}
+}
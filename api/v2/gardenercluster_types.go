@@ -0,0 +1,144 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=gcl,categories={kyma,infrastructure}
+//+kubebuilder:printcolumn:name="STATE",type=string,JSONPath=`.status.state`
+//+kubebuilder:printcolumn:name="SHOOT",type=string,JSONPath=`.spec.shoot.name`
+//+kubebuilder:printcolumn:name="SECRET",type=string,JSONPath=`.spec.kubeconfig.targets[0].name`
+//+kubebuilder:printcolumn:name="LAST-ROTATION",type=date,JSONPath=`.status.lastRotationTime`
+//+kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// GardenerCluster is the Schema for the clusters API
+type GardenerCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GardenerClusterSpec        `json:"spec"`
+	Status imv1.GardenerClusterStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GardenerClusterList contains a list of GardenerCluster
+type GardenerClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GardenerCluster `json:"items"`
+}
+
+// GardenerClusterSpec defines the desired state of GardenerCluster
+type GardenerClusterSpec struct {
+	Kubeconfig Kubeconfig `json:"kubeconfig"`
+	Shoot      imv1.Shoot `json:"shoot"`
+
+	// Purpose of the shoot, it selects the default kubeconfig rotation interval, and labels the metrics of the cluster.
+	// The purpose of the shoot in Gardener is used when not specified.
+	// +optional
+	Purpose imv1.Purpose `json:"purpose,omitempty"`
+
+	// Gardener selects the credentials of the Gardener project of the shoot, the credentials of the controller are used
+	// when not specified.
+	// +optional
+	Gardener *imv1.GardenerCredentials `json:"gardener,omitempty"`
+
+	// Priority orders the reconciliation of the clusters when the work queue is deep, the clusters with a higher
+	// priority are rotated, and repaired first. Only applied with the priority queue enabled on the controller.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// Kubeconfig defines how the kubeconfig is generated, rotated and where it is stored
+type Kubeconfig struct {
+	// Targets defines the secrets the kubeconfig is written to, the first one is the primary secret.
+	// +kubebuilder:validation:MinItems=1
+	Targets []imv1.Secret `json:"targets"`
+
+	// RotationPolicy defines when and how the kubeconfig is rotated.
+	// +optional
+	RotationPolicy RotationPolicy `json:"rotationPolicy,omitempty"`
+
+	// ExpirationSeconds overrides the controller-wide validity of the admin kubeconfig requested from Gardener for this cluster.
+	// +kubebuilder:validation:Minimum=600
+	// +optional
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+
+	// Auth defines how the kubeconfig authenticates against the shoot, the admin kubeconfig is used by default.
+	// +optional
+	Auth *imv1.Auth `json:"auth,omitempty"`
+
+	// AccessLevel defines the permissions granted by the kubeconfig.
+	// +kubebuilder:validation:Enum=Admin;Viewer
+	// +kubebuilder:default=Admin
+	// +optional
+	AccessLevel imv1.AccessLevel `json:"accessLevel,omitempty"`
+
+	// EndpointType defines which API server endpoint of the shoot the kubeconfig targets.
+	// +kubebuilder:validation:Enum=External;Internal
+	// +kubebuilder:default=External
+	// +optional
+	EndpointType imv1.EndpointType `json:"endpointType,omitempty"`
+
+	// CABundle references additional certificate authorities appended to the certificate authority data of the kubeconfig.
+	// +optional
+	CABundle *imv1.CABundleSource `json:"caBundle,omitempty"`
+
+	// ExternalStores defines secret management systems outside of the cluster the kubeconfig is also written to.
+	// +optional
+	ExternalStores *imv1.ExternalStores `json:"externalStores,omitempty"`
+
+	// Encrypted stores the kubeconfig in the secrets envelope-encrypted with the key configured on the controller.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// SecretTemplate overrides the controller-wide templates of the name, and the key of the first target.
+	// +optional
+	SecretTemplate *imv1.SecretTemplate `json:"secretTemplate,omitempty"`
+
+	// SecretFormat defines the resource the kubeconfig is written to, a plain secret, or a Bitnami SealedSecret.
+	// +kubebuilder:validation:Enum=Secret;SealedSecret
+	// +kubebuilder:default=Secret
+	// +optional
+	SecretFormat imv1.SecretFormat `json:"secretFormat,omitempty"`
+
+	// RetainOnDelete keeps the kubeconfig secrets when the GardenerCluster is deleted.
+	// +optional
+	RetainOnDelete bool `json:"retainOnDelete,omitempty"`
+}
+
+// RotationPolicy defines when and how the kubeconfig is rotated
+type RotationPolicy struct {
+	// Interval overrides the controller-wide kubeconfig rotation period for this cluster.
+	// It must be positive and must not exceed the controller-wide rotation period.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Immutable makes the controller create a new secret version on every rotation instead of updating the secret in place.
+	// +optional
+	Immutable bool `json:"immutable,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GardenerCluster{}, &GardenerClusterList{})
+}
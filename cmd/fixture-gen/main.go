@@ -0,0 +1,55 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// fixture-gen writes the manifests of synthetic GardenerClusters, and Runtimes to the standard output, for the scale
+// tests, and the demo environments.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kyma-project/infrastructure-manager/pkg/fixtures"
+)
+
+func main() {
+	var options fixtures.Options
+	var namespaces string
+
+	flag.IntVar(&options.Count, "count", 10, "Number of the clusters") //nolint:gomnd
+	flag.StringVar(&namespaces, "namespaces", fixtures.DefaultNamespace, "Comma-separated namespaces the clusters are spread across")
+	flag.StringVar(&options.Prefix, "prefix", fixtures.DefaultPrefix, "Prefix of the names of the clusters, and their shoots")
+	flag.BoolVar(&options.Runtimes, "runtimes", false, "Generate a Runtime for each cluster")
+	flag.Parse()
+
+	for _, namespace := range strings.Split(namespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			options.Namespaces = append(options.Namespaces, namespace)
+		}
+	}
+
+	generated, err := fixtures.Generate(options)
+	if err == nil {
+		err = fixtures.Write(os.Stdout, generated)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -17,32 +17,53 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/gardener/gardener/pkg/apis/core/v1beta1"
-	gardener_apis "github.com/gardener/gardener/pkg/client/core/clientset/versioned/typed/core/v1beta1"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/go-logr/logr"
 	infrastructuremanagerv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	infrastructuremanagerv2 "github.com/kyma-project/infrastructure-manager/api/v2"
+	"github.com/kyma-project/infrastructure-manager/internal/audit"
 	"github.com/kyma-project/infrastructure-manager/internal/controller"
+	"github.com/kyma-project/infrastructure-manager/internal/encryption"
+	"github.com/kyma-project/infrastructure-manager/internal/featuregates"
 	"github.com/kyma-project/infrastructure-manager/internal/gardener"
+	"github.com/kyma-project/infrastructure-manager/internal/loglevel"
+	"github.com/kyma-project/infrastructure-manager/internal/quota"
+	"github.com/kyma-project/infrastructure-manager/internal/remotecluster"
+	"github.com/kyma-project/infrastructure-manager/internal/sealing"
+	"github.com/kyma-project/infrastructure-manager/internal/secretstore"
+	"github.com/kyma-project/infrastructure-manager/internal/sharding"
+	"github.com/kyma-project/infrastructure-manager/internal/shoot"
+	"github.com/kyma-project/infrastructure-manager/internal/tracing"
+	"github.com/kyma-project/infrastructure-manager/internal/webhookcert"
+	"github.com/kyma-project/infrastructure-manager/pkg/gardener/fake"
 	"github.com/pkg/errors"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
-// The ratio determines what is the minimal time that needs to pass to rotate certificate.
-const minimalRotationTimeRatio = 0.6
-
 var (
 	scheme   = runtime.NewScheme()        //nolint:gochecknoglobals
 	setupLog = ctrl.Log.WithName("setup") //nolint:gochecknoglobals
@@ -52,18 +73,119 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(infrastructuremanagerv1.AddToScheme(scheme))
+	utilruntime.Must(infrastructuremanagerv2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 const defaultExpirationTime = 24 * time.Hour
 
+// Gardener does not issue admin kubeconfigs valid for less than 10 minutes.
+const defaultMinExpirationTime = 10 * time.Minute
+
+const defaultOrphanedSecretsCollectionInterval = time.Hour
+
+const externalStoreRequestTimeout = 30 * time.Second
+
+// logLevelPath serves the log levels on the metrics endpoint, it is protected by the auth proxy together with the metrics.
+const logLevelPath = "/debug/loglevel"
+
+// auditLogActor identifies the controller in the audit records, together with the name of its pod.
+const auditLogActor = "infrastructure-manager"
+
+const tracingShutdownTimeout = 5 * time.Second
+
+// The termination grace period of the pod is 60 seconds, the manager stops its other runnables after the rotations.
+const (
+	defaultShutdownGracePeriod = 30 * time.Second
+	managerShutdownMargin      = 10 * time.Second
+)
+
+// The API server waits 10 seconds for the webhooks, the shoot lookup has to leave time for the rest of the validation.
+const defaultShootAdmissionValidationTimeout = 3 * time.Second
+
+const (
+	webhookCertReconcileInterval = 10 * time.Minute
+	webhookCertIssueTimeout      = 2 * time.Minute
+)
+
+// The credentials are rotated with a fifth of their validity left, well before the consumers could read an expired one.
+const defaultCredentialRotationFraction = 0.8
+
+// The rotation is due at 95% of the rotation period, shortening the requeue by less keeps the rotations on schedule.
+const defaultRequeueJitter = 0.05
+
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var gardenerKubeconfigPath string
 	var gardenerProjectName string
+	var gardenClusterIdentity string
 	var expirationTime time.Duration
+	var minExpirationTime time.Duration
+	var maxExpirationTime time.Duration
+	var orphanedSecretsCollectionInterval time.Duration
+	var orphanedSecretsCollectionDryRun bool
+	var enableWebhooks bool
+	var webhookCertManager bool
+	var webhookCertDir string
+	var webhookNamespace string
+	var webhookNamePrefix string
+	var webhookCertIssuer string
+	var webhookCertIssuerKind string
+	var dryRun bool
+	var kubeconfigValidation bool
+	var vaultAddress string
+	var awsSecretsManager bool
+	var gcpSecretManager bool
+	var gcpSecretVersionGracePeriod time.Duration
+	var azureKeyVault bool
+	var azureKeyVaultDNSSuffix string
+	var azureManagedIdentityClientID string
+	var pushSecret bool
+	var encryptionProvider string
+	var encryptionKey string
+	var sealedSecretsCertPath string
+	var vaultKubernetesAuthMount string
+	var vaultKubernetesAuthRole string
+	var vaultMount string
+	var vaultPathPrefix string
+	var kubeconfigValidationDialTimeout time.Duration
+	var shootAdmissionValidation bool
+	var shootAdmissionValidationTimeout time.Duration
+	var requeueInterval time.Duration
+	var requeueJitter float64
+	var credentialRotationFraction float64
+	var startupSmearingWindow time.Duration
+	var fetchBackoffBase time.Duration
+	var fetchBackoffMax time.Duration
+	var maxConcurrentReconciles int
+	var gardenerQPS float64
+	var gardenerBurst int
+	var gardenerMaxInFlight int
+	var circuitBreakerThreshold int
+	var circuitBreakerCoolDown time.Duration
+	var shutdownGracePeriod time.Duration
+	var gardenerKubeconfigRefreshInterval time.Duration
+	var otlpEndpoint string
+	var otlpInsecure bool
+	var traceSamplingRatio float64
+	var auditLogPath string
+	var runtimeProvisioning bool
+	var propagatedLabelPrefixes string
+	var propagatedAnnotationPrefixes string
+	var purposeRotationIntervals string
+	var watchNamespaces string
+	var secretNameTemplate string
+	var secretKeyTemplate string
+	var crLabelSelector string
+	var shardCount int
+	var configName string
+	var namespaceRotationLimit int
+	var namespaceRotationWindow time.Duration
+	var priorityQueue bool
+	var shardID int
+	var mockGardener bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -72,7 +194,74 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&gardenerKubeconfigPath, "gardener-kubeconfig-path", "/gardener/kubeconfig/kubeconfig", "Kubeconfig file for Gardener cluster")
 	flag.StringVar(&gardenerProjectName, "gardener-project-name", "gardener-project", "Name of the Gardener project")
+	flag.StringVar(&gardenClusterIdentity, "garden-cluster-identity", "", "Identity of the garden cluster used in the gardenlogin kubeconfigs, empty disables the Gardenlogin authentication type")
 	flag.DurationVar(&expirationTime, "kubeconfig-expiration-time", defaultExpirationTime, "Dynamic kubeconfig expiration time")
+	flag.DurationVar(&minExpirationTime, "kubeconfig-min-expiration-time", defaultMinExpirationTime, "Minimal kubeconfig expiration time a GardenerCluster can request")
+	flag.DurationVar(&maxExpirationTime, "kubeconfig-max-expiration-time", 0, "Maximal kubeconfig expiration time a GardenerCluster can request, 0 limits it to the kubeconfig expiration time")
+	flag.DurationVar(&orphanedSecretsCollectionInterval, "orphaned-secrets-collection-interval", defaultOrphanedSecretsCollectionInterval, "Interval of removing kubeconfig secrets without GardenerCluster, 0 disables the collection")
+	flag.BoolVar(&orphanedSecretsCollectionDryRun, "orphaned-secrets-collection-dry-run", false, "Only report orphaned kubeconfig secrets instead of deleting them")
+	flag.DurationVar(&requeueInterval, "requeue-interval", 0, "Interval of the periodic GardenerCluster resync, capped by the kubeconfig rotation period, 0 resyncs once per rotation period")
+	flag.StringVar(&purposeRotationIntervals, "purpose-rotation-intervals", "", "Comma-separated default kubeconfig rotation intervals by shoot purpose, e.g. production=12h,evaluation=48h, capped by the rotation period")
+	flag.Float64Var(&credentialRotationFraction, "credential-rotation-fraction", defaultCredentialRotationFraction, "Fraction of the validity of the kubeconfig credential after which it is rotated, regardless of the rotation period, and the resync interval, 0 disables it")
+	flag.DurationVar(&startupSmearingWindow, "startup-smearing-window", 0, "Warm-up after the start the first reconciliations of the existing GardenerClusters are spread over, the ones whose kubeconfig expires first are reconciled first, 0 reconciles them all at once")
+	flag.Float64Var(&requeueJitter, "requeue-jitter", defaultRequeueJitter, "Fraction of the requeue interval the resync is randomly shortened by, spreading the resyncs of all GardenerClusters in time")
+	flag.DurationVar(&fetchBackoffBase, "kubeconfig-fetch-backoff-base", 30*time.Second, "Initial delay before retrying to get the kubeconfig from Gardener, doubled on every consecutive failure")
+	flag.DurationVar(&fetchBackoffMax, "kubeconfig-fetch-backoff-max", 30*time.Minute, "Maximal delay before retrying to get the kubeconfig from Gardener")
+	flag.Float64Var(&gardenerQPS, "gardener-qps", float64(rest.DefaultQPS), "Maximal rate of requests to the Gardener API server shared by all clients")
+	flag.IntVar(&gardenerBurst, "gardener-burst", rest.DefaultBurst, "Maximal burst of requests to the Gardener API server shared by all clients")
+	flag.IntVar(&gardenerMaxInFlight, "gardener-max-in-flight", 0, "Maximal number of requests to the Gardener API server in flight shared by all clients, independent of --max-concurrent-reconciles, 0 disables the limit")
+	flag.IntVar(&circuitBreakerThreshold, "gardener-circuit-breaker-threshold", 0, "Number of consecutive failures of Gardener after which the requests for kubeconfigs are paused for the cool-down, 0 disables the circuit breaker")
+	flag.DurationVar(&circuitBreakerCoolDown, "gardener-circuit-breaker-cool-down", 5*time.Minute, "Pause of the requests for kubeconfigs before a single request probes whether Gardener recovered")
+	flag.DurationVar(&shutdownGracePeriod, "shutdown-grace-period", defaultShutdownGracePeriod, "Time the kubeconfig rotations running on shutdown get to write the secrets, and the status, the pod's termination grace period has to exceed it")
+	flag.DurationVar(&gardenerKubeconfigRefreshInterval, "gardener-kubeconfig-refresh-interval", time.Minute, "Interval of checking the Gardener kubeconfig file for changes, e.g. a rotated token, and rebuilding the Gardener clients")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1, "Number of GardenerClusters reconciled in parallel")
+	flag.BoolVar(&kubeconfigValidation, "kubeconfig-validation", true, "Check the kubeconfig can be parsed before writing it to the secrets")
+	flag.DurationVar(&kubeconfigValidationDialTimeout, "kubeconfig-validation-dial-timeout", 0, "Timeout of connecting to the API server of the kubeconfig before writing it to the secrets, 0 disables the check")
+	flag.BoolVar(&shootAdmissionValidation, "shoot-admission-validation", false, "Reject the GardenerClusters referencing a shoot which doesn't exist in the Gardener project when they are created")
+	flag.DurationVar(&shootAdmissionValidationTimeout, "shoot-admission-validation-timeout", defaultShootAdmissionValidationTimeout, "Timeout of looking the shoot up in Gardener, the GardenerCluster is admitted with a warning when it expires")
+	flag.StringVar(&vaultAddress, "vault-address", "", "Address of the Vault server kubeconfigs can be written to, empty disables the Vault external store")
+	flag.StringVar(&vaultKubernetesAuthMount, "vault-kubernetes-auth-mount", secretstore.DefaultVaultKubernetesAuthMount, "Path of the Vault Kubernetes auth method, used when the VAULT_TOKEN environment variable is not set")
+	flag.StringVar(&vaultKubernetesAuthRole, "vault-kubernetes-auth-role", "infrastructure-manager", "Role the controller logs in to Vault with using the Kubernetes auth method")
+	flag.StringVar(&vaultMount, "vault-mount", secretstore.DefaultVaultMount, "Path of the only Vault KV version 2 secrets engine the clusters may write their kubeconfigs to")
+	flag.StringVar(&vaultPathPrefix, "vault-path-prefix", secretstore.DefaultVaultPathPrefix, "Path in the Vault secrets engine the kubeconfigs are written below, in <prefix>/<namespace>/<name>/ of the GardenerCluster")
+	flag.BoolVar(&awsSecretsManager, "aws-secrets-manager", false, "Enable writing kubeconfigs to AWS Secrets Manager, the credentials, and the region are taken from the environment, e.g. IRSA")
+	flag.BoolVar(&gcpSecretManager, "gcp-secret-manager", false, "Enable writing kubeconfigs to Google Secret Manager, the application default credentials are used, e.g. workload identity")
+	flag.DurationVar(&gcpSecretVersionGracePeriod, "gcp-secret-version-grace-period", 24*time.Hour, "Time after which superseded Google Secret Manager secret versions are disabled")
+	flag.StringVar(&encryptionProvider, "kubeconfig-encryption-provider", "", "Provider of the key the kubeconfigs are envelope-encrypted with when requested in the spec, one of aws-kms, gcp-kms, age, empty disables the encryption")
+	flag.StringVar(&encryptionKey, "kubeconfig-encryption-key", "", "Key the kubeconfigs are envelope-encrypted with, the AWS KMS key ID, the Cloud KMS key resource name, or the age recipient")
+	flag.StringVar(&sealedSecretsCertPath, "sealed-secrets-cert", "", "Path of the PEM encoded certificate of the sealed-secrets controller the SealedSecrets are sealed with, empty disables the SealedSecret format")
+	flag.BoolVar(&pushSecret, "push-secret", false, "Enable handing kubeconfigs over to the External Secrets Operator with PushSecrets")
+	flag.BoolVar(&azureKeyVault, "azure-key-vault", false, "Enable writing kubeconfigs to Azure Key Vault, the controller authenticates with its managed identity")
+	flag.StringVar(&azureKeyVaultDNSSuffix, "azure-key-vault-dns-suffix", secretstore.DefaultAzureKeyVaultDNSSuffix, "DNS suffix of the key vaults, differs in sovereign clouds")
+	flag.StringVar(&azureManagedIdentityClientID, "azure-managed-identity-client-id", "", "Client ID of the user-assigned managed identity used for Azure Key Vault, empty selects the system-assigned identity")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only log, and record in the GardenerCluster status the kubeconfig secret changes instead of applying them")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Serve the GardenerCluster, and Runtime webhooks, requires the serving certificate to be mounted")
+	flag.BoolVar(&webhookCertManager, "webhook-cert-manager", false, "Source the webhook serving certificate from a cert-manager Certificate created by the manager, instead of a mounted secret")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"), "Directory the webhook server reads the serving certificate from")
+	flag.StringVar(&webhookNamespace, "webhook-namespace", "kcp-system", "Namespace of the webhook service, and of the cert-manager resources")
+	flag.StringVar(&webhookNamePrefix, "webhook-name-prefix", "infrastructure-manager-", "Name prefix of the deployed webhook service, and webhook configurations")
+	flag.StringVar(&webhookCertIssuer, "webhook-cert-issuer", "", "Name of the cert-manager issuer of the webhook serving certificate, a self-signed issuer is created when empty")
+	flag.StringVar(&webhookCertIssuerKind, "webhook-cert-issuer-kind", "Issuer", "Kind of the cert-manager issuer of the webhook serving certificate, Issuer, or ClusterIssuer")
+
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "Host, and port of the OTLP gRPC collector the reconciliation traces are exported to, empty disables tracing")
+	flag.BoolVar(&otlpInsecure, "otlp-insecure", false, "Connect to the OTLP collector without TLS")
+	flag.Float64Var(&traceSamplingRatio, "trace-sampling-ratio", 1, "Fraction of the reconciliations traced")
+	flag.BoolVar(&runtimeProvisioning, "runtime-provisioning", false, "Create, update, and delete the Gardener shoots declared with Runtime objects")
+	flag.StringVar(&propagatedLabelPrefixes, "propagate-label-prefixes", "", "Comma-separated key prefixes of the GardenerCluster, and Runtime labels copied to the shoots, empty disables the propagation")
+	flag.StringVar(&propagatedAnnotationPrefixes, "propagate-annotation-prefixes", "", "Comma-separated key prefixes of the GardenerCluster, and Runtime annotations copied to the shoots, empty disables the propagation")
+	flag.StringVar(&auditLogPath, "audit-log-path", "", "Path of the file the creations, rotations, and deletions of kubeconfig secrets are appended to as JSON lines, empty disables the audit trail")
+
+	flag.StringVar(&crLabelSelector, "cr-label-selector", "", "Label selector of the GardenerClusters reconciled by this instance, e.g. for blue/green rollouts, or manual sharding, empty reconciles all GardenerClusters")
+	flag.IntVar(&namespaceRotationLimit, "namespace-rotation-limit", 0, "Maximal number of kubeconfig rotations in a namespace within the namespace rotation window, the rotations over the limit are delayed, 0 disables the limit")
+	flag.DurationVar(&namespaceRotationWindow, "namespace-rotation-window", time.Hour, "Sliding window the namespace rotation limit applies to")
+	flag.BoolVar(&priorityQueue, "priority-queue", false, "Reconcile the GardenerClusters with a higher spec.priority first when the work queue is deep")
+	flag.StringVar(&configName, "config-name", infrastructuremanagerv1.DefaultInfrastructureManagerConfigName, "Name of the InfrastructureManagerConfig overriding the flags at runtime, the flags apply while it does not exist")
+	flag.IntVar(&shardCount, "shards", 1, "Number of shards the GardenerClusters are split between, each shard is reconciled by its own active replica")
+	flag.IntVar(&shardID, "shard-id", -1, "Shard reconciled by this replica, read from the SHARD_ID environment variable, or the ordinal of the StatefulSet pod in POD_NAME when negative")
+	flag.StringVar(&secretNameTemplate, "secret-name-template", "", "Go template the kubeconfig secret names are rendered from when not specified in the GardenerCluster, e.g. kubeconfig-{{ .Shoot.Name }}, requires the webhooks")
+	flag.StringVar(&secretKeyTemplate, "secret-key-template", "", "Go template the kubeconfig secret keys are rendered from when not specified in the GardenerCluster, requires the webhooks, the key defaults to config when empty")
+	flag.BoolVar(&mockGardener, "mock-gardener", false, "Serve fake kubeconfigs, and shoot metadata from memory instead of requesting them from Gardener, to run the manager locally, e.g. on kind, or k3d, without a Gardener landscape")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma-separated namespaces the manager watches, and writes the kubeconfig secrets to, the RBAC can then be granted with RoleBindings in these namespaces only, empty watches all namespaces")
 
 	opts := zap.Options{
 		Development: true,
@@ -80,17 +269,30 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	logLevels := setupLogLevels(&opts)
 	logger := zap.New(zap.UseFlagOptions(&opts))
 
 	ctrl.SetLogger(logger)
 
+	shutdownTracing := func() {}
+	if otlpEndpoint != "" {
+		shutdownTracing = setupTracing(otlpEndpoint, otlpInsecure, traceSamplingRatio)
+	}
+
+	shard := setupShard(shardID, shardCount)
+
+	managerShutdownTimeout := shutdownGracePeriod + managerShutdownMargin
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443, //nolint:gomnd
+		CertDir:                webhookCertDir,
 		HealthProbeBindAddress: probeAddr,
+		Cache:                  cache.Options{Namespaces: splitList(watchNamespaces)},
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "f1c68560.kyma-project.io",
+		LeaderElectionID:       leaderElectionID(shard),
+		// the rotations running on shutdown are completed before the manager gives up on its runnables
+		GracefulShutdownTimeout: &managerShutdownTimeout,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -109,20 +311,257 @@ func main() {
 	}
 
 	gardenerNamespace := fmt.Sprintf("garden-%s", gardenerProjectName)
-	kubeconfigProvider, err := setupKubernetesKubeconfigProvider(gardenerKubeconfigPath, gardenerNamespace, expirationTime)
+	rateLimiter := gardener.NewRateLimiter(float32(gardenerQPS), gardenerBurst).WithMaxInFlight(gardenerMaxInFlight)
+
+	var backend gardenerBackend
+	if mockGardener {
+		if runtimeProvisioning {
+			setupLog.Error(errors.New("runtime provisioning requires Gardener"), "invalid configuration")
+			os.Exit(1)
+		}
+
+		setupLog.Info("Serving fake kubeconfigs from the mock Gardener, they don't grant access to any cluster")
+		backend = setupMockGardener(expirationTime)
+	} else {
+		if gardenerKubeconfigRefreshInterval <= 0 {
+			setupLog.Error(errors.Errorf("Gardener kubeconfig refresh interval %s must be positive", gardenerKubeconfigRefreshInterval), "invalid configuration")
+			os.Exit(1)
+		}
 
+		backend = setupGardener(mgr, gardenerKubeconfigPath, gardenerNamespace, rateLimiter, gardenerKubeconfigRefreshInterval, expirationTime, logger)
+	}
+
+	kubeconfigProvider := backend.kubeconfigProvider
+
+	if requeueJitter < 0 || requeueJitter >= 1 {
+		setupLog.Error(errors.Errorf("requeue jitter %v must be within [0, 1)", requeueJitter), "invalid configuration")
+		os.Exit(1)
+	}
+
+	if credentialRotationFraction < 0 || credentialRotationFraction >= 1 {
+		setupLog.Error(errors.Errorf("credential rotation fraction %v must be within [0, 1)", credentialRotationFraction), "invalid configuration")
+		os.Exit(1)
+	}
+
+	if maxExpirationTime == 0 {
+		maxExpirationTime = expirationTime
+	}
+
+	if minExpirationTime > maxExpirationTime {
+		setupLog.Error(errors.Errorf("minimal kubeconfig expiration time %s exceeds the maximal one %s", minExpirationTime, maxExpirationTime), "invalid configuration")
+		os.Exit(1)
+	}
+
+	rotationIntervals, err := parsePurposeRotationIntervals(purposeRotationIntervals)
 	if err != nil {
-		setupLog.Error(err, "unable to initialize kubeconfig provider", "controller", "GardenerCluster")
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
+	}
+
+	secretTemplate := infrastructuremanagerv1.SecretTemplate{Name: secretNameTemplate, Key: secretKeyTemplate}
+	for _, text := range []string{secretTemplate.Name, secretTemplate.Key} {
+		if _, err = infrastructuremanagerv1.ParseSecretTemplate(text); err != nil {
+			setupLog.Error(err, "invalid secret template")
+			os.Exit(1)
+		}
+	}
+
+	clusterSelector, err := labels.Parse(crLabelSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid GardenerCluster label selector")
 		os.Exit(1)
 	}
 
-	rotationPeriod := time.Duration(minimalRotationTimeRatio*expirationTime.Minutes()) * time.Minute
-	if err = (controller.NewGardenerClusterController(mgr, kubeconfigProvider, logger, rotationPeriod)).SetupWithManager(mgr); err != nil {
+	featureGates := featuregates.New(map[string]bool{
+		featuregates.KubeconfigValidation:     kubeconfigValidation,
+		featuregates.ShootMetadataPropagation: true,
+		featuregates.ShootAdmissionValidation: shootAdmissionValidation,
+	})
+
+	rotationPeriod := time.Duration(controller.MinimalRotationTimeRatio*expirationTime.Minutes()) * time.Minute
+	gardenerClusterController := controller.NewGardenerClusterController(mgr, kubeconfigProvider, logger.WithName("gardener-cluster-controller"), rotationPeriod).
+		WithRequeue(requeueInterval, requeueJitter).
+		WithCredentialRotation(credentialRotationFraction).
+		WithStartupSmearing(startupSmearingWindow).
+		WithPurposeRotationIntervals(rotationIntervals).
+		WithFetchBackoff(fetchBackoffBase, fetchBackoffMax).
+		WithMaxConcurrentReconciles(maxConcurrentReconciles).
+		WithDryRun(dryRun).
+		WithExpirationLimits(minExpirationTime, maxExpirationTime).
+		WithGardenlogin(gardenClusterIdentity, gardenerNamespace).
+		WithKubeconfigValidation(kubeconfigValidation, kubeconfigValidationDialTimeout).
+		WithGardenerCredentials(setupGardenerProjects(gardenerNamespace, rateLimiter, expirationTime)).
+		WithRemoteClusters(setupRemoteClusters()).
+		WithLabelSelector(clusterSelector).
+		WithShard(shard).
+		WithNamespaceRotationLimit(namespaceRotationLimit, namespaceRotationWindow).
+		WithCircuitBreaker(circuitBreakerThreshold, circuitBreakerCoolDown).
+		WithShutdownGracePeriod(shutdownGracePeriod).
+		WithPriorityQueue(priorityQueue).
+		WithFeatureGates(featureGates)
+	if vaultAddress != "" {
+		gardenerClusterController.WithExternalStore(infrastructuremanagerv1.ExternalStoreTypeVault, secretstore.NewVaultStore(secretstore.VaultConfig{
+			Address:                 vaultAddress,
+			Mount:                   vaultMount,
+			PathPrefix:              vaultPathPrefix,
+			Token:                   os.Getenv("VAULT_TOKEN"),
+			KubernetesAuthMount:     vaultKubernetesAuthMount,
+			KubernetesAuthRole:      vaultKubernetesAuthRole,
+			ServiceAccountTokenPath: secretstore.DefaultServiceAccountTokenPath,
+		}, &http.Client{Timeout: externalStoreRequestTimeout}))
+	}
+
+	if awsSecretsManager {
+		awsConfig, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			setupLog.Error(err, "unable to load AWS configuration")
+			os.Exit(1)
+		}
+
+		gardenerClusterController.WithExternalStore(infrastructuremanagerv1.ExternalStoreTypeAWSSecretsManager, secretstore.NewAWSSecretsManagerStore(awsConfig))
+	}
+
+	if gcpSecretManager {
+		gcpStore, err := secretstore.NewGCPSecretManagerStore(context.Background(), gcpSecretVersionGracePeriod)
+		if err != nil {
+			setupLog.Error(err, "unable to create Google Secret Manager store")
+			os.Exit(1)
+		}
+
+		gardenerClusterController.WithExternalStore(infrastructuremanagerv1.ExternalStoreTypeGCPSecretManager, gcpStore)
+	}
+
+	if azureKeyVault {
+		gardenerClusterController.WithExternalStore(infrastructuremanagerv1.ExternalStoreTypeAzureKeyVault, secretstore.NewAzureKeyVaultStore(secretstore.AzureKeyVaultConfig{
+			DNSSuffix:               azureKeyVaultDNSSuffix,
+			ManagedIdentityClientID: azureManagedIdentityClientID,
+		}, &http.Client{Timeout: externalStoreRequestTimeout}))
+	}
+
+	if encryptionProvider != "" {
+		keyWrapper, err := encryption.NewKeyWrapper(context.Background(), encryptionProvider, encryptionKey)
+		if err != nil {
+			setupLog.Error(err, "unable to configure kubeconfig encryption")
+			os.Exit(1)
+		}
+
+		gardenerClusterController.WithKubeconfigEncryption(encryption.NewEnvelope(keyWrapper))
+	}
+
+	if sealedSecretsCertPath != "" {
+		certificate, err := os.ReadFile(sealedSecretsCertPath)
+		if err != nil {
+			setupLog.Error(err, "unable to read sealed-secrets certificate")
+			os.Exit(1)
+		}
+
+		publicKey, err := sealing.ParseCertificate(certificate)
+		if err != nil {
+			setupLog.Error(err, "unable to parse sealed-secrets certificate")
+			os.Exit(1)
+		}
+
+		gardenerClusterController.WithSealedSecrets(publicKey)
+	}
+
+	var auditLog controller.AuditLog
+	if auditLogPath != "" {
+		auditLog = setupAuditLog(auditLogPath)
+		gardenerClusterController.WithAuditLog(auditLog)
+	}
+
+	metadataPolicy := shoot.MetadataPolicy{
+		LabelPrefixes:      splitList(propagatedLabelPrefixes),
+		AnnotationPrefixes: splitList(propagatedAnnotationPrefixes),
+	}
+	gardenerClusterController.WithMetadataPropagation(metadataPolicy, backend.shootPatcher)
+
+	if pushSecret {
+		gardenerClusterController.WithExternalStore(infrastructuremanagerv1.ExternalStoreTypePushSecret, secretstore.NewPushSecretStore(mgr.GetClient()))
+	}
+
+	if err = gardenerClusterController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GardenerCluster")
 		os.Exit(1)
 	}
+
+	configController := controller.NewInfrastructureManagerConfigController(mgr, configName, backend.configurer, gardenerClusterController, featureGates, logger.WithName("infrastructuremanagerconfig-controller")).
+		WithShard(shard)
+	if err = configController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "InfrastructureManagerConfig")
+		os.Exit(1)
+	}
+
+	// the resources other than the GardenerClusters aren't split between the shards, they are reconciled by the first one
+	if shard.Primary() {
+		if err = (controller.NewKubeconfigRequestController(mgr, kubeconfigProvider, logger.WithName("kubeconfig-request-controller"), expirationTime)).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "KubeconfigRequest")
+			os.Exit(1)
+		}
+
+		if err = (controller.NewSecretMigrationController(mgr, logger.WithName("secret-migration-controller"))).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "SecretMigration")
+			os.Exit(1)
+		}
+
+		if runtimeProvisioning {
+			runtimeController := controller.NewRuntimeController(mgr, backend.shootManager, gardenerNamespace, logger.WithName("runtime-controller")).
+				WithMetadataPropagation(metadataPolicy)
+			if err = runtimeController.SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Runtime")
+				os.Exit(1)
+			}
+		}
+	}
+
+	// the secrets of all shards are collected by the first one
+	if orphanedSecretsCollectionInterval > 0 && shard.Primary() {
+		collector := controller.NewOrphanedSecretsCollector(mgr, logger.WithName("orphaned-secrets-collector"), orphanedSecretsCollectionInterval, orphanedSecretsCollectionDryRun || dryRun).
+			WithAuditLog(auditLog)
+		if err = mgr.Add(collector); err != nil {
+			setupLog.Error(err, "unable to set up orphaned secrets collector")
+			os.Exit(1)
+		}
+	}
+
+	if enableWebhooks && webhookCertManager {
+		setupWebhookCertManager(mgr, webhookcert.Config{
+			Namespace:                       webhookNamespace,
+			ServiceName:                     webhookNamePrefix + "webhook-service",
+			CertificateName:                 webhookNamePrefix + "serving-cert",
+			SecretName:                      webhookNamePrefix + "webhook-server-cert",
+			IssuerName:                      webhookNamePrefix + "selfsigned-issuer",
+			CertDir:                         webhookCertDir,
+			ValidatingWebhookConfigurations: []string{webhookNamePrefix + "validating-webhook-configuration"},
+			MutatingWebhookConfigurations:   []string{webhookNamePrefix + "mutating-webhook-configuration"},
+			ConversionCRDs:                  []string{"gardenerclusters.infrastructuremanager.kyma-project.io"},
+		}, webhookCertIssuer, webhookCertIssuerKind, logger.WithName("webhook-cert-manager"))
+	}
+
+	if enableWebhooks {
+		if err = (&infrastructuremanagerv1.GardenerCluster{}).SetupWebhookWithManager(mgr, secretTemplate, infrastructuremanagerv1.ShootAdmission{
+			Shoots:  backend.shoots,
+			Enabled: func() bool { return featureGates.Enabled(featuregates.ShootAdmissionValidation) },
+			Timeout: shootAdmissionValidationTimeout,
+		}); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GardenerCluster")
+			os.Exit(1)
+		}
+
+		if err = (&infrastructuremanagerv1.Runtime{}).SetupWebhookWithManager(mgr, backend.landscape); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Runtime")
+			os.Exit(1)
+		}
+
+		mgr.GetWebhookServer().Register(quota.WebhookPath, &webhook.Admission{Handler: quota.NewValidator(mgr.GetAPIReader())})
+	}
 	//+kubebuilder:scaffold:builder
 
+	if err = mgr.AddMetricsExtraHandler(logLevelPath, logLevels); err != nil {
+		setupLog.Error(err, "unable to set up log level endpoint")
+		os.Exit(1)
+	}
+
 	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -131,41 +570,247 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err = mgr.AddReadyzCheck("gardener", backend.readyCheck); err != nil {
+		setupLog.Error(err, "unable to set up Gardener ready check")
+		os.Exit(1)
+	}
 
-	setupLog.Info("Starting Manager", "kubeconfigExpirationTime", expirationTime, "kubeconfigRotationPeriod", rotationPeriod)
+	setupLog.Info("Starting Manager", "kubeconfigExpirationTime", expirationTime, "kubeconfigRotationPeriod", rotationPeriod, "watchNamespaces", splitList(watchNamespaces))
 
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
+		shutdownTracing()
 		os.Exit(1)
 	}
+
+	shutdownTracing()
 }
 
-func setupKubernetesKubeconfigProvider(kubeconfigPath string, namespace string, expirationTime time.Duration) (gardener.KubeconfigProvider, error) {
-	restConfig, err := gardener.NewRestConfigFromFile(kubeconfigPath)
+// setupLogLevels makes the levels set with the zap flags changeable at runtime.
+func setupLogLevels(opts *zap.Options) *loglevel.Levels {
+	defaultLevel := zapcore.InfoLevel
+	if opts.Development {
+		defaultLevel = zapcore.DebugLevel
+	}
+
+	logLevels := loglevel.NewLevels(loglevel.InitialLevel(opts.Level, defaultLevel))
+
+	var filter uberzap.Option
+	opts.Level, filter = logLevels.ZapOptions()
+	opts.ZapOpts = append(opts.ZapOpts, filter)
+
+	return logLevels
+}
+
+// setupAuditLog opens the audit trail, the records name the pod of the controller as the actor.
+func setupAuditLog(path string) *audit.Log {
+	file, err := audit.OpenFile(path)
 	if err != nil {
-		return gardener.KubeconfigProvider{}, err
+		setupLog.Error(err, "unable to open audit log")
+		os.Exit(1)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		setupLog.Error(err, "unable to determine the audit log actor")
+		os.Exit(1)
+	}
+
+	return audit.NewLog(file, fmt.Sprintf("%s/%s", auditLogActor, hostname))
+}
+
+// splitList parses a comma-separated list, e.g. of metadata key prefixes, or namespaces. Empty entries are skipped.
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// parsePurposeRotationIntervals parses the comma-separated purpose=interval pairs.
+func parsePurposeRotationIntervals(value string) (map[infrastructuremanagerv1.Purpose]time.Duration, error) {
+	intervals := map[infrastructuremanagerv1.Purpose]time.Duration{}
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		purpose, rawInterval, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, errors.Errorf("rotation interval `%s` is not a purpose=interval pair", pair)
+		}
+
+		switch infrastructuremanagerv1.Purpose(purpose) {
+		case infrastructuremanagerv1.PurposeEvaluation, infrastructuremanagerv1.PurposeTesting, infrastructuremanagerv1.PurposeDevelopment, infrastructuremanagerv1.PurposeProduction:
+		default:
+			return nil, errors.Errorf("unknown shoot purpose `%s`", purpose)
+		}
+
+		interval, err := time.ParseDuration(rawInterval)
+		if err != nil || interval <= 0 {
+			return nil, errors.Errorf("rotation interval `%s` of purpose `%s` must be a positive duration", rawInterval, purpose)
+		}
+
+		intervals[infrastructuremanagerv1.Purpose(purpose)] = interval
 	}
 
-	gardenerClientSet, err := gardener_apis.NewForConfig(restConfig)
+	return intervals, nil
+}
+
+// setupTracing exports the reconciliation traces, the returned function flushes the spans not exported yet.
+func setupTracing(endpoint string, insecure bool, samplingRatio float64) func() {
+	shutdown, err := tracing.Setup(context.Background(), endpoint, insecure, samplingRatio)
 	if err != nil {
-		return gardener.KubeconfigProvider{}, err
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
 	}
 
-	gardenerClient, err := client.New(restConfig, client.Options{})
+	setupLog.Info("Exporting traces", "endpoint", endpoint, "samplingRatio", samplingRatio)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := shutdown(ctx); err != nil {
+			setupLog.Error(err, "failed to flush traces")
+		}
+	}
+}
+
+// setupWebhookCertManager waits for the serving certificate before the webhook server starts, and keeps it renewed afterwards.
+func setupWebhookCertManager(mgr ctrl.Manager, config webhookcert.Config, issuer, issuerKind string, logger logr.Logger) {
+	if issuer != "" {
+		config.IssuerName = issuer
+		config.IssuerKind = issuerKind
+	}
+
+	// the cache of the manager is not started yet, and would watch the webhook configurations cluster-wide
+	certClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
 	if err != nil {
-		return gardener.KubeconfigProvider{}, err
+		setupLog.Error(err, "unable to create client for webhook certificate")
+		os.Exit(1)
 	}
 
-	shootClient := gardenerClientSet.Shoots(namespace)
-	dynamicKubeconfigAPI := gardenerClient.SubResource("adminkubeconfig")
+	certManager := webhookcert.NewCertManager(certClient, config, webhookCertReconcileInterval, logger)
 
-	err = v1beta1.AddToScheme(gardenerClient.Scheme())
+	err = certManager.Bootstrap(context.Background(), webhookCertIssueTimeout)
 	if err != nil {
-		return gardener.KubeconfigProvider{}, errors.Wrap(err, "failed to register Gardener schema")
+		setupLog.Error(err, "unable to obtain webhook serving certificate")
+		os.Exit(1)
 	}
 
-	return gardener.NewKubeconfigProvider(shootClient,
-		dynamicKubeconfigAPI,
+	if err = mgr.Add(certManager); err != nil {
+		setupLog.Error(err, "unable to set up webhook certificate renewal")
+		os.Exit(1)
+	}
+}
+
+// gardenerBackend is what the controllers, and the webhooks use of Gardener. The mock Gardener leaves the clients of
+// the shoots, the Gardener settings, and the landscape nil, which disables the features using them.
+type gardenerBackend struct {
+	kubeconfigProvider controller.KubeconfigProvider
+	shoots             infrastructuremanagerv1.ShootLookup
+	shootPatcher       controller.ShootPatcher
+	shootManager       controller.ShootManager
+	configurer         controller.GardenerConfigurer
+	landscape          infrastructuremanagerv1.Landscape
+	readyCheck         healthz.Checker
+}
+
+// setupGardener builds the clients of the Gardener project, refreshed by the manager when its kubeconfig changes.
+func setupGardener(mgr ctrl.Manager, kubeconfigPath, namespace string, rateLimiter *gardener.RateLimiter, refreshInterval, expirationTime time.Duration, logger logr.Logger) gardenerBackend {
+	clientCache, err := gardener.NewClientCache(kubeconfigPath, namespace, rateLimiter, refreshInterval, logger.WithName("gardener-client-cache"))
+	if err != nil {
+		setupLog.Error(err, "unable to initialize kubeconfig provider", "controller", "GardenerCluster")
+		os.Exit(1)
+	}
+
+	if err = mgr.Add(clientCache); err != nil {
+		setupLog.Error(err, "unable to set up Gardener client refresh")
+		os.Exit(1)
+	}
+
+	return gardenerBackend{
+		kubeconfigProvider: setupKubernetesKubeconfigProvider(clientCache, namespace, expirationTime),
+		shoots:             clientCache,
+		shootPatcher:       clientCache,
+		shootManager:       clientCache,
+		configurer:         clientCache,
+		landscape:          clientCache,
+		readyCheck:         clientCache.Check,
+	}
+}
+
+// setupMockGardener serves the kubeconfigs, and the shoots from memory, any shoot is created on its first request.
+func setupMockGardener(expirationTime time.Duration) gardenerBackend {
+	mock := fake.New().WithTTL(expirationTime).WithShootsOnDemand()
+
+	return gardenerBackend{
+		kubeconfigProvider: mock,
+		shoots:             mock,
+		readyCheck:         healthz.Ping,
+	}
+}
+
+func setupKubernetesKubeconfigProvider(clientCache *gardener.ClientCache, namespace string, expirationTime time.Duration) gardener.KubeconfigProvider {
+	return gardener.NewKubeconfigProvider(clientCache,
+		clientCache,
 		namespace,
-		int64(expirationTime.Seconds())), nil
+		int64(expirationTime.Seconds()))
+}
+
+// setupGardenerProjects builds the kubeconfig providers of the Gardener projects selected by the credentials of the
+// GardenerClusters, sharing the rate limit of the project of the controller.
+func setupGardenerProjects(namespace string, rateLimiter *gardener.RateLimiter, expirationTime time.Duration) controller.GardenerProjects {
+	projects := gardener.NewProjectClients(namespace, rateLimiter, int64(expirationTime.Seconds()))
+
+	return func(key string, rawKubeconfig []byte) (controller.KubeconfigProvider, error) {
+		return projects.KubeconfigProvider(key, rawKubeconfig)
+	}
+}
+
+// setupRemoteClusters builds the clients of the clusters the kubeconfig secrets are delivered to.
+func setupRemoteClusters() controller.RemoteClusters {
+	clients := remotecluster.NewClients(scheme)
+
+	return func(key string, rawKubeconfig []byte) (client.Client, error) {
+		return clients.Client(key, rawKubeconfig)
+	}
+}
+
+// setupShard returns the shard of the replica, the shard ID is read from the environment when not set with the flag.
+func setupShard(shardID, shardCount int) sharding.Shard {
+	if shardCount > 1 && shardID < 0 {
+		var err error
+
+		shardID, err = sharding.IDFromEnvironment()
+		if err != nil {
+			setupLog.Error(err, "unable to determine the shard ID")
+			os.Exit(1)
+		}
+	}
+
+	shard, err := sharding.New(max(shardID, 0), shardCount)
+	if err != nil {
+		setupLog.Error(err, "invalid shard")
+		os.Exit(1)
+	}
+
+	return shard
+}
+
+// leaderElectionID returns the ID of the lease of the shard, the replicas of a shard elect their own leader, so the
+// shards are reconciled in parallel.
+func leaderElectionID(shard sharding.Shard) string {
+	if !shard.Sharded() {
+		return "f1c68560.kyma-project.io"
+	}
+
+	return fmt.Sprintf("f1c68560-shard-%d.kyma-project.io", shard.ID)
 }
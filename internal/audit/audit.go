@@ -0,0 +1,121 @@
+// Package audit writes an append-only trail of the kubeconfig secret changes as JSON lines, for compliance reviews.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Action string
+
+const (
+	ActionCreated Action = "Created"
+	ActionRotated Action = "Rotated"
+	ActionDeleted Action = "Deleted"
+)
+
+// Trigger tells what caused the change of the secret.
+type Trigger string
+
+const (
+	// TriggerSecretMissing is a secret created because it did not exist yet.
+	TriggerSecretMissing Trigger = "SecretMissing"
+	// TriggerRotationDue is a rotation scheduled by the rotation period.
+	TriggerRotationDue Trigger = "RotationDue"
+	// TriggerCredentialExpiring is a rotation scheduled by the fraction of the validity of the credential passed.
+	TriggerCredentialExpiring Trigger = "CredentialExpiring"
+	// TriggerForceRotation is a rotation requested with the force rotation annotation.
+	TriggerForceRotation Trigger = "ForceRotation"
+	// TriggerDrift is a secret restored after another actor modified it.
+	TriggerDrift Trigger = "Drift"
+	// TriggerSpecChange is a rewrite required by a change of the GardenerCluster spec, e.g. enabled encryption.
+	TriggerSpecChange Trigger = "SpecChange"
+	// TriggerShootRecreated is a rotation replacing the kubeconfig of a shoot deleted, and recreated with the same name.
+	TriggerShootRecreated Trigger = "ShootRecreated"
+	// TriggerClusterDeleted is a secret deleted together with its GardenerCluster.
+	TriggerClusterDeleted Trigger = "ClusterDeleted"
+	// TriggerVersionPruned is an outdated secret version deleted in immutable mode.
+	TriggerVersionPruned Trigger = "VersionPruned"
+	// TriggerSecretRelocated is a secret deleted after its target moved, and the kubeconfig was written to the new location.
+	TriggerSecretRelocated Trigger = "SecretRelocated"
+	// TriggerOrphaned is a secret deleted because its GardenerCluster no longer exists.
+	TriggerOrphaned Trigger = "Orphaned"
+)
+
+// Record is a single entry of the audit trail.
+type Record struct {
+	Time             time.Time `json:"time"`
+	Action           Action    `json:"action"`
+	Trigger          Trigger   `json:"trigger"`
+	Actor            string    `json:"actor"`
+	Cluster          string    `json:"cluster,omitempty"`
+	ClusterNamespace string    `json:"clusterNamespace,omitempty"`
+	Shoot            string    `json:"shoot,omitempty"`
+	SecretName       string    `json:"secretName"`
+	SecretNamespace  string    `json:"secretNamespace"`
+	// Fingerprint identifies the written kubeconfig without revealing it, it is empty for deletions.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Log appends records to a writer, one JSON document per line. It is safe for concurrent use.
+type Log struct {
+	mu     sync.Mutex
+	writer io.Writer
+	actor  string
+	now    func() time.Time
+}
+
+// NewLog returns a log writing records on behalf of the given actor, e.g. the service account of the controller.
+func NewLog(writer io.Writer, actor string) *Log {
+	return &Log{
+		writer: writer,
+		actor:  actor,
+		now:    time.Now,
+	}
+}
+
+// OpenFile opens the file in append-only mode, creating it if needed. Existing records are never rewritten.
+func OpenFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gomnd
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open audit log %s", path)
+	}
+
+	return file, nil
+}
+
+// Write appends the record, the time, and the actor are set by the log.
+func (log *Log) Write(record Record) error {
+	record.Time = log.now().UTC()
+	record.Actor = log.actor
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode audit record")
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	// a single write keeps concurrent writers from interleaving the lines
+	_, err = log.writer.Write(append(line, '\n'))
+	if err != nil {
+		return errors.Wrap(err, "failed to write audit record")
+	}
+
+	return nil
+}
+
+// Fingerprint returns the SHA-256 digest of the kubeconfig.
+func Fingerprint(kubeconfig string) string {
+	digest := sha256.Sum256([]byte(kubeconfig))
+
+	return "sha256:" + hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	t.Run("should append records as JSON lines", func(t *testing.T) {
+		// given
+		var buffer bytes.Buffer
+		log := NewLog(&buffer, "infrastructure-manager/pod")
+		log.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+		// when
+		err := log.Write(Record{
+			Action:          ActionRotated,
+			Trigger:         TriggerRotationDue,
+			Cluster:         "cluster",
+			Shoot:           "shoot",
+			SecretName:      "kubeconfig-cluster",
+			SecretNamespace: "kcp-system",
+			Fingerprint:     Fingerprint("kubeconfig"),
+		})
+		require.NoError(t, err)
+
+		err = log.Write(Record{Action: ActionDeleted, Trigger: TriggerOrphaned, SecretName: "orphan", SecretNamespace: "kcp-system"})
+		require.NoError(t, err)
+
+		// then
+		lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+
+		var record Record
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+		assert.Equal(t, ActionRotated, record.Action)
+		assert.Equal(t, TriggerRotationDue, record.Trigger)
+		assert.Equal(t, "infrastructure-manager/pod", record.Actor)
+		assert.Equal(t, "2024-01-02T03:04:05Z", record.Time.Format(time.RFC3339))
+		assert.Equal(t, Fingerprint("kubeconfig"), record.Fingerprint)
+		assert.NotContains(t, lines[1], "fingerprint")
+	})
+
+	t.Run("should not interleave concurrent records", func(t *testing.T) {
+		// given
+		var buffer bytes.Buffer
+		log := NewLog(&buffer, "infrastructure-manager")
+
+		// when
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				_ = log.Write(Record{Action: ActionCreated, SecretName: "secret", SecretNamespace: "kcp-system"})
+			}()
+		}
+
+		wg.Wait()
+
+		// then
+		for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n") {
+			assert.True(t, json.Valid([]byte(line)))
+		}
+	})
+}
+
+func TestOpenFile(t *testing.T) {
+	t.Run("should keep existing records", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "audit.log")
+		require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+
+		// when
+		file, err := OpenFile(path)
+		require.NoError(t, err)
+
+		err = NewLog(file, "infrastructure-manager").Write(Record{Action: ActionCreated})
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		// then
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), "{}\n{"))
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", Fingerprint(""))
+	assert.NotEqual(t, Fingerprint("kubeconfig1"), Fingerprint("kubeconfig2"))
+}
@@ -0,0 +1,152 @@
+// Package cli implements the commands of the kubectl plugin of the infrastructure manager, run as `kubectl im`.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// command is a subcommand of the plugin, its setup registers the flags of the command, and returns the function running
+// it once the flags are parsed.
+type command struct {
+	usage       string
+	description string
+	setup       func(flags *pflag.FlagSet) runFunc
+}
+
+// runFunc runs a command with the arguments left after parsing the flags.
+type runFunc func(ctx context.Context, env *environment, args []string) error
+
+// commands are the subcommands of the plugin by their name.
+var commands = map[string]command{ //nolint:gochecknoglobals
+	"diagnose":       diagnoseCommand,
+	"get-kubeconfig": getKubeconfigCommand,
+	"rotate":         rotateCommand,
+	"status":         statusCommand,
+}
+
+// environment is the management cluster the commands run against, and the output of the commands.
+type environment struct {
+	client      client.Client
+	clientset   kubernetes.Interface
+	namespace   string
+	pathOptions *clientcmd.PathOptions
+	out         io.Writer
+}
+
+// Run runs the subcommand selected by the first argument.
+func Run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		printUsage(out)
+
+		return nil
+	}
+
+	cmd, found := commands[args[0]]
+	if !found {
+		printUsage(out)
+
+		return errors.Errorf("unknown command %q", args[0])
+	}
+
+	flags := pflag.NewFlagSet("kubectl im "+args[0], pflag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() {
+		fmt.Fprintf(out, "Usage: kubectl im %s\n\n%s\n\nFlags:\n%s", cmd.usage, cmd.description, flags.FlagUsages())
+	}
+
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	overrides := &clientcmd.ConfigOverrides{}
+	flags.StringVar(&pathOptions.LoadingRules.ExplicitPath, "kubeconfig", "", "Path to the kubeconfig of the management cluster")
+	flags.StringVar(&overrides.CurrentContext, "context", "", "Context of the management cluster in the kubeconfig")
+	flags.StringVarP(&overrides.Context.Namespace, "namespace", "n", "", "Namespace of the GardenerClusters, the namespace of the context by default")
+	run := cmd.setup(flags)
+
+	err := flags.Parse(args[1:])
+	if err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return nil
+		}
+
+		return err
+	}
+
+	env, err := newEnvironment(pathOptions, overrides, out)
+	if err != nil {
+		return err
+	}
+
+	return run(ctx, env, flags.Args())
+}
+
+func newEnvironment(pathOptions *clientcmd.PathOptions, overrides *clientcmd.ConfigOverrides, out io.Writer) (*environment, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(pathOptions.LoadingRules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the kubeconfig of the management cluster")
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the namespace from the kubeconfig")
+	}
+
+	scheme := runtime.NewScheme()
+	if err = clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err = imv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the client of the management cluster")
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the clientset of the management cluster")
+	}
+
+	return &environment{client: k8sClient, clientset: clientset, namespace: namespace, pathOptions: pathOptions, out: out}, nil
+}
+
+func printUsage(out io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintf(out, "kubectl im manages the GardenerClusters of the infrastructure manager.\n\nCommands:\n")
+
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-16s %s\n", name, commands[name].description)
+	}
+
+	fmt.Fprintf(out, "\nRun `kubectl im <command> --help` for the flags of a command.\n")
+}
+
+// exactArgs fails unless the command got the number of arguments.
+func exactArgs(args []string, count int, usage string) error {
+	if len(args) != count {
+		return errors.Errorf("expected %d argument(s), usage: kubectl im %s", count, usage)
+	}
+
+	return nil
+}
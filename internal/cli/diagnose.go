@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// managerPodSelector selects the pods of the manager deployment.
+	managerPodSelector = "control-plane=controller-manager"
+
+	// maxLogLineLength is the length of the longest log line read, the stack traces of the errors are on one line.
+	maxLogLineLength = 1 << 20
+
+	// the metadata the controller writes to the kubeconfig secrets
+	kubeconfigHashAnnotation     = "operator.kyma-project.io/kubeconfig-hash"
+	lastKubeconfigSyncAnnotation = "operator.kyma-project.io/last-sync"
+	managedByLabel               = "operator.kyma-project.io/managed-by"
+	clusterNameLabel             = "operator.kyma-project.io/cluster-name"
+)
+
+//nolint:gochecknoglobals
+var diagnoseCommand = command{
+	usage:       "diagnose <gardenercluster> [--output <file>] [--manager-namespace <namespace>] [--log-lines <lines>]",
+	description: "Collects the cluster, its events, the manager logs, and a check of its secrets into an archive for support tickets.",
+	setup: func(flags *pflag.FlagSet) runFunc {
+		var options diagnoseOptions
+
+		flags.StringVarP(&options.output, "output", "o", "", "Archive the diagnostics are written to, <gardenercluster>-diagnostics.tar.gz by default, - for the standard output")
+		flags.StringVar(&options.managerNamespace, "manager-namespace", "kcp-system", "Namespace the manager runs in")
+		flags.Int64Var(&options.logLines, "log-lines", 5000, "Number of the last log lines of each manager pod searched for the cluster") //nolint:gomnd
+
+		return func(ctx context.Context, env *environment, args []string) error {
+			err := exactArgs(args, 1, "diagnose <gardenercluster>")
+			if err != nil {
+				return err
+			}
+
+			return diagnose(ctx, env, args[0], options)
+		}
+	},
+}
+
+type diagnoseOptions struct {
+	output           string
+	managerNamespace string
+	logLines         int64
+}
+
+// archiveFile is a file of the diagnostics archive.
+type archiveFile struct {
+	name    string
+	content []byte
+}
+
+// diagnose writes the diagnostics of the cluster to the archive. The parts which can't be collected hold the error
+// instead, so the archive is written as long as the cluster can be read. The kubeconfigs are never included.
+func diagnose(ctx context.Context, env *environment, name string, options diagnoseOptions) error {
+	key := client.ObjectKey{Name: name, Namespace: env.namespace}
+
+	var cluster imv1.GardenerCluster
+
+	err := env.client.Get(ctx, key, &cluster)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the GardenerCluster %s", key)
+	}
+
+	cluster.ManagedFields = nil
+
+	clusterYAML, err := yaml.Marshal(&cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the GardenerCluster")
+	}
+
+	files := []archiveFile{
+		{name: "gardenercluster.yaml", content: clusterYAML},
+		{name: "conditions.txt", content: formatConditions(&cluster)},
+		{name: "events.txt", content: collect(func() ([]byte, error) { return clusterEvents(ctx, env.client, &cluster) })},
+		{name: "secrets.txt", content: checkSecrets(ctx, env.client, &cluster)},
+		{name: "manager.log", content: collect(func() ([]byte, error) {
+			return managerLogs(ctx, env.clientset, options.managerNamespace, options.logLines, &cluster)
+		})},
+	}
+
+	output := options.output
+	if output == "" {
+		output = name + "-diagnostics.tar.gz"
+	}
+
+	if output == stdout {
+		return writeArchive(env.out, files, time.Now())
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", output)
+	}
+
+	err = writeArchive(file, files, time.Now())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", output)
+	}
+
+	fmt.Fprintf(env.out, "Diagnostics of %s written to %s.\n", key, output)
+
+	return nil
+}
+
+// collect returns the collected content, or the error preventing its collection.
+func collect(collector func() ([]byte, error)) []byte {
+	content, err := collector()
+	if err != nil {
+		return []byte(fmt.Sprintf("Failed to collect: %s\n", err))
+	}
+
+	return content
+}
+
+func formatConditions(cluster *imv1.GardenerCluster) []byte {
+	var buffer bytes.Buffer
+
+	fmt.Fprintf(&buffer, "State: %s\n\n", valueOrNone(string(cluster.Status.State)))
+
+	table := tabwriter.NewWriter(&buffer, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(table, "TYPE\tSTATUS\tREASON\tLAST TRANSITION\tMESSAGE")
+
+	for _, condition := range cluster.Status.Conditions {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason,
+			condition.LastTransitionTime.UTC().Format(time.RFC3339), condition.Message)
+	}
+
+	_ = table.Flush()
+
+	return buffer.Bytes()
+}
+
+// clusterEvents returns the events of the cluster, the oldest first.
+func clusterEvents(ctx context.Context, k8sClient client.Client, cluster *imv1.GardenerCluster) ([]byte, error) {
+	var events corev1.EventList
+
+	err := k8sClient.List(ctx, &events, client.InNamespace(cluster.Namespace))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list events")
+	}
+
+	var clusterEvents []corev1.Event
+
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind == "GardenerCluster" && event.InvolvedObject.Name == cluster.Name {
+			clusterEvents = append(clusterEvents, event)
+		}
+	}
+
+	sort.Slice(clusterEvents, func(i, j int) bool {
+		return eventTime(clusterEvents[i]).Before(eventTime(clusterEvents[j]))
+	})
+
+	var buffer bytes.Buffer
+
+	table := tabwriter.NewWriter(&buffer, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(table, "LAST SEEN\tTYPE\tREASON\tCOUNT\tMESSAGE")
+
+	for _, event := range clusterEvents {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%s\n", eventTime(event).UTC().Format(time.RFC3339), event.Type, event.Reason, event.Count, event.Message)
+	}
+
+	err = table.Flush()
+
+	return buffer.Bytes(), err
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+
+	return event.EventTime.Time
+}
+
+// checkSecrets reports the problems of the secrets of the cluster, without their content.
+func checkSecrets(ctx context.Context, k8sClient client.Client, cluster *imv1.GardenerCluster) []byte {
+	var buffer bytes.Buffer
+
+	for _, target := range cluster.Spec.Kubeconfig.Targets() {
+		fmt.Fprintf(&buffer, "Secret %s/%s, key %s:\n", target.Namespace, target.Name, target.Key)
+
+		for _, finding := range checkSecret(ctx, k8sClient, cluster, target) {
+			fmt.Fprintf(&buffer, "  - %s\n", finding)
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+func checkSecret(ctx context.Context, k8sClient client.Client, cluster *imv1.GardenerCluster, target imv1.Secret) []string {
+	switch {
+	case target.Remote():
+		return []string{fmt.Sprintf("delivered to the cluster of the secret %s, not checked", target.ClusterRef.Name)}
+	case cluster.Spec.Kubeconfig.SecretFormat == imv1.SecretFormatSealedSecret:
+		return []string{"stored as a SealedSecret, not checked"}
+	}
+
+	secretName := target.Name
+	if cluster.Spec.Kubeconfig.Immutable {
+		secretName = currentSecretName(cluster, target)
+		if secretName == "" {
+			return []string{"ERROR: no current version in the status"}
+		}
+	}
+
+	var secret corev1.Secret
+
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: secretName, Namespace: target.Namespace}, &secret)
+	if err != nil {
+		return []string{fmt.Sprintf("ERROR: failed to get secret %s: %s", secretName, err)}
+	}
+
+	findings := []string{fmt.Sprintf("secret %s exists, created %s", secretName, secret.CreationTimestamp.UTC().Format(time.RFC3339))}
+
+	if secret.Labels[managedByLabel] == "" {
+		findings = append(findings, "ERROR: not managed by the manager, the "+managedByLabel+" label is missing")
+	} else if owner := secret.Labels[clusterNameLabel]; owner != "" && owner != cluster.Name {
+		findings = append(findings, fmt.Sprintf("ERROR: managed for the GardenerCluster %s", owner))
+	}
+
+	if lastSync, found := secret.Annotations[lastKubeconfigSyncAnnotation]; found {
+		findings = append(findings, "last synced "+lastSync)
+	}
+
+	kubeconfig, found := secret.Data[target.Key]
+	if !found {
+		return append(findings, "ERROR: the key "+target.Key+" is missing")
+	}
+
+	if expectedHash, found := secret.Annotations[kubeconfigHashAnnotation]; found {
+		hash := sha256.Sum256(kubeconfig)
+		if hex.EncodeToString(hash[:]) != expectedHash {
+			findings = append(findings, "ERROR: the kubeconfig was modified after the manager wrote it")
+		}
+	}
+
+	if cluster.Spec.Kubeconfig.Encrypted {
+		return append(findings, "kubeconfig is encrypted, not parsed")
+	}
+
+	return append(findings, checkKubeconfig(kubeconfig))
+}
+
+// checkKubeconfig reports whether the kubeconfig can be used, and the server it connects to.
+func checkKubeconfig(rawKubeconfig []byte) string {
+	kubeconfig, err := clientcmd.Load(rawKubeconfig)
+	if err != nil {
+		return fmt.Sprintf("ERROR: failed to parse the kubeconfig: %s", err)
+	}
+
+	kubeconfigContext, found := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !found {
+		return fmt.Sprintf("ERROR: current context %q not found in the kubeconfig", kubeconfig.CurrentContext)
+	}
+
+	cluster, found := kubeconfig.Clusters[kubeconfigContext.Cluster]
+	if !found {
+		return fmt.Sprintf("ERROR: cluster %q not found in the kubeconfig", kubeconfigContext.Cluster)
+	}
+
+	return "kubeconfig is valid, server " + cluster.Server
+}
+
+// managerLogs returns the lines of the manager logs mentioning the cluster.
+func managerLogs(ctx context.Context, clientset kubernetes.Interface, namespace string, tailLines int64, cluster *imv1.GardenerCluster) ([]byte, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: managerPodSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the manager pods")
+	}
+
+	var buffer bytes.Buffer
+
+	for _, pod := range pods.Items {
+		fmt.Fprintf(&buffer, "=== %s/%s\n", pod.Namespace, pod.Name)
+
+		err = podLogLines(ctx, clientset, pod, tailLines, cluster, &buffer)
+		if err != nil {
+			fmt.Fprintf(&buffer, "Failed to collect: %s\n", err)
+		}
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func podLogLines(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod, tailLines int64, cluster *imv1.GardenerCluster, out io.Writer) error {
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &tailLines}).Stream(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the logs")
+	}
+
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(nil, maxLogLineLength)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, cluster.Name) && strings.Contains(line, cluster.Namespace) {
+			fmt.Fprintln(out, line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// writeArchive writes the files to a gzipped tar archive.
+func writeArchive(out io.Writer, files []archiveFile, modTime time.Time) error {
+	gzipWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, file := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:    file.name,
+			Mode:    0o600,
+			Size:    int64(len(file.content)),
+			ModTime: modTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tarWriter.Write(file.content)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDiagnose(t *testing.T) {
+	t.Run("should archive the cluster, its events, the manager logs, and the check of its secrets", func(t *testing.T) {
+		// given
+		var out bytes.Buffer
+
+		cluster := fixCluster()
+		cluster.Status.State = imv1.ErrorState
+		cluster.Status.Conditions = []metav1.Condition{{Type: "KubeconfigManagement", Status: metav1.ConditionTrue, Reason: "FailedToUpdateSecret"}}
+		secret := fixSecret("kubeconfig-c1")
+		secret.Labels = map[string]string{managedByLabel: "infrastructure-manager", clusterNameLabel: "c1"}
+		secret.Annotations = map[string]string{kubeconfigHashAnnotation: "outdated"}
+		event := &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "c1.1", Namespace: "kcp-system"},
+			InvolvedObject: corev1.ObjectReference{Kind: "GardenerCluster", Name: "c1", Namespace: "kcp-system"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedToUpdateSecret",
+			Message:        "Failed to rotate secret.",
+		}
+		managerPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "infrastructure-manager",
+			Namespace: "kcp-system",
+			Labels:    map[string]string{"control-plane": "controller-manager"},
+		}}
+
+		env := &environment{
+			client:    fakeClient(t, cluster, secret, event),
+			clientset: fakeclientset.NewSimpleClientset(managerPod),
+			namespace: "kcp-system",
+			out:       &out,
+		}
+
+		// when
+		err := diagnose(context.Background(), env, "c1", diagnoseOptions{output: stdout, managerNamespace: "kcp-system", logLines: 100})
+
+		// then
+		require.NoError(t, err)
+
+		files := readArchive(t, out.Bytes())
+		assert.Contains(t, files["gardenercluster.yaml"], "name: c1")
+		assert.Contains(t, files["conditions.txt"], "FailedToUpdateSecret")
+		assert.Contains(t, files["events.txt"], "Failed to rotate secret.")
+		assert.Contains(t, files["secrets.txt"], "ERROR: the kubeconfig was modified after the manager wrote it")
+		assert.Contains(t, files["secrets.txt"], "kubeconfig is valid, server https://api.c1.kyma.example.com")
+		assert.NotContains(t, files["secrets.txt"], "token")
+		assert.Contains(t, files["manager.log"], "=== kcp-system/infrastructure-manager")
+	})
+
+	t.Run("should report an unmanaged, and a missing secret", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+		cluster.Spec.Kubeconfig.AdditionalSecrets = []imv1.Secret{{Name: "missing", Namespace: "kcp-system", Key: "config"}}
+
+		// when
+		report := string(checkSecrets(context.Background(), fakeClient(t, cluster, fixSecret("kubeconfig-c1")), cluster))
+
+		// then
+		assert.Contains(t, report, "ERROR: not managed by the manager")
+		assert.Contains(t, report, "ERROR: failed to get secret missing")
+	})
+
+	t.Run("should fail when the cluster does not exist", func(t *testing.T) {
+		// given
+		env := &environment{client: fakeClient(t), clientset: fakeclientset.NewSimpleClientset(), namespace: "kcp-system", out: io.Discard}
+
+		// when
+		err := diagnose(context.Background(), env, "c1", diagnoseOptions{output: stdout})
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func readArchive(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files
+		}
+
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+
+		files[header.Name] = string(content)
+	}
+}
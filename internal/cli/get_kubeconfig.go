@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stdout selects the standard output as the output file.
+const stdout = "-"
+
+//nolint:gochecknoglobals
+var getKubeconfigCommand = command{
+	usage:       "get-kubeconfig <gardenercluster> [--output <file>] [--context-name <name>] [--switch-context]",
+	description: "Adds the kubeconfig of the cluster to your kubeconfig, or writes it to a file.",
+	setup: func(flags *pflag.FlagSet) runFunc {
+		var options getKubeconfigOptions
+
+		flags.StringVarP(&options.output, "output", "o", "", "Write the kubeconfig to the file instead of adding it to your kubeconfig, - for the standard output")
+		flags.StringVar(&options.contextName, "context-name", "", "Name of the context added to your kubeconfig, the name of the GardenerCluster by default")
+		flags.BoolVar(&options.switchContext, "switch-context", false, "Make the added context the current context")
+
+		return func(ctx context.Context, env *environment, args []string) error {
+			err := exactArgs(args, 1, "get-kubeconfig <gardenercluster>")
+			if err != nil {
+				return err
+			}
+
+			return getKubeconfig(ctx, env, args[0], options)
+		}
+	},
+}
+
+type getKubeconfigOptions struct {
+	output        string
+	contextName   string
+	switchContext bool
+}
+
+func getKubeconfig(ctx context.Context, env *environment, name string, options getKubeconfigOptions) error {
+	kubeconfig, err := readKubeconfig(ctx, env.client, client.ObjectKey{Name: name, Namespace: env.namespace})
+	if err != nil {
+		return err
+	}
+
+	switch options.output {
+	case "":
+	case stdout:
+		_, err = env.out.Write(kubeconfig)
+
+		return err
+	default:
+		return errors.Wrapf(os.WriteFile(options.output, kubeconfig, 0o600), "failed to write %s", options.output)
+	}
+
+	contextName := options.contextName
+	if contextName == "" {
+		contextName = name
+	}
+
+	config, err := env.pathOptions.GetStartingConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to load your kubeconfig")
+	}
+
+	err = mergeKubeconfig(config, kubeconfig, contextName, options.switchContext)
+	if err != nil {
+		return err
+	}
+
+	err = clientcmd.ModifyConfig(env.pathOptions, *config, true)
+	if err != nil {
+		return errors.Wrap(err, "failed to update your kubeconfig")
+	}
+
+	fmt.Fprintf(env.out, "Context %q added to %s.\n", contextName, env.pathOptions.GetDefaultFilename())
+
+	return nil
+}
+
+// readKubeconfig returns the kubeconfig in the primary secret of the cluster. The kubeconfigs which can't be read from
+// the management cluster, as they are sealed, encrypted, or delivered to another cluster, are rejected.
+func readKubeconfig(ctx context.Context, k8sClient client.Client, key client.ObjectKey) ([]byte, error) {
+	var cluster imv1.GardenerCluster
+
+	err := k8sClient.Get(ctx, key, &cluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the GardenerCluster %s", key)
+	}
+
+	target := cluster.Spec.Kubeconfig.Secret
+
+	switch {
+	case cluster.Spec.Kubeconfig.SecretFormat == imv1.SecretFormatSealedSecret:
+		return nil, errors.Errorf("the kubeconfig of %s is stored as a SealedSecret, it can only be read from the unsealed secret", key)
+	case cluster.Spec.Kubeconfig.Encrypted:
+		return nil, errors.Errorf("the kubeconfig of %s is encrypted", key)
+	case target.Remote():
+		return nil, errors.Errorf("the kubeconfig of %s is delivered to the cluster of the secret %s", key, target.ClusterRef.Name)
+	}
+
+	secretName := target.Name
+
+	if cluster.Spec.Kubeconfig.Immutable {
+		secretName = currentSecretName(&cluster, target)
+		if secretName == "" {
+			return nil, errors.Errorf("the kubeconfig of %s is not created yet", key)
+		}
+	}
+
+	var secret corev1.Secret
+
+	secretKey := client.ObjectKey{Name: secretName, Namespace: target.Namespace}
+
+	err = k8sClient.Get(ctx, secretKey, &secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the kubeconfig secret %s", secretKey)
+	}
+
+	kubeconfig, found := secret.Data[target.Key]
+	if !found {
+		return nil, errors.Errorf("the kubeconfig secret %s has no key %s", secretKey, target.Key)
+	}
+
+	return kubeconfig, nil
+}
+
+// currentSecretName returns the name of the secret version holding the current kubeconfig in immutable mode.
+func currentSecretName(cluster *imv1.GardenerCluster, target imv1.Secret) string {
+	for _, status := range cluster.Status.Secrets {
+		if status.Name == target.Name && status.Namespace == target.Namespace {
+			return status.CurrentSecret
+		}
+	}
+
+	return ""
+}
+
+// mergeKubeconfig adds the current context of the kubeconfig to the config. The context, its cluster, and its user are
+// named with the context name, so they replace the ones added for the same cluster before.
+func mergeKubeconfig(config *clientcmdapi.Config, rawKubeconfig []byte, contextName string, switchContext bool) error {
+	kubeconfig, err := clientcmd.Load(rawKubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the kubeconfig")
+	}
+
+	kubeconfigContext, found := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !found {
+		return errors.Errorf("current context %q not found in the kubeconfig", kubeconfig.CurrentContext)
+	}
+
+	cluster, found := kubeconfig.Clusters[kubeconfigContext.Cluster]
+	if !found {
+		return errors.Errorf("cluster %q not found in the kubeconfig", kubeconfigContext.Cluster)
+	}
+
+	authInfo, found := kubeconfig.AuthInfos[kubeconfigContext.AuthInfo]
+	if !found {
+		return errors.Errorf("user %q not found in the kubeconfig", kubeconfigContext.AuthInfo)
+	}
+
+	config.Clusters[contextName] = cluster
+	config.AuthInfos[contextName] = authInfo
+	config.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:   contextName,
+		AuthInfo:  contextName,
+		Namespace: kubeconfigContext.Namespace,
+	}
+
+	if switchContext {
+		config.CurrentContext = contextName
+	}
+
+	return nil
+}
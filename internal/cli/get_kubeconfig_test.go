@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: shoot--kyma--c1
+  cluster:
+    server: https://api.c1.kyma.example.com
+contexts:
+- name: shoot--kyma--c1
+  context:
+    cluster: shoot--kyma--c1
+    user: shoot--kyma--c1-token
+current-context: shoot--kyma--c1
+users:
+- name: shoot--kyma--c1-token
+  user:
+    token: token
+`
+
+func TestGetKubeconfig(t *testing.T) {
+	t.Run("should print the kubeconfig of the cluster", func(t *testing.T) {
+		// given
+		var out bytes.Buffer
+		env := &environment{client: fakeClient(t, fixCluster(), fixSecret("kubeconfig-c1")), namespace: "kcp-system", out: &out}
+
+		// when
+		err := getKubeconfig(context.Background(), env, "c1", getKubeconfigOptions{output: stdout})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testKubeconfig, out.String())
+	})
+
+	t.Run("should read the current version of an immutable kubeconfig", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+		cluster.Spec.Kubeconfig.Immutable = true
+		cluster.Status.Secrets = []imv1.SecretStatus{{Name: "kubeconfig-c1", Namespace: "kcp-system", CurrentSecret: "kubeconfig-c1-v3"}}
+
+		// when
+		kubeconfig, err := readKubeconfig(context.Background(), fakeClient(t, cluster, fixSecret("kubeconfig-c1-v3")), client.ObjectKeyFromObject(cluster))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testKubeconfig, string(kubeconfig))
+	})
+
+	t.Run("should reject a sealed kubeconfig", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+		cluster.Spec.Kubeconfig.SecretFormat = imv1.SecretFormatSealedSecret
+
+		// when
+		_, err := readKubeconfig(context.Background(), fakeClient(t, cluster, fixSecret("kubeconfig-c1")), client.ObjectKeyFromObject(cluster))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SealedSecret")
+	})
+
+	t.Run("should fail when the secret is missing", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+
+		// when
+		_, err := readKubeconfig(context.Background(), fakeClient(t, cluster), client.ObjectKeyFromObject(cluster))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "kcp-system/kubeconfig-c1")
+	})
+}
+
+func TestMergeKubeconfig(t *testing.T) {
+	t.Run("should add the current context named after the cluster", func(t *testing.T) {
+		// given
+		config := clientcmdapi.NewConfig()
+		config.CurrentContext = "management"
+
+		// when
+		err := mergeKubeconfig(config, []byte(testKubeconfig), "c1", false)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.c1.kyma.example.com", config.Clusters["c1"].Server)
+		assert.Equal(t, "token", config.AuthInfos["c1"].Token)
+		assert.Equal(t, "c1", config.Contexts["c1"].Cluster)
+		assert.Equal(t, "c1", config.Contexts["c1"].AuthInfo)
+		assert.Equal(t, "management", config.CurrentContext)
+	})
+
+	t.Run("should switch to the added context", func(t *testing.T) {
+		// given
+		config := clientcmdapi.NewConfig()
+
+		// when
+		err := mergeKubeconfig(config, []byte(testKubeconfig), "c1", true)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "c1", config.CurrentContext)
+	})
+}
+
+func fakeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, imv1.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&imv1.GardenerCluster{}).Build()
+}
+
+func fixCluster() *imv1.GardenerCluster {
+	return &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1", Namespace: "kcp-system"},
+		Spec: imv1.GardenerClusterSpec{
+			Shoot: imv1.Shoot{Name: "c1"},
+			Kubeconfig: imv1.Kubeconfig{
+				Secret: imv1.Secret{Name: "kubeconfig-c1", Namespace: "kcp-system", Key: "config"},
+			},
+		},
+	}
+}
+
+func fixSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kcp-system"},
+		Data:       map[string][]byte{"config": []byte(testKubeconfig)},
+	}
+}
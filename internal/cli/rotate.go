@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultRotationTimeout = 5 * time.Minute
+	rotationPollInterval   = 2 * time.Second
+)
+
+//nolint:gochecknoglobals
+var rotateCommand = command{
+	usage:       "rotate <gardenercluster> [--wait=false] [--timeout <duration>]",
+	description: "Rotates the kubeconfig of the cluster, and waits until the rotation succeeds or fails.",
+	setup: func(flags *pflag.FlagSet) runFunc {
+		var options rotateOptions
+
+		flags.BoolVar(&options.wait, "wait", true, "Wait until the rotation succeeds or fails")
+		flags.DurationVar(&options.timeout, "timeout", defaultRotationTimeout, "Time to wait for the rotation")
+
+		return func(ctx context.Context, env *environment, args []string) error {
+			err := exactArgs(args, 1, "rotate <gardenercluster>")
+			if err != nil {
+				return err
+			}
+
+			return rotate(ctx, env, args[0], options)
+		}
+	},
+}
+
+type rotateOptions struct {
+	wait    bool
+	timeout time.Duration
+}
+
+func rotate(ctx context.Context, env *environment, name string, options rotateOptions) error {
+	key := client.ObjectKey{Name: name, Namespace: env.namespace}
+
+	previousSync, err := requestRotation(ctx, env.client, key)
+	if err != nil {
+		return err
+	}
+
+	// conditions record their transitions in seconds
+	requestedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+
+	fmt.Fprintf(env.out, "Rotation of %s requested.\n", key)
+
+	if !options.wait {
+		return nil
+	}
+
+	var cluster imv1.GardenerCluster
+
+	var throttled bool
+
+	err = wait.PollUntilContextTimeout(ctx, rotationPollInterval, options.timeout, false, func(ctx context.Context) (bool, error) {
+		err := env.client.Get(ctx, key, &cluster)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get the GardenerCluster %s", key)
+		}
+
+		if !throttled && meta.IsStatusConditionTrue(cluster.Status.Conditions, string(imv1.ConditionTypeThrottled)) {
+			throttled = true
+			fmt.Fprintf(env.out, "The rotation is delayed by the rotation limit of the namespace.\n")
+		}
+
+		return rotationFinished(&cluster, previousSync, requestedAt)
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			return errors.Errorf("the kubeconfig of %s was not rotated within %s", key, options.timeout)
+		}
+
+		return err
+	}
+
+	fmt.Fprintf(env.out, "Kubeconfig of %s rotated at %s.\n", key, cluster.Status.LastSyncTime.Format(time.RFC3339))
+
+	return nil
+}
+
+// requestRotation annotates the cluster to rotate its kubeconfig, and returns the time of the last sync before the
+// rotation.
+func requestRotation(ctx context.Context, k8sClient client.Client, key client.ObjectKey) (*metav1.Time, error) {
+	var cluster imv1.GardenerCluster
+
+	err := k8sClient.Get(ctx, key, &cluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the GardenerCluster %s", key)
+	}
+
+	patch := client.MergeFrom(cluster.DeepCopy())
+
+	annotations := cluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[imv1.ForceKubeconfigRotationAnnotation] = "true"
+	cluster.SetAnnotations(annotations)
+
+	err = k8sClient.Patch(ctx, &cluster, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request the rotation of %s", key)
+	}
+
+	return cluster.Status.LastSyncTime, nil
+}
+
+// rotationFinished reports whether the rotation requested at the time succeeded, and fails when it failed. The
+// controller removes the annotation once the kubeconfig is synced, and keeps it when the rotation fails.
+func rotationFinished(cluster *imv1.GardenerCluster, previousSync *metav1.Time, requestedAt metav1.Time) (bool, error) {
+	_, requested := cluster.Annotations[imv1.ForceKubeconfigRotationAnnotation]
+	synced := cluster.Status.LastSyncTime != nil && (previousSync == nil || cluster.Status.LastSyncTime.After(previousSync.Time))
+
+	if !requested && synced {
+		return true, nil
+	}
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeKubeconfigManagement))
+	failed := cluster.Status.State == imv1.ErrorState || cluster.Status.State == imv1.DegradedState
+	if failed && condition != nil && !condition.LastTransitionTime.Before(&requestedAt) {
+		return false, errors.Errorf("the rotation of %s/%s failed: %s (%s)", cluster.Namespace, cluster.Name, condition.Message, condition.Reason)
+	}
+
+	return false, nil
+}
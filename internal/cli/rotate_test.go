@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRotate(t *testing.T) {
+	t.Run("should request the rotation without waiting", func(t *testing.T) {
+		// given
+		var out bytes.Buffer
+		k8sClient := fakeClient(t, fixCluster())
+		env := &environment{client: k8sClient, namespace: "kcp-system", out: &out}
+
+		// when
+		err := rotate(context.Background(), env, "c1", rotateOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "Rotation of kcp-system/c1 requested.")
+
+		var cluster imv1.GardenerCluster
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "c1", Namespace: "kcp-system"}, &cluster))
+		assert.Equal(t, "true", cluster.Annotations[imv1.ForceKubeconfigRotationAnnotation])
+	})
+
+	t.Run("should fail when the cluster does not exist", func(t *testing.T) {
+		// given
+		env := &environment{client: fakeClient(t), namespace: "kcp-system", out: &bytes.Buffer{}}
+
+		// when
+		err := rotate(context.Background(), env, "c1", rotateOptions{})
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestRotationFinished(t *testing.T) {
+	previousSync := metav1.NewTime(time.Now().Add(-time.Hour))
+	requestedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	t.Run("should wait while the rotation is requested", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+		cluster.Annotations = map[string]string{imv1.ForceKubeconfigRotationAnnotation: "true"}
+		cluster.Status.LastSyncTime = &previousSync
+
+		// when
+		finished, err := rotationFinished(cluster, &previousSync, requestedAt)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, finished)
+	})
+
+	t.Run("should finish once the kubeconfig is synced, and the annotation removed", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+		cluster.Status.LastSyncTime = &metav1.Time{Time: time.Now()}
+
+		// when
+		finished, err := rotationFinished(cluster, &previousSync, requestedAt)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, finished)
+	})
+
+	t.Run("should fail when the rotation fails after the request", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+		cluster.Annotations = map[string]string{imv1.ForceKubeconfigRotationAnnotation: "true"}
+		cluster.Status.State = imv1.ErrorState
+		cluster.Status.Conditions = []metav1.Condition{{
+			Type:               string(imv1.ConditionTypeKubeconfigManagement),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(imv1.ConditionReasonFailedToGetKubeconfig),
+			Message:            "Failed to get kubeconfig.",
+			LastTransitionTime: metav1.Now(),
+		}}
+
+		// when
+		_, err := rotationFinished(cluster, &previousSync, requestedAt)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "FailedToGetKubeconfig")
+	})
+
+	t.Run("should ignore a failure before the request", func(t *testing.T) {
+		// given
+		cluster := fixCluster()
+		cluster.Annotations = map[string]string{imv1.ForceKubeconfigRotationAnnotation: "true"}
+		cluster.Status.State = imv1.ErrorState
+		cluster.Status.Conditions = []metav1.Condition{{
+			Type:               string(imv1.ConditionTypeKubeconfigManagement),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(imv1.ConditionReasonFailedToGetKubeconfig),
+			LastTransitionTime: previousSync,
+		}}
+
+		// when
+		finished, err := rotationFinished(cluster, &previousSync, requestedAt)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, finished)
+	})
+}
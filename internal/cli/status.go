@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+//nolint:gochecknoglobals
+var statusCommand = command{
+	usage:       "status [--all-namespaces] [--output table|json]",
+	description: "Lists the GardenerClusters with their state, shoot, last rotation, and kubeconfig expiration.",
+	setup: func(flags *pflag.FlagSet) runFunc {
+		var options statusOptions
+
+		flags.BoolVarP(&options.allNamespaces, "all-namespaces", "A", false, "List the GardenerClusters of all namespaces")
+		flags.StringVarP(&options.output, "output", "o", outputTable, "Output format, table or json")
+
+		return func(ctx context.Context, env *environment, args []string) error {
+			err := exactArgs(args, 0, "status")
+			if err != nil {
+				return err
+			}
+
+			return status(ctx, env, options)
+		}
+	},
+}
+
+type statusOptions struct {
+	allNamespaces bool
+	output        string
+}
+
+// fleetStatus is the JSON output of the status command.
+type fleetStatus struct {
+	Clusters []clusterStatus `json:"clusters"`
+	States   map[string]int  `json:"states"`
+}
+
+type clusterStatus struct {
+	Namespace                string     `json:"namespace"`
+	Name                     string     `json:"name"`
+	State                    string     `json:"state"`
+	Shoot                    string     `json:"shoot"`
+	LastRotationTime         *time.Time `json:"lastRotationTime,omitempty"`
+	KubeconfigExpirationTime *time.Time `json:"kubeconfigExpirationTime,omitempty"`
+}
+
+func status(ctx context.Context, env *environment, options statusOptions) error {
+	if options.output != outputTable && options.output != outputJSON {
+		return errors.Errorf("unsupported output format %q", options.output)
+	}
+
+	var listOptions []client.ListOption
+	if !options.allNamespaces {
+		listOptions = append(listOptions, client.InNamespace(env.namespace))
+	}
+
+	var clusters imv1.GardenerClusterList
+
+	err := env.client.List(ctx, &clusters, listOptions...)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the GardenerClusters")
+	}
+
+	fleet := summarize(clusters.Items)
+
+	if options.output == outputJSON {
+		encoder := json.NewEncoder(env.out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(fleet)
+	}
+
+	return writeTable(env.out, fleet, options.allNamespaces, time.Now())
+}
+
+// summarize returns the status of the clusters ordered by their namespace, and name, and the number of clusters in each
+// state. The clusters not reconciled yet have no state.
+func summarize(clusters []imv1.GardenerCluster) fleetStatus {
+	fleet := fleetStatus{Clusters: make([]clusterStatus, 0, len(clusters)), States: map[string]int{}}
+
+	for i := range clusters {
+		cluster := &clusters[i]
+		summary := clusterStatus{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name,
+			State:     string(cluster.Status.State),
+			Shoot:     cluster.Spec.Shoot.Name,
+		}
+
+		// the creation of the first secret is recorded as the last sync only
+		if lastRotation := cluster.Status.LastRotationTime; lastRotation != nil {
+			summary.LastRotationTime = &lastRotation.Time
+		} else if lastSync := cluster.Status.LastSyncTime; lastSync != nil {
+			summary.LastRotationTime = &lastSync.Time
+		}
+
+		if expiration := cluster.Status.KubeconfigExpirationTime; expiration != nil {
+			summary.KubeconfigExpirationTime = &expiration.Time
+		}
+
+		fleet.Clusters = append(fleet.Clusters, summary)
+		fleet.States[summary.State]++
+	}
+
+	sort.Slice(fleet.Clusters, func(i, j int) bool {
+		if fleet.Clusters[i].Namespace != fleet.Clusters[j].Namespace {
+			return fleet.Clusters[i].Namespace < fleet.Clusters[j].Namespace
+		}
+
+		return fleet.Clusters[i].Name < fleet.Clusters[j].Name
+	})
+
+	return fleet
+}
+
+func writeTable(out io.Writer, fleet fleetStatus, allNamespaces bool, now time.Time) error {
+	table := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0) //nolint:gomnd
+
+	header := "NAME\tSTATE\tSHOOT\tLAST ROTATION\tEXPIRES"
+	if allNamespaces {
+		header = "NAMESPACE\t" + header
+	}
+
+	fmt.Fprintln(table, header)
+
+	for _, cluster := range fleet.Clusters {
+		row := strings.Join([]string{
+			cluster.Name,
+			valueOrNone(cluster.State),
+			cluster.Shoot,
+			sinceOrNone(cluster.LastRotationTime, now),
+			expiresIn(cluster.KubeconfigExpirationTime, now),
+		}, "\t")
+
+		if allNamespaces {
+			row = cluster.Namespace + "\t" + row
+		}
+
+		fmt.Fprintln(table, row)
+	}
+
+	err := table.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\n%d cluster(s)%s\n", len(fleet.Clusters), stateCounts(fleet.States))
+
+	return nil
+}
+
+// stateCounts returns the number of clusters in each state, ordered by the state.
+func stateCounts(states map[string]int) string {
+	names := make([]string, 0, len(states))
+	for state := range states {
+		names = append(names, state)
+	}
+
+	sort.Strings(names)
+
+	counts := make([]string, 0, len(names))
+	for _, state := range names {
+		counts = append(counts, fmt.Sprintf("%d %s", states[state], valueOrNone(state)))
+	}
+
+	if len(counts) == 0 {
+		return ""
+	}
+
+	return ": " + strings.Join(counts, ", ")
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+
+	return value
+}
+
+func sinceOrNone(timestamp *time.Time, now time.Time) string {
+	if timestamp == nil {
+		return "<none>"
+	}
+
+	return duration.HumanDuration(now.Sub(*timestamp)) + " ago"
+}
+
+func expiresIn(timestamp *time.Time, now time.Time) string {
+	switch {
+	case timestamp == nil:
+		return "<none>"
+	case !timestamp.After(now):
+		return "expired"
+	default:
+		return "in " + duration.HumanDuration(timestamp.Sub(now))
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should list the clusters of the namespace as a table", func(t *testing.T) {
+		// given
+		ready := fixStatusCluster("kcp-system", "c1", imv1.ReadyState, now)
+		failed := fixStatusCluster("kcp-system", "c2", imv1.ErrorState, now)
+		other := fixStatusCluster("other", "c3", imv1.ReadyState, now)
+		var out bytes.Buffer
+		env := &environment{client: fakeClient(t, ready, failed, other), namespace: "kcp-system", out: &out}
+
+		// when
+		err := status(context.Background(), env, statusOptions{output: outputTable})
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "NAME   STATE   SHOOT")
+		assert.Contains(t, out.String(), "c1     Ready   shoot-c1")
+		assert.NotContains(t, out.String(), "c3")
+		assert.Contains(t, out.String(), "2 cluster(s): 1 Error, 1 Ready")
+	})
+
+	t.Run("should list the clusters of all namespaces as JSON", func(t *testing.T) {
+		// given
+		var out bytes.Buffer
+		env := &environment{client: fakeClient(t, fixStatusCluster("other", "c3", imv1.ReadyState, now), fixStatusCluster("kcp-system", "c1", imv1.ReadyState, now)), out: &out}
+
+		// when
+		err := status(context.Background(), env, statusOptions{allNamespaces: true, output: outputJSON})
+
+		// then
+		require.NoError(t, err)
+
+		var fleet fleetStatus
+		require.NoError(t, json.Unmarshal(out.Bytes(), &fleet))
+		require.Len(t, fleet.Clusters, 2)
+		assert.Equal(t, "kcp-system", fleet.Clusters[0].Namespace)
+		assert.Equal(t, "shoot-c1", fleet.Clusters[0].Shoot)
+		assert.Equal(t, map[string]int{"Ready": 2}, fleet.States)
+	})
+
+	t.Run("should reject an unsupported output format", func(t *testing.T) {
+		// given
+		env := &environment{client: fakeClient(t), namespace: "kcp-system", out: &bytes.Buffer{}}
+
+		// when
+		err := status(context.Background(), env, statusOptions{output: "yaml"})
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestWriteTable(t *testing.T) {
+	t.Run("should show the age of the last rotation, and the time to the expiration", func(t *testing.T) {
+		// given
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		lastRotation := now.Add(-3 * time.Hour)
+		expiration := now.Add(21 * time.Hour)
+		fleet := fleetStatus{
+			Clusters: []clusterStatus{
+				{Namespace: "kcp-system", Name: "c1", State: "Ready", Shoot: "shoot-c1", LastRotationTime: &lastRotation, KubeconfigExpirationTime: &expiration},
+				{Namespace: "kcp-system", Name: "c2", Shoot: "shoot-c2"},
+			},
+			States: map[string]int{"Ready": 1, "": 1},
+		}
+		var out bytes.Buffer
+
+		// when
+		err := writeTable(&out, fleet, true, now)
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "3h ago")
+		assert.Contains(t, out.String(), "in 21h")
+		assert.Contains(t, out.String(), "2 cluster(s): 1 <none>, 1 Ready")
+	})
+}
+
+func fixStatusCluster(namespace, name string, state imv1.State, now time.Time) *imv1.GardenerCluster {
+	cluster := fixCluster()
+	cluster.Namespace = namespace
+	cluster.Name = name
+	cluster.Spec.Shoot.Name = "shoot-" + name
+	cluster.Status.State = state
+	cluster.Status.LastSyncTime = &metav1.Time{Time: now}
+	cluster.Status.KubeconfigExpirationTime = &metav1.Time{Time: now.Add(24 * time.Hour)}
+
+	return cluster
+}
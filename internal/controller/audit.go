@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/audit"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AuditLog records the creations, rotations, and deletions of the kubeconfig secrets.
+type AuditLog interface {
+	Write(record audit.Record) error
+}
+
+// WithAuditLog enables the audit trail of the kubeconfig secret changes.
+func (controller *GardenerClusterController) WithAuditLog(auditLog AuditLog) *GardenerClusterController {
+	controller.auditLog = auditLog
+
+	return controller
+}
+
+// WithAuditLog enables the audit trail of the deleted orphaned secrets.
+func (collector *OrphanedSecretsCollector) WithAuditLog(auditLog AuditLog) *OrphanedSecretsCollector {
+	collector.auditLog = auditLog
+
+	return collector
+}
+
+// rotationTrigger tells why the kubeconfig secrets are written in this reconciliation.
+func rotationTrigger(cluster *imv1.GardenerCluster, existingSecrets []*corev1.Secret, rotationPeriod time.Duration, drifted, recreated, expiring bool) audit.Trigger {
+	switch {
+	case existingSecrets[0] == nil:
+		return audit.TriggerSecretMissing
+	case recreated:
+		return audit.TriggerShootRecreated
+	case secretRotationForced(cluster):
+		return audit.TriggerForceRotation
+	case drifted:
+		return audit.TriggerDrift
+	case secretsNeedToBeRotated(cluster, existingSecrets, rotationPeriod):
+		return audit.TriggerRotationDue
+	case expiring:
+		return audit.TriggerCredentialExpiring
+	default:
+		return audit.TriggerSpecChange
+	}
+}
+
+// auditSecretWritten records the kubeconfig written to the secret of the target.
+func (controller *GardenerClusterController) auditSecretWritten(cluster *imv1.GardenerCluster, target imv1.Secret, created bool, trigger audit.Trigger, kubeconfig string) {
+	action := audit.ActionRotated
+	if created {
+		action = audit.ActionCreated
+	}
+
+	record := clusterAuditRecord(cluster, action, trigger, target.Namespace, currentSecretName(cluster, target))
+	record.Fingerprint = audit.Fingerprint(kubeconfig)
+
+	writeAuditRecord(controller.auditLog, controller.log, record)
+}
+
+// auditSecretDeleted records a kubeconfig secret removed by the controller.
+func (controller *GardenerClusterController) auditSecretDeleted(cluster *imv1.GardenerCluster, secret *corev1.Secret, trigger audit.Trigger) {
+	record := clusterAuditRecord(cluster, audit.ActionDeleted, trigger, secret.Namespace, secret.Name)
+
+	writeAuditRecord(controller.auditLog, controller.log, record)
+}
+
+func clusterAuditRecord(cluster *imv1.GardenerCluster, action audit.Action, trigger audit.Trigger, secretNamespace, secretName string) audit.Record {
+	return audit.Record{
+		Action:           action,
+		Trigger:          trigger,
+		Cluster:          cluster.Name,
+		ClusterNamespace: cluster.Namespace,
+		Shoot:            cluster.Spec.Shoot.Name,
+		SecretName:       secretName,
+		SecretNamespace:  secretNamespace,
+	}
+}
+
+// currentSecretName returns the name of the secret written for the target, it is the latest version in immutable mode.
+func currentSecretName(cluster *imv1.GardenerCluster, target imv1.Secret) string {
+	for _, secretStatus := range cluster.Status.Secrets {
+		if secretStatus.Name == target.Name && secretStatus.Namespace == target.Namespace && secretStatus.CurrentSecret != "" {
+			return secretStatus.CurrentSecret
+		}
+	}
+
+	return target.Name
+}
+
+// writeAuditRecord does not fail the reconciliation, the secret has already been changed when the record is written.
+func writeAuditRecord(auditLog AuditLog, logger logr.Logger, record audit.Record) {
+	if auditLog == nil {
+		return
+	}
+
+	err := auditLog.Write(record)
+	if err != nil {
+		logger.Error(err, "Failed to write audit record.", "action", record.Action, "secret", record.SecretName, "namespace", record.SecretNamespace)
+	}
+}
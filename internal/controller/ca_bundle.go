@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/kubeconfig"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// applyCABundle appends the CA bundle referenced in the spec to the certificate authority data of the kubeconfig.
+func (controller *GardenerClusterController) applyCABundle(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfigContent string) (string, error) {
+	if cluster.Spec.Kubeconfig.CABundle == nil {
+		return kubeconfigContent, nil
+	}
+
+	caBundle, err := controller.getCABundle(ctx, cluster.Namespace, *cluster.Spec.Kubeconfig.CABundle)
+	if err != nil {
+		return "", err
+	}
+
+	return kubeconfig.WithCABundle(kubeconfigContent, caBundle)
+}
+
+func (controller *GardenerClusterController) getCABundle(ctx context.Context, namespace string, source imv1.CABundleSource) ([]byte, error) {
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		var configMap corev1.ConfigMap
+
+		err := controller.Get(ctx, types.NamespacedName{Name: source.ConfigMapKeyRef.Name, Namespace: namespace}, &configMap)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get CA bundle config map %s", source.ConfigMapKeyRef.Name)
+		}
+
+		caBundle, found := configMap.Data[source.ConfigMapKeyRef.Key]
+		if !found {
+			return nil, errors.Errorf("key %s not found in CA bundle config map %s", source.ConfigMapKeyRef.Key, source.ConfigMapKeyRef.Name)
+		}
+
+		return []byte(caBundle), nil
+	case source.SecretKeyRef != nil:
+		var secret corev1.Secret
+
+		err := controller.Get(ctx, types.NamespacedName{Name: source.SecretKeyRef.Name, Namespace: namespace}, &secret)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get CA bundle secret %s", source.SecretKeyRef.Name)
+		}
+
+		caBundle, found := secret.Data[source.SecretKeyRef.Key]
+		if !found {
+			return nil, errors.Errorf("key %s not found in CA bundle secret %s", source.SecretKeyRef.Key, source.SecretKeyRef.Name)
+		}
+
+		return caBundle, nil
+	default:
+		return nil, errors.New("CA bundle reference is missing")
+	}
+}
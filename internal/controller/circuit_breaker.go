@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WithCircuitBreaker pauses the requests for kubeconfigs for the cool-down once Gardener failed the given number of
+// requests in a row, e.g. during a maintenance of the landscape, instead of retrying each cluster on its own backoff.
+// After the cool-down a single request probes Gardener, the requests resume when it succeeds. Zero disables it.
+func (controller *GardenerClusterController) WithCircuitBreaker(threshold int, coolDown time.Duration) *GardenerClusterController {
+	controller.circuitBreaker = nil
+	if threshold > 0 && coolDown > 0 {
+		controller.circuitBreaker = newCircuitBreaker(threshold, coolDown)
+	}
+
+	return controller
+}
+
+// gardenerUnavailableError delays the rotation of a cluster while the requests for kubeconfigs are paused.
+type gardenerUnavailableError struct {
+	retryAfter time.Duration
+}
+
+func (err gardenerUnavailableError) Error() string {
+	return fmt.Sprintf("requests to Gardener are paused after repeated failures, retrying in %s", err.retryAfter)
+}
+
+// checkGardenerAvailable reports the paused requests in the conditions of the cluster.
+func (controller *GardenerClusterController) checkGardenerAvailable(cluster *imv1.GardenerCluster, now time.Time) error {
+	if controller.circuitBreaker == nil {
+		return nil
+	}
+
+	retryAfter := controller.circuitBreaker.allow(now)
+	if retryAfter == 0 {
+		return nil
+	}
+
+	err := gardenerUnavailableError{retryAfter: retryAfter}
+	cluster.SetCondition(imv1.ConditionTypeGardenerAccess, metav1.ConditionFalse, imv1.ConditionReasonGardenerUnavailable, err)
+	cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonGardenerUnavailable, metav1.ConditionTrue, err)
+
+	return err
+}
+
+// recordGardenerOutcome counts the failures of Gardener itself, the requests rejected for the shoot, or the
+// credentials of the cluster show Gardener is available.
+func (controller *GardenerClusterController) recordGardenerOutcome(err error, now time.Time) {
+	if controller.circuitBreaker == nil {
+		return
+	}
+
+	class := classifyFetchError(err)
+	failed := err != nil && (class == fetchErrorTransient || class == fetchErrorThrottled)
+
+	if controller.circuitBreaker.record(failed, now) {
+		controller.log.Info("Gardener failed repeatedly, pausing the requests for kubeconfigs.", "coolDown", controller.circuitBreaker.coolDown)
+	}
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker counts the consecutive failures of Gardener shared by all clusters, the state is kept in memory, so
+// each replica pauses its requests separately.
+type circuitBreaker struct {
+	threshold int
+	coolDown  time.Duration
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	// openedAt is when the circuit opened, or the probe was let through in the half-open state
+	openedAt time.Time
+}
+
+func newCircuitBreaker(threshold int, coolDown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, coolDown: coolDown}
+}
+
+// allow returns zero when the request may be sent, otherwise the delay before the next attempt. After the cool-down a
+// single probe is let through, another one only when the probe doesn't report its outcome within the cool-down.
+func (breaker *circuitBreaker) allow(now time.Time) time.Duration {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if breaker.state == circuitClosed {
+		return 0
+	}
+
+	if remaining := breaker.openedAt.Add(breaker.coolDown).Sub(now); remaining > 0 {
+		return remaining
+	}
+
+	breaker.state = circuitHalfOpen
+	breaker.openedAt = now
+
+	return 0
+}
+
+// record counts the outcome of a request, and reports whether it opened the circuit.
+func (breaker *circuitBreaker) record(failed bool, now time.Time) bool {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if !failed {
+		breaker.state = circuitClosed
+		breaker.failures = 0
+		metrics.SetGardenerCircuitOpen(false)
+
+		return false
+	}
+
+	// the requests sent before the circuit opened don't extend the cool-down
+	if breaker.state == circuitOpen {
+		return false
+	}
+
+	breaker.failures++
+	if breaker.state == circuitClosed && breaker.failures < breaker.threshold {
+		return false
+	}
+
+	// the failed probe opens the circuit for another cool-down
+	breaker.state = circuitOpen
+	breaker.openedAt = now
+	metrics.SetGardenerCircuitOpen(true)
+
+	return true
+}
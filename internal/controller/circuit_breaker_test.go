@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Circuit breaker", func() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	unavailable := errors.New("connection refused")
+
+	It("Should pause the requests once the failures reach the threshold", func() {
+		breaker := newCircuitBreaker(2, 5*time.Minute)
+
+		Expect(breaker.record(true, now)).To(BeFalse())
+		Expect(breaker.allow(now)).To(BeZero())
+		Expect(breaker.record(true, now)).To(BeTrue())
+		Expect(breaker.allow(now.Add(time.Minute))).To(Equal(4 * time.Minute))
+	})
+
+	It("Should let a single probe through after the cool-down, and resume when it succeeds", func() {
+		breaker := newCircuitBreaker(1, 5*time.Minute)
+		breaker.record(true, now)
+
+		Expect(breaker.allow(now.Add(5 * time.Minute))).To(BeZero())
+		Expect(breaker.allow(now.Add(5*time.Minute + time.Second))).ToNot(BeZero())
+
+		breaker.record(false, now.Add(6*time.Minute))
+		Expect(breaker.allow(now.Add(6 * time.Minute))).To(BeZero())
+	})
+
+	It("Should pause the requests for another cool-down when the probe fails", func() {
+		breaker := newCircuitBreaker(3, 5*time.Minute)
+		for i := 0; i < 3; i++ {
+			breaker.record(true, now)
+		}
+
+		Expect(breaker.allow(now.Add(5 * time.Minute))).To(BeZero())
+		breaker.record(true, now.Add(6*time.Minute))
+
+		Expect(breaker.allow(now.Add(7 * time.Minute))).To(Equal(4 * time.Minute))
+	})
+
+	It("Should not extend the cool-down with the failures of the requests sent before the circuit opened", func() {
+		breaker := newCircuitBreaker(1, 5*time.Minute)
+		Expect(breaker.record(true, now)).To(BeTrue())
+
+		Expect(breaker.record(true, now.Add(4*time.Minute))).To(BeFalse())
+
+		Expect(breaker.allow(now.Add(5 * time.Minute))).To(BeZero())
+	})
+
+	It("Should reset the failures when Gardener answers", func() {
+		breaker := newCircuitBreaker(2, 5*time.Minute)
+
+		breaker.record(true, now)
+		breaker.record(false, now)
+		breaker.record(true, now)
+
+		Expect(breaker.allow(now)).To(BeZero())
+	})
+
+	It("Should count only the failures of Gardener itself", func() {
+		controller := (&GardenerClusterController{log: log.Log}).WithCircuitBreaker(1, 5*time.Minute)
+		notFound := k8serrors.NewNotFound(schema.GroupResource{Group: "core.gardener.cloud", Resource: "shoots"}, "shoot")
+
+		controller.recordGardenerOutcome(notFound, now)
+		Expect(controller.checkGardenerAvailable(&imv1.GardenerCluster{}, now)).To(Succeed())
+
+		controller.recordGardenerOutcome(unavailable, now)
+		Expect(controller.checkGardenerAvailable(&imv1.GardenerCluster{}, now)).ToNot(Succeed())
+	})
+
+	It("Should mark the cluster with the GardenerUnavailable reason while the requests are paused", func() {
+		controller := (&GardenerClusterController{log: log.Log}).WithCircuitBreaker(1, 5*time.Minute)
+		controller.recordGardenerOutcome(unavailable, now)
+		cluster := &imv1.GardenerCluster{}
+
+		err := controller.checkGardenerAvailable(cluster, now.Add(time.Minute))
+
+		Expect(err).To(MatchError(gardenerUnavailableError{retryAfter: 4 * time.Minute}))
+		Expect(cluster.Status.State).To(Equal(imv1.ErrorState))
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeGardenerAccess))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(string(imv1.ConditionReasonGardenerUnavailable)))
+	})
+
+	It("Should not pause the requests without the circuit breaker", func() {
+		controller := (&GardenerClusterController{}).WithCircuitBreaker(0, 5*time.Minute)
+		controller.recordGardenerOutcome(unavailable, now)
+
+		Expect(controller.checkGardenerAvailable(&imv1.GardenerCluster{}, now)).To(Succeed())
+	})
+})
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"github.com/kyma-project/infrastructure-manager/internal/sharding"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// WithLabelSelector limits the reconciliation to the clusters matching the selector, the other clusters are left to
+// other instances of the controller, e.g. during a blue/green rollout, or when the clusters are sharded manually.
+func (controller *GardenerClusterController) WithLabelSelector(selector labels.Selector) *GardenerClusterController {
+	controller.labelSelector = selector
+
+	return controller
+}
+
+// WithShard makes the controller reconcile only the clusters assigned to the shard, the other clusters are reconciled
+// by the replicas of the other shards.
+func (controller *GardenerClusterController) WithShard(shard sharding.Shard) *GardenerClusterController {
+	controller.shard = shard
+
+	return controller
+}
+
+// selectedClusterPredicate filters the events of the clusters not reconciled by this instance. The requests enqueued
+// for the secrets are filtered in the reconciliation.
+func (controller *GardenerClusterController) selectedClusterPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(controller.selected)
+}
+
+// selected reports whether the cluster is reconciled by this instance of the controller, it matches the label selector,
+// and is assigned to the shard of the instance.
+func (controller *GardenerClusterController) selected(object client.Object) bool {
+	if controller.labelSelector != nil && !controller.labelSelector.Matches(labels.Set(object.GetLabels())) {
+		return false
+	}
+
+	return controller.shard.Owns(object.GetUID())
+}
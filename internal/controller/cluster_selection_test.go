@@ -0,0 +1,52 @@
+package controller
+
+import (
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/sharding"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Cluster selection", func() {
+	clusterWithLabels := func(clusterLabels map[string]string) *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Labels: clusterLabels}}
+	}
+
+	It("Should reconcile all clusters without a selector", func() {
+		controller := &GardenerClusterController{}
+
+		Expect(controller.selected(clusterWithLabels(nil))).To(BeTrue())
+	})
+
+	It("Should reconcile only the clusters matching the selector", func() {
+		selector, err := labels.Parse("operator.kyma-project.io/deployment=green")
+		Expect(err).ToNot(HaveOccurred())
+
+		controller := (&GardenerClusterController{}).WithLabelSelector(selector)
+		green := clusterWithLabels(map[string]string{"operator.kyma-project.io/deployment": "green"})
+		blue := clusterWithLabels(map[string]string{"operator.kyma-project.io/deployment": "blue"})
+
+		Expect(controller.selected(green)).To(BeTrue())
+		Expect(controller.selected(blue)).To(BeFalse())
+		Expect(controller.selectedClusterPredicate().Create(event.CreateEvent{Object: green})).To(BeTrue())
+		Expect(controller.selectedClusterPredicate().Create(event.CreateEvent{Object: blue})).To(BeFalse())
+	})
+
+	It("Should reconcile only the clusters assigned to the shard", func() {
+		cluster := clusterWithLabels(nil)
+		cluster.UID = types.UID("5b3c2a54-6a6f-4f0e-9c1e-3b8f5f1d2e7a")
+		owner := sharding.Assign(cluster.UID, 2)
+
+		ownerShard, err := sharding.New(owner, 2)
+		Expect(err).ToNot(HaveOccurred())
+		otherShard, err := sharding.New(1-owner, 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect((&GardenerClusterController{}).WithShard(ownerShard).selected(cluster)).To(BeTrue())
+		Expect((&GardenerClusterController{}).WithShard(otherShard).selected(cluster)).To(BeFalse())
+	})
+})
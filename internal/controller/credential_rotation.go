@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+)
+
+// WithCredentialRotation rotates the kubeconfig once the fraction of the validity of its credential passed, regardless
+// of the rotation period, and the resync interval, so that a credential issued for less than requested is replaced
+// before it expires. Zero disables it.
+func (controller *GardenerClusterController) WithCredentialRotation(fraction float64) *GardenerClusterController {
+	controller.credentialRotationFraction = fraction
+
+	return controller
+}
+
+// credentialRotationTime returns when the credential of the kubeconfig written last has to be rotated, measured from the
+// time it was written to its expiration. It is zero when the rotation is disabled, or the expiration is unknown.
+func (controller *GardenerClusterController) credentialRotationTime(cluster *imv1.GardenerCluster) time.Time {
+	issued, expiration := cluster.Status.LastSyncTime, cluster.Status.KubeconfigExpirationTime
+	if controller.credentialRotationFraction <= 0 || issued == nil || expiration == nil || expiration.IsZero() {
+		return time.Time{}
+	}
+
+	validity := expiration.Sub(issued.Time)
+	if validity <= 0 {
+		return issued.Time
+	}
+
+	return issued.Add(time.Duration(controller.credentialRotationFraction * float64(validity)))
+}
+
+// credentialRotationDue reports whether the fraction of the validity of the credential passed.
+func (controller *GardenerClusterController) credentialRotationDue(cluster *imv1.GardenerCluster, now time.Time) bool {
+	rotationTime := controller.credentialRotationTime(cluster)
+
+	return !rotationTime.IsZero() && !now.Before(rotationTime)
+}
+
+// requeuePeriodFor shortens the requeue to the rotation of the credential when it is due before the rotation period.
+func (controller *GardenerClusterController) requeuePeriodFor(cluster *imv1.GardenerCluster, rotationPeriod time.Duration, now time.Time) time.Duration {
+	rotationTime := controller.credentialRotationTime(cluster)
+	if rotationTime.IsZero() {
+		return rotationPeriod
+	}
+
+	untilRotation := rotationTime.Sub(now)
+	if untilRotation <= 0 || untilRotation >= rotationPeriod {
+		return rotationPeriod
+	}
+
+	return untilRotation
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/audit"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Credential rotation", func() {
+	now := time.Now()
+
+	// clusterWithCredential returns a cluster whose credential was issued an hour ago, valid for the given duration
+	clusterWithCredential := func(validity time.Duration) *imv1.GardenerCluster {
+		issued := now.Add(-time.Hour)
+
+		return &imv1.GardenerCluster{
+			Status: imv1.GardenerClusterStatus{
+				LastSyncTime:             &metav1.Time{Time: issued},
+				KubeconfigExpirationTime: &metav1.Time{Time: issued.Add(validity)},
+			},
+		}
+	}
+
+	controller := (&GardenerClusterController{}).WithCredentialRotation(0.8)
+
+	It("Should rotate once the fraction of the validity passed", func() {
+		Expect(controller.credentialRotationDue(clusterWithCredential(time.Hour+time.Minute), now)).To(BeTrue())
+	})
+
+	It("Should not rotate before the fraction of the validity passed", func() {
+		Expect(controller.credentialRotationDue(clusterWithCredential(2*time.Hour), now)).To(BeFalse())
+	})
+
+	It("Should not rotate with the rotation disabled", func() {
+		Expect((&GardenerClusterController{}).credentialRotationDue(clusterWithCredential(time.Hour), now)).To(BeFalse())
+	})
+
+	It("Should not rotate before the kubeconfig was written", func() {
+		Expect(controller.credentialRotationDue(&imv1.GardenerCluster{}, now)).To(BeFalse())
+	})
+
+	It("Should requeue at the rotation of the credential due before the rotation period", func() {
+		// 80% of 2 hours is 96 minutes after issuing, 36 minutes from now
+		Expect(controller.requeuePeriodFor(clusterWithCredential(2*time.Hour), 24*time.Hour, now)).To(Equal(36 * time.Minute))
+	})
+
+	It("Should requeue after the rotation period when it is shorter", func() {
+		Expect(controller.requeuePeriodFor(clusterWithCredential(2*time.Hour), 10*time.Minute, now)).To(Equal(10 * time.Minute))
+	})
+
+	It("Should audit the rotation of the expiring credential", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{lastKubeconfigSyncAnnotation: now.Format(time.RFC3339)}}}
+
+		Expect(rotationTrigger(&imv1.GardenerCluster{}, []*corev1.Secret{secret}, 24*time.Hour, false, false, true)).To(Equal(audit.TriggerCredentialExpiring))
+	})
+})
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// degradeWhileServing reports the Degraded state instead of the Error state when the rotation failed, but the secret
+// still serves a kubeconfig which has not expired yet, so that monitoring can tell the clusters which break at the
+// expiration from the ones broken already.
+func (controller *GardenerClusterController) degradeWhileServing(ctx context.Context, cluster *imv1.GardenerCluster, now time.Time) {
+	expiration := cluster.Status.KubeconfigExpirationTime
+	if cluster.Status.State != imv1.ErrorState || expiration == nil || !now.Before(expiration.Time) {
+		return
+	}
+
+	// the secret holds a kubeconfig the controller didn't write
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, string(imv1.ConditionTypeSecretConflict)) {
+		return
+	}
+
+	_, err := controller.getCurrentSecret(ctx, cluster, cluster.Spec.Kubeconfig.Secret)
+	if err != nil {
+		return
+	}
+
+	cluster.Status.State = imv1.DegradedState
+}
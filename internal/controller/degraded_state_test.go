@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Degraded state", func() {
+	now := time.Now()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "kcp-system"}}
+
+	failedCluster := func(expiration time.Time) *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+			Spec: imv1.GardenerClusterSpec{Kubeconfig: imv1.Kubeconfig{
+				Secret: imv1.Secret{Name: "kubeconfig", Namespace: "kcp-system", Key: "config"},
+			}},
+			Status: imv1.GardenerClusterStatus{
+				State:                    imv1.ErrorState,
+				KubeconfigExpirationTime: &metav1.Time{Time: expiration},
+			},
+		}
+	}
+
+	newController := func(objects ...client.Object) *GardenerClusterController {
+		return &GardenerClusterController{Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objects...).Build()}
+	}
+
+	It("Should report the Degraded state while the secret serves a valid kubeconfig", func() {
+		cluster := failedCluster(now.Add(time.Hour))
+
+		newController(secret).degradeWhileServing(context.Background(), cluster, now)
+
+		Expect(cluster.Status.State).To(Equal(imv1.DegradedState))
+	})
+
+	It("Should keep the Error state once the kubeconfig expired", func() {
+		cluster := failedCluster(now.Add(-time.Minute))
+
+		newController(secret).degradeWhileServing(context.Background(), cluster, now)
+
+		Expect(cluster.Status.State).To(Equal(imv1.ErrorState))
+	})
+
+	It("Should keep the Error state without the secret", func() {
+		cluster := failedCluster(now.Add(time.Hour))
+
+		newController().degradeWhileServing(context.Background(), cluster, now)
+
+		Expect(cluster.Status.State).To(Equal(imv1.ErrorState))
+	})
+
+	It("Should keep the Error state when the secret is not managed for the cluster", func() {
+		cluster := failedCluster(now.Add(time.Hour))
+		cluster.SetCondition(imv1.ConditionTypeSecretConflict, metav1.ConditionTrue, imv1.ConditionReasonUnmanagedSecretExists, nil)
+
+		newController(secret).degradeWhileServing(context.Background(), cluster, now)
+
+		Expect(cluster.Status.State).To(Equal(imv1.ErrorState))
+	})
+})
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WithDryRun makes the controller only report the secret changes it would apply, the GardenerCluster status is still updated.
+func (controller *GardenerClusterController) WithDryRun(dryRun bool) *GardenerClusterController {
+	controller.dryRun = dryRun
+
+	return controller
+}
+
+// reportDryRun logs the skipped actions, and records them in the status. It reports whether the status has changed.
+func (controller *GardenerClusterController) reportDryRun(cluster *imv1.GardenerCluster, actions []string) bool {
+	if len(actions) > 0 {
+		controller.log.Info("Dry run, secrets not changed.", append(loggingContextFromCluster(cluster), "actions", actions)...)
+	}
+
+	conditionsBefore := len(cluster.Status.Conditions)
+	cluster.SetDryRunCondition(actions)
+
+	return len(actions) > 0 || conditionsBefore != len(cluster.Status.Conditions)
+}
+
+// reportDryRunDeletion logs the secret changes skipped on deletion, the status is not updated as the cluster is going away.
+func (controller *GardenerClusterController) reportDryRunDeletion(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	secrets, err := controller.listKubeconfigSecrets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	actions := deletionActions(cluster, secrets)
+	if !cluster.Spec.Kubeconfig.RetainOnDelete {
+		actions = append(actions, controller.externalStoreActions(cluster, "Delete")...)
+	}
+
+	controller.reportDryRun(cluster, actions)
+
+	return nil
+}
+
+// syncActions returns the actions needed to write a new kubeconfig to the targets.
+func syncActions(cluster *imv1.GardenerCluster, targets []imv1.Secret, existingSecrets []*corev1.Secret, drifted bool) []string {
+	actions := make([]string, 0, len(targets))
+
+	for i, target := range targets {
+		switch {
+		case existingSecrets[i] == nil:
+			actions = append(actions, fmt.Sprintf("Create secret %s/%s.", target.Namespace, target.Name))
+		case cluster.Spec.Kubeconfig.Immutable:
+			actions = append(actions, fmt.Sprintf("Create new version of secret %s/%s.", target.Namespace, target.Name))
+		case drifted:
+			actions = append(actions, fmt.Sprintf("Restore secret %s/%s.", target.Namespace, target.Name))
+		default:
+			actions = append(actions, fmt.Sprintf("Rotate secret %s/%s.", target.Namespace, target.Name))
+		}
+	}
+
+	return actions
+}
+
+// metadataRestoreActions returns the actions needed to restore the metadata of the existing secrets.
+func metadataRestoreActions(targets []imv1.Secret, existingSecrets []*corev1.Secret) []string {
+	var actions []string
+
+	for i, target := range targets {
+		if existingSecrets[i] == nil || !applySecretMetadata(target, existingSecrets[i].DeepCopy()) {
+			continue
+		}
+
+		actions = append(actions, fmt.Sprintf("Restore metadata of secret %s/%s.", target.Namespace, target.Name))
+	}
+
+	return actions
+}
+
+// deletionActions returns the actions needed to delete, or release the existing secrets.
+func deletionActions(cluster *imv1.GardenerCluster, secrets []corev1.Secret) []string {
+	actions := make([]string, 0, len(secrets))
+
+	for _, secret := range secrets {
+		if cluster.Spec.Kubeconfig.RetainOnDelete {
+			actions = append(actions, fmt.Sprintf("Release secret %s/%s.", secret.Namespace, secret.Name))
+		} else {
+			actions = append(actions, fmt.Sprintf("Delete secret %s/%s.", secret.Namespace, secret.Name))
+		}
+	}
+
+	return actions
+}
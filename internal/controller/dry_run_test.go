@@ -0,0 +1,51 @@
+package controller
+
+import (
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Dry run", func() {
+	targets := []imv1.Secret{
+		{Name: "secret", Namespace: "default", Key: "config", Labels: map[string]string{"team": "a"}},
+		{Name: "secret-copy", Namespace: "other", Key: "config"},
+	}
+
+	It("Should report creation, and rotation of secrets", func() {
+		cluster := &imv1.GardenerCluster{}
+		existingSecrets := []*corev1.Secret{{}, nil}
+
+		actions := syncActions(cluster, targets, existingSecrets, false)
+
+		Expect(actions).To(Equal([]string{"Rotate secret default/secret.", "Create secret other/secret-copy."}))
+	})
+
+	It("Should report metadata restore only for changed secrets", func() {
+		existingSecrets := []*corev1.Secret{
+			{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "b"}}},
+			{},
+		}
+
+		actions := metadataRestoreActions(targets, existingSecrets)
+
+		Expect(actions).To(Equal([]string{"Restore metadata of secret default/secret."}))
+		Expect(existingSecrets[0].Labels["team"]).To(Equal("b"))
+	})
+
+	It("Should record the actions in the status, and clear them when there are none", func() {
+		controller := &GardenerClusterController{}
+		cluster := &imv1.GardenerCluster{}
+
+		Expect(controller.reportDryRun(cluster, []string{"Create secret default/secret."})).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(imv1.ConditionTypeDryRun))).To(BeTrue())
+
+		Expect(controller.reportDryRun(cluster, nil)).To(BeTrue())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeDryRun))).To(BeNil())
+
+		Expect(controller.reportDryRun(cluster, nil)).To(BeFalse())
+	})
+})
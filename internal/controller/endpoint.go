@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/kubeconfig"
+	"github.com/pkg/errors"
+)
+
+// applyEndpointType points the kubeconfig to the API server endpoint of the shoot selected in the spec.
+// The kubeconfig issued by Gardener targets the external endpoint.
+func (controller *GardenerClusterController) applyEndpointType(ctx context.Context, provider KubeconfigProvider, cluster *imv1.GardenerCluster, kubeconfigContent string) (string, error) {
+	if cluster.Spec.Kubeconfig.EndpointType != imv1.EndpointTypeInternal {
+		return kubeconfigContent, nil
+	}
+
+	shootInfo, err := provider.FetchShootInfo(ctx, cluster.Spec.Shoot.Name)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get internal endpoint of the shoot")
+	}
+
+	if shootInfo.InternalEndpoint == "" {
+		return "", errors.New("shoot does not advertise an internal endpoint")
+	}
+
+	return kubeconfig.WithServer(kubeconfigContent, shootInfo.InternalEndpoint)
+}
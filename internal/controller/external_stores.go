@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/secretstore"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WithExternalStore enables writing the kubeconfigs to the external store of the given type, when requested in the spec.
+func (controller *GardenerClusterController) WithExternalStore(storeType imv1.ExternalStoreType, store secretstore.Store) *GardenerClusterController {
+	if controller.externalStores == nil {
+		controller.externalStores = map[imv1.ExternalStoreType]secretstore.Store{}
+	}
+
+	controller.externalStores[storeType] = store
+
+	return controller
+}
+
+// checkExternalStores fails when the spec requests an external store the controller is not configured for.
+func (controller *GardenerClusterController) checkExternalStores(cluster *imv1.GardenerCluster) error {
+	for _, storeType := range cluster.Spec.Kubeconfig.ExternalStoreTypes() {
+		if _, found := controller.externalStores[storeType]; !found {
+			return errors.Errorf("external store `%s` is not configured on the controller", storeType)
+		}
+	}
+
+	return nil
+}
+
+// externalStoresPending reports whether the current kubeconfig still needs to be written to any of the requested external stores.
+func (controller *GardenerClusterController) externalStoresPending(cluster *imv1.GardenerCluster) bool {
+	for _, storeType := range cluster.Spec.Kubeconfig.ExternalStoreTypes() {
+		store := controller.externalStores[storeType]
+		if store != nil && !cluster.ExternalStoreSynced(storeType, store.Location(cluster)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeExternalStores writes the kubeconfig to all requested external stores, and records the entries in the status.
+func (controller *GardenerClusterController) writeExternalStores(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string, writeTime time.Time) error {
+	storeTypes := cluster.Spec.Kubeconfig.ExternalStoreTypes()
+	cluster.Status.ExternalStores = pruneExternalStoreStatus(cluster.Status.ExternalStores, storeTypes)
+
+	for _, storeType := range storeTypes {
+		store := controller.externalStores[storeType]
+
+		version, err := store.Write(ctx, cluster, kubeconfig)
+		if err != nil {
+			return err
+		}
+
+		cluster.SetExternalStoreWritten(storeType, store.Location(cluster), version, metav1.Time{Time: writeTime})
+	}
+
+	return nil
+}
+
+// deleteExternalStores removes the entries of the cluster from all requested external stores.
+func (controller *GardenerClusterController) deleteExternalStores(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	for _, storeType := range cluster.Spec.Kubeconfig.ExternalStoreTypes() {
+		store, found := controller.externalStores[storeType]
+		if !found {
+			continue
+		}
+
+		err := store.Delete(ctx, cluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// externalStoreActions returns the dry-run actions for the requested external stores.
+func (controller *GardenerClusterController) externalStoreActions(cluster *imv1.GardenerCluster, action string) []string {
+	var actions []string
+
+	for _, storeType := range cluster.Spec.Kubeconfig.ExternalStoreTypes() {
+		if store, found := controller.externalStores[storeType]; found {
+			actions = append(actions, fmt.Sprintf("%s %s entry %s.", action, storeType, store.Location(cluster)))
+		}
+	}
+
+	return actions
+}
+
+func pruneExternalStoreStatus(storeStatuses []imv1.ExternalStoreStatus, storeTypes []imv1.ExternalStoreType) []imv1.ExternalStoreStatus {
+	var pruned []imv1.ExternalStoreStatus
+
+	for _, storeStatus := range storeStatuses {
+		for _, storeType := range storeTypes {
+			if storeStatus.Type == storeType {
+				pruned = append(pruned, storeStatus)
+				break
+			}
+		}
+	}
+
+	return pruned
+}
@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"github.com/kyma-project/infrastructure-manager/internal/featuregates"
+)
+
+// WithFeatureGates lets the features of the controller be enabled, or disabled at runtime.
+func (controller *GardenerClusterController) WithFeatureGates(gates *featuregates.Gates) *GardenerClusterController {
+	controller.featureGates = gates
+
+	return controller
+}
+
+// featureEnabled reports whether the feature is enabled, the given default applies without feature gates.
+func (controller *GardenerClusterController) featureEnabled(name string, defaultEnabled bool) bool {
+	if controller.featureGates == nil {
+		return defaultEnabled
+	}
+
+	return controller.featureGates.Enabled(name)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultFetchBackoffBase = 30 * time.Second
+	defaultFetchBackoffMax  = 30 * time.Minute
+)
+
+// WithFetchBackoff configures the exponential backoff applied to a cluster after failures of requesting the kubeconfig from Gardener.
+func (controller *GardenerClusterController) WithFetchBackoff(base, max time.Duration) *GardenerClusterController {
+	controller.fetchBackoffBase = base
+	controller.fetchBackoffMax = max
+
+	return controller
+}
+
+// fetchBackoff returns the delay before the next attempt after the given number of consecutive failures.
+func (controller *GardenerClusterController) fetchBackoff(failures int32) time.Duration {
+	backoff := controller.fetchBackoffBase
+	for i := int32(1); i < failures && backoff < controller.fetchBackoffMax; i++ {
+		backoff *= 2
+	}
+
+	if backoff > controller.fetchBackoffMax {
+		return controller.fetchBackoffMax
+	}
+
+	return backoff
+}
+
+// scheduleFetchRetry records the failure in the status, and returns the delay before the next attempt.
+func (controller *GardenerClusterController) scheduleFetchRetry(cluster *imv1.GardenerCluster, err kubeconfigFetchError, now time.Time) time.Duration {
+	cluster.Status.KubeconfigFetchFailures++
+
+	backoff := controller.fetchRetryDelay(err, cluster.Status.KubeconfigFetchFailures)
+	cluster.Status.NextRetryTime = &metav1.Time{Time: now.Add(backoff)}
+
+	return backoff
+}
+
+// fetchRetryPending returns the remaining backoff, a retry is not delayed when the spec changed since the last failure.
+func fetchRetryPending(cluster *imv1.GardenerCluster, now time.Time) time.Duration {
+	if cluster.Status.NextRetryTime == nil || cluster.Status.ObservedGeneration != cluster.Generation || secretRotationForced(cluster) {
+		return 0
+	}
+
+	return cluster.Status.NextRetryTime.Sub(now)
+}
+
+func resetFetchBackoff(cluster *imv1.GardenerCluster) {
+	cluster.Status.KubeconfigFetchFailures = 0
+	cluster.Status.NextRetryTime = nil
+}
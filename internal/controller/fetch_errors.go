@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// fetchErrorClass tells how a failure of requesting the kubeconfig from Gardener is reported, and retried.
+type fetchErrorClass string
+
+const (
+	// fetchErrorNotFound means the shoot does not exist (yet), it is retried with backoff in case the shoot is being created.
+	fetchErrorNotFound fetchErrorClass = "NotFound"
+	// fetchErrorUnauthorized means Gardener rejected the credentials of the controller, retrying does not help until they
+	// are replaced, so it is retried with the maximal backoff.
+	fetchErrorUnauthorized fetchErrorClass = "Unauthorized"
+	// fetchErrorThrottled means Gardener rejected the request because of the rate limits, it is retried after the delay
+	// suggested by Gardener.
+	fetchErrorThrottled fetchErrorClass = "Throttled"
+	// fetchErrorTransient covers all other failures, e.g. timeouts, or unavailable API servers, retried with backoff.
+	fetchErrorTransient fetchErrorClass = "Transient"
+)
+
+// kubeconfigFetchError marks failures of requesting the kubeconfig from Gardener, these are retried with a per-cluster backoff.
+type kubeconfigFetchError struct {
+	error
+	class fetchErrorClass
+}
+
+func newKubeconfigFetchError(err error) kubeconfigFetchError {
+	return kubeconfigFetchError{error: err, class: classifyFetchError(err)}
+}
+
+func (err kubeconfigFetchError) Unwrap() error {
+	return err.error
+}
+
+func classifyFetchError(err error) fetchErrorClass {
+	switch {
+	case k8serrors.IsNotFound(err):
+		return fetchErrorNotFound
+	case k8serrors.IsUnauthorized(err), k8serrors.IsForbidden(err):
+		return fetchErrorUnauthorized
+	case k8serrors.IsTooManyRequests(err):
+		return fetchErrorThrottled
+	default:
+		return fetchErrorTransient
+	}
+}
+
+// conditionReason returns the reason of the KubeconfigManagement, and GardenerAccess conditions reporting the failure.
+func (class fetchErrorClass) conditionReason() imv1.ConditionReason {
+	switch class {
+	case fetchErrorNotFound:
+		return imv1.ConditionReasonShootNotFound
+	case fetchErrorUnauthorized:
+		return imv1.ConditionReasonGardenerUnauthorized
+	case fetchErrorThrottled:
+		return imv1.ConditionReasonGardenerThrottled
+	default:
+		return imv1.ConditionReasonFailedToGetKubeconfig
+	}
+}
+
+// fetchRetryDelay returns the delay before the next attempt after the given number of consecutive failures.
+func (controller *GardenerClusterController) fetchRetryDelay(err kubeconfigFetchError, failures int32) time.Duration {
+	switch err.class {
+	case fetchErrorUnauthorized:
+		return controller.fetchBackoffMax
+	case fetchErrorThrottled:
+		if seconds, found := k8serrors.SuggestsClientDelay(err); found && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return controller.fetchBackoff(failures)
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Kubeconfig fetch errors", func() {
+	shoots := schema.GroupResource{Group: "core.gardener.cloud", Resource: "shoots"}
+	controller := &GardenerClusterController{fetchBackoffBase: 30 * time.Second, fetchBackoffMax: 30 * time.Minute}
+
+	DescribeTable("Should classify the failure",
+		func(err error, class fetchErrorClass, reason imv1.ConditionReason) {
+			fetchErr := newKubeconfigFetchError(errors.Wrap(err, "failed to get shoot"))
+
+			Expect(fetchErr.class).To(Equal(class))
+			Expect(fetchErr.class.conditionReason()).To(Equal(reason))
+		},
+		Entry("missing shoot", k8serrors.NewNotFound(shoots, "shoot"), fetchErrorNotFound, imv1.ConditionReasonShootNotFound),
+		Entry("expired token", k8serrors.NewUnauthorized("token expired"), fetchErrorUnauthorized, imv1.ConditionReasonGardenerUnauthorized),
+		Entry("missing permissions", k8serrors.NewForbidden(shoots, "shoot", errors.New("denied")), fetchErrorUnauthorized, imv1.ConditionReasonGardenerUnauthorized),
+		Entry("rate limit", k8serrors.NewTooManyRequests("slow down", 10), fetchErrorThrottled, imv1.ConditionReasonGardenerThrottled),
+		Entry("timeout", k8serrors.NewTimeoutError("timeout", 0), fetchErrorTransient, imv1.ConditionReasonFailedToGetKubeconfig),
+		Entry("connection failure", errors.New("connection refused"), fetchErrorTransient, imv1.ConditionReasonFailedToGetKubeconfig),
+	)
+
+	It("Should retry throttled requests after the delay suggested by Gardener", func() {
+		fetchErr := newKubeconfigFetchError(k8serrors.NewTooManyRequests("slow down", 10))
+
+		Expect(controller.fetchRetryDelay(fetchErr, 3)).To(Equal(10 * time.Second))
+	})
+
+	It("Should retry rejected credentials with the maximal backoff", func() {
+		fetchErr := newKubeconfigFetchError(k8serrors.NewUnauthorized("token expired"))
+
+		Expect(controller.fetchRetryDelay(fetchErr, 1)).To(Equal(30 * time.Minute))
+	})
+
+	It("Should retry transient failures with exponential backoff", func() {
+		fetchErr := newKubeconfigFetchError(errors.New("connection refused"))
+
+		Expect(controller.fetchRetryDelay(fetchErr, 1)).To(Equal(30 * time.Second))
+		Expect(controller.fetchRetryDelay(fetchErr, 3)).To(Equal(2 * time.Minute))
+	})
+})
@@ -18,27 +18,54 @@ package controller
 
 import (
 	"context"
+	"crypto/rsa"
 	"fmt"
+	"math/rand"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/audit"
+	"github.com/kyma-project/infrastructure-manager/internal/featuregates"
+	"github.com/kyma-project/infrastructure-manager/internal/kubeconfig"
+	"github.com/kyma-project/infrastructure-manager/internal/metrics"
+	"github.com/kyma-project/infrastructure-manager/internal/secretstore"
+	"github.com/kyma-project/infrastructure-manager/internal/sharding"
+	"github.com/kyma-project/infrastructure-manager/internal/shoot"
+	"github.com/kyma-project/infrastructure-manager/internal/tracing"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
 const (
 	lastKubeconfigSyncAnnotation      = "operator.kyma-project.io/last-sync"
-	forceKubeconfigRotationAnnotation = "operator.kyma-project.io/force-kubeconfig-rotation"
+	forceKubeconfigRotationAnnotation = imv1.ForceKubeconfigRotationAnnotation
 	clusterCRNameLabel                = "operator.kyma-project.io/cluster-name"
+	clusterCRNamespaceLabel           = "operator.kyma-project.io/cluster-namespace"
+	managedByLabel                    = "operator.kyma-project.io/managed-by"
+	managedByLabelValue               = "infrastructure-manager"
+	kubeconfigSecretFinalizer         = "infrastructuremanager.kyma-project.io/kubeconfig-secret"
+	reconcilerDisabledAnnotation      = "operator.kyma-project.io/managed-by-reconciler-disabled"
+)
+
+const (
+	eventReasonKubeconfigSecretDeleted  = "KubeconfigSecretDeleted"
+	eventReasonKubeconfigSecretRetained = "KubeconfigSecretRetained"
 )
 
 // GardenerClusterController reconciles a GardenerCluster object
@@ -46,8 +73,55 @@ type GardenerClusterController struct {
 	client.Client
 	Scheme             *runtime.Scheme
 	KubeconfigProvider KubeconfigProvider
+	reader             client.Reader
 	log                logr.Logger
+	recorder           record.EventRecorder
 	rotationPeriod     time.Duration
+	requeueInterval    time.Duration
+	requeueJitter      float64
+	purposeRotation    map[imv1.Purpose]time.Duration
+	fetchBackoffBase   time.Duration
+	fetchBackoffMax    time.Duration
+	maxConcurrency     int
+	shootLocks         *shootLocks
+	dryRun             bool
+	minExpiration      time.Duration
+	maxExpiration      time.Duration
+	tokenGenerator     ServiceAccountTokenGenerator
+	gardenlogin        gardenloginConfig
+
+	validateKubeconfigs   bool
+	kubeconfigDialTimeout time.Duration
+	externalStores        map[imv1.ExternalStoreType]secretstore.Store
+	encrypter             KubeconfigEncrypter
+	sealingKey            *rsa.PublicKey
+	auditLog              AuditLog
+	metadataPolicy        shoot.MetadataPolicy
+	shootPatcher          ShootPatcher
+	gardenerProjects      GardenerProjects
+	remoteClusters        RemoteClusters
+	priorityQueue         bool
+	labelSelector         labels.Selector
+	shard                 sharding.Shard
+	rotationLimiter       *namespaceRotationLimiter
+	circuitBreaker        *circuitBreaker
+	featureGates          *featuregates.Gates
+
+	// shutdownGracePeriod is how long the running reconciliations may continue after the controller stops
+	shutdownGracePeriod time.Duration
+	// startupWindow is the warm-up the first reconciliations of the existing clusters are spread over
+	startupWindow time.Duration
+	// credentialRotationFraction is the fraction of the validity of the credential the kubeconfig is rotated after
+	credentialRotationFraction float64
+
+	// rotationPeriodOverride is the rotation period set at runtime, zero when the rotation period applies
+	rotationPeriodOverride atomic.Int64
+}
+
+// gardenloginConfig identifies the garden cluster, and the project namespace of the shoots in the gardenlogin kubeconfigs.
+type gardenloginConfig struct {
+	gardenClusterIdentity string
+	shootNamespace        string
 }
 
 func NewGardenerClusterController(mgr ctrl.Manager, kubeconfigProvider KubeconfigProvider, logger logr.Logger, rotationPeriod time.Duration) *GardenerClusterController {
@@ -55,20 +129,72 @@ func NewGardenerClusterController(mgr ctrl.Manager, kubeconfigProvider Kubeconfi
 		Client:             mgr.GetClient(),
 		Scheme:             mgr.GetScheme(),
 		KubeconfigProvider: kubeconfigProvider,
+		reader:             mgr.GetAPIReader(),
 		log:                logger,
+		recorder:           mgr.GetEventRecorderFor("gardener-cluster-controller"),
 		rotationPeriod:     rotationPeriod,
+		fetchBackoffBase:   defaultFetchBackoffBase,
+		fetchBackoffMax:    defaultFetchBackoffMax,
+		maxConcurrency:     1,
+		shootLocks:         newShootLocks(),
+		tokenGenerator:     kubeconfig.NewServiceAccountTokenGenerator(),
 	}
 }
 
+// WithRequeue configures the periodic resync of the clusters. The interval is capped by the rotation period, and is not applied
+// when zero. The jitter is the fraction of the interval the requeue is randomly shortened by, spreading the reconciliations in time.
+func (controller *GardenerClusterController) WithRequeue(interval time.Duration, jitter float64) *GardenerClusterController {
+	controller.requeueInterval = interval
+	controller.requeueJitter = jitter
+
+	return controller
+}
+
+// WithPurposeRotationIntervals configures the default rotation intervals of the clusters by the purpose of their shoots,
+// the intervals are capped by the rotation period. The rotation interval in the spec takes precedence.
+func (controller *GardenerClusterController) WithPurposeRotationIntervals(intervals map[imv1.Purpose]time.Duration) *GardenerClusterController {
+	controller.purposeRotation = intervals
+
+	return controller
+}
+
+// WithGardenlogin enables the gardenlogin kubeconfigs for the shoots in the namespace of the garden cluster with the given identity.
+func (controller *GardenerClusterController) WithGardenlogin(gardenClusterIdentity, shootNamespace string) *GardenerClusterController {
+	controller.gardenlogin = gardenloginConfig{
+		gardenClusterIdentity: gardenClusterIdentity,
+		shootNamespace:        shootNamespace,
+	}
+
+	return controller
+}
+
+// WithMaxConcurrentReconciles configures how many clusters are reconciled in parallel.
+func (controller *GardenerClusterController) WithMaxConcurrentReconciles(maxConcurrency int) *GardenerClusterController {
+	controller.maxConcurrency = maxConcurrency
+
+	return controller
+}
+
 //go:generate mockery --name=KubeconfigProvider
 type KubeconfigProvider interface {
-	Fetch(shootName string) (string, error)
+	Fetch(ctx context.Context, shootName string) (string, time.Time, error)
+	FetchWithExpiration(ctx context.Context, shootName string, expiration time.Duration) (string, time.Time, error)
+	FetchShootInfo(ctx context.Context, shootName string) (imv1.ShootInfo, error)
+}
+
+// ServiceAccountTokenGenerator issues kubeconfigs bound to a service account in the shoot, using the admin kubeconfig.
+type ServiceAccountTokenGenerator interface {
+	Generate(ctx context.Context, adminKubeconfig string, serviceAccount kubeconfig.ServiceAccount, expiration time.Duration) (string, time.Time, error)
 }
 
 //+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=gardenerclusters,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=bitnami.com,resources=sealedsecrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=external-secrets.io,resources=pushsecrets,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=gardenerclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=gardenerclusters/status,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -79,44 +205,237 @@ type KubeconfigProvider interface {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.15.0/pkg/reconcile
 func (controller *GardenerClusterController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { //nolint:revive
+	// no rotation is started once the controller stops, the running ones are completed within the grace period
+	if ctx.Err() != nil {
+		controller.log.Info("Controller is stopping, skipping reconciliation.", loggingContext(req)...)
+
+		return controller.resultWithoutRequeue(), nil
+	}
+
+	ctx, cancel := withShutdownGrace(ctx, controller.shutdownGracePeriod)
+	defer cancel()
+
+	ctx, span := tracing.StartSpan(ctx, "GardenerCluster.Reconcile", tracing.ClusterAttributes(req.Name, req.Namespace)...)
+	start := time.Now()
+	result, err := controller.reconcile(ctx, req)
+	metrics.ObserveReconcile(start, err)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (controller *GardenerClusterController) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	controller.log.Info("Starting reconciliation.", loggingContext(req)...)
 
 	var cluster imv1.GardenerCluster
 
-	err := controller.Client.Get(ctx, req.NamespacedName, &cluster)
+	err := controller.getCluster(ctx, req, &cluster)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			err = controller.deleteKubeconfigSecret(req.NamespacedName.Name)
+			metrics.DeleteCluster(req.Name, req.Namespace)
 		}
 
-		if err == nil {
-			controller.log.Info("Secret has been deleted.", loggingContext(req)...)
+		return controller.resultWithoutRequeue(), client.IgnoreNotFound(err)
+	}
+
+	if !controller.selected(&cluster) {
+		controller.log.Info("Cluster not selected by the label selector, or assigned to another shard, skipping.", loggingContext(req)...)
+
+		return controller.resultWithoutRequeue(), nil
+	}
+
+	recordClusterMetrics(&cluster)
+
+	if reconciliationSuspended(&cluster) {
+		controller.log.Info("Reconciliation suspended.", loggingContext(req)...)
+
+		return controller.resultWithoutRequeue(), controller.suspendReconciliation(ctx, &cluster)
+	}
+
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeSuspended)) != nil {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(imv1.ConditionTypeSuspended))
+
+		err = controller.persistStatusChange(ctx, &cluster)
+		if err != nil {
+			return controller.resultWithoutRequeue(), err
 		}
+	}
 
-		return controller.resultWithoutRequeue(), err
+	if !cluster.DeletionTimestamp.IsZero() {
+		return controller.resultWithoutRequeue(), controller.handleDeletion(ctx, &cluster)
+	}
+
+	if !controllerutil.ContainsFinalizer(&cluster, kubeconfigSecretFinalizer) {
+		controllerutil.AddFinalizer(&cluster, kubeconfigSecretFinalizer)
+
+		err = controller.Client.Update(ctx, &cluster)
+		if err != nil {
+			return controller.resultWithoutRequeue(), err
+		}
+	}
+
+	expiration, err := controller.expirationFor(&cluster)
+	if err != nil {
+		controller.log.Error(err, "Invalid kubeconfig expiration.", loggingContext(req)...)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonInvalidExpiration, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(&cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return controller.resultWithoutRequeue(), controller.persistStatusChange(ctx, &cluster)
+	}
+
+	err = controller.checkExternalStores(&cluster)
+	if err != nil {
+		controller.log.Error(err, "Invalid external stores.", loggingContext(req)...)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToWriteExternalStore, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(&cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return controller.resultWithoutRequeue(), controller.persistStatusChange(ctx, &cluster)
+	}
+
+	rotationPeriod, err := controller.rotationPeriodFor(&cluster, controller.maxRotationPeriodFor(expiration))
+	if err != nil {
+		controller.log.Error(err, "Invalid rotation interval.", loggingContext(req)...)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonInvalidRotationInterval, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(&cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		// The spec needs to be fixed before the secret can be managed, there is no point in retrying.
+		return controller.resultWithoutRequeue(), controller.persistStatusChange(ctx, &cluster)
 	}
 
 	lastSyncTime := time.Now()
-	kubeconfigRotated, err := controller.createOrRotateKubeconfigSecret(ctx, &cluster, lastSyncTime)
+
+	if retryAfter := fetchRetryPending(&cluster, lastSyncTime); retryAfter > 0 {
+		controller.log.Info("Backing off after failure to get kubeconfig.", append(loggingContext(req), "retryAfter", retryAfter)...)
+
+		return ctrl.Result{RequeueAfter: retryAfter}, nil
+	}
+
+	unlockShoot := controller.shootLocks.Lock(cluster.Spec.Shoot.Name)
+	kubeconfigRotated, err := controller.createOrRotateKubeconfigSecret(ctx, &cluster, lastSyncTime, rotationPeriod, expiration)
+	unlockShoot()
+
+	var throttledErr rotationThrottledError
+	if errors.As(err, &throttledErr) {
+		controller.log.Info("Rotation throttled by the namespace limit.", append(loggingContext(req), "retryAfter", throttledErr.retryAfter)...)
+
+		return ctrl.Result{RequeueAfter: throttledErr.retryAfter}, controller.persistStatusChange(ctx, &cluster)
+	}
+
+	var unavailableErr gardenerUnavailableError
+	if errors.As(err, &unavailableErr) {
+		controller.log.Info("Requests to Gardener paused after repeated failures.", append(loggingContext(req), "retryAfter", unavailableErr.retryAfter)...)
+		controller.degradeWhileServing(ctx, &cluster, lastSyncTime)
+
+		return ctrl.Result{RequeueAfter: unavailableErr.retryAfter}, controller.persistStatusChange(ctx, &cluster)
+	}
+
 	if err != nil {
-		_ = controller.persistStatusChange(ctx, &cluster)
+		metrics.RecordRotationFailure(kubeconfigManagementReason(&cluster))
+	}
 
-		return controller.resultWithoutRequeue(), err
+	var fetchErr kubeconfigFetchError
+	if errors.As(err, &fetchErr) {
+		// the generation is recorded to retry without delay once the spec changes
+		cluster.Status.ObservedGeneration = cluster.Generation
+		retryAfter := controller.scheduleFetchRetry(&cluster, fetchErr, lastSyncTime)
+		controller.log.Error(err, "Failed to get kubeconfig.", append(loggingContext(req), "errorClass", fetchErr.class, "retryAfter", retryAfter)...)
+		controller.degradeWhileServing(ctx, &cluster, lastSyncTime)
+
+		return ctrl.Result{RequeueAfter: retryAfter}, controller.persistStatusChange(ctx, &cluster)
 	}
 
-	err = controller.removeForceRotationAnnotation(ctx, &cluster)
 	if err != nil {
+		controller.degradeWhileServing(ctx, &cluster, lastSyncTime)
+		_ = controller.persistStatusChange(ctx, &cluster)
+
 		return controller.resultWithoutRequeue(), err
 	}
 
-	if kubeconfigRotated {
+	secretsRelocated := false
+
+	if !controller.dryRun {
+		err = controller.removeForceRotationAnnotation(ctx, &cluster)
+		if err != nil {
+			return controller.resultWithoutRequeue(), err
+		}
+
+		secretsRelocated, err = controller.relocateSecrets(ctx, &cluster)
+		if err != nil {
+			controller.log.Error(err, "Failed to move the secrets to the new locations.", loggingContext(req)...)
+			_ = controller.persistStatusChange(ctx, &cluster)
+
+			return controller.resultWithoutRequeue(), err
+		}
+	}
+
+	controller.propagateShootMetadata(ctx, &cluster)
+
+	if kubeconfigRotated || secretsRelocated || cluster.Status.ObservedGeneration != cluster.Generation {
+		cluster.Status.ObservedGeneration = cluster.Generation
+
 		err = controller.persistStatusChange(ctx, &cluster)
 		if err != nil {
 			return controller.resultWithoutRequeue(), err
 		}
 	}
 
-	return controller.resultWithRequeue(), nil
+	return controller.resultWithRequeue(controller.requeuePeriodFor(&cluster, rotationPeriod, time.Now())), nil
+}
+
+// getCluster fetches the GardenerCluster, and adds the shoot to the reconciliation span.
+func (controller *GardenerClusterController) getCluster(ctx context.Context, req ctrl.Request, cluster *imv1.GardenerCluster) error {
+	_, span := tracing.StartSpan(ctx, "GardenerCluster.Get")
+	err := controller.Client.Get(ctx, req.NamespacedName, cluster)
+	tracing.End(span, client.IgnoreNotFound(err))
+
+	if err == nil {
+		tracing.SetShoot(ctx, cluster.Spec.Shoot.Name)
+	}
+
+	return err
+}
+
+// recordClusterMetrics exports the persisted state of the cluster.
+func recordClusterMetrics(cluster *imv1.GardenerCluster) {
+	metrics.SetClusterState(cluster.Name, cluster.Namespace, cluster.Status.State)
+	metrics.SetClusterPurpose(cluster.Name, cluster.Namespace, cluster.ShootPurpose())
+	metrics.SetKubeconfigExpiration(cluster)
+}
+
+// kubeconfigManagementReason returns the reason of the kubeconfig management condition, which explains a failed rotation.
+func kubeconfigManagementReason(cluster *imv1.GardenerCluster) string {
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeKubeconfigManagement))
+	if condition == nil {
+		return ""
+	}
+
+	return condition.Reason
+}
+
+// reconciliationSuspended reports whether the kubeconfig management, including the cleanup on deletion, is paused for the cluster.
+func reconciliationSuspended(cluster *imv1.GardenerCluster) bool {
+	return cluster.Annotations[reconcilerDisabledAnnotation] == "true"
+}
+
+func (controller *GardenerClusterController) suspendReconciliation(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, string(imv1.ConditionTypeSuspended)) {
+		return nil
+	}
+
+	cluster.SetCondition(imv1.ConditionTypeSuspended, metav1.ConditionTrue, imv1.ConditionReasonReconciliationSuspended, nil)
+	controller.recordConditionEvent(cluster, imv1.ConditionTypeSuspended, corev1.EventTypeNormal)
+
+	return controller.persistStatusChange(ctx, cluster)
+}
+
+// recordConditionEvent emits an event with the reason, and the message of the given condition.
+func (controller *GardenerClusterController) recordConditionEvent(cluster *imv1.GardenerCluster, conditionType imv1.ConditionType, eventType string) {
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(conditionType))
+	if condition == nil {
+		return
+	}
+
+	controller.recorder.Event(cluster, eventType, condition.Reason, condition.Message)
 }
 
 func loggingContextFromCluster(cluster *imv1.GardenerCluster) []any {
@@ -127,93 +446,307 @@ func loggingContext(req ctrl.Request) []any {
 	return []any{"GardenerCluster", req.Name, "Namespace", req.Namespace}
 }
 
-func (controller *GardenerClusterController) resultWithRequeue() ctrl.Result {
+func (controller *GardenerClusterController) resultWithRequeue(rotationPeriod time.Duration) ctrl.Result {
+	requeueAfter := rotationPeriod
+	if controller.requeueInterval > 0 && controller.requeueInterval < requeueAfter {
+		requeueAfter = controller.requeueInterval
+	}
+
+	if controller.requeueJitter > 0 {
+		requeueAfter -= time.Duration(rand.Float64() * controller.requeueJitter * float64(requeueAfter)) //nolint:gosec
+	}
+
 	return ctrl.Result{
 		Requeue:      true,
-		RequeueAfter: controller.rotationPeriod,
+		RequeueAfter: requeueAfter,
+	}
+}
+
+// rotationPeriodFor returns the rotation period for the cluster, taking the optional per-cluster rotation interval, and
+// the default interval of the purpose of the shoot into account.
+func (controller *GardenerClusterController) rotationPeriodFor(cluster *imv1.GardenerCluster, maxRotationPeriod time.Duration) (time.Duration, error) {
+	rotationInterval := cluster.Spec.Kubeconfig.RotationInterval
+	if rotationInterval == nil {
+		if interval, ok := controller.purposeRotation[cluster.ShootPurpose()]; ok && interval < maxRotationPeriod {
+			return interval, nil
+		}
+
+		return maxRotationPeriod, nil
+	}
+
+	if rotationInterval.Duration <= 0 {
+		return 0, errors.Errorf("rotation interval `%s` must be positive", rotationInterval.Duration)
+	}
+
+	if rotationInterval.Duration > maxRotationPeriod {
+		return 0, errors.Errorf("rotation interval `%s` exceeds the maximal rotation period `%s`", rotationInterval.Duration, maxRotationPeriod)
 	}
+
+	return rotationInterval.Duration, nil
 }
 
 func (controller *GardenerClusterController) resultWithoutRequeue() ctrl.Result {
 	return ctrl.Result{}
 }
 
-func (controller *GardenerClusterController) persistStatusChange(ctx context.Context, cluster *imv1.GardenerCluster) error {
+func (controller *GardenerClusterController) persistStatusChange(ctx context.Context, cluster *imv1.GardenerCluster) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "GardenerCluster.UpdateStatus")
+	defer func() { tracing.End(span, err) }()
+
 	key := types.NamespacedName{
 		Name:      cluster.Name,
 		Namespace: cluster.Namespace,
 	}
-	var clusterToUpdate imv1.GardenerCluster
 
-	err := controller.Client.Get(ctx, key, &clusterToUpdate)
+	// the cache may not have observed the previous write yet, the retries read the latest version without the cache
+	var reader client.Reader = controller.Client
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var clusterToUpdate imv1.GardenerCluster
+
+		getErr := reader.Get(ctx, key, &clusterToUpdate)
+		if getErr != nil {
+			return getErr
+		}
+
+		clusterToUpdate.Status = cluster.Status
+		reader = controller.reader
+
+		return controller.Client.Status().Update(ctx, &clusterToUpdate)
+	})
 	if err != nil {
+		controller.log.Error(err, "Failed to set state for GardenerCluster")
+
 		return err
 	}
 
-	clusterToUpdate.Status = cluster.Status
-
-	statusErr := controller.Client.Status().Update(ctx, &clusterToUpdate)
-	if statusErr != nil {
-		controller.log.Error(statusErr, "Failed to set state for GardenerCluster")
-	}
+	recordClusterMetrics(cluster)
 
-	return statusErr
+	return nil
 }
 
-func (controller *GardenerClusterController) deleteKubeconfigSecret(clusterCRName string) error {
-	selector := client.MatchingLabels(map[string]string{
-		clusterCRNameLabel: clusterCRName,
-	})
+// handleDeletion removes the kubeconfig secrets, unless they should be retained, and releases the finalizer.
+func (controller *GardenerClusterController) handleDeletion(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	if !controllerutil.ContainsFinalizer(cluster, kubeconfigSecretFinalizer) {
+		return nil
+	}
 
-	var secretList corev1.SecretList
-	err := controller.Client.List(context.TODO(), &secretList, selector)
+	cluster.UpdateConditionForDeletingState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonKubeconfigSecretDeleting, metav1.ConditionUnknown)
+
+	err := controller.persistStatusChange(ctx, cluster)
 	if err != nil {
 		return err
 	}
 
-	if len(secretList.Items) != 1 {
-		return errors.Errorf("unexpected numer of secrets found for cluster CR `%s`", clusterCRName)
+	switch {
+	case controller.dryRun:
+		err = controller.reportDryRunDeletion(ctx, cluster)
+		if err != nil {
+			return err
+		}
+	case cluster.Spec.Kubeconfig.RetainOnDelete:
+		err = controller.releaseKubeconfigSecrets(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		controller.log.Info("Secret is retained.", loggingContextFromCluster(cluster)...)
+		controller.recorder.Event(cluster, corev1.EventTypeNormal, eventReasonKubeconfigSecretRetained, "Secret retained after deletion.")
+	default:
+		err = controller.deleteKubeconfigSecrets(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		err = controller.deleteExternalStores(ctx, cluster)
+		if err != nil {
+			cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToDeleteExternalStore, metav1.ConditionTrue, err)
+			_ = controller.persistStatusChange(ctx, cluster)
+
+			return err
+		}
+
+		controller.log.Info("Secret has been deleted.", loggingContextFromCluster(cluster)...)
+		controller.recorder.Event(cluster, corev1.EventTypeNormal, eventReasonKubeconfigSecretDeleted, "Secret deleted.")
+	}
+
+	// Status has been persisted in the meantime, so patch to avoid a conflict on the outdated resource version.
+	patch := client.MergeFrom(cluster.DeepCopy())
+	controllerutil.RemoveFinalizer(cluster, kubeconfigSecretFinalizer)
+
+	return controller.Client.Patch(ctx, cluster, patch)
+}
+
+// releaseKubeconfigSecrets removes the managed-by label from retained secrets, so that they are not collected as orphans.
+func (controller *GardenerClusterController) releaseKubeconfigSecrets(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	for _, target := range cluster.Spec.Kubeconfig.Targets() {
+		secrets, err := controller.listTargetSecrets(ctx, cluster, target)
+		if err != nil {
+			return err
+		}
+
+		secretClient, err := controller.secretsFor(ctx, cluster, target)
+		if err != nil {
+			return err
+		}
+
+		for i := range secrets {
+			if _, found := secrets[i].Labels[managedByLabel]; !found {
+				continue
+			}
+
+			delete(secrets[i].Labels, managedByLabel)
+
+			err = secretClient.Update(ctx, &secrets[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (controller *GardenerClusterController) deleteKubeconfigSecrets(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	for _, target := range cluster.Spec.Kubeconfig.Targets() {
+		secrets, err := controller.listTargetSecrets(ctx, cluster, target)
+		if err != nil {
+			return err
+		}
+
+		secretClient, err := controller.secretsFor(ctx, cluster, target)
+		if err != nil {
+			return err
+		}
+
+		for i := range secrets {
+			err = secretClient.Delete(ctx, &secrets[i])
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+
+			if err == nil {
+				controller.auditSecretDeleted(cluster, &secrets[i], audit.TriggerClusterDeleted)
+			}
+		}
 	}
 
-	return controller.Client.Delete(context.TODO(), &secretList.Items[0])
+	return nil
 }
 
-func (controller *GardenerClusterController) getSecret(shootName string) (*corev1.Secret, error) {
-	var secretList corev1.SecretList
+// listKubeconfigSecrets returns all existing secrets of the cluster, including all versions in immutable mode.
+func (controller *GardenerClusterController) listKubeconfigSecrets(ctx context.Context, cluster *imv1.GardenerCluster) ([]corev1.Secret, error) {
+	var secrets []corev1.Secret
 
-	shootNameSelector := client.MatchingLabels(map[string]string{
-		"kyma-project.io/shoot-name": shootName,
-	})
+	for _, target := range cluster.Spec.Kubeconfig.Targets() {
+		targetSecrets, err := controller.listTargetSecrets(ctx, cluster, target)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, targetSecrets...)
+	}
+
+	return secrets, nil
+}
 
-	err := controller.Client.List(context.Background(), &secretList, shootNameSelector)
+// listTargetSecrets returns the existing secrets of the target, including all versions in immutable mode.
+func (controller *GardenerClusterController) listTargetSecrets(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret) ([]corev1.Secret, error) {
+	if cluster.Spec.Kubeconfig.Immutable {
+		return controller.listSecretVersions(ctx, cluster, target)
+	}
+
+	secret, err := controller.getSecret(ctx, cluster, target)
 	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+
 		return nil, err
 	}
 
-	size := len(secretList.Items)
+	return []corev1.Secret{*secret}, nil
+}
+
+func (controller *GardenerClusterController) getSecret(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret) (*corev1.Secret, error) {
+	var secret corev1.Secret
 
-	if size == 0 {
-		return nil, k8serrors.NewNotFound(schema.GroupResource{}, "") //nolint:all TODO: update with valid GroupResource
+	key := types.NamespacedName{
+		Name:      target.Name,
+		Namespace: target.Namespace,
 	}
 
-	if size > 1 {
-		return nil, fmt.Errorf("unexpected numer of secrets found for shoot `%s`", shootName)
+	secretClient, err := controller.secretsFor(ctx, cluster, target)
+	if err != nil {
+		return nil, err
 	}
 
-	return &secretList.Items[0], nil
+	err = secretClient.Get(ctx, key, &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
 }
 
-func (controller *GardenerClusterController) createOrRotateKubeconfigSecret(ctx context.Context, cluster *imv1.GardenerCluster, lastSyncTime time.Time) (bool, error) {
-	existingSecret, err := controller.getSecret(cluster.Spec.Shoot.Name)
-	if err != nil && !k8serrors.IsNotFound(err) {
-		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToGetSecret, metav1.ConditionTrue, err)
+// getCurrentSecret returns the secret holding the current kubeconfig for the target.
+func (controller *GardenerClusterController) getCurrentSecret(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret) (*corev1.Secret, error) {
+	if cluster.Spec.Kubeconfig.Immutable {
+		return controller.getCurrentSecretVersion(ctx, cluster, target)
+	}
+
+	return controller.getSecret(ctx, cluster, target)
+}
+
+// createOrRotateKubeconfigSecret writes the kubeconfig to all target secrets of the cluster.
+// All targets are rotated together with a single kubeconfig, if any of them fails the whole rotation is reported as failed,
+// and the next attempt rewrites all targets again.
+func (controller *GardenerClusterController) createOrRotateKubeconfigSecret(ctx context.Context, cluster *imv1.GardenerCluster, lastSyncTime time.Time, rotationPeriod, expiration time.Duration) (bool, error) {
+	targets := cluster.Spec.Kubeconfig.Targets()
+	existingSecrets := make([]*corev1.Secret, len(targets))
+
+	for i, target := range targets {
+		existingSecret, err := controller.getCurrentSecret(ctx, cluster, target)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			cluster.UpdateSecretStatus(target, imv1.ConditionReasonFailedToGetSecret, err)
+			cluster.SetCondition(imv1.ConditionTypeSecretSynced, metav1.ConditionFalse, imv1.ConditionReasonFailedToGetSecret, err)
+			cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToGetSecret, metav1.ConditionTrue, err)
+			controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+			return true, err
+		}
+
+		existingSecrets[i] = existingSecret
+	}
+
+	err := controller.checkSecretsOwnership(cluster, existingSecrets)
+	if err != nil {
 		return true, err
 	}
 
-	if !secretNeedsToBeRotated(cluster, existingSecret, controller.rotationPeriod) {
+	drifted := secretsDrifted(cluster, targets, existingSecrets)
+	if drifted {
+		message := fmt.Sprintf("Secret %s in namespace %s has been modified by another actor, restoring it.", cluster.Spec.Kubeconfig.Secret.Name, cluster.Spec.Kubeconfig.Secret.Namespace)
+		controller.log.Info(message, loggingContextFromCluster(cluster)...)
+	}
+
+	recreated := controller.shootRecreated(ctx, cluster)
+	expiring := controller.credentialRotationDue(cluster, lastSyncTime)
+
+	if !drifted && !recreated && !expiring && !secretsNeedToBeRotated(cluster, existingSecrets, rotationPeriod) && !controller.externalStoresPending(cluster) && !encryptionChanged(cluster, existingSecrets) {
 		message := fmt.Sprintf("Secret %s in namespace %s does not need to be rotated yet.", cluster.Spec.Kubeconfig.Secret.Name, cluster.Spec.Kubeconfig.Secret.Namespace)
 		controller.log.Info(message, loggingContextFromCluster(cluster)...)
-		return false, nil
+
+		if controller.dryRun {
+			return controller.reportDryRun(cluster, metadataRestoreActions(targets, existingSecrets)), nil
+		}
+
+		return false, controller.restoreSecretsMetadata(ctx, cluster, targets, existingSecrets)
+	}
+
+	if controller.dryRun {
+		actions := append(syncActions(cluster, targets, existingSecrets, drifted), controller.externalStoreActions(cluster, "Write")...)
+
+		return controller.reportDryRun(cluster, actions), nil
 	}
 
 	if secretRotationForced(cluster) {
@@ -221,21 +754,362 @@ func (controller *GardenerClusterController) createOrRotateKubeconfigSecret(ctx
 		controller.log.Info(message, loggingContextFromCluster(cluster)...)
 	}
 
-	kubeconfig, err := controller.KubeconfigProvider.Fetch(cluster.Spec.Shoot.Name)
+	err = controller.checkGardenerAvailable(cluster, lastSyncTime)
+	if err != nil {
+		return true, err
+	}
+
+	err = controller.throttleRotation(cluster, lastSyncTime)
+	if err != nil {
+		return true, err
+	}
+
+	setProcessingState(cluster, existingSecrets[0])
+
+	provider, err := controller.kubeconfigProviderFor(ctx, cluster)
+	if err != nil {
+		controller.refundRotation(cluster, lastSyncTime)
+		cluster.SetCondition(imv1.ConditionTypeGardenerAccess, metav1.ConditionFalse, imv1.ConditionReasonInvalidGardenerCredentials, err)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonInvalidGardenerCredentials, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return true, err
+	}
+
+	adminKubeconfigExpiration := expiration
+	if cluster.Spec.Kubeconfig.AuthType() == imv1.AuthTypeServiceAccountToken {
+		adminKubeconfigExpiration = serviceAccountSetupExpiration
+	}
+
+	fetchCtx, fetchSpan := tracing.StartSpan(ctx, "Gardener.RequestKubeconfig")
+	kubeconfig, expirationTime, err := controller.fetchKubeconfig(fetchCtx, provider, cluster.Spec.Shoot.Name, adminKubeconfigExpiration)
+	tracing.End(fetchSpan, err)
+	controller.recordGardenerOutcome(err, time.Now())
+
+	if err != nil {
+		controller.refundRotation(cluster, lastSyncTime)
+
+		fetchErr := newKubeconfigFetchError(err)
+		setFetchFailedConditions(cluster, fetchErr)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, fetchErr.class.conditionReason(), metav1.ConditionTrue, err)
+
+		if meta.IsStatusConditionFalse(cluster.Status.Conditions, string(imv1.ConditionTypeShootAvailable)) {
+			controller.recordConditionEvent(cluster, imv1.ConditionTypeShootAvailable, corev1.EventTypeWarning)
+		} else {
+			controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+		}
+
+		return true, fetchErr
+	}
+
+	resetFetchBackoff(cluster)
+
+	cluster.SetCondition(imv1.ConditionTypeGardenerAccess, metav1.ConditionTrue, imv1.ConditionReasonGardenerAccessible, nil)
+	cluster.SetCondition(imv1.ConditionTypeShootAvailable, metav1.ConditionTrue, imv1.ConditionReasonShootFound, nil)
+
+	generateCtx, generateSpan := tracing.StartSpan(ctx, "Kubeconfig.Generate")
+	kubeconfig, expirationTime, err = controller.generateKubeconfig(generateCtx, cluster, kubeconfig, expirationTime, expiration)
+	if err == nil {
+		kubeconfig, err = controller.applyEndpointType(generateCtx, provider, cluster, kubeconfig)
+	}
+
+	if err == nil {
+		kubeconfig, err = controller.applyCABundle(generateCtx, cluster, kubeconfig)
+	}
+
+	tracing.End(generateSpan, err)
+
+	if err != nil {
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToGenerateKubeconfig, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return true, err
+	}
+
+	err = controller.validateKubeconfig(ctx, kubeconfig)
+	if err != nil {
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonInvalidKubeconfig, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return true, err
+	}
+
+	// The external stores are written first, a failure leaves the secrets unchanged, so that the rotation is retried.
+	externalStoresCtx, externalStoresSpan := tracing.StartSpan(ctx, "ExternalStores.Write")
+	err = controller.writeExternalStores(externalStoresCtx, cluster, kubeconfig, lastSyncTime)
+	tracing.End(externalStoresSpan, err)
+
+	if err != nil {
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToWriteExternalStore, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return true, err
+	}
+
+	secretKubeconfig, encryptionAnnotations, err := controller.encryptKubeconfig(ctx, cluster, kubeconfig)
 	if err != nil {
-		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToGetKubeconfig, metav1.ConditionTrue, err)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToEncryptKubeconfig, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
 		return true, err
 	}
 
-	if existingSecret != nil {
-		return true, controller.updateExistingSecret(ctx, kubeconfig, cluster, existingSecret, lastSyncTime)
+	secretKubeconfigs, err := controller.secretKubeconfigs(cluster, targets, secretKubeconfig)
+	if err != nil {
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToSealKubeconfig, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return true, err
 	}
 
-	return true, controller.createNewSecret(ctx, kubeconfig, cluster, lastSyncTime)
+	var rotationErr error
+	rotationErrReason := imv1.ConditionReasonFailedToUpdateSecret
+	trigger := rotationTrigger(cluster, existingSecrets, rotationPeriod, drifted, recreated, expiring)
+	stale := staleSecretStatuses(cluster)
+	cluster.Status.Secrets = nil
+
+	secretsCtx, secretsSpan := tracing.StartSpan(ctx, "Secrets.Write")
+
+	for i, target := range targets {
+		target = withEncryptionAnnotations(target, encryptionAnnotations)
+
+		switch {
+		case cluster.Spec.Kubeconfig.Immutable:
+			err = controller.createNextSecretVersion(secretsCtx, secretKubeconfigs[i], cluster, target, existingSecrets[i], lastSyncTime)
+		case existingSecrets[i] != nil:
+			err = controller.updateExistingSecret(secretsCtx, secretKubeconfigs[i], cluster, target, existingSecrets[i], lastSyncTime)
+		default:
+			err = controller.createNewSecret(secretsCtx, secretKubeconfigs[i], cluster, target, lastSyncTime)
+		}
+
+		if err == nil {
+			controller.auditSecretWritten(cluster, target, existingSecrets[i] == nil, trigger, kubeconfig)
+		}
+
+		if err != nil && rotationErr == nil {
+			rotationErr = err
+			if existingSecrets[i] == nil {
+				rotationErrReason = imv1.ConditionReasonFailedToCreateSecret
+			}
+		}
+	}
+
+	tracing.End(secretsSpan, rotationErr)
+	keepPendingRelocations(cluster, stale)
+
+	if rotationErr != nil {
+		cluster.SetCondition(imv1.ConditionTypeSecretSynced, metav1.ConditionFalse, rotationErrReason, rotationErr)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, rotationErrReason, metav1.ConditionTrue, rotationErr)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+		return true, rotationErr
+	}
+
+	reason := imv1.ConditionReasonKubeconfigSecretRotated
+
+	switch {
+	case drifted:
+		reason = imv1.ConditionReasonSecretDriftDetected
+	case recreated:
+		reason = imv1.ConditionReasonShootRecreated
+	case existingSecrets[0] == nil:
+		reason = imv1.ConditionReasonKubeconfigSecretCreated
+	}
+
+	cluster.SetCondition(imv1.ConditionTypeSecretSynced, metav1.ConditionTrue, reason, nil)
+	cluster.UpdateConditionForReadyState(imv1.ConditionTypeKubeconfigManagement, reason, metav1.ConditionTrue)
+	metrics.RecordRotation(string(reason))
+
+	if drifted {
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+	} else {
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeNormal)
+	}
+
+	shootInfoCtx, shootInfoSpan := tracing.StartSpan(ctx, "Gardener.GetShootInfo")
+	controller.updateShootInfo(shootInfoCtx, provider, cluster)
+	shootInfoSpan.End()
+
+	cluster.Status.KubeconfigExpirationTime = &metav1.Time{Time: expirationTime}
+	cluster.Status.LastSyncTime = &metav1.Time{Time: lastSyncTime}
+
+	if existingSecrets[0] != nil {
+		cluster.Status.LastRotationTime = &metav1.Time{Time: lastSyncTime}
+		cluster.Status.RotationCount++
+	}
+
+	return true, nil
+}
+
+// restoreSecretsMetadata reverts changes made by other actors to the labels and annotations defined in the spec.
+func (controller *GardenerClusterController) restoreSecretsMetadata(ctx context.Context, cluster *imv1.GardenerCluster, targets []imv1.Secret, existingSecrets []*corev1.Secret) error {
+	for i, target := range targets {
+		if !applySecretMetadata(target, existingSecrets[i]) {
+			continue
+		}
+
+		err := controller.updateSecret(ctx, cluster, target, existingSecrets[i])
+		if err != nil {
+			cluster.UpdateSecretStatus(target, imv1.ConditionReasonFailedToUpdateSecret, err)
+			cluster.SetCondition(imv1.ConditionTypeSecretSynced, metav1.ConditionFalse, imv1.ConditionReasonFailedToUpdateSecret, err)
+			cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToUpdateSecret, metav1.ConditionTrue, err)
+			controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+			return err
+		}
+
+		message := fmt.Sprintf("Metadata of secret %s in namespace %s has been restored.", target.Name, target.Namespace)
+		controller.log.Info(message, loggingContextFromCluster(cluster)...)
+	}
+
+	return nil
+}
+
+// applySecretMetadata sets labels and annotations defined for the target on the secret, and reports whether the secret has changed.
+func applySecretMetadata(target imv1.Secret, secret *corev1.Secret) bool {
+	labels, labelsChanged := mergeMetadata(secret.GetLabels(), target.Labels)
+	annotations, annotationsChanged := mergeMetadata(secret.GetAnnotations(), target.Annotations)
+
+	secret.SetLabels(labels)
+	secret.SetAnnotations(annotations)
+
+	return labelsChanged || annotationsChanged
+}
+
+func mergeMetadata(existing, desired map[string]string) (map[string]string, bool) {
+	changed := false
+
+	if existing == nil && len(desired) > 0 {
+		existing = map[string]string{}
+	}
+
+	for key, val := range desired {
+		if existingVal, found := existing[key]; !found || existingVal != val {
+			existing[key] = val
+			changed = true
+		}
+	}
+
+	return existing, changed
 }
 
-func secretNeedsToBeRotated(cluster *imv1.GardenerCluster, secret *corev1.Secret, rotationPeriod time.Duration) bool {
-	return secretRotationTimePassed(secret, rotationPeriod) || secretRotationForced(cluster)
+// serviceAccountFor returns the service account created in the shoot for the kubeconfig of the cluster,
+// and whether the kubeconfig is bound to a service account at all.
+func serviceAccountFor(cluster *imv1.GardenerCluster) (kubeconfig.ServiceAccount, bool) {
+	switch {
+	case cluster.Spec.Kubeconfig.AccessLevel == imv1.AccessLevelViewer:
+		return kubeconfig.ServiceAccount{
+			Name:        "infrastructure-manager-viewer",
+			Namespace:   "kube-system",
+			ClusterRole: "view",
+		}, true
+	case cluster.Spec.Kubeconfig.AuthType() == imv1.AuthTypeServiceAccountToken:
+		return kubeconfig.ServiceAccount{
+			Name:        "infrastructure-manager-admin",
+			Namespace:   "kube-system",
+			ClusterRole: "cluster-admin",
+		}, true
+	default:
+		return kubeconfig.ServiceAccount{}, false
+	}
+}
+
+// generateKubeconfig derives the kubeconfig stored in the secrets from the admin kubeconfig, according to the access level
+// and the authentication type of the cluster.
+func (controller *GardenerClusterController) generateKubeconfig(ctx context.Context, cluster *imv1.GardenerCluster, adminKubeconfig string, expirationTime time.Time, expiration time.Duration) (string, time.Time, error) {
+	serviceAccount, bound := serviceAccountFor(cluster)
+
+	switch {
+	case bound && cluster.Spec.Kubeconfig.AuthType() == imv1.AuthTypeServiceAccountToken:
+		return controller.tokenGenerator.Generate(ctx, adminKubeconfig, serviceAccount, controller.expirationOrDefault(expiration))
+	case bound:
+		return controller.tokenGenerator.Generate(ctx, adminKubeconfig, serviceAccount, time.Until(expirationTime))
+	}
+
+	if cluster.Spec.Kubeconfig.AuthType() == imv1.AuthTypeGardenlogin {
+		kubeconfig, err := controller.gardenloginKubeconfig(cluster, adminKubeconfig)
+
+		return kubeconfig, expirationTime, err
+	}
+
+	kubeconfig, err := kubeconfigForAuthType(cluster, adminKubeconfig)
+
+	return kubeconfig, expirationTime, err
+}
+
+func (controller *GardenerClusterController) gardenloginKubeconfig(cluster *imv1.GardenerCluster, adminKubeconfig string) (string, error) {
+	if controller.gardenlogin.gardenClusterIdentity == "" {
+		return "", errors.New("gardenlogin kubeconfigs are not enabled, the garden cluster identity is not configured")
+	}
+
+	return kubeconfig.ToGardenlogin(adminKubeconfig, kubeconfig.Gardenlogin{
+		GardenClusterIdentity: controller.gardenlogin.gardenClusterIdentity,
+		ShootNamespace:        controller.gardenlogin.shootNamespace,
+		ShootName:             cluster.Spec.Shoot.Name,
+	})
+}
+
+// kubeconfigForAuthType converts the admin kubeconfig requested from Gardener to the authentication type of the cluster.
+func kubeconfigForAuthType(cluster *imv1.GardenerCluster, adminKubeconfig string) (string, error) {
+	switch cluster.Spec.Kubeconfig.AuthType() {
+	case imv1.AuthTypeAdminKubeconfig:
+		return adminKubeconfig, nil
+	case imv1.AuthTypeOIDC:
+		if cluster.Spec.Kubeconfig.Auth.OIDC == nil {
+			return "", errors.New("OIDC configuration is missing")
+		}
+
+		return kubeconfig.ToOIDC(adminKubeconfig, *cluster.Spec.Kubeconfig.Auth.OIDC)
+	default:
+		return "", errors.Errorf("unsupported authentication type `%s`", cluster.Spec.Kubeconfig.AuthType())
+	}
+}
+
+// updateShootInfo refreshes the shoot metadata in the status, failures are not critical for the kubeconfig management.
+func (controller *GardenerClusterController) updateShootInfo(ctx context.Context, provider KubeconfigProvider, cluster *imv1.GardenerCluster) {
+	shootInfo, err := provider.FetchShootInfo(ctx, cluster.Spec.Shoot.Name)
+	if err != nil {
+		controller.log.Error(err, "Failed to fetch shoot metadata", loggingContextFromCluster(cluster)...)
+		return
+	}
+
+	cluster.Status.Shoot = &shootInfo
+}
+
+// setFetchFailedConditions distinguishes a missing shoot from problems with accessing Gardener.
+func setFetchFailedConditions(cluster *imv1.GardenerCluster, err kubeconfigFetchError) {
+	if err.class == fetchErrorNotFound {
+		cluster.SetCondition(imv1.ConditionTypeGardenerAccess, metav1.ConditionTrue, imv1.ConditionReasonGardenerAccessible, nil)
+		cluster.SetCondition(imv1.ConditionTypeShootAvailable, metav1.ConditionFalse, imv1.ConditionReasonShootNotFound, err.error)
+
+		return
+	}
+
+	cluster.SetCondition(imv1.ConditionTypeGardenerAccess, metav1.ConditionFalse, err.class.conditionReason(), err.error)
+}
+
+// setProcessingState marks the secret as being created, or rotated, the state is written together with the outcome of
+// the rotation, so each rotation updates the status once.
+func setProcessingState(cluster *imv1.GardenerCluster, existingSecret *corev1.Secret) {
+	reason := imv1.ConditionReasonKubeconfigSecretRotating
+	if existingSecret == nil {
+		reason = imv1.ConditionReasonKubeconfigSecretCreating
+	}
+
+	cluster.UpdateConditionForProcessingState(imv1.ConditionTypeKubeconfigManagement, reason, metav1.ConditionUnknown)
+}
+
+func secretsNeedToBeRotated(cluster *imv1.GardenerCluster, secrets []*corev1.Secret, rotationPeriod time.Duration) bool {
+	if secretRotationForced(cluster) {
+		return true
+	}
+
+	for _, secret := range secrets {
+		if secretRotationTimePassed(secret, rotationPeriod) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func secretRotationTimePassed(secret *corev1.Secret, rotationPeriod time.Duration) bool {
@@ -275,16 +1149,16 @@ func secretRotationForced(cluster *imv1.GardenerCluster) bool {
 	return found
 }
 
-func (controller *GardenerClusterController) createNewSecret(ctx context.Context, kubeconfig string, cluster *imv1.GardenerCluster, lastSyncTime time.Time) error {
-	newSecret := controller.newSecret(*cluster, kubeconfig, lastSyncTime)
-	err := controller.Client.Create(ctx, &newSecret)
+func (controller *GardenerClusterController) createNewSecret(ctx context.Context, kubeconfig string, cluster *imv1.GardenerCluster, target imv1.Secret, lastSyncTime time.Time) error {
+	newSecret := controller.newSecret(*cluster, target, kubeconfig, lastSyncTime)
+	err := controller.applySecret(ctx, cluster, target, &newSecret)
 	if err != nil {
-		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToCreateSecret, metav1.ConditionTrue, err)
+		cluster.UpdateSecretStatus(target, imv1.ConditionReasonFailedToCreateSecret, err)
 
 		return err
 	}
 
-	cluster.UpdateConditionForReadyState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonKubeconfigSecretCreated, metav1.ConditionTrue)
+	cluster.UpdateSecretStatus(target, imv1.ConditionReasonKubeconfigSecretCreated, nil)
 
 	message := fmt.Sprintf("Secret %s has been created in %s namespace.", newSecret.Name, newSecret.Namespace)
 	controller.log.Info(message, loggingContextFromCluster(cluster)...)
@@ -292,24 +1166,24 @@ func (controller *GardenerClusterController) createNewSecret(ctx context.Context
 	return nil
 }
 
-func (controller *GardenerClusterController) updateExistingSecret(ctx context.Context, kubeconfig string, cluster *imv1.GardenerCluster, existingSecret *corev1.Secret, lastSyncTime time.Time) error {
-	existingSecret.Data[cluster.Spec.Kubeconfig.Secret.Key] = []byte(kubeconfig)
-	annotations := existingSecret.GetAnnotations()
-	if annotations == nil {
-		annotations = map[string]string{}
+func (controller *GardenerClusterController) updateExistingSecret(ctx context.Context, kubeconfig string, cluster *imv1.GardenerCluster, target imv1.Secret, existingSecret *corev1.Secret, lastSyncTime time.Time) error {
+	err := controller.removeStaleEncryptionAnnotations(ctx, cluster, target, existingSecret)
+	if err != nil {
+		cluster.UpdateSecretStatus(target, imv1.ConditionReasonFailedToUpdateSecret, err)
+
+		return err
 	}
 
-	annotations[lastKubeconfigSyncAnnotation] = lastSyncTime.UTC().Format(time.RFC3339)
-	existingSecret.SetAnnotations(annotations)
+	desiredSecret := controller.newSecret(*cluster, target, kubeconfig, lastSyncTime)
 
-	err := controller.Client.Update(ctx, existingSecret)
+	err = controller.applySecret(ctx, cluster, target, &desiredSecret)
 	if err != nil {
-		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonFailedToUpdateSecret, metav1.ConditionTrue, err)
+		cluster.UpdateSecretStatus(target, imv1.ConditionReasonFailedToUpdateSecret, err)
 
 		return err
 	}
 
-	cluster.UpdateConditionForReadyState(imv1.ConditionTypeKubeconfigManagement, imv1.ConditionReasonKubeconfigSecretRotated, metav1.ConditionTrue)
+	cluster.UpdateSecretStatus(target, imv1.ConditionReasonKubeconfigSecretRotated, nil)
 
 	message := fmt.Sprintf("Secret %s has been updated in %s namespace.", existingSecret.Name, existingSecret.Namespace)
 	controller.log.Info(message, loggingContextFromCluster(cluster)...)
@@ -342,29 +1216,63 @@ func (controller *GardenerClusterController) removeForceRotationAnnotation(ctx c
 	return nil
 }
 
-func (controller *GardenerClusterController) newSecret(cluster imv1.GardenerCluster, kubeconfig string, lastSyncTime time.Time) corev1.Secret {
+func (controller *GardenerClusterController) newSecret(cluster imv1.GardenerCluster, target imv1.Secret, kubeconfig string, lastSyncTime time.Time) corev1.Secret {
 	labels := map[string]string{}
 
 	for key, val := range cluster.Labels {
 		labels[key] = val
 	}
-	labels["operator.kyma-project.io/managed-by"] = "infrastructure-manager"
+	for key, val := range target.Labels {
+		labels[key] = val
+	}
+	labels[managedByLabel] = managedByLabelValue
 	labels[clusterCRNameLabel] = cluster.Name
+	labels[clusterCRNamespaceLabel] = cluster.Namespace
+
+	annotations := map[string]string{}
+	for key, val := range target.Annotations {
+		annotations[key] = val
+	}
+	annotations[lastKubeconfigSyncAnnotation] = lastSyncTime.UTC().Format(time.RFC3339)
+	annotations[kubeconfigHashAnnotation] = kubeconfigHash([]byte(kubeconfig))
 
 	return corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        cluster.Spec.Kubeconfig.Secret.Name,
-			Namespace:   cluster.Spec.Kubeconfig.Secret.Namespace,
+			Name:        target.Name,
+			Namespace:   target.Namespace,
 			Labels:      labels,
-			Annotations: map[string]string{lastKubeconfigSyncAnnotation: lastSyncTime.UTC().Format(time.RFC3339)},
+			Annotations: annotations,
 		},
-		StringData: map[string]string{cluster.Spec.Kubeconfig.Secret.Key: kubeconfig},
+		Data: map[string][]byte{target.Key: []byte(kubeconfig)},
 	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (controller *GardenerClusterController) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&imv1.GardenerCluster{}, builder.WithPredicates()).
-		Complete(controller)
+	err := metrics.RegisterManagedSecrets(mgr.GetClient(), client.MatchingLabels{managedByLabel: managedByLabelValue})
+	if err != nil {
+		return err
+	}
+
+	clusterController, err := ctrl.NewControllerManagedBy(mgr).
+		For(&imv1.GardenerCluster{}, builder.WithPredicates(controller.selectedClusterPredicate())).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(secretToGardenerCluster), builder.WithPredicates(managedSecretPredicate())).
+		WithOptions(crcontroller.Options{MaxConcurrentReconciles: controller.maxConcurrency}).
+		Build(controller)
+	if err != nil {
+		return err
+	}
+
+	if controller.priorityQueue {
+		err = usePriorityQueue(clusterController, mgr.GetCache())
+		if err != nil {
+			return err
+		}
+	}
+
+	if controller.startupWindow > 0 {
+		return controller.smearStartup(clusterController)
+	}
+
+	return nil
 }
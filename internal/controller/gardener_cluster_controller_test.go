@@ -9,8 +9,10 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("Gardener Cluster controller", func() {
@@ -45,6 +47,17 @@ var _ = Describe("Gardener Cluster controller", func() {
 				return newGardenerCluster.Status.State == imv1.ReadyState
 			}, time.Second*30, time.Second*3).Should(BeTrue())
 
+			Expect(newGardenerCluster.Status.ObservedGeneration).To(Equal(newGardenerCluster.Generation))
+			Expect(meta.IsStatusConditionTrue(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeGardenerAccess))).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeShootAvailable))).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeSecretSynced))).To(BeTrue())
+			Expect(newGardenerCluster.Status.LastSyncTime).NotTo(BeNil())
+			Expect(newGardenerCluster.Status.LastRotationTime).To(BeNil())
+			Expect(newGardenerCluster.Status.RotationCount).To(BeZero())
+			Expect(newGardenerCluster.Status.KubeconfigExpirationTime).NotTo(BeNil())
+			Expect(newGardenerCluster.Status.KubeconfigExpirationTime.Time).To(BeTemporally("==", TestKubeconfigExpirationTime))
+			Expect(newGardenerCluster.Status.Shoot).To(Equal(&TestShootInfo))
+
 			err := k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret)
 			Expect(err).To(BeNil())
 			expectedSecret := fixNewSecret(secretName, namespace, kymaName, shootName, "kubeconfig1", "")
@@ -53,6 +66,22 @@ var _ = Describe("Gardener Cluster controller", func() {
 			lastSyncTime := kubeconfigSecret.Annotations[lastKubeconfigSyncAnnotation]
 			Expect(lastSyncTime).ToNot(BeEmpty())
 
+			By("Wait for event")
+			Eventually(func() bool {
+				var events corev1.EventList
+				err := k8sClient.List(context.Background(), &events, client.InNamespace(namespace))
+				if err != nil {
+					return false
+				}
+
+				for _, event := range events.Items {
+					if event.InvolvedObject.Name == kymaName && event.Reason == string(imv1.ConditionReasonKubeconfigSecretCreated) {
+						return true
+					}
+				}
+
+				return false
+			}, time.Second*30, time.Second*3).Should(BeTrue())
 		})
 
 		It("Should delete secret", func() {
@@ -84,6 +113,42 @@ var _ = Describe("Gardener Cluster controller", func() {
 			}, time.Second*30, time.Second*3).Should(BeTrue())
 		})
 
+		It("Should retain secret when requested", func() {
+			kymaName := "kymaname10"
+			secretName := "secret-name10"
+			shootName := "shootName10"
+			namespace := "default"
+
+			By("Create GardenerCluster CR")
+
+			gardenerClusterCR := newTestGardenerClusterCR(kymaName, namespace, shootName, secretName).
+				WithLabels(fixGardenerClusterLabels(kymaName, shootName)).
+				WithRetainOnDelete().
+				ToCluster()
+			Expect(k8sClient.Create(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			By("Wait for secret creation")
+			var kubeconfigSecret corev1.Secret
+			secretKey := types.NamespacedName{Name: secretName, Namespace: namespace}
+
+			Eventually(func() bool {
+				return k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			By("Delete Cluster CR")
+			Expect(k8sClient.Delete(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			By("Wait for Cluster CR deletion")
+			gardenerClusterKey := types.NamespacedName{Name: gardenerClusterCR.Name, Namespace: gardenerClusterCR.Namespace}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), gardenerClusterKey, &imv1.GardenerCluster{})
+				return err != nil && k8serrors.IsNotFound(err)
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			Expect(k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret)).To(Succeed())
+			Expect(kubeconfigSecret.Labels).NotTo(HaveKey("operator.kyma-project.io/managed-by"))
+		})
+
 		It("Should set Error status on CR if failed to fetch kubeconfig", func() {
 			kymaName := "kymaname3"
 			secretName := "secret-name3"
@@ -103,6 +168,253 @@ var _ = Describe("Gardener Cluster controller", func() {
 
 				return newGardenerCluster.Status.State == imv1.ErrorState
 			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			Expect(meta.IsStatusConditionFalse(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeGardenerAccess))).To(BeTrue())
+			Expect(newGardenerCluster.Status.KubeconfigFetchFailures).To(BeNumerically(">=", 1))
+			Expect(newGardenerCluster.Status.NextRetryTime).NotTo(BeNil())
+		})
+
+		It("Should create all target secrets", func() {
+			kymaName := "kymaname8"
+			secretName := "secret-name8"
+			shootName := "shootName8"
+			namespace := "default"
+			additionalSecret := imv1.Secret{Name: "secret-name8-copy", Namespace: "kube-public", Key: "kubeconfig"}
+
+			By("Create GardenerCluster CR")
+
+			gardenerClusterCR := newTestGardenerClusterCR(kymaName, namespace, shootName, secretName).
+				WithLabels(fixGardenerClusterLabels(kymaName, shootName)).
+				WithAdditionalSecrets(additionalSecret).
+				ToCluster()
+			Expect(k8sClient.Create(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			By("Wait for all secrets creation")
+			var kubeconfigSecret corev1.Secret
+			var additionalKubeconfigSecret corev1.Secret
+			secretKey := types.NamespacedName{Name: secretName, Namespace: namespace}
+			additionalSecretKey := types.NamespacedName{Name: additionalSecret.Name, Namespace: additionalSecret.Namespace}
+
+			Eventually(func() bool {
+				return k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret) == nil &&
+					k8sClient.Get(context.Background(), additionalSecretKey, &additionalKubeconfigSecret) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			Expect(string(kubeconfigSecret.Data["config"])).To(Equal("kubeconfig8"))
+			Expect(string(additionalKubeconfigSecret.Data["kubeconfig"])).To(Equal("kubeconfig8"))
+			Expect(additionalKubeconfigSecret.Annotations[lastKubeconfigSyncAnnotation]).To(Equal(kubeconfigSecret.Annotations[lastKubeconfigSyncAnnotation]))
+
+			gardenerClusterKey := types.NamespacedName{Name: gardenerClusterCR.Name, Namespace: gardenerClusterCR.Namespace}
+			var newGardenerCluster imv1.GardenerCluster
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), gardenerClusterKey, &newGardenerCluster)
+				if err != nil {
+					return false
+				}
+
+				return newGardenerCluster.Status.State == imv1.ReadyState
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			Expect(newGardenerCluster.Status.Secrets).To(HaveLen(2))
+			for _, secretStatus := range newGardenerCluster.Status.Secrets {
+				Expect(secretStatus.Status).To(Equal(metav1.ConditionTrue))
+			}
+		})
+
+		It("Should apply custom labels and annotations to the secret", func() {
+			kymaName := "kymaname9"
+			secretName := "secret-name9"
+			shootName := "shootName9"
+			namespace := "default"
+
+			gardenerClusterCR := newTestGardenerClusterCR(kymaName, namespace, shootName, secretName).
+				WithLabels(fixGardenerClusterLabels(kymaName, shootName)).
+				WithSecretMetadata(map[string]string{"team": "sre"}, map[string]string{"owner": "sre@example.com"}).
+				ToCluster()
+			Expect(k8sClient.Create(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			var kubeconfigSecret corev1.Secret
+			secretKey := types.NamespacedName{Name: secretName, Namespace: namespace}
+
+			Eventually(func() bool {
+				return k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			Expect(kubeconfigSecret.Labels).To(HaveKeyWithValue("team", "sre"))
+			Expect(kubeconfigSecret.Labels).To(HaveKeyWithValue("operator.kyma-project.io/managed-by", "infrastructure-manager"))
+			Expect(kubeconfigSecret.Annotations).To(HaveKeyWithValue("owner", "sre@example.com"))
+			Expect(kubeconfigSecret.Annotations).To(HaveKey(lastKubeconfigSyncAnnotation))
+		})
+
+		It("Should create new secret version on rotation in immutable mode", func() {
+			kymaName := "kymaname11"
+			secretName := "secret-name11"
+			shootName := "shootName11"
+			namespace := "default"
+
+			gardenerClusterCR := newTestGardenerClusterCR(kymaName, namespace, shootName, secretName).
+				WithLabels(fixGardenerClusterLabels(kymaName, shootName)).
+				WithImmutableSecrets().
+				ToCluster()
+			Expect(k8sClient.Create(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			By("Wait for the first secret version")
+			var kubeconfigSecret corev1.Secret
+			firstVersionKey := types.NamespacedName{Name: secretName + "-v1", Namespace: namespace}
+
+			Eventually(func() bool {
+				return k8sClient.Get(context.Background(), firstVersionKey, &kubeconfigSecret) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			Expect(kubeconfigSecret.Immutable).NotTo(BeNil())
+			Expect(*kubeconfigSecret.Immutable).To(BeTrue())
+
+			gardenerClusterKey := types.NamespacedName{Name: gardenerClusterCR.Name, Namespace: gardenerClusterCR.Namespace}
+			var newGardenerCluster imv1.GardenerCluster
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), gardenerClusterKey, &newGardenerCluster)
+				if err != nil || len(newGardenerCluster.Status.Secrets) == 0 {
+					return false
+				}
+
+				return newGardenerCluster.Status.Secrets[0].CurrentSecret == firstVersionKey.Name
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			By("Force rotation")
+			newGardenerCluster.SetAnnotations(map[string]string{forceKubeconfigRotationAnnotation: "true"})
+			Expect(k8sClient.Update(context.Background(), &newGardenerCluster)).To(Succeed())
+
+			secondVersionKey := types.NamespacedName{Name: secretName + "-v2", Namespace: namespace}
+			Eventually(func() bool {
+				return k8sClient.Get(context.Background(), secondVersionKey, &kubeconfigSecret) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			By("Previous version is kept")
+			Expect(k8sClient.Get(context.Background(), firstVersionKey, &kubeconfigSecret)).To(Succeed())
+		})
+
+		It("Should restore secret modified by another actor", func() {
+			kymaName := "kymaname12"
+			secretName := "secret-name12"
+			shootName := "shootName12"
+			namespace := "default"
+
+			gardenerClusterCR := fixGardenerClusterCR(kymaName, namespace, shootName, secretName)
+			Expect(k8sClient.Create(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			var kubeconfigSecret corev1.Secret
+			secretKey := types.NamespacedName{Name: secretName, Namespace: namespace}
+
+			Eventually(func() bool {
+				return k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			By("Modify secret")
+			kubeconfigSecret.Data["config"] = []byte("tampered")
+			Expect(k8sClient.Update(context.Background(), &kubeconfigSecret)).To(Succeed())
+
+			By("Wait for secret restoration")
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret)
+				if err != nil {
+					return false
+				}
+
+				return string(kubeconfigSecret.Data["config"]) == "kubeconfig12"
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			gardenerClusterKey := types.NamespacedName{Name: gardenerClusterCR.Name, Namespace: gardenerClusterCR.Namespace}
+			var newGardenerCluster imv1.GardenerCluster
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), gardenerClusterKey, &newGardenerCluster)
+				if err != nil {
+					return false
+				}
+
+				condition := meta.FindStatusCondition(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeKubeconfigManagement))
+
+				return condition != nil && condition.Reason == string(imv1.ConditionReasonSecretDriftDetected)
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+		})
+
+		It("Should not manage secret while reconciliation is suspended", func() {
+			kymaName := "kymaname14"
+			secretName := "secret-name14"
+			shootName := "shootName14"
+			namespace := "default"
+
+			gardenerClusterCR := newTestGardenerClusterCR(kymaName, namespace, shootName, secretName).
+				WithLabels(fixGardenerClusterLabels(kymaName, shootName)).
+				WithAnnotations(map[string]string{reconcilerDisabledAnnotation: "true"}).
+				ToCluster()
+			Expect(k8sClient.Create(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			gardenerClusterKey := types.NamespacedName{Name: gardenerClusterCR.Name, Namespace: gardenerClusterCR.Namespace}
+			var newGardenerCluster imv1.GardenerCluster
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), gardenerClusterKey, &newGardenerCluster)
+				if err != nil {
+					return false
+				}
+
+				return meta.IsStatusConditionTrue(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeSuspended))
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			var kubeconfigSecret corev1.Secret
+			secretKey := types.NamespacedName{Name: secretName, Namespace: namespace}
+			err := k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+			By("Resume reconciliation")
+			delete(newGardenerCluster.Annotations, reconcilerDisabledAnnotation)
+			Expect(k8sClient.Update(context.Background(), &newGardenerCluster)).To(Succeed())
+
+			Eventually(func() bool {
+				return k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), gardenerClusterKey, &newGardenerCluster)
+				if err != nil {
+					return false
+				}
+
+				return meta.FindStatusCondition(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeSuspended)) == nil
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+		})
+
+		It("Should set Error status on CR if rotation interval exceeds the rotation period", func() {
+			kymaName := "kymaname7"
+			secretName := "secret-name7"
+			shootName := "shootName7"
+			namespace := "default"
+
+			gardenerClusterCR := newTestGardenerClusterCR(kymaName, namespace, shootName, secretName).
+				WithLabels(fixGardenerClusterLabels(kymaName, shootName)).
+				WithRotationInterval(2 * TestKubeconfigValidityTime).
+				ToCluster()
+			Expect(k8sClient.Create(context.Background(), &gardenerClusterCR)).To(Succeed())
+
+			gardenerClusterKey := types.NamespacedName{Name: gardenerClusterCR.Name, Namespace: gardenerClusterCR.Namespace}
+			var newGardenerCluster imv1.GardenerCluster
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), gardenerClusterKey, &newGardenerCluster)
+				if err != nil {
+					return false
+				}
+
+				return newGardenerCluster.Status.State == imv1.ErrorState
+			}, time.Second*30, time.Second*3).Should(BeTrue())
+
+			condition := meta.FindStatusCondition(newGardenerCluster.Status.Conditions, string(imv1.ConditionTypeKubeconfigManagement))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(string(imv1.ConditionReasonInvalidRotationInterval)))
+
+			By("Secret is not created")
+			var kubeconfigSecret corev1.Secret
+			secretKey := types.NamespacedName{Name: secretName, Namespace: namespace}
+			err := k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
 		})
 	})
 
@@ -147,6 +459,9 @@ var _ = Describe("Gardener Cluster controller", func() {
 				return readyState && !forceRotationAnnotationFound
 			}, time.Second*45, time.Second*3).Should(BeTrue())
 
+			Expect(newGardenerCluster.Status.LastRotationTime).NotTo(BeNil())
+			Expect(newGardenerCluster.Status.RotationCount).To(BeNumerically(">=", 1))
+
 			err := k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret)
 			Expect(err).To(BeNil())
 			Expect(string(kubeconfigSecret.Data["config"])).To(Equal(expectedKubeconfig))
@@ -190,10 +505,156 @@ var _ = Describe("Gardener Cluster controller", func() {
 			}, time.Second*45, time.Second*3).Should(BeTrue())
 		})
 	})
+
+	Context("Kubeconfig fetch backoff", func() {
+		It("Should double the backoff on every failure up to the maximum", func() {
+			controller := (&GardenerClusterController{}).WithFetchBackoff(time.Second, 10*time.Second)
+
+			Expect(controller.fetchBackoff(1)).To(Equal(time.Second))
+			Expect(controller.fetchBackoff(2)).To(Equal(2 * time.Second))
+			Expect(controller.fetchBackoff(4)).To(Equal(8 * time.Second))
+			Expect(controller.fetchBackoff(5)).To(Equal(10 * time.Second))
+			Expect(controller.fetchBackoff(100)).To(Equal(10 * time.Second))
+		})
+
+		It("Should not delay the retry after spec change", func() {
+			now := time.Now()
+			cluster := imv1.GardenerCluster{}
+			cluster.Generation = 2
+			cluster.Status.ObservedGeneration = 2
+			cluster.Status.NextRetryTime = &metav1.Time{Time: now.Add(time.Minute)}
+
+			Expect(fetchRetryPending(&cluster, now)).To(Equal(time.Minute))
+
+			cluster.Generation = 3
+			Expect(fetchRetryPending(&cluster, now)).To(BeZero())
+		})
+	})
+
+	Context("Requeue", func() {
+		It("Should requeue after the rotation period without jitter", func() {
+			controller := &GardenerClusterController{}
+
+			Expect(controller.resultWithRequeue(time.Hour).RequeueAfter).To(Equal(time.Hour))
+		})
+
+		It("Should cap the requeue interval with the rotation period", func() {
+			controller := (&GardenerClusterController{}).WithRequeue(2*time.Hour, 0)
+
+			Expect(controller.resultWithRequeue(time.Hour).RequeueAfter).To(Equal(time.Hour))
+		})
+
+		It("Should shorten the requeue interval by at most the jitter", func() {
+			controller := (&GardenerClusterController{}).WithRequeue(10*time.Minute, 0.1)
+
+			for i := 0; i < 100; i++ {
+				requeueAfter := controller.resultWithRequeue(time.Hour).RequeueAfter
+				Expect(requeueAfter).To(BeNumerically("<=", 10*time.Minute))
+				Expect(requeueAfter).To(BeNumerically(">=", 9*time.Minute))
+			}
+		})
+	})
+
+	Context("Purpose", func() {
+		intervals := map[imv1.Purpose]time.Duration{imv1.PurposeProduction: time.Hour, imv1.PurposeEvaluation: 48 * time.Hour}
+
+		It("Should default the rotation period by the purpose of the shoot", func() {
+			controller := (&GardenerClusterController{}).WithPurposeRotationIntervals(intervals)
+			cluster := &imv1.GardenerCluster{Status: imv1.GardenerClusterStatus{Shoot: &imv1.ShootInfo{Purpose: imv1.PurposeProduction}}}
+
+			Expect(controller.rotationPeriodFor(cluster, 6*time.Hour)).To(Equal(time.Hour))
+		})
+
+		It("Should prefer the purpose of the spec, and cap the interval with the rotation period", func() {
+			controller := (&GardenerClusterController{}).WithPurposeRotationIntervals(intervals)
+			cluster := &imv1.GardenerCluster{
+				Spec:   imv1.GardenerClusterSpec{Purpose: imv1.PurposeEvaluation},
+				Status: imv1.GardenerClusterStatus{Shoot: &imv1.ShootInfo{Purpose: imv1.PurposeProduction}},
+			}
+
+			Expect(controller.rotationPeriodFor(cluster, 6*time.Hour)).To(Equal(6 * time.Hour))
+		})
+	})
+
+	Context("Expiration", func() {
+		fixClusterWithExpiration := func(expiration time.Duration) *imv1.GardenerCluster {
+			expirationSeconds := int64(expiration.Seconds())
+
+			return &imv1.GardenerCluster{Spec: imv1.GardenerClusterSpec{Kubeconfig: imv1.Kubeconfig{ExpirationSeconds: &expirationSeconds}}}
+		}
+
+		It("Should use the controller-wide expiration and rotation period when not set", func() {
+			controller := (&GardenerClusterController{rotationPeriod: time.Hour}).WithExpirationLimits(10*time.Minute, 24*time.Hour)
+
+			expiration, err := controller.expirationFor(&imv1.GardenerCluster{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expiration).To(BeZero())
+			Expect(controller.maxRotationPeriodFor(expiration)).To(Equal(time.Hour))
+		})
+
+		It("Should adjust the rotation period to the per-cluster expiration", func() {
+			controller := (&GardenerClusterController{rotationPeriod: time.Hour}).WithExpirationLimits(10*time.Minute, 24*time.Hour)
+
+			expiration, err := controller.expirationFor(fixClusterWithExpiration(10 * time.Hour))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expiration).To(Equal(10 * time.Hour))
+			Expect(controller.maxRotationPeriodFor(expiration)).To(Equal(6 * time.Hour))
+		})
+
+		It("Should reject expiration outside of the limits", func() {
+			controller := (&GardenerClusterController{rotationPeriod: time.Hour}).WithExpirationLimits(10*time.Minute, 24*time.Hour)
+
+			_, err := controller.expirationFor(fixClusterWithExpiration(5 * time.Minute))
+			Expect(err).To(HaveOccurred())
+
+			_, err = controller.expirationFor(fixClusterWithExpiration(48 * time.Hour))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should derive the default expiration from the rotation period", func() {
+			controller := &GardenerClusterController{rotationPeriod: 6 * time.Hour}
+
+			Expect(controller.expirationOrDefault(0)).To(Equal(10 * time.Hour))
+			Expect(controller.expirationOrDefault(time.Hour)).To(Equal(time.Hour))
+		})
+	})
+
+	Context("Service account kubeconfig", func() {
+		It("Should bind service account token kubeconfig to the cluster-admin role", func() {
+			cluster := &imv1.GardenerCluster{Spec: imv1.GardenerClusterSpec{Kubeconfig: imv1.Kubeconfig{
+				Auth: &imv1.Auth{Type: imv1.AuthTypeServiceAccountToken},
+			}}}
+
+			serviceAccount, bound := serviceAccountFor(cluster)
+
+			Expect(bound).To(BeTrue())
+			Expect(serviceAccount.ClusterRole).To(Equal("cluster-admin"))
+		})
+
+		It("Should bind viewer kubeconfig to the view role", func() {
+			cluster := &imv1.GardenerCluster{Spec: imv1.GardenerClusterSpec{Kubeconfig: imv1.Kubeconfig{
+				AccessLevel: imv1.AccessLevelViewer,
+				Auth:        &imv1.Auth{Type: imv1.AuthTypeServiceAccountToken},
+			}}}
+
+			serviceAccount, bound := serviceAccountFor(cluster)
+
+			Expect(bound).To(BeTrue())
+			Expect(serviceAccount.ClusterRole).To(Equal("view"))
+		})
+
+		It("Should not bind admin kubeconfig to a service account", func() {
+			_, bound := serviceAccountFor(&imv1.GardenerCluster{})
+
+			Expect(bound).To(BeFalse())
+		})
+	})
 })
 
 func fixNewSecret(name, namespace, kymaName, shootName, data string, lastSyncTime string) corev1.Secret {
-	labels := fixSecretLabels(kymaName, shootName)
+	labels := fixSecretLabels(kymaName, namespace, shootName)
 	annotations := map[string]string{lastKubeconfigSyncAnnotation: lastSyncTime}
 
 	builder := newTestSecret(name, namespace)
@@ -237,10 +698,11 @@ type TestSecret struct {
 	secret corev1.Secret
 }
 
-func fixSecretLabels(kymaName, shootName string) map[string]string {
+func fixSecretLabels(kymaName, namespace, shootName string) map[string]string {
 	labels := fixGardenerClusterLabels(kymaName, shootName)
 	labels["operator.kyma-project.io/managed-by"] = "infrastructure-manager"
 	labels["operator.kyma-project.io/cluster-name"] = kymaName
+	labels["operator.kyma-project.io/cluster-namespace"] = namespace
 	return labels
 }
 
@@ -293,6 +755,37 @@ func (sb *TestGardenerClusterCR) WithAnnotations(annotations map[string]string)
 	return sb
 }
 
+func (sb *TestGardenerClusterCR) WithRotationInterval(rotationInterval time.Duration) *TestGardenerClusterCR {
+	sb.gardenerCluster.Spec.Kubeconfig.RotationInterval = &metav1.Duration{Duration: rotationInterval}
+
+	return sb
+}
+
+func (sb *TestGardenerClusterCR) WithAdditionalSecrets(secrets ...imv1.Secret) *TestGardenerClusterCR {
+	sb.gardenerCluster.Spec.Kubeconfig.AdditionalSecrets = secrets
+
+	return sb
+}
+
+func (sb *TestGardenerClusterCR) WithSecretMetadata(labels, annotations map[string]string) *TestGardenerClusterCR {
+	sb.gardenerCluster.Spec.Kubeconfig.Secret.Labels = labels
+	sb.gardenerCluster.Spec.Kubeconfig.Secret.Annotations = annotations
+
+	return sb
+}
+
+func (sb *TestGardenerClusterCR) WithRetainOnDelete() *TestGardenerClusterCR {
+	sb.gardenerCluster.Spec.Kubeconfig.RetainOnDelete = true
+
+	return sb
+}
+
+func (sb *TestGardenerClusterCR) WithImmutableSecrets() *TestGardenerClusterCR {
+	sb.gardenerCluster.Spec.Kubeconfig.Immutable = true
+
+	return sb
+}
+
 func (sb *TestGardenerClusterCR) ToCluster() imv1.GardenerCluster {
 	return sb.gardenerCluster
 }
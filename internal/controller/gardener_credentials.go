@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GardenerProjects returns the kubeconfig provider of the Gardener project of the kubeconfig, the key identifies the
+// secret the kubeconfig is read from.
+type GardenerProjects func(key string, rawKubeconfig []byte) (KubeconfigProvider, error)
+
+// WithGardenerCredentials lets the clusters select the Gardener project of their shoot with their own credentials,
+// without it only the project of the controller is supported.
+func (controller *GardenerClusterController) WithGardenerCredentials(projects GardenerProjects) *GardenerClusterController {
+	controller.gardenerProjects = projects
+
+	return controller
+}
+
+// kubeconfigProviderFor returns the provider of the Gardener project of the cluster. The credentials are read from the
+// namespace of the cluster only, so a cluster can't use the credentials of another namespace.
+func (controller *GardenerClusterController) kubeconfigProviderFor(ctx context.Context, cluster *imv1.GardenerCluster) (KubeconfigProvider, error) {
+	if cluster.Spec.Gardener == nil {
+		return controller.KubeconfigProvider, nil
+	}
+
+	if controller.gardenerProjects == nil {
+		return nil, errors.New("the controller does not support Gardener credentials of the clusters")
+	}
+
+	secretRef := cluster.Spec.Gardener.SecretRef
+	key := client.ObjectKey{Name: secretRef.Name, Namespace: cluster.Namespace}
+
+	var secret corev1.Secret
+
+	err := controller.Get(ctx, key, &secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the Gardener credentials %s", key)
+	}
+
+	rawKubeconfig, found := secret.Data[secretRef.KeyOrDefault()]
+	if !found {
+		return nil, errors.Errorf("the Gardener credentials %s have no key %s", key, secretRef.KeyOrDefault())
+	}
+
+	return controller.gardenerProjects(key.String(), rawKubeconfig)
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/controller/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Gardener credentials", func() {
+	defaultProvider := &mocks.KubeconfigProvider{}
+	projectProvider := &mocks.KubeconfigProvider{}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gardener-eu", Namespace: "tenant"},
+		Data:       map[string][]byte{imv1.DefaultGardenerCredentialsKey: []byte("kubeconfig")},
+	}
+
+	var requestedKey string
+
+	newController := func() *GardenerClusterController {
+		controller := &GardenerClusterController{
+			Client:             fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(credentials).Build(),
+			KubeconfigProvider: defaultProvider,
+		}
+
+		return controller.WithGardenerCredentials(func(key string, rawKubeconfig []byte) (KubeconfigProvider, error) {
+			requestedKey = key
+			Expect(string(rawKubeconfig)).To(Equal("kubeconfig"))
+
+			return projectProvider, nil
+		})
+	}
+
+	clusterWithCredentials := func(namespace, secretName string) *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: namespace},
+			Spec: imv1.GardenerClusterSpec{
+				Gardener: &imv1.GardenerCredentials{SecretRef: imv1.GardenerCredentialsSecretRef{Name: secretName}},
+			},
+		}
+	}
+
+	It("Should use the project of the controller without credentials", func() {
+		provider, err := newController().kubeconfigProviderFor(context.Background(), &imv1.GardenerCluster{})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provider).To(BeIdenticalTo(defaultProvider))
+	})
+
+	It("Should use the project of the credentials in the namespace of the cluster", func() {
+		provider, err := newController().kubeconfigProviderFor(context.Background(), clusterWithCredentials("tenant", "gardener-eu"))
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provider).To(BeIdenticalTo(projectProvider))
+		Expect(requestedKey).To(Equal("tenant/gardener-eu"))
+	})
+
+	It("Should not read the credentials from another namespace", func() {
+		_, err := newController().kubeconfigProviderFor(context.Background(), clusterWithCredentials("other-tenant", "gardener-eu"))
+
+		Expect(err).To(MatchError(ContainSubstring("failed to get the Gardener credentials other-tenant/gardener-eu")))
+	})
+
+	It("Should fail when the controller does not support credentials", func() {
+		controller := &GardenerClusterController{KubeconfigProvider: defaultProvider}
+
+		_, err := controller.kubeconfigProviderFor(context.Background(), clusterWithCredentials("tenant", "gardener-eu"))
+
+		Expect(err).To(HaveOccurred())
+	})
+})
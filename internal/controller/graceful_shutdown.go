@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// WithShutdownGracePeriod lets the reconciliations running when the controller stops complete their secret writes, and
+// status updates within the grace period. Without it, the writes are cancelled together with the controller, and a
+// kubeconfig issued by Gardener may never reach the secrets. Zero cancels the reconciliations right away.
+func (controller *GardenerClusterController) WithShutdownGracePeriod(gracePeriod time.Duration) *GardenerClusterController {
+	controller.shutdownGracePeriod = gracePeriod
+
+	return controller
+}
+
+// withShutdownGrace returns a context which is cancelled the grace period after the given one, or when the returned
+// cancel function is called. The values of the given context are kept.
+func withShutdownGrace(ctx context.Context, gracePeriod time.Duration) (context.Context, context.CancelFunc) {
+	if gracePeriod <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	graceful, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-graceful.Done():
+			return
+		}
+
+		timer := time.NewTimer(gracePeriod)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel()
+		case <-graceful.Done():
+		}
+	}()
+
+	return graceful, cancel
+}
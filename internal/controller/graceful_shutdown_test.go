@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Graceful shutdown", func() {
+	It("Should keep the reconciliation running for the grace period after the controller stops", func() {
+		controllerCtx, stop := context.WithCancel(context.Background())
+		ctx, cancel := withShutdownGrace(controllerCtx, 100*time.Millisecond)
+		defer cancel()
+
+		stop()
+
+		Consistently(ctx.Done(), 50*time.Millisecond).ShouldNot(BeClosed())
+		Eventually(ctx.Done()).Should(BeClosed())
+	})
+
+	It("Should cancel the reconciliation right away without a grace period", func() {
+		controllerCtx, stop := context.WithCancel(context.Background())
+		ctx, cancel := withShutdownGrace(controllerCtx, 0)
+		defer cancel()
+
+		stop()
+
+		Expect(ctx.Err()).To(MatchError(context.Canceled))
+	})
+
+	It("Should not start a reconciliation once the controller stopped", func() {
+		ctx, stop := context.WithCancel(context.Background())
+		stop()
+		// without a client the reconciliation fails as soon as it reads the cluster
+		controller := &GardenerClusterController{log: log.Log}
+
+		result, err := controller.Reconcile(ctx, reconcile.Request{})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+	})
+})
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/featuregates"
+	"github.com/kyma-project/infrastructure-manager/internal/sharding"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// GardenerConfigurer replaces the kubeconfig, the endpoint, and the project namespace of the Gardener clients, the
+// empty values return to the flags.
+type GardenerConfigurer interface {
+	Configure(rawKubeconfig []byte, endpoint, namespace string) (bool, error)
+}
+
+// RotationPeriodConfigurer overrides the default rotation period of the kubeconfigs, zero returns to the flags.
+type RotationPeriodConfigurer interface {
+	SetRotationPeriod(period time.Duration)
+}
+
+// InfrastructureManagerConfigController applies the InfrastructureManagerConfig to the running manager, so the flags
+// it overrides can be changed without a restart. Only the config with the configured name is applied. The config is
+// applied by all replicas, as the Gardener clients are also used by the webhooks, and by the GardenerCluster controllers
+// of all shards.
+type InfrastructureManagerConfigController struct {
+	client.Client
+	shard          sharding.Shard
+	name           string
+	gardener       GardenerConfigurer
+	rotationPeriod RotationPeriodConfigurer
+	featureGates   *featuregates.Gates
+	log            logr.Logger
+}
+
+func NewInfrastructureManagerConfigController(mgr ctrl.Manager, name string, gardener GardenerConfigurer, rotationPeriod RotationPeriodConfigurer, featureGates *featuregates.Gates, logger logr.Logger) *InfrastructureManagerConfigController {
+	return &InfrastructureManagerConfigController{
+		Client:         mgr.GetClient(),
+		name:           name,
+		gardener:       gardener,
+		rotationPeriod: rotationPeriod,
+		featureGates:   featureGates,
+		log:            logger,
+	}
+}
+
+// WithShard makes only the primary shard report the applied config in the status, the other shards apply it only.
+func (controller *InfrastructureManagerConfigController) WithShard(shard sharding.Shard) *InfrastructureManagerConfigController {
+	controller.shard = shard
+
+	return controller
+}
+
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=infrastructuremanagerconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=infrastructuremanagerconfigs/status,verbs=update
+
+func (controller *InfrastructureManagerConfigController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { //nolint:revive
+	if req.Name != controller.name {
+		return ctrl.Result{}, nil
+	}
+
+	controller.log.Info("Starting reconciliation.", "InfrastructureManagerConfig", req.Name)
+
+	var config imv1.InfrastructureManagerConfig
+
+	err := controller.Get(ctx, req.NamespacedName, &config)
+	if k8serrors.IsNotFound(err) {
+		controller.log.Info("Config deleted, returning to the flags.", "InfrastructureManagerConfig", req.Name)
+
+		return ctrl.Result{}, controller.apply(ctx, imv1.InfrastructureManagerConfigSpec{})
+	}
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = controller.apply(ctx, config.Spec)
+
+	var invalidErr invalidConfigError
+	if err != nil && !errors.As(err, &invalidErr) {
+		return ctrl.Result{}, err
+	}
+
+	original := config.Status.DeepCopy()
+
+	if err != nil {
+		controller.log.Error(err, "Invalid config, the previous config stays applied.", "InfrastructureManagerConfig", req.Name)
+		setConfigCondition(&config, metav1.ConditionFalse, imv1.ConditionReasonInvalidConfig, err.Error())
+	} else {
+		setConfigCondition(&config, metav1.ConditionTrue, imv1.ConditionReasonConfigApplied, "Config applied.")
+	}
+
+	config.Status.ObservedGeneration = config.Generation
+
+	// the replicas apply the same config, only the first one of the primary shard updates the status
+	if !controller.shard.Primary() || equality.Semantic.DeepEqual(original, &config.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, controller.Status().Update(ctx, &config)
+}
+
+// invalidConfigError is a config which can't be applied until it, or the secret it references are changed.
+type invalidConfigError struct {
+	error
+}
+
+// apply validates the whole config before changing the settings, so an invalid config is not applied partially.
+func (controller *InfrastructureManagerConfigController) apply(ctx context.Context, spec imv1.InfrastructureManagerConfigSpec) error {
+	var rotationPeriod time.Duration
+
+	if spec.RotationPeriod != nil {
+		rotationPeriod = spec.RotationPeriod.Duration
+		if rotationPeriod <= 0 {
+			return invalidConfigError{errors.Errorf("rotation period %s must be positive", rotationPeriod)}
+		}
+	}
+
+	var rawKubeconfig []byte
+
+	var endpoint, namespace string
+
+	if spec.Gardener != nil {
+		endpoint, namespace = spec.Gardener.Endpoint, spec.Gardener.ProjectNamespace
+
+		if spec.Gardener.CredentialsRef != nil {
+			var err error
+
+			rawKubeconfig, err = controller.readCredentials(ctx, *spec.Gardener.CredentialsRef)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := controller.featureGates.Validate(spec.FeatureGates)
+	if err != nil {
+		return invalidConfigError{err}
+	}
+
+	// without Gardener clients, e.g. with the mock Gardener, the Gardener settings are ignored
+	if controller.gardener != nil {
+		_, err = controller.gardener.Configure(rawKubeconfig, endpoint, namespace)
+		if err != nil {
+			return invalidConfigError{err}
+		}
+	}
+
+	err = controller.featureGates.Set(spec.FeatureGates)
+	if err != nil {
+		return invalidConfigError{err}
+	}
+
+	controller.rotationPeriod.SetRotationPeriod(rotationPeriod)
+
+	return nil
+}
+
+func (controller *InfrastructureManagerConfigController) readCredentials(ctx context.Context, ref imv1.GardenerConfigCredentialsRef) ([]byte, error) {
+	var secret corev1.Secret
+
+	err := controller.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, &secret)
+	if k8serrors.IsNotFound(err) {
+		return nil, invalidConfigError{errors.Errorf("Gardener credentials %s/%s not found", ref.Namespace, ref.Name)}
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the Gardener credentials")
+	}
+
+	rawKubeconfig, found := secret.Data[ref.KeyOrDefault()]
+	if !found {
+		return nil, invalidConfigError{errors.Errorf("Gardener credentials %s/%s have no key %s", ref.Namespace, ref.Name, ref.KeyOrDefault())}
+	}
+
+	return rawKubeconfig, nil
+}
+
+func setConfigCondition(config *imv1.InfrastructureManagerConfig, status metav1.ConditionStatus, reason imv1.ConditionReason, message string) {
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:               string(imv1.ConditionTypeConfigApplied),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: config.Generation,
+	})
+}
+
+// credentialsToConfig enqueues the config when the secret with its Gardener credentials changes.
+func (controller *InfrastructureManagerConfigController) credentialsToConfig(ctx context.Context, object client.Object) []reconcile.Request {
+	var config imv1.InfrastructureManagerConfig
+
+	err := controller.Get(ctx, client.ObjectKey{Name: controller.name}, &config)
+	if err != nil || config.Spec.Gardener == nil || config.Spec.Gardener.CredentialsRef == nil {
+		return nil
+	}
+
+	ref := config.Spec.Gardener.CredentialsRef
+	if ref.Name != object.GetName() || ref.Namespace != object.GetNamespace() {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: controller.name}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (controller *InfrastructureManagerConfigController) SetupWithManager(mgr ctrl.Manager) error {
+	needLeaderElection := false
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&imv1.InfrastructureManagerConfig{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(controller.credentialsToConfig)).
+		WithOptions(crcontroller.Options{NeedLeaderElection: &needLeaderElection}).
+		Complete(controller)
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/featuregates"
+	"github.com/kyma-project/infrastructure-manager/internal/sharding"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeGardenerConfigurer struct {
+	rawKubeconfig []byte
+	endpoint      string
+	namespace     string
+}
+
+func (configurer *fakeGardenerConfigurer) Configure(rawKubeconfig []byte, endpoint, namespace string) (bool, error) {
+	configurer.rawKubeconfig, configurer.endpoint, configurer.namespace = rawKubeconfig, endpoint, namespace
+
+	return true, nil
+}
+
+type fakeRotationPeriodConfigurer struct {
+	period time.Duration
+}
+
+func (configurer *fakeRotationPeriodConfigurer) SetRotationPeriod(period time.Duration) {
+	configurer.period = period
+}
+
+var _ = Describe("InfrastructureManagerConfig controller", func() {
+	var gardener *fakeGardenerConfigurer
+	var rotation *fakeRotationPeriodConfigurer
+	var gates *featuregates.Gates
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gardener-credentials", Namespace: "kcp-system"},
+		Data:       map[string][]byte{imv1.DefaultGardenerCredentialsKey: []byte("kubeconfig")},
+	}
+
+	newController := func(objects ...client.Object) *InfrastructureManagerConfigController {
+		scheme := k8sruntime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+
+		gardener = &fakeGardenerConfigurer{}
+		rotation = &fakeRotationPeriodConfigurer{}
+		gates = featuregates.New(map[string]bool{featuregates.KubeconfigValidation: true})
+
+		return &InfrastructureManagerConfigController{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(objects...).
+				WithStatusSubresource(&imv1.InfrastructureManagerConfig{}).
+				Build(),
+			name:           imv1.DefaultInfrastructureManagerConfigName,
+			gardener:       gardener,
+			rotationPeriod: rotation,
+			featureGates:   gates,
+			log:            log.Log,
+		}
+	}
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: imv1.DefaultInfrastructureManagerConfigName}}
+
+	It("Should apply the config, and report it in the status", func() {
+		config := &imv1.InfrastructureManagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: imv1.DefaultInfrastructureManagerConfigName},
+			Spec: imv1.InfrastructureManagerConfigSpec{
+				Gardener: &imv1.GardenerConfig{
+					Endpoint:         "https://gardener.example.com",
+					ProjectNamespace: "garden-kyma",
+					CredentialsRef:   &imv1.GardenerConfigCredentialsRef{Name: "gardener-credentials", Namespace: "kcp-system"},
+				},
+				RotationPeriod: &metav1.Duration{Duration: 12 * time.Hour},
+				FeatureGates:   map[string]bool{featuregates.KubeconfigValidation: false},
+			},
+		}
+		controller := newController(config, credentials)
+
+		_, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(gardener.rawKubeconfig)).To(Equal("kubeconfig"))
+		Expect(gardener.endpoint).To(Equal("https://gardener.example.com"))
+		Expect(gardener.namespace).To(Equal("garden-kyma"))
+		Expect(rotation.period).To(Equal(12 * time.Hour))
+		Expect(gates.Enabled(featuregates.KubeconfigValidation)).To(BeFalse())
+
+		Expect(controller.Get(context.Background(), request.NamespacedName, config)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(config.Status.Conditions, string(imv1.ConditionTypeConfigApplied))).To(BeTrue())
+	})
+
+	It("Should apply the config without reporting it on the other shards", func() {
+		config := &imv1.InfrastructureManagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: imv1.DefaultInfrastructureManagerConfigName},
+			Spec:       imv1.InfrastructureManagerConfigSpec{RotationPeriod: &metav1.Duration{Duration: 12 * time.Hour}},
+		}
+		controller := newController(config).WithShard(sharding.Shard{ID: 1, Count: 2})
+
+		_, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotation.period).To(Equal(12 * time.Hour))
+
+		Expect(controller.Get(context.Background(), request.NamespacedName, config)).To(Succeed())
+		Expect(config.Status.Conditions).To(BeEmpty())
+	})
+
+	It("Should keep the previous config when the config is invalid", func() {
+		config := &imv1.InfrastructureManagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: imv1.DefaultInfrastructureManagerConfigName},
+			Spec: imv1.InfrastructureManagerConfigSpec{
+				RotationPeriod: &metav1.Duration{Duration: 12 * time.Hour},
+				FeatureGates:   map[string]bool{"Unknown": true},
+			},
+		}
+		controller := newController(config)
+
+		_, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotation.period).To(BeZero())
+
+		Expect(controller.Get(context.Background(), request.NamespacedName, config)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(config.Status.Conditions, string(imv1.ConditionTypeConfigApplied))).To(BeTrue())
+	})
+
+	It("Should apply the other settings without Gardener clients", func() {
+		config := &imv1.InfrastructureManagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: imv1.DefaultInfrastructureManagerConfigName},
+			Spec: imv1.InfrastructureManagerConfigSpec{
+				Gardener:       &imv1.GardenerConfig{ProjectNamespace: "garden-kyma"},
+				RotationPeriod: &metav1.Duration{Duration: 12 * time.Hour},
+			},
+		}
+		controller := newController(config)
+		controller.gardener = nil
+
+		_, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rotation.period).To(Equal(12 * time.Hour))
+	})
+
+	It("Should return to the flags when the config is deleted", func() {
+		controller := newController()
+		rotation.period = time.Hour
+
+		_, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(gardener.rawKubeconfig).To(BeNil())
+		Expect(gardener.namespace).To(BeEmpty())
+		Expect(rotation.period).To(BeZero())
+	})
+})
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/encryption"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// KubeconfigEncrypter encrypts the kubeconfig before it is written to the secrets, and returns the annotations
+// consumers need to decrypt it.
+type KubeconfigEncrypter interface {
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, map[string]string, error)
+}
+
+// WithKubeconfigEncryption enables the envelope encryption of the kubeconfigs of clusters requesting it.
+func (controller *GardenerClusterController) WithKubeconfigEncryption(encrypter KubeconfigEncrypter) *GardenerClusterController {
+	controller.encrypter = encrypter
+
+	return controller
+}
+
+// encryptKubeconfig returns the kubeconfig as stored in the secrets, together with the annotations of the secrets.
+func (controller *GardenerClusterController) encryptKubeconfig(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, map[string]string, error) {
+	if !cluster.Spec.Kubeconfig.Encrypted {
+		return kubeconfig, nil, nil
+	}
+
+	if controller.encrypter == nil {
+		return "", nil, errors.New("kubeconfig encryption requested, but no encryption key is configured on the controller")
+	}
+
+	ciphertext, annotations, err := controller.encrypter.Encrypt(ctx, []byte(kubeconfig))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(ciphertext), annotations, nil
+}
+
+// withEncryptionAnnotations returns the target with the encryption annotations added to the annotations defined in the spec.
+func withEncryptionAnnotations(target imv1.Secret, encryptionAnnotations map[string]string) imv1.Secret {
+	if len(encryptionAnnotations) == 0 {
+		return target
+	}
+
+	annotations := make(map[string]string, len(target.Annotations)+len(encryptionAnnotations))
+	for key, val := range target.Annotations {
+		annotations[key] = val
+	}
+
+	for key, val := range encryptionAnnotations {
+		annotations[key] = val
+	}
+
+	target.Annotations = annotations
+
+	return target
+}
+
+// removeStaleEncryptionAnnotations drops the encryption annotations the target no longer sets from the existing secret.
+// The secrets written before the controller switched to server-side apply have the annotations owned by the update
+// manager, so applying the secret without them doesn't remove them.
+func (controller *GardenerClusterController) removeStaleEncryptionAnnotations(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret, secret *corev1.Secret) error {
+	annotations := secret.GetAnnotations()
+	stale := false
+
+	for _, key := range encryption.Annotations {
+		_, existing := annotations[key]
+		_, desired := target.Annotations[key]
+
+		if existing && !desired {
+			delete(annotations, key)
+			stale = true
+		}
+	}
+
+	if !stale {
+		return nil
+	}
+
+	return controller.updateSecret(ctx, cluster, target, secret)
+}
+
+// encryptionChanged reports whether any existing secret does not match the requested encryption, and needs to be rewritten.
+func encryptionChanged(cluster *imv1.GardenerCluster, secrets []*corev1.Secret) bool {
+	for _, secret := range secrets {
+		if secret == nil {
+			continue
+		}
+
+		_, encrypted := secret.GetAnnotations()[encryption.ProviderAnnotation]
+		if encrypted != cluster.Spec.Kubeconfig.Encrypted {
+			return true
+		}
+	}
+
+	return false
+}
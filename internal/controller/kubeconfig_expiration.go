@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+)
+
+// MinimalRotationTimeRatio determines what is the minimal time that needs to pass to rotate the kubeconfig,
+// as a fraction of its expiration time.
+const MinimalRotationTimeRatio = 0.6
+
+// serviceAccountSetupExpiration is the validity of the admin kubeconfig used only to set up the service account in the shoot,
+// Gardener does not issue shorter ones.
+const serviceAccountSetupExpiration = 10 * time.Minute
+
+// WithExpirationLimits configures the range the per-cluster kubeconfig expiration must be within, zero disables the limit.
+func (controller *GardenerClusterController) WithExpirationLimits(minExpiration, maxExpiration time.Duration) *GardenerClusterController {
+	controller.minExpiration = minExpiration
+	controller.maxExpiration = maxExpiration
+
+	return controller
+}
+
+// expirationFor returns the per-cluster kubeconfig expiration, zero when the controller-wide expiration applies.
+func (controller *GardenerClusterController) expirationFor(cluster *imv1.GardenerCluster) (time.Duration, error) {
+	if cluster.Spec.Kubeconfig.ExpirationSeconds == nil {
+		return 0, nil
+	}
+
+	expiration := time.Duration(*cluster.Spec.Kubeconfig.ExpirationSeconds) * time.Second
+
+	if expiration < controller.minExpiration {
+		return 0, errors.Errorf("expiration `%s` is shorter than the minimal expiration `%s`", expiration, controller.minExpiration)
+	}
+
+	if controller.maxExpiration > 0 && expiration > controller.maxExpiration {
+		return 0, errors.Errorf("expiration `%s` exceeds the maximal expiration `%s`", expiration, controller.maxExpiration)
+	}
+
+	return expiration, nil
+}
+
+// expirationOrDefault returns the per-cluster expiration, or the controller-wide one matching the rotation period.
+func (controller *GardenerClusterController) expirationOrDefault(expiration time.Duration) time.Duration {
+	if expiration != 0 {
+		return expiration
+	}
+
+	return time.Duration(controller.currentRotationPeriod().Minutes()/MinimalRotationTimeRatio) * time.Minute
+}
+
+// maxRotationPeriodFor returns the rotation period matching the per-cluster expiration, or the controller-wide rotation period.
+func (controller *GardenerClusterController) maxRotationPeriodFor(expiration time.Duration) time.Duration {
+	if expiration == 0 {
+		return controller.currentRotationPeriod()
+	}
+
+	return time.Duration(MinimalRotationTimeRatio*expiration.Minutes()) * time.Minute
+}
+
+// SetRotationPeriod overrides the rotation period the controller was created with at runtime, zero restores it.
+func (controller *GardenerClusterController) SetRotationPeriod(period time.Duration) {
+	controller.rotationPeriodOverride.Store(int64(period))
+}
+
+func (controller *GardenerClusterController) currentRotationPeriod() time.Duration {
+	if override := time.Duration(controller.rotationPeriodOverride.Load()); override > 0 {
+		return override
+	}
+
+	return controller.rotationPeriod
+}
+
+func (controller *GardenerClusterController) fetchKubeconfig(ctx context.Context, provider KubeconfigProvider, shootName string, expiration time.Duration) (string, time.Time, error) {
+	if expiration == 0 && controller.rotationPeriodOverride.Load() > 0 {
+		// the default expiration of the provider matches the rotation period the controller was created with
+		expiration = controller.expirationOrDefault(0)
+	}
+
+	if expiration == 0 {
+		return provider.Fetch(ctx, shootName)
+	}
+
+	return provider.FetchWithExpiration(ctx, shootName, expiration)
+}
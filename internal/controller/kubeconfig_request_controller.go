@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	kubeconfigRequestLabel = "operator.kyma-project.io/kubeconfig-request"
+	// kubeconfigExpirationAnnotation of the secret restores the status of the request when the status update failed
+	// after the secret was created.
+	kubeconfigExpirationAnnotation = "operator.kyma-project.io/kubeconfig-expiration-time"
+)
+
+// KubeconfigRequestController issues short-lived kubeconfigs requested with KubeconfigRequest objects,
+// and deletes the kubeconfig secrets once they expire.
+type KubeconfigRequestController struct {
+	client.Client
+	reader             client.Reader
+	Scheme             *runtime.Scheme
+	KubeconfigProvider KubeconfigProvider
+	log                logr.Logger
+	maxTTL             time.Duration
+}
+
+func NewKubeconfigRequestController(mgr ctrl.Manager, kubeconfigProvider KubeconfigProvider, logger logr.Logger, maxTTL time.Duration) *KubeconfigRequestController {
+	return &KubeconfigRequestController{
+		Client:             mgr.GetClient(),
+		reader:             mgr.GetAPIReader(),
+		Scheme:             mgr.GetScheme(),
+		KubeconfigProvider: kubeconfigProvider,
+		log:                logger,
+		maxTTL:             maxTTL,
+	}
+}
+
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=kubeconfigrequests,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=kubeconfigrequests/status,verbs=update
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=gardenerclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=runtimes,verbs=get;list;watch
+
+func (controller *KubeconfigRequestController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { //nolint:revive
+	controller.log.Info("Starting reconciliation.", "KubeconfigRequest", req.Name, "Namespace", req.Namespace)
+
+	var request imv1.KubeconfigRequest
+
+	err := controller.Get(ctx, req.NamespacedName, &request)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if request.Status.State == imv1.ExpiredState {
+		return ctrl.Result{}, nil
+	}
+
+	if request.Status.ExpirationTime != nil {
+		return controller.handleIssuedKubeconfig(ctx, &request)
+	}
+
+	ttl := request.Spec.TTL.Duration
+	if ttl <= 0 || ttl > controller.maxTTL {
+		err = errors.Errorf("TTL %s must be positive, and must not exceed %s", ttl, controller.maxTTL)
+		request.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonInvalidTTL, err)
+
+		return ctrl.Result{}, controller.Status().Update(ctx, &request)
+	}
+
+	err = controller.verifyShootReferenced(ctx, &request)
+	if err != nil {
+		request.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonShootNotReferenced, err)
+
+		return ctrl.Result{}, controller.persistRequestStatus(ctx, &request, err)
+	}
+
+	expirationTime, issued, err := controller.issuedExpirationTime(ctx, &request)
+	if err != nil {
+		request.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToCreateSecret, err)
+
+		return ctrl.Result{}, controller.persistRequestStatus(ctx, &request, err)
+	}
+
+	if !issued {
+		var kubeconfig string
+
+		kubeconfig, expirationTime, err = controller.KubeconfigProvider.FetchWithExpiration(ctx, request.Spec.Shoot.Name, ttl)
+		if err != nil {
+			request.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToGetKubeconfig, err)
+
+			return ctrl.Result{}, controller.persistRequestStatus(ctx, &request, err)
+		}
+
+		err = controller.createKubeconfigSecret(ctx, &request, kubeconfig, expirationTime)
+		if err != nil {
+			request.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToCreateSecret, err)
+
+			return ctrl.Result{}, controller.persistRequestStatus(ctx, &request, err)
+		}
+	}
+
+	request.Status.SecretName = request.SecretName()
+	request.Status.ExpirationTime = &metav1.Time{Time: expirationTime}
+	request.UpdateCondition(imv1.ReadyState, metav1.ConditionTrue, imv1.ConditionReasonKubeconfigIssued, nil)
+
+	err = controller.Status().Update(ctx, &request)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: time.Until(expirationTime)}, nil
+}
+
+func (controller *KubeconfigRequestController) handleIssuedKubeconfig(ctx context.Context, request *imv1.KubeconfigRequest) (ctrl.Result, error) {
+	remaining := time.Until(request.Status.ExpirationTime.Time)
+	if remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      request.Status.SecretName,
+			Namespace: request.Namespace,
+		},
+	}
+
+	err := controller.Delete(ctx, &secret)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	controller.log.Info("Kubeconfig expired, secret deleted.", loggingContextFromRequest(request)...)
+	request.UpdateCondition(imv1.ExpiredState, metav1.ConditionFalse, imv1.ConditionReasonKubeconfigExpired, nil)
+
+	return ctrl.Result{}, controller.Status().Update(ctx, request)
+}
+
+// verifyShootReferenced allows kubeconfigs only for the shoots of the GardenerClusters, or Runtimes in the namespace of
+// the request, so that a request can't issue a kubeconfig of any shoot in the Gardener project. The kubeconfigs are
+// issued by the project of the controller, the GardenerClusters with their own Gardener credentials reference shoots
+// of other projects, and don't count.
+func (controller *KubeconfigRequestController) verifyShootReferenced(ctx context.Context, request *imv1.KubeconfigRequest) error {
+	var clusters imv1.GardenerClusterList
+
+	err := controller.List(ctx, &clusters, client.InNamespace(request.Namespace))
+	if err != nil {
+		return errors.Wrap(err, "failed to list GardenerClusters")
+	}
+
+	for _, cluster := range clusters.Items {
+		if cluster.Spec.Gardener == nil && cluster.Spec.Shoot.Name == request.Spec.Shoot.Name {
+			return nil
+		}
+	}
+
+	var runtimes imv1.RuntimeList
+
+	err = controller.List(ctx, &runtimes, client.InNamespace(request.Namespace))
+	if err != nil {
+		return errors.Wrap(err, "failed to list Runtimes")
+	}
+
+	for _, runtimeCR := range runtimes.Items {
+		if runtimeCR.Spec.Shoot.Name == request.Spec.Shoot.Name {
+			return nil
+		}
+	}
+
+	return errors.Errorf("shoot %s is not referenced by any GardenerCluster, or Runtime in namespace %s", request.Spec.Shoot.Name, request.Namespace)
+}
+
+// issuedExpirationTime returns the expiration time of the secret created for the request before, when the status update
+// failed after its creation, so that the retry doesn't issue another kubeconfig. The secret is read from the API server,
+// the cache might not have seen it yet.
+func (controller *KubeconfigRequestController) issuedExpirationTime(ctx context.Context, request *imv1.KubeconfigRequest) (time.Time, bool, error) {
+	var secret corev1.Secret
+
+	err := controller.reader.Get(ctx, client.ObjectKey{Name: request.SecretName(), Namespace: request.Namespace}, &secret)
+	if k8serrors.IsNotFound(err) {
+		return time.Time{}, false, nil
+	}
+
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, "failed to get the kubeconfig secret")
+	}
+
+	if !metav1.IsControlledBy(&secret, request) {
+		return time.Time{}, false, errors.Errorf("secret %s exists, and is not owned by the KubeconfigRequest", secret.Name)
+	}
+
+	expirationTime, err := time.Parse(time.RFC3339, secret.Annotations[kubeconfigExpirationAnnotation])
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "invalid expiration time of secret %s", secret.Name)
+	}
+
+	return expirationTime, true, nil
+}
+
+func (controller *KubeconfigRequestController) createKubeconfigSecret(ctx context.Context, request *imv1.KubeconfigRequest, kubeconfig string, expirationTime time.Time) error {
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      request.SecretName(),
+			Namespace: request.Namespace,
+			Labels: map[string]string{
+				kubeconfigRequestLabel: request.Name,
+			},
+			Annotations: map[string]string{
+				kubeconfigExpirationAnnotation: expirationTime.UTC().Format(time.RFC3339),
+			},
+		},
+		StringData: map[string]string{imv1.DefaultKubeconfigSecretKey: kubeconfig},
+	}
+
+	err := controllerutil.SetControllerReference(request, &secret, controller.Scheme)
+	if err != nil {
+		return err
+	}
+
+	return controller.Create(ctx, &secret)
+}
+
+// persistRequestStatus stores the error state, the original error is returned to retry the request with backoff.
+func (controller *KubeconfigRequestController) persistRequestStatus(ctx context.Context, request *imv1.KubeconfigRequest, reconcileErr error) error {
+	err := controller.Status().Update(ctx, request)
+	if err != nil {
+		controller.log.Error(err, "Failed to update status", loggingContextFromRequest(request)...)
+	}
+
+	return reconcileErr
+}
+
+func loggingContextFromRequest(request *imv1.KubeconfigRequest) []any {
+	return []any{"KubeconfigRequest", request.Name, "Namespace", request.Namespace}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (controller *KubeconfigRequestController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&imv1.KubeconfigRequest{}).
+		Complete(controller)
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Kubeconfig Request controller", func() {
+	It("Should issue kubeconfig, and delete the secret after expiration", func() {
+		namespace := "default"
+		request := imv1.KubeconfigRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "request1",
+				Namespace: namespace,
+			},
+			Spec: imv1.KubeconfigRequestSpec{
+				Shoot: imv1.Shoot{Name: "shootName13"},
+				TTL:   metav1.Duration{Duration: 5 * time.Second},
+			},
+		}
+
+		By("Create GardenerCluster CR referencing the shoot")
+		cluster := fixGardenerClusterCR("kymaname13", namespace, "shootName13", "secret-name13")
+		Expect(k8sClient.Create(context.Background(), &cluster)).To(Succeed())
+
+		By("Create KubeconfigRequest CR")
+		Expect(k8sClient.Create(context.Background(), &request)).To(Succeed())
+
+		By("Wait for secret creation")
+		var kubeconfigSecret corev1.Secret
+		secretKey := types.NamespacedName{Name: "request1-kubeconfig", Namespace: namespace}
+
+		Eventually(func() bool {
+			return k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret) == nil
+		}, time.Second*30, time.Second*1).Should(BeTrue())
+
+		Expect(kubeconfigSecret.Data[imv1.DefaultKubeconfigSecretKey]).To(Equal([]byte("kubeconfig13")))
+		Expect(kubeconfigSecret.OwnerReferences).To(HaveLen(1))
+		Expect(kubeconfigSecret.OwnerReferences[0].Name).To(Equal(request.Name))
+
+		By("Wait for secret deletion after expiration")
+		Eventually(func() bool {
+			err := k8sClient.Get(context.Background(), secretKey, &kubeconfigSecret)
+			return err != nil && k8serrors.IsNotFound(err)
+		}, time.Second*30, time.Second*1).Should(BeTrue())
+
+		var expiredRequest imv1.KubeconfigRequest
+		Eventually(func() bool {
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: request.Name, Namespace: namespace}, &expiredRequest)
+			return err == nil && expiredRequest.Status.State == imv1.ExpiredState
+		}, time.Second*30, time.Second*1).Should(BeTrue())
+	})
+
+	It("Should reject TTL exceeding the kubeconfig validity time", func() {
+		namespace := "default"
+		request := imv1.KubeconfigRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "request2",
+				Namespace: namespace,
+			},
+			Spec: imv1.KubeconfigRequestSpec{
+				Shoot: imv1.Shoot{Name: "shootName13"},
+				TTL:   metav1.Duration{Duration: 2 * TestKubeconfigValidityTime},
+			},
+		}
+
+		By("Create KubeconfigRequest CR")
+		Expect(k8sClient.Create(context.Background(), &request)).To(Succeed())
+
+		By("Wait for Error state")
+		var invalidRequest imv1.KubeconfigRequest
+		Eventually(func() bool {
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: request.Name, Namespace: namespace}, &invalidRequest)
+			return err == nil && invalidRequest.Status.State == imv1.ErrorState
+		}, time.Second*30, time.Second*1).Should(BeTrue())
+
+		Expect(invalidRequest.Status.Conditions[0].Reason).To(Equal(string(imv1.ConditionReasonInvalidTTL)))
+	})
+})
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/controller/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Issuance of the requested kubeconfigs", func() {
+	var provider *mocks.KubeconfigProvider
+	var scheme *k8sruntime.Scheme
+
+	expirationTime := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	newRequest := func() *imv1.KubeconfigRequest {
+		return &imv1.KubeconfigRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "request", Namespace: "kcp-system", UID: "request-uid"},
+			Spec: imv1.KubeconfigRequestSpec{
+				Shoot: imv1.Shoot{Name: "shoot"},
+				TTL:   metav1.Duration{Duration: time.Hour},
+			},
+		}
+	}
+
+	newController := func(objects ...client.Object) *KubeconfigRequestController {
+		scheme = k8sruntime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+
+		provider = &mocks.KubeconfigProvider{}
+		provider.On("FetchWithExpiration", mock.Anything, "shoot", time.Hour).Return("kubeconfig", expirationTime, nil)
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&imv1.KubeconfigRequest{}).
+			Build()
+
+		return &KubeconfigRequestController{
+			Client:             fakeClient,
+			reader:             fakeClient,
+			Scheme:             scheme,
+			KubeconfigProvider: provider,
+			log:                log.Log,
+			maxTTL:             24 * time.Hour,
+		}
+	}
+
+	reconcile := func(controller *KubeconfigRequestController) (*imv1.KubeconfigRequest, error) {
+		key := types.NamespacedName{Name: "request", Namespace: "kcp-system"}
+		_, err := controller.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+
+		var request imv1.KubeconfigRequest
+		Expect(controller.Get(context.Background(), key, &request)).To(Succeed())
+
+		return &request, err
+	}
+
+	referencingCluster := func(namespace string) *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: namespace},
+			Spec:       imv1.GardenerClusterSpec{Shoot: imv1.Shoot{Name: "shoot"}},
+		}
+	}
+
+	It("Should issue the kubeconfig of a shoot referenced by a GardenerCluster in the namespace", func() {
+		controller := newController(newRequest(), referencingCluster("kcp-system"))
+
+		request, err := reconcile(controller)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(request.Status.State).To(Equal(imv1.ReadyState))
+		Expect(request.Status.ExpirationTime.Time).To(BeTemporally("==", expirationTime))
+
+		var secret corev1.Secret
+		Expect(controller.Get(context.Background(), client.ObjectKey{Name: "request-kubeconfig", Namespace: "kcp-system"}, &secret)).To(Succeed())
+		Expect(secret.Annotations).To(HaveKeyWithValue(kubeconfigExpirationAnnotation, expirationTime.Format(time.RFC3339)))
+	})
+
+	It("Should issue the kubeconfig of a shoot referenced by a Runtime in the namespace", func() {
+		runtime := &imv1.Runtime{
+			ObjectMeta: metav1.ObjectMeta{Name: "runtime", Namespace: "kcp-system"},
+			Spec:       imv1.RuntimeSpec{Shoot: imv1.RuntimeShoot{Name: "shoot"}},
+		}
+		controller := newController(newRequest(), runtime)
+
+		request, err := reconcile(controller)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(request.Status.State).To(Equal(imv1.ReadyState))
+	})
+
+	It("Should refuse the kubeconfig of a shoot referenced only in another namespace", func() {
+		controller := newController(newRequest(), referencingCluster("other-tenant"))
+
+		request, err := reconcile(controller)
+
+		Expect(err).To(HaveOccurred())
+		Expect(request.Status.State).To(Equal(imv1.ErrorState))
+
+		condition := meta.FindStatusCondition(request.Status.Conditions, string(imv1.ConditionTypeKubeconfigIssued))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(string(imv1.ConditionReasonShootNotReferenced)))
+		provider.AssertNotCalled(GinkgoT(), "FetchWithExpiration", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("Should refuse the kubeconfig of a shoot referenced only by a GardenerCluster of another Gardener project", func() {
+		cluster := referencingCluster("kcp-system")
+		cluster.Spec.Gardener = &imv1.GardenerCredentials{SecretRef: imv1.GardenerCredentialsSecretRef{Name: "tenant-gardener"}}
+		controller := newController(newRequest(), cluster)
+
+		request, err := reconcile(controller)
+
+		Expect(err).To(HaveOccurred())
+		Expect(request.Status.State).To(Equal(imv1.ErrorState))
+		provider.AssertNotCalled(GinkgoT(), "FetchWithExpiration", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("Should reuse the secret created before a failed status update instead of issuing another kubeconfig", func() {
+		request := newRequest()
+		controller := newController(request, referencingCluster("kcp-system"))
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "request-kubeconfig",
+				Namespace:   "kcp-system",
+				Annotations: map[string]string{kubeconfigExpirationAnnotation: expirationTime.Format(time.RFC3339)},
+			},
+		}
+		Expect(controllerutil.SetControllerReference(request, secret, scheme)).To(Succeed())
+		Expect(controller.Create(context.Background(), secret)).To(Succeed())
+
+		issuedRequest, err := reconcile(controller)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issuedRequest.Status.State).To(Equal(imv1.ReadyState))
+		Expect(issuedRequest.Status.ExpirationTime.Time).To(BeTemporally("==", expirationTime))
+		provider.AssertNotCalled(GinkgoT(), "FetchWithExpiration", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("Should refuse to issue the kubeconfig into a secret not owned by the request", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "request-kubeconfig", Namespace: "kcp-system"}}
+		controller := newController(newRequest(), referencingCluster("kcp-system"), secret)
+
+		request, err := reconcile(controller)
+
+		Expect(err).To(HaveOccurred())
+		Expect(request.Status.State).To(Equal(imv1.ErrorState))
+		provider.AssertNotCalled(GinkgoT(), "FetchWithExpiration", mock.Anything, mock.Anything, mock.Anything)
+	})
+})
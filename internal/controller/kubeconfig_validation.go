@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/infrastructure-manager/internal/featuregates"
+	"github.com/kyma-project/infrastructure-manager/internal/kubeconfig"
+)
+
+// WithKubeconfigValidation makes the controller check the kubeconfig before writing it to the secrets, so that a working secret
+// is not overwritten with a broken kubeconfig. A positive dial timeout additionally checks the API server accepts connections.
+func (controller *GardenerClusterController) WithKubeconfigValidation(enabled bool, dialTimeout time.Duration) *GardenerClusterController {
+	controller.validateKubeconfigs = enabled
+	controller.kubeconfigDialTimeout = dialTimeout
+
+	return controller
+}
+
+func (controller *GardenerClusterController) validateKubeconfig(ctx context.Context, kubeconfigContent string) error {
+	if !controller.featureEnabled(featuregates.KubeconfigValidation, controller.validateKubeconfigs) {
+		return nil
+	}
+
+	return kubeconfig.Validate(ctx, kubeconfigContent, controller.kubeconfigDialTimeout)
+}
@@ -2,30 +2,100 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	v1 "github.com/kyma-project/infrastructure-manager/api/v1"
+)
 
 // KubeconfigProvider is an autogenerated mock type for the KubeconfigProvider type
 type KubeconfigProvider struct {
 	mock.Mock
 }
 
-// Fetch provides a mock function with given fields: shootName
-func (_m *KubeconfigProvider) Fetch(shootName string) (string, error) {
-	ret := _m.Called(shootName)
+// Fetch provides a mock function with given fields: ctx, shootName
+func (_m *KubeconfigProvider) Fetch(ctx context.Context, shootName string) (string, time.Time, error) {
+	ret := _m.Called(ctx, shootName)
 
 	var r0 string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(shootName)
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, time.Time, error)); ok {
+		return rf(ctx, shootName)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(shootName)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, shootName)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(shootName)
+	if rf, ok := ret.Get(1).(func(context.Context, string) time.Time); ok {
+		r1 = rf(ctx, shootName)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, shootName)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchWithExpiration provides a mock function with given fields: ctx, shootName, expiration
+func (_m *KubeconfigProvider) FetchWithExpiration(ctx context.Context, shootName string, expiration time.Duration) (string, time.Time, error) {
+	ret := _m.Called(ctx, shootName, expiration)
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (string, time.Time, error)); ok {
+		return rf(ctx, shootName, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) string); ok {
+		r0 = rf(ctx, shootName, expiration)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) time.Time); ok {
+		r1 = rf(ctx, shootName, expiration)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, time.Duration) error); ok {
+		r2 = rf(ctx, shootName, expiration)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchShootInfo provides a mock function with given fields: ctx, shootName
+func (_m *KubeconfigProvider) FetchShootInfo(ctx context.Context, shootName string) (v1.ShootInfo, error) {
+	ret := _m.Called(ctx, shootName)
+
+	var r0 v1.ShootInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (v1.ShootInfo, error)); ok {
+		return rf(ctx, shootName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) v1.ShootInfo); ok {
+		r0 = rf(ctx, shootName)
+	} else {
+		r0 = ret.Get(0).(v1.ShootInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, shootName)
 	} else {
 		r1 = ret.Error(1)
 	}
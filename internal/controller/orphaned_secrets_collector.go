@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/audit"
+	"github.com/kyma-project/infrastructure-manager/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OrphanedSecretsCollector periodically removes managed kubeconfig secrets whose GardenerCluster no longer exists
+type OrphanedSecretsCollector struct {
+	client.Client
+	log      logr.Logger
+	interval time.Duration
+	dryRun   bool
+	auditLog AuditLog
+}
+
+func NewOrphanedSecretsCollector(mgr ctrl.Manager, logger logr.Logger, interval time.Duration, dryRun bool) *OrphanedSecretsCollector {
+	return &OrphanedSecretsCollector{
+		Client:   mgr.GetClient(),
+		log:      logger,
+		interval: interval,
+		dryRun:   dryRun,
+	}
+}
+
+// Start runs the collection every interval until the context is cancelled. It is called by the manager.
+func (collector *OrphanedSecretsCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(collector.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := collector.Collect(ctx)
+			if err != nil {
+				collector.log.Error(err, "Failed to collect orphaned secrets.")
+			}
+		}
+	}
+}
+
+// Collect finds and deletes orphaned secrets. In dry-run mode the secrets are only reported.
+func (collector *OrphanedSecretsCollector) Collect(ctx context.Context) error {
+	var secretList corev1.SecretList
+
+	err := collector.Client.List(ctx, &secretList, client.MatchingLabels{managedByLabel: managedByLabelValue})
+	if err != nil {
+		return err
+	}
+
+	var clusterList imv1.GardenerClusterList
+
+	err = collector.Client.List(ctx, &clusterList)
+	if err != nil {
+		return err
+	}
+
+	for i := range secretList.Items {
+		secret := &secretList.Items[i]
+		if !isOrphaned(secret, clusterList.Items) {
+			continue
+		}
+
+		metrics.RecordOrphanedSecretFound()
+
+		if collector.dryRun {
+			message := fmt.Sprintf("Secret %s in namespace %s is orphaned, skipping deletion in dry-run mode.", secret.Name, secret.Namespace)
+			collector.log.Info(message)
+
+			continue
+		}
+
+		err = collector.Client.Delete(ctx, secret)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		metrics.RecordOrphanedSecretDeleted()
+		writeAuditRecord(collector.auditLog, collector.log, audit.Record{
+			Action:           audit.ActionDeleted,
+			Trigger:          audit.TriggerOrphaned,
+			Cluster:          secret.Labels[clusterCRNameLabel],
+			ClusterNamespace: secret.Labels[clusterCRNamespaceLabel],
+			SecretName:       secret.Name,
+			SecretNamespace:  secret.Namespace,
+		})
+
+		message := fmt.Sprintf("Orphaned secret %s has been deleted from %s namespace.", secret.Name, secret.Namespace)
+		collector.log.Info(message)
+	}
+
+	return nil
+}
+
+// isOrphaned checks whether the GardenerCluster referenced by the secret labels exists.
+// Secrets created before the namespace label was introduced are matched by name only.
+func isOrphaned(secret *corev1.Secret, clusters []imv1.GardenerCluster) bool {
+	clusterName, found := secret.Labels[clusterCRNameLabel]
+	if !found {
+		return false
+	}
+
+	clusterNamespace, namespaceFound := secret.Labels[clusterCRNamespaceLabel]
+
+	for _, cluster := range clusters {
+		if cluster.Name == clusterName && (!namespaceFound || cluster.Namespace == clusterNamespace) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Orphaned secrets collector", func() {
+	namespace := "default"
+
+	It("Should delete secret without GardenerCluster", func() {
+		secret := fixNewSecret("orphaned-secret", namespace, "non-existing-kyma", "shootName", "kubeconfig", "")
+		Expect(k8sClient.Create(context.Background(), &secret)).To(Succeed())
+
+		collector := OrphanedSecretsCollector{Client: k8sClient, log: logf.Log}
+		Expect(collector.Collect(context.Background())).To(Succeed())
+
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: namespace}, &corev1.Secret{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should keep orphaned secret in dry-run mode", func() {
+		secret := fixNewSecret("orphaned-secret-dry-run", namespace, "non-existing-kyma", "shootName", "kubeconfig", "")
+		Expect(k8sClient.Create(context.Background(), &secret)).To(Succeed())
+
+		collector := OrphanedSecretsCollector{Client: k8sClient, log: logf.Log, dryRun: true}
+		Expect(collector.Collect(context.Background())).To(Succeed())
+
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: secret.Name, Namespace: namespace}, &corev1.Secret{})).To(Succeed())
+	})
+})
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/priorityqueue"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// priorityQueueName names the metrics of the queue like the ones of the default queue of the controller
+	priorityQueueName = "gardenercluster"
+
+	// priorityLookupTimeout limits reading the priority of a cluster from the cache when it is queued
+	priorityLookupTimeout = 5 * time.Second
+)
+
+// WithPriorityQueue makes the controller reconcile the clusters with a higher priority first when the work queue is
+// deep, without it the clusters are reconciled in the order they were queued.
+func (controller *GardenerClusterController) WithPriorityQueue(enabled bool) *GardenerClusterController {
+	controller.priorityQueue = enabled
+
+	return controller
+}
+
+// clusterPriority returns the priority of the cluster of a request, read from the cache. The clusters which are gone
+// have the default priority, their reconciliation only removes them from the queue.
+func clusterPriority(reader client.Reader) priorityqueue.PriorityFunc {
+	return func(item any) int {
+		request, ok := item.(reconcile.Request)
+		if !ok {
+			return 0
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), priorityLookupTimeout)
+		defer cancel()
+
+		var cluster imv1.GardenerCluster
+
+		err := reader.Get(ctx, request.NamespacedName, &cluster)
+		if err != nil {
+			return 0
+		}
+
+		return int(cluster.Spec.Priority)
+	}
+}
+
+// usePriorityQueue replaces the work queue of the controller with a priority queue before the controller is started.
+func usePriorityQueue(clusterController crcontroller.Controller, reader client.Reader) error {
+	return wrapQueue(clusterController, func(func() workqueue.RateLimitingInterface) workqueue.RateLimitingInterface {
+		return priorityqueue.NewRateLimiting(priorityQueueName, workqueue.DefaultControllerRateLimiter(), clusterPriority(reader))
+	})
+}
+
+// wrapQueue replaces the work queue of the controller with the one built by the wrapper, which is given the constructor of
+// the queue the controller would create otherwise. controller-runtime v0.15 has no option for the queue of a controller,
+// so the queue constructor is set directly.
+func wrapQueue(clusterController crcontroller.Controller, wrap func(makeQueue func() workqueue.RateLimitingInterface) workqueue.RateLimitingInterface) error {
+	value := reflect.ValueOf(clusterController)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	var makeQueue func() workqueue.RateLimitingInterface
+
+	field := value.FieldByName("MakeQueue")
+	if !field.IsValid() || !field.CanSet() || field.Type() != reflect.TypeOf(makeQueue) {
+		return errors.Errorf("the controller %T does not support a custom work queue", clusterController)
+	}
+
+	makeQueue = field.Interface().(func() workqueue.RateLimitingInterface)
+	field.Set(reflect.ValueOf(func() workqueue.RateLimitingInterface {
+		return wrap(makeQueue)
+	}))
+
+	return nil
+}
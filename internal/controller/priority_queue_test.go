@@ -0,0 +1,40 @@
+package controller
+
+import (
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Priority queue", func() {
+	It("Should read the priority of the cluster from the cache", func() {
+		scheme := runtime.NewScheme()
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+		cluster := &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "kcp-system"},
+			Spec:       imv1.GardenerClusterSpec{Priority: 100},
+		}
+		priority := clusterPriority(fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build())
+
+		Expect(priority(reconcile.Request{NamespacedName: types.NamespacedName{Name: "production", Namespace: "kcp-system"}})).To(Equal(100))
+		Expect(priority(reconcile.Request{NamespacedName: types.NamespacedName{Name: "deleted", Namespace: "kcp-system"}})).To(Equal(0))
+	})
+
+	It("Should replace the work queue of the controller", func() {
+		mgr, err := ctrl.NewManager(&rest.Config{Host: "https://localhost:6443"}, ctrl.Options{MetricsBindAddress: "0"})
+		Expect(err).ToNot(HaveOccurred())
+
+		clusterController, err := crcontroller.NewUnmanaged("gardenercluster", mgr, crcontroller.Options{Reconciler: &GardenerClusterController{}})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(usePriorityQueue(clusterController, mgr.GetCache())).To(Succeed())
+	})
+})
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RemoteClusters returns the client of the cluster of the kubeconfig, the key identifies the secret the kubeconfig is
+// read from.
+type RemoteClusters func(key string, rawKubeconfig []byte) (client.Client, error)
+
+// WithRemoteClusters lets the clusters deliver their kubeconfig secrets to other clusters, without it only the cluster
+// of the controller is supported.
+func (controller *GardenerClusterController) WithRemoteClusters(remoteClusters RemoteClusters) *GardenerClusterController {
+	controller.remoteClusters = remoteClusters
+
+	return controller
+}
+
+// clientFor returns the client of the cluster the target secret is written to. The kubeconfig of a remote cluster is
+// read from the namespace of the cluster only, so a cluster can't write to the clusters of another namespace.
+func (controller *GardenerClusterController) clientFor(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret) (client.Client, error) {
+	if !target.Remote() {
+		return controller.Client, nil
+	}
+
+	if controller.remoteClusters == nil {
+		return nil, errors.New("the controller does not support secrets in remote clusters")
+	}
+
+	clusterRef := target.ClusterRef
+	key := client.ObjectKey{Name: clusterRef.Name, Namespace: cluster.Namespace}
+
+	var secret corev1.Secret
+
+	err := controller.Get(ctx, key, &secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the kubeconfig of the target cluster %s", key)
+	}
+
+	rawKubeconfig, found := secret.Data[clusterRef.KeyOrDefault()]
+	if !found {
+		return nil, errors.Errorf("the kubeconfig of the target cluster %s has no key %s", key, clusterRef.KeyOrDefault())
+	}
+
+	return controller.remoteClusters(key.String(), rawKubeconfig)
+}
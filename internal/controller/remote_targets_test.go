@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Remote targets", func() {
+	targetKubeconfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "target-cluster", Namespace: "tenant"},
+		Data:       map[string][]byte{imv1.DefaultClusterRefKey: []byte("kubeconfig")},
+	}
+
+	var localClient, remoteClient client.Client
+
+	var requestedKey string
+
+	newController := func() *GardenerClusterController {
+		localClient = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(targetKubeconfig).Build()
+		remoteClient = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+		controller := &GardenerClusterController{Client: localClient}
+
+		return controller.WithRemoteClusters(func(key string, rawKubeconfig []byte) (client.Client, error) {
+			requestedKey = key
+			Expect(string(rawKubeconfig)).To(Equal("kubeconfig"))
+
+			return remoteClient, nil
+		})
+	}
+
+	clusterWithTarget := func(namespace string, target imv1.Secret) *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: namespace},
+			Spec: imv1.GardenerClusterSpec{
+				Kubeconfig: imv1.Kubeconfig{Secret: target},
+			},
+		}
+	}
+
+	remoteTarget := imv1.Secret{Name: "kubeconfig", Namespace: "kyma-system", Key: "config", ClusterRef: &imv1.SecretClusterRef{Name: "target-cluster"}}
+
+	It("Should write the secret of a remote target to the target cluster", func() {
+		controller := newController()
+		cluster := clusterWithTarget("tenant", remoteTarget)
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "kyma-system"}}
+
+		err := controller.createSecret(context.Background(), cluster, remoteTarget, secret)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requestedKey).To(Equal("tenant/target-cluster"))
+		Expect(remoteClient.Get(context.Background(), client.ObjectKeyFromObject(secret), &corev1.Secret{})).To(Succeed())
+		Expect(k8serrors.IsNotFound(localClient.Get(context.Background(), client.ObjectKeyFromObject(secret), &corev1.Secret{}))).To(BeTrue())
+	})
+
+	It("Should delete the secret of a remote target from the target cluster", func() {
+		controller := newController()
+		cluster := clusterWithTarget("tenant", remoteTarget)
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "kyma-system"}}
+		Expect(remoteClient.Create(context.Background(), secret)).To(Succeed())
+
+		err := controller.deleteKubeconfigSecrets(context.Background(), cluster)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(k8serrors.IsNotFound(remoteClient.Get(context.Background(), client.ObjectKeyFromObject(secret), &corev1.Secret{}))).To(BeTrue())
+	})
+
+	It("Should write the secret of a local target to the cluster of the controller", func() {
+		controller := newController()
+		localTarget := imv1.Secret{Name: "kubeconfig", Namespace: "tenant", Key: "config"}
+
+		targetClient, err := controller.clientFor(context.Background(), clusterWithTarget("tenant", localTarget), localTarget)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targetClient).To(BeIdenticalTo(localClient))
+	})
+
+	It("Should not read the kubeconfig of the target cluster from another namespace", func() {
+		_, err := newController().clientFor(context.Background(), clusterWithTarget("other-tenant", remoteTarget), remoteTarget)
+
+		Expect(err).To(MatchError(ContainSubstring("failed to get the kubeconfig of the target cluster other-tenant/target-cluster")))
+	})
+
+	It("Should fail when the controller does not support remote targets", func() {
+		controller := &GardenerClusterController{Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()}
+
+		_, err := controller.clientFor(context.Background(), clusterWithTarget("tenant", remoteTarget), remoteTarget)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
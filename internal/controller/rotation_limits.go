@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WithNamespaceRotationLimit caps the rotations in each namespace within the window, so a single tenant can't exhaust
+// the Gardener API capacity for the others. The rotations over the limit are delayed, zero disables the limit.
+func (controller *GardenerClusterController) WithNamespaceRotationLimit(limit int, window time.Duration) *GardenerClusterController {
+	controller.rotationLimiter = nil
+	if limit > 0 && window > 0 {
+		controller.rotationLimiter = newNamespaceRotationLimiter(limit, window)
+	}
+
+	return controller
+}
+
+// rotationThrottledError delays the rotation of a cluster until its namespace is below the rotation limit.
+type rotationThrottledError struct {
+	namespace  string
+	retryAfter time.Duration
+}
+
+func (err rotationThrottledError) Error() string {
+	return fmt.Sprintf("rotation limit of namespace %s exceeded, retrying in %s", err.namespace, err.retryAfter)
+}
+
+// throttleRotation reserves a rotation in the namespace of the cluster, and reports the delay in the Throttled
+// condition when the namespace is over the limit.
+func (controller *GardenerClusterController) throttleRotation(cluster *imv1.GardenerCluster, now time.Time) error {
+	if controller.rotationLimiter == nil {
+		return nil
+	}
+
+	retryAfter := controller.rotationLimiter.reserve(cluster.Namespace, now)
+	if retryAfter > 0 {
+		err := rotationThrottledError{namespace: cluster.Namespace, retryAfter: retryAfter}
+		cluster.SetCondition(imv1.ConditionTypeThrottled, metav1.ConditionTrue, imv1.ConditionReasonNamespaceRotationLimit, err)
+
+		return err
+	}
+
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(imv1.ConditionTypeThrottled))
+
+	return nil
+}
+
+// refundRotation releases the rotation reserved by throttleRotation when it failed before Gardener issued a
+// kubeconfig, so the retries of a failing cluster don't use up the limit of its namespace.
+func (controller *GardenerClusterController) refundRotation(cluster *imv1.GardenerCluster, reservedAt time.Time) {
+	if controller.rotationLimiter == nil {
+		return
+	}
+
+	controller.rotationLimiter.release(cluster.Namespace, reservedAt)
+}
+
+// namespaceRotationLimiter counts the rotations of each namespace in a sliding window, the counts are kept in memory,
+// so each replica limits the namespaces separately.
+type namespaceRotationLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	rotations map[string][]time.Time
+}
+
+func newNamespaceRotationLimiter(limit int, window time.Duration) *namespaceRotationLimiter {
+	return &namespaceRotationLimiter{
+		limit:     limit,
+		window:    window,
+		rotations: map[string][]time.Time{},
+	}
+}
+
+// reserve records a rotation in the namespace, and returns zero. When the namespace is at the limit, nothing is
+// recorded, and the delay until the oldest rotation leaves the window is returned.
+func (limiter *namespaceRotationLimiter) reserve(namespace string, now time.Time) time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	windowStart := now.Add(-limiter.window)
+	rotations := limiter.rotations[namespace]
+
+	expired := 0
+	for expired < len(rotations) && !rotations[expired].After(windowStart) {
+		expired++
+	}
+
+	rotations = rotations[expired:]
+
+	if len(rotations) >= limiter.limit {
+		limiter.rotations[namespace] = rotations
+
+		return rotations[0].Sub(windowStart)
+	}
+
+	limiter.rotations[namespace] = append(rotations, now)
+
+	return 0
+}
+
+// release removes the rotation reserved in the namespace at the given time, if it is still in the window.
+func (limiter *namespaceRotationLimiter) release(namespace string, reservedAt time.Time) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	rotations := limiter.rotations[namespace]
+	for i := len(rotations) - 1; i >= 0; i-- {
+		if rotations[i].Equal(reservedAt) {
+			limiter.rotations[namespace] = append(rotations[:i], rotations[i+1:]...)
+
+			return
+		}
+	}
+}
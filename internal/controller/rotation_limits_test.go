@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/controller/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Namespace rotation limit", func() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	It("Should delay the rotations over the limit until the oldest one leaves the window", func() {
+		limiter := newNamespaceRotationLimiter(2, time.Hour)
+
+		Expect(limiter.reserve("tenant-a", now)).To(BeZero())
+		Expect(limiter.reserve("tenant-a", now.Add(10*time.Minute))).To(BeZero())
+		Expect(limiter.reserve("tenant-a", now.Add(20*time.Minute))).To(Equal(40 * time.Minute))
+		Expect(limiter.reserve("tenant-b", now.Add(20*time.Minute))).To(BeZero())
+		Expect(limiter.reserve("tenant-a", now.Add(time.Hour))).To(BeZero())
+	})
+
+	It("Should report the throttled rotation in the condition, and remove it once the rotation proceeds", func() {
+		controller := (&GardenerClusterController{}).WithNamespaceRotationLimit(1, time.Hour)
+		cluster := &imv1.GardenerCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "tenant-a"}}
+
+		Expect(controller.throttleRotation(cluster, now)).To(Succeed())
+
+		err := controller.throttleRotation(cluster, now.Add(time.Minute))
+		Expect(err).To(MatchError(rotationThrottledError{namespace: "tenant-a", retryAfter: 59 * time.Minute}))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(imv1.ConditionTypeThrottled))).To(BeTrue())
+
+		Expect(controller.throttleRotation(cluster, now.Add(2*time.Hour))).To(Succeed())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeThrottled))).To(BeNil())
+	})
+
+	It("Should refund the rotation when Gardener doesn't issue the kubeconfig", func() {
+		scheme := k8sruntime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+
+		cluster := &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "tenant-a"},
+			Spec: imv1.GardenerClusterSpec{
+				Shoot: imv1.Shoot{Name: "shoot"},
+				Kubeconfig: imv1.Kubeconfig{
+					Secret: imv1.Secret{Name: "kubeconfig", Namespace: "tenant-a", Key: "config"},
+				},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(cluster).
+			WithStatusSubresource(&imv1.GardenerCluster{}).
+			Build()
+
+		provider := &mocks.KubeconfigProvider{}
+		provider.On("Fetch", mock.Anything, "shoot").Return("", time.Time{}, errors.New("connection refused"))
+
+		controller := (&GardenerClusterController{
+			Client:             fakeClient,
+			reader:             fakeClient,
+			Scheme:             scheme,
+			KubeconfigProvider: provider,
+			log:                log.Log,
+			recorder:           record.NewFakeRecorder(10),
+			rotationPeriod:     time.Hour,
+			shootLocks:         newShootLocks(),
+		}).WithNamespaceRotationLimit(1, time.Hour)
+
+		_, err := controller.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+
+		Expect(err).ToNot(HaveOccurred())
+		provider.AssertCalled(GinkgoT(), "Fetch", mock.Anything, "shoot")
+		Expect(controller.rotationLimiter.reserve("tenant-a", time.Now())).To(BeZero())
+	})
+
+	It("Should not limit the rotations without a limit", func() {
+		controller := (&GardenerClusterController{}).WithNamespaceRotationLimit(0, time.Hour)
+
+		Expect(controller.throttleRotation(&imv1.GardenerCluster{}, now)).To(Succeed())
+		Expect(controller.throttleRotation(&imv1.GardenerCluster{}, now)).To(Succeed())
+	})
+})
@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/shoot"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const runtimeShootFinalizer = "infrastructuremanager.kyma-project.io/shoot"
+
+const (
+	// Gardener is polled while it operates on the shoot, the shoots can't be watched in the garden cluster.
+	runtimeProcessingRequeueInterval = 30 * time.Second
+	// runtimeSyncInterval reverts changes made to the shoot by other actors, and refreshes the state of ready shoots.
+	runtimeSyncInterval = 5 * time.Minute
+)
+
+// ShootManager creates, updates, and deletes the shoots in the Gardener project of the controller.
+type ShootManager interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*gardener.Shoot, error)
+	CreateShoot(ctx context.Context, shoot *gardener.Shoot) (*gardener.Shoot, error)
+	UpdateShoot(ctx context.Context, shoot *gardener.Shoot) (*gardener.Shoot, error)
+	DeleteShoot(ctx context.Context, name string) error
+}
+
+// RuntimeController provisions the Gardener shoots declared with Runtime objects.
+type RuntimeController struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	ShootManager   ShootManager
+	reader         client.Reader
+	log            logr.Logger
+	shootNamespace string
+	metadataPolicy shoot.MetadataPolicy
+}
+
+func NewRuntimeController(mgr ctrl.Manager, shootManager ShootManager, shootNamespace string, logger logr.Logger) *RuntimeController {
+	return &RuntimeController{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		ShootManager:   shootManager,
+		reader:         mgr.GetAPIReader(),
+		log:            logger,
+		shootNamespace: shootNamespace,
+	}
+}
+
+// WithMetadataPropagation copies the labels, and annotations of the Runtimes selected by the policy to their shoots.
+func (controller *RuntimeController) WithMetadataPropagation(policy shoot.MetadataPolicy) *RuntimeController {
+	controller.metadataPolicy = policy
+
+	return controller
+}
+
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=runtimes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=runtimes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=runtimes/finalizers,verbs=update
+
+func (controller *RuntimeController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { //nolint:revive
+	controller.log.Info("Starting reconciliation.", "Runtime", req.Name, "Namespace", req.Namespace)
+
+	var rt imv1.Runtime
+
+	err := controller.Get(ctx, req.NamespacedName, &rt)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !rt.DeletionTimestamp.IsZero() {
+		return controller.handleDeletion(ctx, &rt)
+	}
+
+	if !controllerutil.ContainsFinalizer(&rt, runtimeShootFinalizer) {
+		controllerutil.AddFinalizer(&rt, runtimeShootFinalizer)
+
+		err = controller.Update(ctx, &rt)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	existingShoot, err := controller.ShootManager.Get(ctx, rt.Spec.Shoot.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return controller.createShoot(ctx, &rt)
+	}
+
+	if err != nil {
+		rt.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToGetShoot, "", err)
+
+		return ctrl.Result{}, controller.persistRuntimeStatus(ctx, &rt, err)
+	}
+
+	if !managedBy(existingShoot, &rt) {
+		err = errors.Errorf("shoot %s exists, but is not managed by this Runtime", existingShoot.Name)
+		rt.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToUpdateShoot, "", err)
+
+		// the shoot of another owner is never modified, the spec needs to be fixed
+		return ctrl.Result{}, controller.persistRuntimeStatus(ctx, &rt, nil)
+	}
+
+	specChanged := shoot.Update(existingShoot, &rt)
+	metadataChanged := controller.metadataPolicy.Apply(existingShoot, &rt)
+
+	if specChanged || metadataChanged {
+		return controller.updateShoot(ctx, &rt, existingShoot)
+	}
+
+	rt.Status.ObservedGeneration = rt.Generation
+	requeueAfter := setShootOperationStatus(&rt, existingShoot)
+	rt.Status.Workers = shoot.WorkerStatuses(existingShoot, rt.Status.State)
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, controller.persistRuntimeStatus(ctx, &rt, nil)
+}
+
+func (controller *RuntimeController) createShoot(ctx context.Context, rt *imv1.Runtime) (ctrl.Result, error) {
+	newShoot := shoot.New(rt, controller.shootNamespace)
+	controller.metadataPolicy.Apply(newShoot, rt)
+
+	_, err := controller.ShootManager.CreateShoot(ctx, newShoot)
+	if err != nil {
+		rt.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToCreateShoot, "", err)
+
+		return ctrl.Result{}, controller.persistRuntimeStatus(ctx, rt, err)
+	}
+
+	controller.log.Info("Shoot created.", loggingContextFromRuntime(rt)...)
+	rt.Status.ObservedGeneration = rt.Generation
+	rt.UpdateCondition(imv1.ProcessingState, metav1.ConditionFalse, imv1.ConditionReasonShootCreating, "", nil)
+	rt.Status.Workers = shoot.WorkerStatuses(newShoot, imv1.ProcessingState)
+
+	return ctrl.Result{RequeueAfter: runtimeProcessingRequeueInterval}, controller.persistRuntimeStatus(ctx, rt, nil)
+}
+
+func (controller *RuntimeController) updateShoot(ctx context.Context, rt *imv1.Runtime, desiredShoot *gardener.Shoot) (ctrl.Result, error) {
+	_, err := controller.ShootManager.UpdateShoot(ctx, desiredShoot)
+	if err != nil {
+		rt.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToUpdateShoot, "", err)
+
+		return ctrl.Result{}, controller.persistRuntimeStatus(ctx, rt, err)
+	}
+
+	controller.log.Info("Shoot updated.", loggingContextFromRuntime(rt)...)
+	rt.Status.ObservedGeneration = rt.Generation
+	rt.UpdateCondition(imv1.ProcessingState, metav1.ConditionFalse, imv1.ConditionReasonShootUpdating, "", nil)
+	rt.Status.Workers = shoot.WorkerStatuses(desiredShoot, imv1.ProcessingState)
+
+	return ctrl.Result{RequeueAfter: runtimeProcessingRequeueInterval}, controller.persistRuntimeStatus(ctx, rt, nil)
+}
+
+// handleDeletion deletes the shoot, and releases the finalizer once Gardener has removed it.
+func (controller *RuntimeController) handleDeletion(ctx context.Context, rt *imv1.Runtime) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(rt, runtimeShootFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// the webhook rejects the deletion of protected Runtimes, it is checked again in case the webhook is disabled
+	if !rt.DeletionAllowed() {
+		controller.log.Info("Shoot deletion not confirmed.", loggingContextFromRuntime(rt)...)
+		rt.UpdateCondition(imv1.DeletingState, metav1.ConditionFalse, imv1.ConditionReasonDeletionNotConfirmed, "", nil)
+
+		// adding the annotation triggers the next reconciliation
+		return ctrl.Result{}, controller.persistRuntimeStatus(ctx, rt, nil)
+	}
+
+	existingShoot, err := controller.ShootManager.Get(ctx, rt.Spec.Shoot.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		rt.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToGetShoot, "", err)
+
+		return ctrl.Result{}, controller.persistRuntimeStatus(ctx, rt, err)
+	}
+
+	// a shoot of another owner is left in place
+	if k8serrors.IsNotFound(err) || !managedBy(existingShoot, rt) {
+		controller.log.Info("Shoot deleted.", loggingContextFromRuntime(rt)...)
+		controllerutil.RemoveFinalizer(rt, runtimeShootFinalizer)
+
+		return ctrl.Result{}, controller.Update(ctx, rt)
+	}
+
+	if existingShoot.DeletionTimestamp.IsZero() {
+		err = controller.ShootManager.DeleteShoot(ctx, existingShoot.Name)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			rt.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonFailedToDeleteShoot, "", err)
+
+			return ctrl.Result{}, controller.persistRuntimeStatus(ctx, rt, err)
+		}
+	}
+
+	rt.Status.LastOperation = lastOperation(existingShoot)
+	rt.UpdateCondition(imv1.DeletingState, metav1.ConditionFalse, imv1.ConditionReasonShootDeleting, "", nil)
+
+	return ctrl.Result{RequeueAfter: runtimeProcessingRequeueInterval}, controller.persistRuntimeStatus(ctx, rt, nil)
+}
+
+// setShootOperationStatus reflects the last operation of Gardener on the shoot, and returns when to check it again.
+func setShootOperationStatus(rt *imv1.Runtime, existingShoot *gardener.Shoot) time.Duration {
+	rt.Status.LastOperation = lastOperation(existingShoot)
+	rt.SetHibernationCondition(shoot.HibernationEnabled(existingShoot), existingShoot.Status.IsHibernated)
+
+	operation := existingShoot.Status.LastOperation
+	if operation == nil {
+		rt.UpdateCondition(imv1.ProcessingState, metav1.ConditionFalse, imv1.ConditionReasonShootCreating, "", nil)
+
+		return runtimeProcessingRequeueInterval
+	}
+
+	switch operation.State {
+	case gardener.LastOperationStateSucceeded:
+		rt.UpdateCondition(imv1.ReadyState, metav1.ConditionTrue, imv1.ConditionReasonShootReady, "", nil)
+
+		// the control plane is rolled out once Gardener has reconciled the current spec
+		if existingShoot.Status.ObservedGeneration == existingShoot.Generation {
+			rt.Status.ControlPlaneFailureTolerance = shoot.ControlPlaneFailureTolerance(existingShoot)
+		}
+
+		return runtimeSyncInterval
+	case gardener.LastOperationStateFailed, gardener.LastOperationStateError:
+		// Gardener keeps retrying operations in the Error state, Failed operations are retried on the next update
+		rt.UpdateCondition(imv1.ErrorState, metav1.ConditionFalse, imv1.ConditionReasonShootOperationFailed, operation.Description, nil)
+
+		return runtimeProcessingRequeueInterval
+	}
+
+	reason := imv1.ConditionReasonShootUpdating
+	if operation.Type == gardener.LastOperationTypeCreate {
+		reason = imv1.ConditionReasonShootCreating
+	}
+
+	rt.UpdateCondition(imv1.ProcessingState, metav1.ConditionFalse, reason, "", nil)
+
+	return runtimeProcessingRequeueInterval
+}
+
+func lastOperation(existingShoot *gardener.Shoot) string {
+	operation := existingShoot.Status.LastOperation
+	if operation == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s %s (%d%%)", operation.Type, operation.State, operation.Progress)
+}
+
+// managedBy checks the labels the controller sets on the shoots it creates.
+func managedBy(existingShoot *gardener.Shoot, rt *imv1.Runtime) bool {
+	return existingShoot.Labels[shoot.RuntimeNameLabel] == rt.Name && existingShoot.Labels[shoot.RuntimeNamespaceLabel] == rt.Namespace
+}
+
+// persistRuntimeStatus stores the status, the reconciliation error is returned to retry the request with backoff.
+func (controller *RuntimeController) persistRuntimeStatus(ctx context.Context, rt *imv1.Runtime, reconcileErr error) error {
+	status := rt.Status
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		updateErr := controller.Status().Update(ctx, rt)
+		if !k8serrors.IsConflict(updateErr) {
+			return updateErr
+		}
+
+		// the status is written again to the latest version of the Runtime, read without the cache
+		getErr := controller.reader.Get(ctx, client.ObjectKeyFromObject(rt), rt)
+		if getErr != nil {
+			return getErr
+		}
+
+		rt.Status = status
+
+		return updateErr
+	})
+	if err != nil {
+		controller.log.Error(err, "Failed to update status", loggingContextFromRuntime(rt)...)
+
+		if reconcileErr == nil {
+			return err
+		}
+	}
+
+	return reconcileErr
+}
+
+func loggingContextFromRuntime(rt *imv1.Runtime) []any {
+	return []any{"Runtime", rt.Name, "Namespace", rt.Namespace, "Shoot", rt.Spec.Shoot.Name}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (controller *RuntimeController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&imv1.Runtime{}).
+		Complete(controller)
+}
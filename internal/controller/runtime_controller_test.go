@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/shoot"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Runtime controller", func() {
+	const namespace = "default"
+
+	fixRuntime := func(name, shootName string) imv1.Runtime {
+		return imv1.Runtime{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: imv1.RuntimeSpec{
+				Shoot: imv1.RuntimeShoot{
+					Name:              shootName,
+					Region:            "eu-central-1",
+					CloudProfileName:  "aws",
+					SecretBindingName: "aws-credentials",
+					Provider:          imv1.RuntimeProvider{Type: "aws"},
+					Kubernetes:        imv1.RuntimeKubernetes{Version: "1.27.5"},
+					Workers: []imv1.RuntimeWorker{
+						{Name: "cpu-worker", MachineType: "m5.xlarge", Minimum: 1, Maximum: 3, Zones: []string{"eu-central-1a"}},
+					},
+				},
+			},
+		}
+	}
+
+	runtimeState := func(key types.NamespacedName) func() imv1.State {
+		return func() imv1.State {
+			var rt imv1.Runtime
+			if err := k8sClient.Get(context.Background(), key, &rt); err != nil {
+				return ""
+			}
+
+			return rt.Status.State
+		}
+	}
+
+	It("Should create the shoot, and report it ready", func() {
+		rt := fixRuntime("runtime1", "runtime1")
+		Expect(k8sClient.Create(context.Background(), &rt)).To(Succeed())
+
+		key := types.NamespacedName{Name: rt.Name, Namespace: rt.Namespace}
+		Eventually(runtimeState(key), time.Second*30, time.Second).Should(Equal(imv1.ReadyState))
+
+		createdShoot, err := testShootManager.Get(context.Background(), "runtime1", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(createdShoot.Spec.Region).To(Equal("eu-central-1"))
+		Expect(createdShoot.Spec.Provider.Workers).To(HaveLen(1))
+		Expect(createdShoot.Labels).To(HaveKeyWithValue(shoot.RuntimeNameLabel, "runtime1"))
+	})
+
+	It("Should update the shoot when the spec changes", func() {
+		rt := fixRuntime("runtime2", "runtime2")
+		Expect(k8sClient.Create(context.Background(), &rt)).To(Succeed())
+
+		key := types.NamespacedName{Name: rt.Name, Namespace: rt.Namespace}
+		Eventually(runtimeState(key), time.Second*30, time.Second).Should(Equal(imv1.ReadyState))
+
+		Expect(k8sClient.Get(context.Background(), key, &rt)).To(Succeed())
+		rt.Spec.Shoot.Kubernetes.Version = "1.28.2"
+		Expect(k8sClient.Update(context.Background(), &rt)).To(Succeed())
+
+		Eventually(func() string {
+			updatedShoot, err := testShootManager.Get(context.Background(), "runtime2", metav1.GetOptions{})
+			if err != nil {
+				return ""
+			}
+
+			return updatedShoot.Spec.Kubernetes.Version
+		}, time.Second*30, time.Second).Should(Equal("1.28.2"))
+	})
+
+	It("Should delete the shoot before releasing the Runtime", func() {
+		rt := fixRuntime("runtime3", "runtime3")
+		Expect(k8sClient.Create(context.Background(), &rt)).To(Succeed())
+
+		key := types.NamespacedName{Name: rt.Name, Namespace: rt.Namespace}
+		Eventually(runtimeState(key), time.Second*30, time.Second).Should(Equal(imv1.ReadyState))
+
+		Expect(k8sClient.Delete(context.Background(), &rt)).To(Succeed())
+
+		Eventually(func() bool {
+			return k8serrors.IsNotFound(k8sClient.Get(context.Background(), key, &rt))
+		}, time.Second*30, time.Second).Should(BeTrue())
+
+		_, err := testShootManager.Get(context.Background(), "runtime3", metav1.GetOptions{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should keep the shoot of a protected Runtime until the deletion is confirmed", func() {
+		rt := fixRuntime("runtime5", "runtime5")
+		protected := true
+		rt.Spec.DeletionProtection = &protected
+		Expect(k8sClient.Create(context.Background(), &rt)).To(Succeed())
+
+		key := types.NamespacedName{Name: rt.Name, Namespace: rt.Namespace}
+		Eventually(runtimeState(key), time.Second*30, time.Second).Should(Equal(imv1.ReadyState))
+
+		// the webhook is not served in the test environment
+		Expect(k8sClient.Delete(context.Background(), &rt)).To(Succeed())
+		Eventually(runtimeState(key), time.Second*30, time.Second).Should(Equal(imv1.DeletingState))
+
+		Consistently(func() error {
+			_, err := testShootManager.Get(context.Background(), "runtime5", metav1.GetOptions{})
+			return err
+		}, time.Second*3, time.Second).Should(Succeed())
+
+		Expect(k8sClient.Get(context.Background(), key, &rt)).To(Succeed())
+		rt.Annotations = map[string]string{imv1.DeletionConfirmationAnnotation: "true"}
+		Expect(k8sClient.Update(context.Background(), &rt)).To(Succeed())
+
+		Eventually(func() bool {
+			return k8serrors.IsNotFound(k8sClient.Get(context.Background(), key, &rt))
+		}, time.Second*30, time.Second).Should(BeTrue())
+
+		_, err := testShootManager.Get(context.Background(), "runtime5", metav1.GetOptions{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should not modify a shoot of another owner", func() {
+		foreignShoot := &gardener.Shoot{ObjectMeta: metav1.ObjectMeta{Name: "foreign"}}
+		_, err := testShootManager.CreateShoot(context.Background(), foreignShoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		rt := fixRuntime("runtime4", "foreign")
+		Expect(k8sClient.Create(context.Background(), &rt)).To(Succeed())
+
+		key := types.NamespacedName{Name: rt.Name, Namespace: rt.Namespace}
+		Eventually(runtimeState(key), time.Second*30, time.Second).Should(Equal(imv1.ErrorState))
+
+		existingShoot, err := testShootManager.Get(context.Background(), "foreign", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(existingShoot.Spec.Provider.Workers).To(BeEmpty())
+	})
+
+	It("Should propagate the allowlisted labels to the shoot", func() {
+		rt := fixRuntime("runtime6", "runtime6")
+		rt.Labels = map[string]string{testPropagatedLabelPrefix + "tier": "free", "app": "kcp"}
+		Expect(k8sClient.Create(context.Background(), &rt)).To(Succeed())
+
+		key := types.NamespacedName{Name: rt.Name, Namespace: rt.Namespace}
+		Eventually(runtimeState(key), time.Second*30, time.Second).Should(Equal(imv1.ReadyState))
+
+		createdShoot, err := testShootManager.Get(context.Background(), "runtime6", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(createdShoot.Labels).To(HaveKeyWithValue(testPropagatedLabelPrefix+"tier", "free"))
+		Expect(createdShoot.Labels).NotTo(HaveKey("app"))
+
+		Expect(k8sClient.Get(context.Background(), key, &rt)).To(Succeed())
+		delete(rt.Labels, testPropagatedLabelPrefix+"tier")
+		Expect(k8sClient.Update(context.Background(), &rt)).To(Succeed())
+
+		Eventually(func() map[string]string {
+			updatedShoot, err := testShootManager.Get(context.Background(), "runtime6", metav1.GetOptions{})
+			if err != nil {
+				return nil
+			}
+
+			return updatedShoot.Labels
+		}, time.Second*30, time.Second).ShouldNot(HaveKey(testPropagatedLabelPrefix + "tier"))
+	})
+})
+
+// fakeShootManager keeps the shoots in memory, the operations of Gardener succeed immediately.
+type fakeShootManager struct {
+	mu     sync.Mutex
+	shoots map[string]*gardener.Shoot
+}
+
+func newFakeShootManager() *fakeShootManager {
+	return &fakeShootManager{shoots: map[string]*gardener.Shoot{}}
+}
+
+func (manager *fakeShootManager) Get(_ context.Context, name string, _ metav1.GetOptions) (*gardener.Shoot, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	existing, found := manager.shoots[name]
+	if !found {
+		return nil, k8serrors.NewNotFound(gardener.Resource("shoots"), name)
+	}
+
+	return existing.DeepCopy(), nil
+}
+
+func (manager *fakeShootManager) CreateShoot(_ context.Context, newShoot *gardener.Shoot) (*gardener.Shoot, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if _, found := manager.shoots[newShoot.Name]; found {
+		return nil, k8serrors.NewAlreadyExists(gardener.Resource("shoots"), newShoot.Name)
+	}
+
+	created := newShoot.DeepCopy()
+	created.Status.LastOperation = succeededOperation(gardener.LastOperationTypeCreate)
+	manager.shoots[created.Name] = created
+
+	return created.DeepCopy(), nil
+}
+
+func (manager *fakeShootManager) UpdateShoot(_ context.Context, updatedShoot *gardener.Shoot) (*gardener.Shoot, error) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if _, found := manager.shoots[updatedShoot.Name]; !found {
+		return nil, k8serrors.NewNotFound(gardener.Resource("shoots"), updatedShoot.Name)
+	}
+
+	updated := updatedShoot.DeepCopy()
+	updated.Status.LastOperation = succeededOperation(gardener.LastOperationTypeReconcile)
+	manager.shoots[updated.Name] = updated
+
+	return updated.DeepCopy(), nil
+}
+
+func (manager *fakeShootManager) DeleteShoot(_ context.Context, name string) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if _, found := manager.shoots[name]; !found {
+		return k8serrors.NewNotFound(gardener.Resource("shoots"), name)
+	}
+
+	delete(manager.shoots, name)
+
+	return nil
+}
+
+func succeededOperation(operationType gardener.LastOperationType) *gardener.LastOperation {
+	return &gardener.LastOperation{Type: operationType, State: gardener.LastOperationStateSucceeded, Progress: 100} //nolint:gomnd
+}
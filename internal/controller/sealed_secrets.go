@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"context"
+	"crypto/rsa"
+	"strings"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/sealing"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//nolint:gochecknoglobals
+var sealedSecretGVK = schema.GroupVersionKind{Group: "bitnami.com", Version: "v1alpha1", Kind: "SealedSecret"}
+
+// secretFieldManager is the field manager the kubeconfig secrets are applied with, the controller owns only the fields
+// it sets, so labels, and annotations added by other actors are kept.
+const secretFieldManager = "infrastructure-manager"
+
+// controllerMetadataPrefix marks the labels, and annotations of the controller, they are not propagated to the unsealed secrets,
+// so that those are not mistaken for managed secrets.
+const controllerMetadataPrefix = "operator.kyma-project.io/"
+
+// WithSealedSecrets configures the public key of the sealed-secrets controller the SealedSecrets are sealed with.
+func (controller *GardenerClusterController) WithSealedSecrets(publicKey *rsa.PublicKey) *GardenerClusterController {
+	controller.sealingKey = publicKey
+
+	return controller
+}
+
+// secretClient reads, and writes the kubeconfig secrets in the format requested in the spec.
+type secretClient interface {
+	Get(ctx context.Context, key client.ObjectKey, secret *corev1.Secret) error
+	Create(ctx context.Context, secret *corev1.Secret) error
+	Update(ctx context.Context, secret *corev1.Secret) error
+	Apply(ctx context.Context, secret *corev1.Secret) error
+	Delete(ctx context.Context, secret *corev1.Secret) error
+}
+
+// secretsFor returns the client of the secrets of the target, in the cluster the target is written to.
+func (controller *GardenerClusterController) secretsFor(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret) (secretClient, error) {
+	targetClient, err := controller.clientFor(ctx, cluster, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.Spec.Kubeconfig.SecretFormat == imv1.SecretFormatSealedSecret {
+		return sealedSecretClient{client: targetClient}, nil
+	}
+
+	return plainSecretClient{client: targetClient}, nil
+}
+
+func (controller *GardenerClusterController) createSecret(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret, secret *corev1.Secret) error {
+	secrets, err := controller.secretsFor(ctx, cluster, target)
+	if err != nil {
+		return err
+	}
+
+	return secrets.Create(ctx, secret)
+}
+
+func (controller *GardenerClusterController) updateSecret(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret, secret *corev1.Secret) error {
+	secrets, err := controller.secretsFor(ctx, cluster, target)
+	if err != nil {
+		return err
+	}
+
+	return secrets.Update(ctx, secret)
+}
+
+// applySecret writes the desired state of the secret with server-side apply, the secret is created if it doesn't exist.
+func (controller *GardenerClusterController) applySecret(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret, secret *corev1.Secret) error {
+	secrets, err := controller.secretsFor(ctx, cluster, target)
+	if err != nil {
+		return err
+	}
+
+	return secrets.Apply(ctx, secret)
+}
+
+// secretKubeconfigs returns the kubeconfig as written to each of the targets, sealed for the target if requested.
+func (controller *GardenerClusterController) secretKubeconfigs(cluster *imv1.GardenerCluster, targets []imv1.Secret, kubeconfig string) ([]string, error) {
+	kubeconfigs := make([]string, len(targets))
+
+	for i, target := range targets {
+		if cluster.Spec.Kubeconfig.SecretFormat != imv1.SecretFormatSealedSecret {
+			kubeconfigs[i] = kubeconfig
+			continue
+		}
+
+		if controller.sealingKey == nil {
+			return nil, errors.New("SealedSecret format requested, but no sealed-secrets certificate is configured on the controller")
+		}
+
+		sealed, err := sealing.Seal(controller.sealingKey, target.Namespace, target.Name, []byte(kubeconfig))
+		if err != nil {
+			return nil, err
+		}
+
+		kubeconfigs[i] = sealed
+	}
+
+	return kubeconfigs, nil
+}
+
+type plainSecretClient struct {
+	client client.Client
+}
+
+func (secrets plainSecretClient) Get(ctx context.Context, key client.ObjectKey, secret *corev1.Secret) error {
+	return secrets.client.Get(ctx, key, secret)
+}
+
+func (secrets plainSecretClient) Create(ctx context.Context, secret *corev1.Secret) error {
+	return secrets.client.Create(ctx, secret)
+}
+
+func (secrets plainSecretClient) Update(ctx context.Context, secret *corev1.Secret) error {
+	return secrets.client.Update(ctx, secret)
+}
+
+func (secrets plainSecretClient) Apply(ctx context.Context, secret *corev1.Secret) error {
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	secret.ResourceVersion = ""
+	secret.ManagedFields = nil
+
+	return secrets.client.Patch(ctx, secret, client.Apply, client.FieldOwner(secretFieldManager), client.ForceOwnership)
+}
+
+func (secrets plainSecretClient) Delete(ctx context.Context, secret *corev1.Secret) error {
+	return secrets.client.Delete(ctx, secret)
+}
+
+// sealedSecretClient stores the secrets as SealedSecrets. The data of the secrets holds the sealed values, which are
+// mapped to the encrypted data of the SealedSecrets, so that the rotation, and the drift detection work unchanged.
+type sealedSecretClient struct {
+	client client.Client
+}
+
+func (secrets sealedSecretClient) Get(ctx context.Context, key client.ObjectKey, secret *corev1.Secret) error {
+	sealedSecret := newSealedSecret()
+
+	err := secrets.client.Get(ctx, key, sealedSecret)
+	if err != nil {
+		return err
+	}
+
+	encryptedData, _, err := unstructured.NestedStringMap(sealedSecret.Object, "spec", "encryptedData")
+	if err != nil {
+		return err
+	}
+
+	secret.Name = sealedSecret.GetName()
+	secret.Namespace = sealedSecret.GetNamespace()
+	secret.UID = sealedSecret.GetUID()
+	secret.ResourceVersion = sealedSecret.GetResourceVersion()
+	secret.Labels = sealedSecret.GetLabels()
+	secret.Annotations = sealedSecret.GetAnnotations()
+	secret.Data = map[string][]byte{}
+
+	for key, val := range encryptedData {
+		secret.Data[key] = []byte(val)
+	}
+
+	return nil
+}
+
+func (secrets sealedSecretClient) Create(ctx context.Context, secret *corev1.Secret) error {
+	sealedSecret, err := toSealedSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	err = secrets.client.Create(ctx, sealedSecret)
+	secret.ResourceVersion = sealedSecret.GetResourceVersion()
+
+	return err
+}
+
+func (secrets sealedSecretClient) Update(ctx context.Context, secret *corev1.Secret) error {
+	sealedSecret, err := toSealedSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	err = secrets.client.Update(ctx, sealedSecret)
+	secret.ResourceVersion = sealedSecret.GetResourceVersion()
+
+	return err
+}
+
+func (secrets sealedSecretClient) Apply(ctx context.Context, secret *corev1.Secret) error {
+	sealedSecret, err := toSealedSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	sealedSecret.SetResourceVersion("")
+
+	err = secrets.client.Patch(ctx, sealedSecret, client.Apply, client.FieldOwner(secretFieldManager), client.ForceOwnership)
+	secret.ResourceVersion = sealedSecret.GetResourceVersion()
+
+	return err
+}
+
+func (secrets sealedSecretClient) Delete(ctx context.Context, secret *corev1.Secret) error {
+	sealedSecret := newSealedSecret()
+	sealedSecret.SetName(secret.Name)
+	sealedSecret.SetNamespace(secret.Namespace)
+
+	return secrets.client.Delete(ctx, sealedSecret)
+}
+
+func newSealedSecret() *unstructured.Unstructured {
+	sealedSecret := &unstructured.Unstructured{}
+	sealedSecret.SetGroupVersionKind(sealedSecretGVK)
+
+	return sealedSecret
+}
+
+func toSealedSecret(secret *corev1.Secret) (*unstructured.Unstructured, error) {
+	sealedSecret := newSealedSecret()
+	sealedSecret.SetName(secret.Name)
+	sealedSecret.SetNamespace(secret.Namespace)
+	sealedSecret.SetResourceVersion(secret.ResourceVersion)
+	sealedSecret.SetLabels(secret.Labels)
+	sealedSecret.SetAnnotations(secret.Annotations)
+
+	encryptedData := map[string]any{}
+	for key, val := range secret.Data {
+		encryptedData[key] = string(val)
+	}
+
+	for key, val := range secret.StringData {
+		encryptedData[key] = val
+	}
+
+	templateMetadata := map[string]any{
+		"name":      secret.Name,
+		"namespace": secret.Namespace,
+	}
+
+	if labels := userMetadata(secret.Labels); len(labels) > 0 {
+		templateMetadata["labels"] = labels
+	}
+
+	if annotations := userMetadata(secret.Annotations); len(annotations) > 0 {
+		templateMetadata["annotations"] = annotations
+	}
+
+	err := unstructured.SetNestedMap(sealedSecret.Object, map[string]any{
+		"encryptedData": encryptedData,
+		"template": map[string]any{
+			"metadata": templateMetadata,
+			"type":     string(corev1.SecretTypeOpaque),
+		},
+	}, "spec")
+
+	return sealedSecret, err
+}
+
+// userMetadata returns the labels, or annotations not set by the controller.
+func userMetadata(metadata map[string]string) map[string]any {
+	filtered := map[string]any{}
+
+	for key, val := range metadata {
+		if !strings.HasPrefix(key, controllerMetadataPrefix) {
+			filtered[key] = val
+		}
+	}
+
+	return filtered
+}
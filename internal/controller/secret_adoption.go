@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"fmt"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// secretConflictError stops the controller from overwriting a secret it doesn't own.
+type secretConflictError struct {
+	namespace string
+	name      string
+	reason    imv1.ConditionReason
+}
+
+func (err secretConflictError) Error() string {
+	if err.reason == imv1.ConditionReasonSecretOwnedByAnotherCluster {
+		return fmt.Sprintf("secret %s in namespace %s is managed for another GardenerCluster", err.name, err.namespace)
+	}
+
+	return fmt.Sprintf("secret %s in namespace %s is not managed by the controller, and is not annotated with %s=true", err.name, err.namespace, imv1.AdoptSecretAnnotation)
+}
+
+// checkSecretsOwnership verifies that the controller may write the existing target secrets, and reports the first
+// conflict in the SecretConflict condition. Unmanaged secrets are only adopted with the adopt annotation, the secrets of
+// other clusters are never taken over.
+func (controller *GardenerClusterController) checkSecretsOwnership(cluster *imv1.GardenerCluster, existingSecrets []*corev1.Secret) error {
+	for _, secret := range existingSecrets {
+		if secret == nil {
+			continue
+		}
+
+		reason, conflict := secretOwnershipConflict(cluster, secret)
+		if !conflict {
+			continue
+		}
+
+		err := secretConflictError{namespace: secret.Namespace, name: secret.Name, reason: reason}
+		cluster.SetCondition(imv1.ConditionTypeSecretConflict, metav1.ConditionTrue, reason, err)
+		cluster.UpdateConditionForErrorState(imv1.ConditionTypeKubeconfigManagement, reason, metav1.ConditionTrue, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeKubeconfigManagement, corev1.EventTypeWarning)
+
+		return err
+	}
+
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(imv1.ConditionTypeSecretConflict))
+
+	return nil
+}
+
+// secretOwnershipConflict returns the reason the controller may not write the secret for the cluster.
+func secretOwnershipConflict(cluster *imv1.GardenerCluster, secret *corev1.Secret) (imv1.ConditionReason, bool) {
+	labels := secret.GetLabels()
+
+	if labels[managedByLabel] != managedByLabelValue {
+		if secret.GetAnnotations()[imv1.AdoptSecretAnnotation] == "true" {
+			return "", false
+		}
+
+		return imv1.ConditionReasonUnmanagedSecretExists, true
+	}
+
+	// secrets written before the cluster labels were introduced belong to the cluster referencing them
+	name, nameFound := labels[clusterCRNameLabel]
+	namespace, namespaceFound := labels[clusterCRNamespaceLabel]
+
+	if (nameFound && name != cluster.Name) || (namespaceFound && namespace != cluster.Namespace) {
+		return imv1.ConditionReasonSecretOwnedByAnotherCluster, true
+	}
+
+	return "", false
+}
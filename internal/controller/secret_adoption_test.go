@@ -0,0 +1,78 @@
+package controller
+
+import (
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Adoption of existing secrets", func() {
+	newCluster := func() *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"}}
+	}
+
+	existingSecret := func(labels, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "kubeconfig",
+			Namespace:   "kcp-system",
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+
+	DescribeTable("checkSecretsOwnership", func(secret *corev1.Secret, expectedReason imv1.ConditionReason) {
+		controller := &GardenerClusterController{recorder: record.NewFakeRecorder(10)}
+		cluster := newCluster()
+
+		err := controller.checkSecretsOwnership(cluster, []*corev1.Secret{nil, secret})
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeSecretConflict))
+		if expectedReason == "" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(BeNil())
+
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(string(expectedReason)))
+		Expect(cluster.Status.State).To(Equal(imv1.ErrorState))
+	},
+		Entry("Should write the secrets managed for the cluster",
+			existingSecret(map[string]string{
+				managedByLabel:          managedByLabelValue,
+				clusterCRNameLabel:      "cluster",
+				clusterCRNamespaceLabel: "kcp-system",
+			}, nil), imv1.ConditionReason("")),
+		Entry("Should adopt an unmanaged secret with the adopt annotation",
+			existingSecret(nil, map[string]string{imv1.AdoptSecretAnnotation: "true"}), imv1.ConditionReason("")),
+		Entry("Should not overwrite an unmanaged secret",
+			existingSecret(map[string]string{"app": "custom"}, nil), imv1.ConditionReasonUnmanagedSecretExists),
+		Entry("Should not overwrite an unmanaged secret with the adopt annotation set to false",
+			existingSecret(nil, map[string]string{imv1.AdoptSecretAnnotation: "false"}), imv1.ConditionReasonUnmanagedSecretExists),
+		Entry("Should not take over the secret of another cluster",
+			existingSecret(map[string]string{
+				managedByLabel:          managedByLabelValue,
+				clusterCRNameLabel:      "other",
+				clusterCRNamespaceLabel: "kcp-system",
+			}, map[string]string{imv1.AdoptSecretAnnotation: "true"}), imv1.ConditionReasonSecretOwnedByAnotherCluster),
+	)
+
+	It("Should clear the conflict once the secret is adopted", func() {
+		controller := &GardenerClusterController{recorder: record.NewFakeRecorder(10)}
+		cluster := newCluster()
+		secret := existingSecret(nil, nil)
+
+		Expect(controller.checkSecretsOwnership(cluster, []*corev1.Secret{secret})).ToNot(Succeed())
+
+		secret.Annotations = map[string]string{imv1.AdoptSecretAnnotation: "true"}
+
+		Expect(controller.checkSecretsOwnership(cluster, []*corev1.Secret{secret})).To(Succeed())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeSecretConflict))).To(BeNil())
+	})
+})
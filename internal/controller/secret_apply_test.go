@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/encryption"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Server-side apply of the kubeconfig secrets", func() {
+	target := imv1.Secret{Name: "kubeconfig", Namespace: "kcp-system", Key: "config", Labels: map[string]string{"team": "kyma"}}
+	cluster := &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+		Spec:       imv1.GardenerClusterSpec{Kubeconfig: imv1.Kubeconfig{Secret: target}},
+	}
+
+	existingSecret := func(annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "kubeconfig",
+				Namespace:   "kcp-system",
+				Labels:      map[string]string{"backup.example.com/include": "true"},
+				Annotations: annotations,
+			},
+			Data: map[string][]byte{"config": []byte("previous")},
+		}
+	}
+
+	It("Should keep the labels, and annotations added by other actors", func() {
+		secret := existingSecret(map[string]string{"note": "added by another actor"})
+		fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(secret).Build()
+		controller := &GardenerClusterController{Client: fakeClient}
+
+		err := controller.updateExistingSecret(context.Background(), "rotated", cluster.DeepCopy(), target, secret.DeepCopy(), time.Now())
+
+		Expect(err).ToNot(HaveOccurred())
+
+		var updated corev1.Secret
+		Expect(fakeClient.Get(context.Background(), client.ObjectKeyFromObject(secret), &updated)).To(Succeed())
+		Expect(string(updated.Data["config"])).To(Equal("rotated"))
+		Expect(updated.Labels).To(HaveKeyWithValue("backup.example.com/include", "true"))
+		Expect(updated.Labels).To(HaveKeyWithValue("team", "kyma"))
+		Expect(updated.Labels).To(HaveKeyWithValue(managedByLabel, managedByLabelValue))
+		Expect(updated.Annotations).To(HaveKeyWithValue("note", "added by another actor"))
+		Expect(updated.Annotations).To(HaveKey(kubeconfigHashAnnotation))
+	})
+
+	It("Should remove the encryption annotations of a previously encrypted kubeconfig", func() {
+		secret := existingSecret(map[string]string{encryption.ProviderAnnotation: "vault"})
+		fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(secret).Build()
+		controller := &GardenerClusterController{Client: fakeClient}
+
+		err := controller.updateExistingSecret(context.Background(), "plaintext", cluster.DeepCopy(), target, secret.DeepCopy(), time.Now())
+
+		Expect(err).ToNot(HaveOccurred())
+
+		var updated corev1.Secret
+		Expect(fakeClient.Get(context.Background(), client.ObjectKeyFromObject(secret), &updated)).To(Succeed())
+		Expect(updated.Annotations).ToNot(HaveKey(encryption.ProviderAnnotation))
+		Expect(string(updated.Data["config"])).To(Equal("plaintext"))
+	})
+})
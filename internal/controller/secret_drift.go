@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const kubeconfigHashAnnotation = "operator.kyma-project.io/kubeconfig-hash"
+
+func kubeconfigHash(kubeconfig []byte) string {
+	hash := sha256.Sum256(kubeconfig)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// secretsDrifted reports whether any secret previously written by the controller has been deleted, or its kubeconfig
+// has been modified by another actor.
+func secretsDrifted(cluster *imv1.GardenerCluster, targets []imv1.Secret, secrets []*corev1.Secret) bool {
+	for i, target := range targets {
+		if secretDrifted(cluster, target, secrets[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func secretDrifted(cluster *imv1.GardenerCluster, target imv1.Secret, secret *corev1.Secret) bool {
+	if secret == nil {
+		return cluster.SecretSynced(target)
+	}
+
+	expectedHash, found := secret.GetAnnotations()[kubeconfigHashAnnotation]
+	if !found {
+		// Secrets written before the hash was introduced can't be verified.
+		return false
+	}
+
+	return kubeconfigHash(secret.Data[target.Key]) != expectedHash
+}
+
+// managedSecretPredicate filters out secrets which are not managed by the controller.
+func managedSecretPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetLabels()[managedByLabel] == managedByLabelValue
+	})
+}
+
+// secretToGardenerCluster maps a managed secret to the GardenerCluster owning it.
+func secretToGardenerCluster(_ context.Context, object client.Object) []reconcile.Request {
+	labels := object.GetLabels()
+
+	name, nameFound := labels[clusterCRNameLabel]
+	namespace, namespaceFound := labels[clusterCRNamespaceLabel]
+
+	if !nameFound || !namespaceFound {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
@@ -0,0 +1,371 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretMigrationController migrates the kubeconfig secrets to the naming convention, and the label schema requested
+// with the SecretMigrations. The secrets are read without the cache before they are changed, so a secret migrated in a
+// previous batch is not migrated again.
+type SecretMigrationController struct {
+	client.Client
+	reader client.Reader
+	log    logr.Logger
+}
+
+func NewSecretMigrationController(mgr ctrl.Manager, logger logr.Logger) *SecretMigrationController {
+	return &SecretMigrationController{
+		Client: mgr.GetClient(),
+		reader: mgr.GetAPIReader(),
+		log:    logger,
+	}
+}
+
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=secretmigrations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=secretmigrations/status,verbs=update
+
+func (controller *SecretMigrationController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { //nolint:revive
+	var migration imv1.SecretMigration
+
+	err := controller.Get(ctx, req.NamespacedName, &migration)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// a changed spec restarts the migration
+	if migration.Status.ObservedGeneration != migration.Generation {
+		migration.Status = imv1.SecretMigrationStatus{ObservedGeneration: migration.Generation}
+	}
+
+	if migration.Status.Phase == imv1.MigrationPhaseCompleted || migration.Status.Phase == imv1.MigrationPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	controller.log.Info("Starting reconciliation.", "SecretMigration", req.Name)
+
+	err = validateMigration(migration.Spec)
+	if err != nil {
+		migration.Status.Phase = imv1.MigrationPhaseFailed
+		setMigrationCondition(&migration, metav1.ConditionFalse, imv1.ConditionReasonInvalidMigration, err.Error())
+
+		return ctrl.Result{}, controller.Status().Update(ctx, &migration)
+	}
+
+	pending, err := controller.pendingSecrets(ctx, &migration)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	batch := pending[:min(migration.Spec.BatchSizeOrDefault(), len(pending))]
+	remaining := len(pending) - len(batch)
+
+	for i := range batch {
+		migrated, err := controller.migrateSecret(ctx, migration.Spec, &batch[i])
+
+		switch {
+		case k8serrors.IsConflict(err):
+			// the secret, or its GardenerCluster changed meanwhile, the secret is migrated with the next batch
+			remaining++
+		case err != nil:
+			key := fmt.Sprintf("%s/%s", batch[i].Namespace, batch[i].Name)
+			controller.log.Error(err, "Failed to migrate secret.", "SecretMigration", req.Name, "Secret", key)
+			migration.Status.FailedSecrets = append(migration.Status.FailedSecrets, key)
+		case migrated:
+			migration.Status.Migrated++
+		}
+	}
+
+	migration.Status.Remaining = int32(remaining)
+	migration.Status.Failed = int32(len(migration.Status.FailedSecrets))
+	updateMigrationPhase(&migration)
+
+	err = controller.Status().Update(ctx, &migration)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{Requeue: remaining > 0}, nil
+}
+
+// updateMigrationPhase reports the migration as running until all the secrets have been processed.
+func updateMigrationPhase(migration *imv1.SecretMigration) {
+	status := &migration.Status
+
+	if status.Remaining > 0 {
+		status.Phase = imv1.MigrationPhaseRunning
+		message := fmt.Sprintf("%d secrets migrated, %d remaining.", status.Migrated, status.Remaining)
+		setMigrationCondition(migration, metav1.ConditionFalse, imv1.ConditionReasonMigrationInProgress, message)
+
+		return
+	}
+
+	now := metav1.Now()
+	status.CompletionTime = &now
+
+	if status.Failed > 0 {
+		status.Phase = imv1.MigrationPhaseFailed
+		message := fmt.Sprintf("%d secrets migrated, %d failed.", status.Migrated, status.Failed)
+		setMigrationCondition(migration, metav1.ConditionTrue, imv1.ConditionReasonSecretMigrationFailed, message)
+
+		return
+	}
+
+	status.Phase = imv1.MigrationPhaseCompleted
+	message := fmt.Sprintf("%d secrets migrated.", status.Migrated)
+	setMigrationCondition(migration, metav1.ConditionTrue, imv1.ConditionReasonMigrationSucceeded, message)
+}
+
+func setMigrationCondition(migration *imv1.SecretMigration, status metav1.ConditionStatus, reason imv1.ConditionReason, message string) {
+	meta.SetStatusCondition(&migration.Status.Conditions, metav1.Condition{
+		Type:               string(imv1.ConditionTypeMigrationCompleted),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: migration.Generation,
+	})
+}
+
+// validateMigration rejects the renames which would match the migrated secrets again.
+func validateMigration(spec imv1.SecretMigrationSpec) error {
+	for _, renames := range [][]imv1.MetadataRename{spec.LabelRenames, spec.AnnotationRenames} {
+		sources := map[string]bool{}
+		for _, rename := range renames {
+			if rename.From == "" || rename.To == "" || rename.From == rename.To {
+				return errors.Errorf("invalid rename of %q to %q", rename.From, rename.To)
+			}
+
+			sources[rename.From] = true
+		}
+
+		for _, rename := range renames {
+			if sources[rename.To] {
+				return errors.Errorf("%q is renamed, and can't be the target of a rename", rename.To)
+			}
+		}
+	}
+
+	if prefix := spec.NamePrefix; prefix != nil {
+		if prefix.From == "" || strings.HasPrefix(prefix.To, prefix.From) {
+			return errors.Errorf("the prefix %q of the renamed secrets must not start with the prefix %q", prefix.To, prefix.From)
+		}
+	}
+
+	return nil
+}
+
+// pendingSecrets returns the secrets still to be migrated, in a stable order.
+func (controller *SecretMigrationController) pendingSecrets(ctx context.Context, migration *imv1.SecretMigration) ([]corev1.Secret, error) {
+	selector := labels.SelectorFromSet(labels.Set{managedByLabel: managedByLabelValue})
+
+	if migration.Spec.Selector != nil {
+		var err error
+
+		selector, err = metav1.LabelSelectorAsSelector(migration.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid selector")
+		}
+	}
+
+	namespaces := migration.Spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	failed := map[string]bool{}
+	for _, key := range migration.Status.FailedSecrets {
+		failed[key] = true
+	}
+
+	var pending []corev1.Secret
+
+	for _, namespace := range namespaces {
+		var secrets corev1.SecretList
+
+		err := controller.List(ctx, &secrets, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list secrets")
+		}
+
+		for _, secret := range secrets.Items {
+			if needsMigration(migration.Spec, &secret) && !failed[fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)] {
+				pending = append(pending, secret)
+			}
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].Namespace != pending[j].Namespace {
+			return pending[i].Namespace < pending[j].Namespace
+		}
+
+		return pending[i].Name < pending[j].Name
+	})
+
+	return pending, nil
+}
+
+// migrateSecret applies the migration to the current state of the secret, and reports whether it was changed.
+func (controller *SecretMigrationController) migrateSecret(ctx context.Context, spec imv1.SecretMigrationSpec, cached *corev1.Secret) (bool, error) {
+	var secret corev1.Secret
+
+	err := controller.reader.Get(ctx, client.ObjectKeyFromObject(cached), &secret)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get secret")
+	}
+
+	if !needsMigration(spec, &secret) {
+		return false, nil
+	}
+
+	renameMetadata(secret.Labels, spec.LabelRenames)
+	renameMetadata(secret.Annotations, spec.AnnotationRenames)
+
+	newName, renamed := renamedSecretName(spec, secret.Name)
+	if !renamed {
+		return true, controller.Update(ctx, &secret)
+	}
+
+	return true, controller.renameSecret(ctx, &secret, newName)
+}
+
+// renameSecret moves the secret to the new name, and points the GardenerCluster writing it to the new secret before
+// the old one is deleted, so the kubeconfig stays available.
+func (controller *SecretMigrationController) renameSecret(ctx context.Context, secret *corev1.Secret, newName string) error {
+	var cluster imv1.GardenerCluster
+
+	key := client.ObjectKey{Name: secret.Labels[clusterCRNameLabel], Namespace: secret.Labels[clusterCRNamespaceLabel]}
+
+	err := controller.Get(ctx, key, &cluster)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the GardenerCluster %s of the secret", key)
+	}
+
+	if cluster.Spec.Kubeconfig.Immutable {
+		return errors.Errorf("the versioned secrets of GardenerCluster %s are not renamed", key)
+	}
+
+	targetIndex := -1
+
+	for i, target := range cluster.Spec.Kubeconfig.Targets() {
+		if target.Name == secret.Name && target.Namespace == secret.Namespace && !target.Remote() {
+			targetIndex = i
+		}
+	}
+
+	if targetIndex < 0 {
+		return errors.Errorf("the secret is not a target of GardenerCluster %s", key)
+	}
+
+	err = controller.writeRenamedSecret(ctx, secret, newName, key)
+	if err != nil {
+		return err
+	}
+
+	if targetIndex == 0 {
+		cluster.Spec.Kubeconfig.Secret.Name = newName
+	} else {
+		cluster.Spec.Kubeconfig.AdditionalSecrets[targetIndex-1].Name = newName
+	}
+
+	err = controller.Update(ctx, &cluster)
+	if err != nil {
+		return err
+	}
+
+	return client.IgnoreNotFound(controller.Delete(ctx, secret))
+}
+
+// writeRenamedSecret creates the copy of the secret with the new name, a copy left by an interrupted rename is updated.
+func (controller *SecretMigrationController) writeRenamedSecret(ctx context.Context, secret *corev1.Secret, newName string, cluster client.ObjectKey) error {
+	renamed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        newName,
+			Namespace:   secret.Namespace,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+
+	err := controller.Create(ctx, renamed)
+	if !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	var existing corev1.Secret
+
+	err = controller.reader.Get(ctx, client.ObjectKeyFromObject(renamed), &existing)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the renamed secret")
+	}
+
+	if existing.Labels[clusterCRNameLabel] != cluster.Name || existing.Labels[clusterCRNamespaceLabel] != cluster.Namespace {
+		return errors.Errorf("secret %s already exists in namespace %s", newName, secret.Namespace)
+	}
+
+	renamed.ResourceVersion = existing.ResourceVersion
+
+	return controller.Update(ctx, renamed)
+}
+
+// needsMigration reports whether the migration changes the secret.
+func needsMigration(spec imv1.SecretMigrationSpec, secret *corev1.Secret) bool {
+	for _, rename := range spec.LabelRenames {
+		if _, found := secret.Labels[rename.From]; found {
+			return true
+		}
+	}
+
+	for _, rename := range spec.AnnotationRenames {
+		if _, found := secret.Annotations[rename.From]; found {
+			return true
+		}
+	}
+
+	_, renamed := renamedSecretName(spec, secret.Name)
+
+	return renamed
+}
+
+func renameMetadata(metadata map[string]string, renames []imv1.MetadataRename) {
+	for _, rename := range renames {
+		if val, found := metadata[rename.From]; found {
+			delete(metadata, rename.From)
+			metadata[rename.To] = val
+		}
+	}
+}
+
+func renamedSecretName(spec imv1.SecretMigrationSpec, name string) (string, bool) {
+	if spec.NamePrefix == nil || !strings.HasPrefix(name, spec.NamePrefix.From) {
+		return "", false
+	}
+
+	return spec.NamePrefix.To + strings.TrimPrefix(name, spec.NamePrefix.From), true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (controller *SecretMigrationController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&imv1.SecretMigration{}).
+		Complete(controller)
+}
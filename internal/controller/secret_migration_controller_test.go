@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("SecretMigration controller", func() {
+	newController := func(objects ...client.Object) *SecretMigrationController {
+		scheme := k8sruntime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&imv1.SecretMigration{}).
+			Build()
+
+		return &SecretMigrationController{Client: fakeClient, reader: fakeClient, log: log.Log}
+	}
+
+	cluster := func(name string, immutable bool) *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kcp-system"},
+			Spec: imv1.GardenerClusterSpec{Kubeconfig: imv1.Kubeconfig{
+				Secret:    imv1.Secret{Name: "kubeconfig-" + name, Namespace: "kcp-system", Key: "config"},
+				Immutable: immutable,
+			}},
+		}
+	}
+
+	secret := func(clusterName string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kubeconfig-" + clusterName,
+				Namespace: "kcp-system",
+				Labels: map[string]string{
+					managedByLabel:                       managedByLabelValue,
+					clusterCRNameLabel:                   clusterName,
+					clusterCRNamespaceLabel:              "kcp-system",
+					"operator.kyma-project.io/kyma-name": clusterName,
+				},
+			},
+			Data: map[string][]byte{"config": []byte("kubeconfig")},
+		}
+	}
+
+	migration := &imv1.SecretMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "migration"},
+		Spec: imv1.SecretMigrationSpec{
+			LabelRenames: []imv1.MetadataRename{{From: "operator.kyma-project.io/kyma-name", To: "kyma-project.io/kyma-name"}},
+			NamePrefix:   &imv1.NamePrefixRename{From: "kubeconfig-", To: "kyma-"},
+			BatchSize:    1,
+		},
+	}
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "migration"}}
+
+	It("Should relabel, and rename the secrets in batches, and report the progress", func() {
+		controller := newController(migration.DeepCopy(), cluster("first", false), secret("first"), cluster("second", false), secret("second"))
+
+		result, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+
+		var progress imv1.SecretMigration
+		Expect(controller.Get(context.Background(), request.NamespacedName, &progress)).To(Succeed())
+		Expect(progress.Status.Phase).To(Equal(imv1.MigrationPhaseRunning))
+		Expect(progress.Status.Migrated).To(Equal(int32(1)))
+		Expect(progress.Status.Remaining).To(Equal(int32(1)))
+
+		result, err = controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+		Expect(controller.Get(context.Background(), request.NamespacedName, &progress)).To(Succeed())
+		Expect(progress.Status.Phase).To(Equal(imv1.MigrationPhaseCompleted))
+		Expect(progress.Status.Migrated).To(Equal(int32(2)))
+		Expect(progress.Status.CompletionTime).ToNot(BeNil())
+
+		var renamed corev1.Secret
+		Expect(controller.Get(context.Background(), client.ObjectKey{Name: "kyma-first", Namespace: "kcp-system"}, &renamed)).To(Succeed())
+		Expect(renamed.Labels).To(HaveKeyWithValue("kyma-project.io/kyma-name", "first"))
+		Expect(renamed.Labels).ToNot(HaveKey("operator.kyma-project.io/kyma-name"))
+		Expect(string(renamed.Data["config"])).To(Equal("kubeconfig"))
+
+		err = controller.Get(context.Background(), client.ObjectKey{Name: "kubeconfig-first", Namespace: "kcp-system"}, &corev1.Secret{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		var updatedCluster imv1.GardenerCluster
+		Expect(controller.Get(context.Background(), client.ObjectKey{Name: "first", Namespace: "kcp-system"}, &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Spec.Kubeconfig.Secret.Name).To(Equal("kyma-first"))
+	})
+
+	It("Should report the secrets which can't be renamed", func() {
+		controller := newController(migration.DeepCopy(), cluster("versioned", true), secret("versioned"))
+
+		_, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+
+		var progress imv1.SecretMigration
+		Expect(controller.Get(context.Background(), request.NamespacedName, &progress)).To(Succeed())
+		Expect(progress.Status.Phase).To(Equal(imv1.MigrationPhaseFailed))
+		Expect(progress.Status.FailedSecrets).To(ConsistOf("kcp-system/kubeconfig-versioned"))
+		Expect(controller.Get(context.Background(), client.ObjectKey{Name: "kubeconfig-versioned", Namespace: "kcp-system"}, &corev1.Secret{})).To(Succeed())
+	})
+
+	It("Should reject a rename matching the renamed secrets again", func() {
+		invalid := migration.DeepCopy()
+		invalid.Spec.NamePrefix = &imv1.NamePrefixRename{From: "kubeconfig-", To: "kubeconfig-kyma-"}
+		controller := newController(invalid, cluster("first", false), secret("first"))
+
+		_, err := controller.Reconcile(context.Background(), request)
+
+		Expect(err).ToNot(HaveOccurred())
+
+		var progress imv1.SecretMigration
+		Expect(controller.Get(context.Background(), request.NamespacedName, &progress)).To(Succeed())
+		Expect(progress.Status.Phase).To(Equal(imv1.MigrationPhaseFailed))
+		Expect(progress.Status.Conditions[0].Reason).To(Equal(string(imv1.ConditionReasonInvalidMigration)))
+	})
+})
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/audit"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// staleSecretStatuses returns the statuses of the secrets which are no longer targets of the cluster, i.e. the
+// previous locations of the targets moved to another name, or namespace.
+func staleSecretStatuses(cluster *imv1.GardenerCluster) []imv1.SecretStatus {
+	var stale []imv1.SecretStatus
+
+	for _, secretStatus := range cluster.Status.Secrets {
+		if !isTarget(cluster, secretStatus) {
+			stale = append(stale, secretStatus)
+		}
+	}
+
+	return stale
+}
+
+func isTarget(cluster *imv1.GardenerCluster, secretStatus imv1.SecretStatus) bool {
+	for _, target := range cluster.Spec.Kubeconfig.Targets() {
+		if target.Name == secretStatus.Name && target.Namespace == secretStatus.Namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keepPendingRelocations records the previous locations in the status again after the statuses of the targets were
+// reset, so that the secrets there are deleted once the secrets at the new locations are verified.
+func keepPendingRelocations(cluster *imv1.GardenerCluster, stale []imv1.SecretStatus) {
+	for _, secretStatus := range stale {
+		cluster.AddPendingRelocation(secretStatus)
+	}
+}
+
+// relocateSecrets deletes the secrets at the previous locations of moved targets. The secrets at the new locations are
+// verified first, the previous ones are kept until all targets hold the kubeconfig, so that consumers don't lose access.
+func (controller *GardenerClusterController) relocateSecrets(ctx context.Context, cluster *imv1.GardenerCluster) (bool, error) {
+	stale := staleSecretStatuses(cluster)
+	if len(stale) == 0 {
+		return false, nil
+	}
+
+	err := controller.verifyTargetSecrets(ctx, cluster)
+	if err == nil {
+		err = controller.deletePreviousSecrets(ctx, cluster, stale)
+	}
+
+	if err != nil {
+		cluster.SetCondition(imv1.ConditionTypeSecretRelocated, metav1.ConditionFalse, imv1.ConditionReasonFailedToRelocateSecret, err)
+		controller.recordConditionEvent(cluster, imv1.ConditionTypeSecretRelocated, corev1.EventTypeWarning)
+
+		return true, err
+	}
+
+	previousLocations := make([]string, 0, len(stale))
+	for _, secretStatus := range stale {
+		previousLocations = append(previousLocations, fmt.Sprintf("%s/%s", secretStatus.Namespace, secretStatus.Name))
+	}
+
+	cluster.SetSecretRelocatedCondition(previousLocations)
+	controller.recordConditionEvent(cluster, imv1.ConditionTypeSecretRelocated, corev1.EventTypeNormal)
+	controller.log.Info("Secrets moved to the new locations.", append(loggingContextFromCluster(cluster), "previousLocations", previousLocations)...)
+
+	return true, nil
+}
+
+// verifyTargetSecrets checks that the secrets at the current locations exist, and hold the kubeconfig.
+func (controller *GardenerClusterController) verifyTargetSecrets(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	for _, target := range cluster.Spec.Kubeconfig.Targets() {
+		secret, err := controller.getCurrentSecret(ctx, cluster, target)
+		if err != nil {
+			return errors.Wrapf(err, "failed to verify secret %s in namespace %s", target.Name, target.Namespace)
+		}
+
+		if secret.Labels[managedByLabel] != managedByLabelValue || len(secret.Data[target.Key]) == 0 {
+			return errors.Errorf("secret %s in namespace %s does not hold the kubeconfig under the %s key", secret.Name, secret.Namespace, target.Key)
+		}
+	}
+
+	return nil
+}
+
+// deletePreviousSecrets deletes the secrets of the cluster at the previous locations, and removes them from the status.
+// Secrets which are not managed for the cluster are left untouched.
+func (controller *GardenerClusterController) deletePreviousSecrets(ctx context.Context, cluster *imv1.GardenerCluster, stale []imv1.SecretStatus) error {
+	for _, secretStatus := range stale {
+		previous := imv1.Secret{Name: secretStatus.Name, Namespace: secretStatus.Namespace, ClusterRef: secretStatus.ClusterRef}
+
+		secrets, err := controller.listTargetSecrets(ctx, cluster, previous)
+		if err != nil {
+			return err
+		}
+
+		secretClient, err := controller.secretsFor(ctx, cluster, previous)
+		if err != nil {
+			return err
+		}
+
+		for i := range secrets {
+			if _, conflict := secretOwnershipConflict(cluster, &secrets[i]); conflict || secrets[i].Labels[managedByLabel] != managedByLabelValue {
+				continue
+			}
+
+			err = secretClient.Delete(ctx, &secrets[i])
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+
+			if err == nil {
+				controller.auditSecretDeleted(cluster, &secrets[i], audit.TriggerSecretRelocated)
+			}
+		}
+
+		removeSecretStatus(cluster, secretStatus)
+	}
+
+	return nil
+}
+
+func removeSecretStatus(cluster *imv1.GardenerCluster, removed imv1.SecretStatus) {
+	statuses := cluster.Status.Secrets[:0]
+
+	for _, secretStatus := range cluster.Status.Secrets {
+		if secretStatus.Name != removed.Name || secretStatus.Namespace != removed.Namespace {
+			statuses = append(statuses, secretStatus)
+		}
+	}
+
+	cluster.Status.Secrets = statuses
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Relocation of the kubeconfig secrets", func() {
+	managedSecret := func(name, namespace string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					managedByLabel:          managedByLabelValue,
+					clusterCRNameLabel:      "cluster",
+					clusterCRNamespaceLabel: "kcp-system",
+				},
+			},
+			Data: map[string][]byte{"config": []byte("kubeconfig")},
+		}
+	}
+
+	movedCluster := func() *imv1.GardenerCluster {
+		cluster := &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+			Spec: imv1.GardenerClusterSpec{Kubeconfig: imv1.Kubeconfig{
+				Secret: imv1.Secret{Name: "kubeconfig-moved", Namespace: "kyma-system", Key: "config"},
+			}},
+		}
+		cluster.UpdateSecretStatus(cluster.Spec.Kubeconfig.Secret, imv1.ConditionReasonKubeconfigSecretCreated, nil)
+		cluster.AddPendingRelocation(imv1.SecretStatus{Name: "kubeconfig", Namespace: "kcp-system"})
+
+		return cluster
+	}
+
+	newController := func(objects ...client.Object) *GardenerClusterController {
+		fakeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objects...).Build()
+
+		return &GardenerClusterController{Client: fakeClient, log: log.Log, recorder: record.NewFakeRecorder(10)}
+	}
+
+	It("Should delete the secret at the previous location once the new one is verified", func() {
+		cluster := movedCluster()
+		controller := newController(managedSecret("kubeconfig", "kcp-system"), managedSecret("kubeconfig-moved", "kyma-system"))
+
+		relocated, err := controller.relocateSecrets(context.Background(), cluster)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(relocated).To(BeTrue())
+
+		err = controller.Get(context.Background(), client.ObjectKey{Name: "kubeconfig", Namespace: "kcp-system"}, &corev1.Secret{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+		Expect(cluster.Status.Secrets).To(HaveLen(1))
+		Expect(cluster.Status.Secrets[0].Name).To(Equal("kubeconfig-moved"))
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(imv1.ConditionTypeSecretRelocated))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("kcp-system/kubeconfig"))
+	})
+
+	It("Should keep the secret at the previous location while the new one doesn't hold the kubeconfig", func() {
+		cluster := movedCluster()
+		controller := newController(managedSecret("kubeconfig", "kcp-system"))
+
+		_, err := controller.relocateSecrets(context.Background(), cluster)
+
+		Expect(err).To(HaveOccurred())
+		Expect(controller.Get(context.Background(), client.ObjectKey{Name: "kubeconfig", Namespace: "kcp-system"}, &corev1.Secret{})).To(Succeed())
+		Expect(cluster.Status.Secrets).To(HaveLen(2))
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(imv1.ConditionTypeSecretRelocated))).To(BeTrue())
+	})
+
+	It("Should not delete a secret at the previous location which is not managed for the cluster", func() {
+		cluster := movedCluster()
+		unmanaged := managedSecret("kubeconfig", "kcp-system")
+		unmanaged.Labels = nil
+		controller := newController(unmanaged, managedSecret("kubeconfig-moved", "kyma-system"))
+
+		_, err := controller.relocateSecrets(context.Background(), cluster)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(controller.Get(context.Background(), client.ObjectKey{Name: "kubeconfig", Namespace: "kcp-system"}, &corev1.Secret{})).To(Succeed())
+		Expect(cluster.Status.Secrets).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// shootRecreated compares the UID of the shoot with the one recorded in the status when the kubeconfig was last
+// written, they differ when the shoot was deleted, and recreated with the same name. The kubeconfig of the previous shoot
+// is useless then, so it is replaced without waiting for the rotation. Failures to check are not critical, the
+// kubeconfig is still rotated on schedule.
+func (controller *GardenerClusterController) shootRecreated(ctx context.Context, cluster *imv1.GardenerCluster) bool {
+	if cluster.Status.Shoot == nil || cluster.Status.Shoot.UID == "" {
+		return false
+	}
+
+	provider, err := controller.kubeconfigProviderFor(ctx, cluster)
+	if err != nil {
+		return false
+	}
+
+	shootInfo, err := provider.FetchShootInfo(ctx, cluster.Spec.Shoot.Name)
+	if err != nil {
+		controller.log.Error(err, "Failed to check the identity of the shoot.", loggingContextFromCluster(cluster)...)
+		return false
+	}
+
+	if shootInfo.UID == "" || shootInfo.UID == cluster.Status.Shoot.UID {
+		return false
+	}
+
+	controller.log.Info("Shoot recreated, regenerating the kubeconfig.", append(loggingContextFromCluster(cluster), "previousUID", cluster.Status.Shoot.UID, "uid", shootInfo.UID)...)
+	cluster.SetCondition(imv1.ConditionTypeShootRecreated, metav1.ConditionTrue, imv1.ConditionReasonShootRecreated, nil)
+	controller.recordConditionEvent(cluster, imv1.ConditionTypeShootRecreated, corev1.EventTypeWarning)
+
+	return true
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/controller/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Shoot identity", func() {
+	newController := func(shootUID types.UID, err error) *GardenerClusterController {
+		provider := &mocks.KubeconfigProvider{}
+		provider.On("FetchShootInfo", mock.Anything, "shoot").Return(imv1.ShootInfo{UID: shootUID}, err)
+
+		return &GardenerClusterController{KubeconfigProvider: provider, log: log.Log, recorder: record.NewFakeRecorder(10)}
+	}
+
+	clusterWithShootUID := func(shootUID types.UID) *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+			Spec:       imv1.GardenerClusterSpec{Shoot: imv1.Shoot{Name: "shoot"}},
+			Status:     imv1.GardenerClusterStatus{Shoot: &imv1.ShootInfo{UID: shootUID}},
+		}
+	}
+
+	It("Should detect the shoot recreated with the same name", func() {
+		cluster := clusterWithShootUID("previous")
+
+		Expect(newController("recreated", nil).shootRecreated(context.Background(), cluster)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(imv1.ConditionTypeShootRecreated))).To(BeTrue())
+	})
+
+	It("Should not report the same shoot as recreated", func() {
+		cluster := clusterWithShootUID("previous")
+
+		Expect(newController("previous", nil).shootRecreated(context.Background(), cluster)).To(BeFalse())
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+
+	It("Should not check the shoot before its UID was recorded", func() {
+		cluster := clusterWithShootUID("")
+		controller := newController("recreated", nil)
+
+		Expect(controller.shootRecreated(context.Background(), cluster)).To(BeFalse())
+		controller.KubeconfigProvider.(*mocks.KubeconfigProvider).AssertNotCalled(GinkgoT(), "FetchShootInfo", mock.Anything, "shoot")
+	})
+
+	It("Should not report recreation when the shoot can't be checked", func() {
+		cluster := clusterWithShootUID("previous")
+
+		Expect(newController("", errors.New("shoot not found")).shootRecreated(context.Background(), cluster)).To(BeFalse())
+	})
+})
@@ -0,0 +1,43 @@
+package controller
+
+import "sync"
+
+// shootLocks serializes the kubeconfig management of clusters referencing the same shoot when reconciling in parallel.
+type shootLocks struct {
+	mu    sync.Mutex
+	locks map[string]*shootLock
+}
+
+type shootLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newShootLocks() *shootLocks {
+	return &shootLocks{locks: map[string]*shootLock{}}
+}
+
+// Lock blocks until the shoot is not processed by another reconciliation, the returned function releases the lock.
+func (sl *shootLocks) Lock(shootName string) func() {
+	sl.mu.Lock()
+	lock, found := sl.locks[shootName]
+	if !found {
+		lock = &shootLock{}
+		sl.locks[shootName] = lock
+	}
+	lock.refs++
+	sl.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		sl.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(sl.locks, shootName)
+		}
+		sl.mu.Unlock()
+	}
+}
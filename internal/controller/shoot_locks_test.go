@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shoot locks", func() {
+	It("Should serialize processing of the same shoot", func() {
+		locks := newShootLocks()
+		var active, maxActive int32
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock := locks.Lock("shoot")
+				defer unlock()
+
+				current := atomic.AddInt32(&active, 1)
+				for {
+					previous := atomic.LoadInt32(&maxActive)
+					if current <= previous || atomic.CompareAndSwapInt32(&maxActive, previous, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&active, -1)
+			}()
+		}
+		wg.Wait()
+
+		Expect(maxActive).To(Equal(int32(1)))
+		Expect(locks.locks).To(BeEmpty())
+	})
+
+	It("Should not block processing of different shoots", func() {
+		locks := newShootLocks()
+
+		unlockFirst := locks.Lock("shoot1")
+		unlockSecond := locks.Lock("shoot2")
+
+		Expect(locks.locks).To(HaveLen(2))
+		unlockFirst()
+		unlockSecond()
+		Expect(locks.locks).To(BeEmpty())
+	})
+})
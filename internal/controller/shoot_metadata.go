@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/featuregates"
+	"github.com/kyma-project/infrastructure-manager/internal/shoot"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ShootPatcher reads, and patches the shoots in the Gardener project of the controller.
+type ShootPatcher interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*gardener.Shoot, error)
+	PatchShoot(ctx context.Context, name string, patch []byte) error
+}
+
+// WithMetadataPropagation copies the labels, and annotations of the GardenerClusters selected by the policy to their
+// shoots.
+func (controller *GardenerClusterController) WithMetadataPropagation(policy shoot.MetadataPolicy, shoots ShootPatcher) *GardenerClusterController {
+	controller.metadataPolicy = policy
+	controller.shootPatcher = shoots
+
+	return controller
+}
+
+// propagateShootMetadata patches the propagated labels, and annotations of the shoot, only the changed keys are sent.
+// Failures are not critical for the kubeconfig management, the metadata is propagated again on the next reconciliation.
+// The shoots of the clusters with their own Gardener credentials are not in the project of the controller, and are skipped.
+func (controller *GardenerClusterController) propagateShootMetadata(ctx context.Context, cluster *imv1.GardenerCluster) {
+	if !controller.metadataPolicy.Enabled() || controller.shootPatcher == nil || cluster.Spec.Gardener != nil {
+		return
+	}
+
+	if !controller.featureEnabled(featuregates.ShootMetadataPropagation, true) {
+		return
+	}
+
+	err := controller.patchShootMetadata(ctx, cluster)
+	if err != nil {
+		controller.log.Error(err, "Failed to propagate metadata to shoot", loggingContextFromCluster(cluster)...)
+	}
+}
+
+func (controller *GardenerClusterController) patchShootMetadata(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	existingShoot, err := controller.shootPatcher.Get(ctx, cluster.Spec.Shoot.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get shoot")
+	}
+
+	original := existingShoot.DeepCopy()
+	if !controller.metadataPolicy.Apply(existingShoot, cluster) {
+		return nil
+	}
+
+	if controller.dryRun {
+		controller.log.Info("Dry run, shoot metadata not changed.", loggingContextFromCluster(cluster)...)
+
+		return nil
+	}
+
+	patch, err := client.MergeFrom(original).Data(existingShoot)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute shoot metadata patch")
+	}
+
+	return errors.Wrap(controller.shootPatcher.PatchShoot(ctx, existingShoot.Name, patch), "failed to patch shoot")
+}
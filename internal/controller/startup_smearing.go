@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// smearingListTimeout limits listing the inventory the warm-up schedule is built from
+const smearingListTimeout = 30 * time.Second
+
+// WithStartupSmearing spreads the first reconciliations of the existing clusters over the warm-up window after the
+// controller starts, instead of reconciling the whole inventory at once, and hammering Gardener. The clusters whose
+// kubeconfig expires first are reconciled first. Zero disables it.
+func (controller *GardenerClusterController) WithStartupSmearing(window time.Duration) *GardenerClusterController {
+	controller.startupWindow = window
+
+	return controller
+}
+
+// startupSchedule holds the times the existing clusters are reconciled at first, it is built from the inventory when the
+// first request is queued, which is when the informers list the clusters, and their secrets.
+type startupSchedule struct {
+	window time.Duration
+	reader client.Reader
+	owns   func(object client.Object) bool
+	log    logr.Logger
+
+	once    sync.Once
+	end     time.Time
+	startAt map[reconcile.Request]time.Time
+}
+
+// delay returns how long the request has to wait for its turn in the warm-up, zero when it is due, or the cluster is not
+// a part of the inventory the schedule was built from.
+func (schedule *startupSchedule) delay(item any, now time.Time) time.Duration {
+	schedule.once.Do(func() { schedule.build(now) })
+
+	request, ok := item.(reconcile.Request)
+	if !ok || !now.Before(schedule.end) {
+		return 0
+	}
+
+	startAt, found := schedule.startAt[request]
+	if !found || !now.Before(startAt) {
+		return 0
+	}
+
+	return startAt.Sub(now)
+}
+
+// build orders the clusters by the expiration of their kubeconfigs, the ones without a kubeconfig first, and spreads
+// them evenly over the window. The clusters are reconciled without a delay when they can't be listed.
+func (schedule *startupSchedule) build(now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), smearingListTimeout)
+	defer cancel()
+
+	var clusterList imv1.GardenerClusterList
+
+	err := schedule.reader.List(ctx, &clusterList)
+	if err != nil {
+		schedule.log.Error(err, "Failed to list the clusters, reconciling them without the warm-up.")
+		return
+	}
+
+	clusters := make([]imv1.GardenerCluster, 0, len(clusterList.Items))
+	for i := range clusterList.Items {
+		if schedule.owns(&clusterList.Items[i]) {
+			clusters = append(clusters, clusterList.Items[i])
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return expiresBefore(clusters[i].Status.KubeconfigExpirationTime, clusters[j].Status.KubeconfigExpirationTime)
+	})
+
+	schedule.end = now.Add(schedule.window)
+	schedule.startAt = make(map[reconcile.Request]time.Time, len(clusters))
+
+	for i := range clusters {
+		offset := time.Duration(float64(schedule.window) * float64(i) / float64(len(clusters)))
+		schedule.startAt[reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusters[i])}] = now.Add(offset)
+	}
+
+	schedule.log.Info("Spreading the reconciliation of the existing clusters over the warm-up.", "clusters", len(clusters), "window", schedule.window)
+}
+
+// expiresBefore orders the expirations, the unknown ones first.
+func expiresBefore(expiration, other *metav1.Time) bool {
+	if expiration.IsZero() || other.IsZero() {
+		return expiration.IsZero() && !other.IsZero()
+	}
+
+	return expiration.Before(other)
+}
+
+// smearedQueue delays the requests queued before their turn in the warm-up, whatever the event they were queued for.
+// The requeues after a reconciliation are not delayed, they are added with a delay, or the rate limit.
+type smearedQueue struct {
+	workqueue.RateLimitingInterface
+	schedule *startupSchedule
+}
+
+func (queue *smearedQueue) Add(item any) {
+	delay := queue.schedule.delay(item, time.Now())
+	if delay > 0 {
+		queue.RateLimitingInterface.AddAfter(item, delay)
+		return
+	}
+
+	queue.RateLimitingInterface.Add(item)
+}
+
+// smearStartup delays the first reconciliations of the existing clusters according to the warm-up schedule.
+func (controller *GardenerClusterController) smearStartup(clusterController crcontroller.Controller) error {
+	schedule := &startupSchedule{
+		window: controller.startupWindow,
+		reader: controller.reader,
+		owns:   controller.selected,
+		log:    controller.log,
+	}
+
+	return wrapQueue(clusterController, func(makeQueue func() workqueue.RateLimitingInterface) workqueue.RateLimitingInterface {
+		return &smearedQueue{RateLimitingInterface: makeQueue(), schedule: schedule}
+	})
+}
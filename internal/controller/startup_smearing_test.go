@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Startup smearing", func() {
+	now := time.Now()
+
+	clusterExpiringIn := func(name string, expiresIn time.Duration) *imv1.GardenerCluster {
+		cluster := &imv1.GardenerCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kcp-system"}}
+		if expiresIn > 0 {
+			cluster.Status.KubeconfigExpirationTime = &metav1.Time{Time: now.Add(expiresIn)}
+		}
+
+		return cluster
+	}
+
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "kcp-system"}}
+	}
+
+	newSchedule := func(objects ...client.Object) *startupSchedule {
+		scheme := runtime.NewScheme()
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+
+		return &startupSchedule{
+			window: 30 * time.Minute,
+			reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			owns:   func(object client.Object) bool { return object.GetName() != "other-shard" },
+			log:    log.Log,
+		}
+	}
+
+	It("Should spread the clusters over the window in the order of their expiration", func() {
+		schedule := newSchedule(
+			clusterExpiringIn("late", 20*time.Hour),
+			clusterExpiringIn("early", 2*time.Hour),
+			clusterExpiringIn("new", 0),
+		)
+
+		Expect(schedule.delay(request("new"), now)).To(BeZero())
+		Expect(schedule.delay(request("early"), now)).To(Equal(10 * time.Minute))
+		Expect(schedule.delay(request("late"), now)).To(Equal(20 * time.Minute))
+	})
+
+	It("Should not delay the clusters created after the start, or reconciled by other instances", func() {
+		schedule := newSchedule(clusterExpiringIn("existing", time.Hour), clusterExpiringIn("other-shard", 0))
+
+		Expect(schedule.delay(request("existing"), now)).To(BeZero())
+		Expect(schedule.delay(request("other-shard"), now)).To(BeZero())
+		Expect(schedule.delay(request("created"), now)).To(BeZero())
+	})
+
+	It("Should not delay the clusters once the window passed", func() {
+		schedule := newSchedule(clusterExpiringIn("first", time.Hour), clusterExpiringIn("second", 2*time.Hour))
+		Expect(schedule.delay(request("second"), now)).To(Equal(15 * time.Minute))
+
+		Expect(schedule.delay(request("second"), now.Add(15*time.Minute))).To(BeZero())
+	})
+
+	It("Should queue the requests at their turn", func() {
+		queue := &smearedQueue{
+			RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+			schedule:              newSchedule(clusterExpiringIn("first", time.Hour), clusterExpiringIn("second", 2*time.Hour)),
+		}
+		defer queue.ShutDown()
+
+		queue.Add(request("first"))
+		queue.Add(request("second"))
+
+		Expect(queue.Len()).To(Equal(1))
+	})
+
+	It("Should wrap the priority queue of the controller", func() {
+		mgr, err := ctrl.NewManager(&rest.Config{Host: "https://localhost:6443"}, ctrl.Options{MetricsBindAddress: "0"})
+		Expect(err).ToNot(HaveOccurred())
+
+		controller := (&GardenerClusterController{reader: mgr.GetAPIReader(), log: log.Log}).WithStartupSmearing(time.Minute)
+		clusterController, err := crcontroller.NewUnmanaged("gardenercluster", mgr, crcontroller.Options{Reconciler: controller})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(usePriorityQueue(clusterController, mgr.GetCache())).To(Succeed())
+		Expect(controller.smearStartup(clusterController)).To(Succeed())
+	})
+})
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/controller/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Status updates", func() {
+	// staleCache serves the objects in the version they had when the cache was created, like an informer which hasn't
+	// observed the latest write yet
+	staleCache := func(objects ...client.Object) (client.Client, client.WithWatch) {
+		scheme := k8sruntime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+
+		apiServer := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&imv1.GardenerCluster{}, &imv1.Runtime{}).
+			Build()
+
+		stale := map[client.ObjectKey]client.Object{}
+		for _, object := range objects {
+			current := object.DeepCopyObject().(client.Object)
+			Expect(apiServer.Get(context.Background(), client.ObjectKeyFromObject(object), current)).To(Succeed())
+			stale[client.ObjectKeyFromObject(object)] = current
+		}
+
+		cache := interceptor.NewClient(apiServer, interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if object, found := stale[key]; found {
+					return c.Scheme().Convert(object.DeepCopyObject(), obj, nil)
+				}
+
+				return c.Get(ctx, key, obj, opts...)
+			},
+		})
+
+		return cache, apiServer
+	}
+
+	It("Should write the status of the GardenerCluster to the latest version after a conflict", func() {
+		cluster := &imv1.GardenerCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"}}
+		cache, apiServer := staleCache(cluster)
+
+		// another writer changes the cluster after the cache observed it
+		var latest imv1.GardenerCluster
+		Expect(apiServer.Get(context.Background(), client.ObjectKeyFromObject(cluster), &latest)).To(Succeed())
+		latest.Labels = map[string]string{"changed": "true"}
+		Expect(apiServer.Update(context.Background(), &latest)).To(Succeed())
+
+		controller := &GardenerClusterController{Client: cache, reader: apiServer, log: log.Log}
+		cluster.Status.State = imv1.ReadyState
+
+		Expect(controller.persistStatusChange(context.Background(), cluster)).To(Succeed())
+
+		Expect(apiServer.Get(context.Background(), client.ObjectKeyFromObject(cluster), &latest)).To(Succeed())
+		Expect(latest.Status.State).To(Equal(imv1.ReadyState))
+		Expect(latest.Labels).To(HaveKeyWithValue("changed", "true"))
+	})
+
+	It("Should write the status of the Runtime to the latest version after a conflict", func() {
+		rt := &imv1.Runtime{ObjectMeta: metav1.ObjectMeta{Name: "runtime", Namespace: "kcp-system"}}
+		_, apiServer := staleCache(rt)
+
+		// the status is computed on the version read at the start of the reconciliation
+		var outdated imv1.Runtime
+		Expect(apiServer.Get(context.Background(), client.ObjectKeyFromObject(rt), &outdated)).To(Succeed())
+
+		latest := outdated.DeepCopy()
+		latest.Labels = map[string]string{"changed": "true"}
+		Expect(apiServer.Update(context.Background(), latest)).To(Succeed())
+
+		controller := &RuntimeController{Client: apiServer, reader: apiServer, log: log.Log}
+		outdated.Status.State = imv1.ReadyState
+
+		Expect(controller.persistRuntimeStatus(context.Background(), &outdated, nil)).To(Succeed())
+
+		Expect(apiServer.Get(context.Background(), client.ObjectKeyFromObject(rt), latest)).To(Succeed())
+		Expect(latest.Status.State).To(Equal(imv1.ReadyState))
+		Expect(latest.Labels).To(HaveKeyWithValue("changed", "true"))
+	})
+
+	It("Should update the status of the GardenerCluster once per rotation", func() {
+		scheme := k8sruntime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(imv1.AddToScheme(scheme)).To(Succeed())
+
+		cluster := &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system", Finalizers: []string{kubeconfigSecretFinalizer}},
+			Spec: imv1.GardenerClusterSpec{
+				Shoot:      imv1.Shoot{Name: "shoot"},
+				Kubeconfig: imv1.Kubeconfig{Secret: imv1.Secret{Name: "kubeconfig", Namespace: "kcp-system", Key: "config"}},
+			},
+		}
+		apiServer := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(cluster).
+			WithStatusSubresource(&imv1.GardenerCluster{}).
+			Build()
+
+		statusUpdates := 0
+		countingClient := interceptor.NewClient(apiServer, interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				statusUpdates++
+
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		})
+
+		provider := &mocks.KubeconfigProvider{}
+		provider.On("Fetch", mock.Anything, "shoot").Return("", time.Time{}, errors.New("connection refused"))
+
+		controller := &GardenerClusterController{
+			Client:             countingClient,
+			reader:             countingClient,
+			Scheme:             scheme,
+			KubeconfigProvider: provider,
+			log:                log.Log,
+			recorder:           record.NewFakeRecorder(10),
+			rotationPeriod:     time.Hour,
+			shootLocks:         newShootLocks(),
+		}
+
+		_, err := controller.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(statusUpdates).To(Equal(1))
+	})
+})
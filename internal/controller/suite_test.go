@@ -24,9 +24,11 @@ import (
 
 	infrastructuremanagerv1 "github.com/kyma-project/infrastructure-manager/api/v1"
 	"github.com/kyma-project/infrastructure-manager/internal/controller/mocks"
+	"github.com/kyma-project/infrastructure-manager/internal/shoot"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -45,10 +47,23 @@ var (
 	testEnv        *envtest.Environment //nolint:gochecknoglobals
 	suiteCtx       context.Context      //nolint:gochecknoglobals
 	cancelSuiteCtx context.CancelFunc   //nolint:gochecknoglobals
+
+	testShootManager *fakeShootManager //nolint:gochecknoglobals
 )
 
 const TestKubeconfigValidityTime = 24 * time.Hour
 
+const testPropagatedLabelPrefix = "kyma-project.io/"
+
+var TestKubeconfigExpirationTime = time.Date(2023, time.October, 10, 23, 0, 0, 0, time.UTC) //nolint:gochecknoglobals
+
+var TestShootInfo = infrastructuremanagerv1.ShootInfo{ //nolint:gochecknoglobals
+	ProviderType:      "aws",
+	Region:            "eu-central-1",
+	KubernetesVersion: "1.27.5",
+	Domain:            "shoot.example.com",
+}
+
 func TestControllers(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -86,6 +101,14 @@ var _ = BeforeSuite(func() {
 	err = controller.SetupWithManager(mgr)
 	Expect(err).To(BeNil())
 
+	requestController := NewKubeconfigRequestController(mgr, kubeconfigProviderMock, logger, TestKubeconfigValidityTime)
+	Expect(requestController.SetupWithManager(mgr)).To(Succeed())
+
+	testShootManager = newFakeShootManager()
+	runtimeController := NewRuntimeController(mgr, testShootManager, "garden-test", logger).
+		WithMetadataPropagation(shoot.MetadataPolicy{LabelPrefixes: []string{testPropagatedLabelPrefix}})
+	Expect(runtimeController.SetupWithManager(mgr)).To(Succeed())
+
 	//+kubebuilder:scaffold:scheme
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
@@ -102,12 +125,23 @@ var _ = BeforeSuite(func() {
 })
 
 func setupKubeconfigProviderMock(kpMock *mocks.KubeconfigProvider) {
-	kpMock.On("Fetch", "shootName1").Return("kubeconfig1", nil)
-	kpMock.On("Fetch", "shootName2").Return("kubeconfig2", nil)
-	kpMock.On("Fetch", "shootName3").Return("", errors.New("failed to get kubeconfig"))
-	kpMock.On("Fetch", "shootName6").Return("kubeconfig6", nil)
-	kpMock.On("Fetch", "shootName4").Return("kubeconfig4", nil)
-	kpMock.On("Fetch", "shootName5").Return("kubeconfig5", nil)
+	kpMock.On("Fetch", mock.Anything, "shootName1").Return("kubeconfig1", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName2").Return("kubeconfig2", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName3").Return("", time.Time{}, errors.New("failed to get kubeconfig"))
+	kpMock.On("Fetch", mock.Anything, "shootName6").Return("kubeconfig6", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName4").Return("kubeconfig4", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName5").Return("kubeconfig5", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName8").Return("kubeconfig8", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName9").Return("kubeconfig9", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName10").Return("kubeconfig10", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName11").Return("kubeconfig11", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName12").Return("kubeconfig12", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName13").Return("kubeconfig13", TestKubeconfigExpirationTime, nil)
+	kpMock.On("Fetch", mock.Anything, "shootName14").Return("kubeconfig14", TestKubeconfigExpirationTime, nil)
+	kpMock.On("FetchShootInfo", mock.Anything, mock.Anything).Return(TestShootInfo, nil)
+	kpMock.On("FetchWithExpiration", mock.Anything, "shootName13", mock.Anything).Return(func(_ context.Context, _ string, expiration time.Duration) (string, time.Time, error) {
+		return "kubeconfig13", time.Now().Add(expiration), nil
+	})
 }
 
 var _ = AfterSuite(func() {
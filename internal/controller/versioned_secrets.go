@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/audit"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	secretBaseNameLabel = "operator.kyma-project.io/secret-name"
+	secretVersionLabel  = "operator.kyma-project.io/secret-version"
+)
+
+// versionedSecretName returns the name of a version of the secret. In immutable mode every rotation creates a new
+// secret named `<name>-v<version>`, the previous version is kept for consumers that still mount it, and all older
+// versions are removed.
+func versionedSecretName(target imv1.Secret, version int) string {
+	return fmt.Sprintf("%s-v%d", target.Name, version)
+}
+
+func secretVersion(secret *corev1.Secret) int {
+	version, err := strconv.Atoi(secret.Labels[secretVersionLabel])
+	if err != nil {
+		return 0
+	}
+
+	return version
+}
+
+func (controller *GardenerClusterController) listSecretVersions(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret) ([]corev1.Secret, error) {
+	targetClient, err := controller.clientFor(ctx, cluster, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var secretList corev1.SecretList
+
+	err = targetClient.List(ctx, &secretList, client.InNamespace(target.Namespace), client.MatchingLabels{
+		clusterCRNameLabel:      cluster.Name,
+		clusterCRNamespaceLabel: cluster.Namespace,
+		secretBaseNameLabel:     target.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secretList.Items, nil
+}
+
+// getCurrentSecretVersion returns the secret with the highest version, or NotFound error if no version exists yet.
+func (controller *GardenerClusterController) getCurrentSecretVersion(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret) (*corev1.Secret, error) {
+	versions, err := controller.listSecretVersions(ctx, cluster, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *corev1.Secret
+
+	for i := range versions {
+		if current == nil || secretVersion(&versions[i]) > secretVersion(current) {
+			current = &versions[i]
+		}
+	}
+
+	if current == nil {
+		return nil, k8serrors.NewNotFound(corev1.Resource("secrets"), target.Name)
+	}
+
+	return current, nil
+}
+
+func (controller *GardenerClusterController) createNextSecretVersion(ctx context.Context, kubeconfig string, cluster *imv1.GardenerCluster, target imv1.Secret, currentSecret *corev1.Secret, lastSyncTime time.Time) error {
+	version := 1
+	if currentSecret != nil {
+		version = secretVersion(currentSecret) + 1
+	}
+
+	immutable := true
+	newSecret := controller.newSecret(*cluster, target, kubeconfig, lastSyncTime)
+	newSecret.Name = versionedSecretName(target, version)
+	newSecret.Labels[secretBaseNameLabel] = target.Name
+	newSecret.Labels[secretVersionLabel] = strconv.Itoa(version)
+	newSecret.Immutable = &immutable
+
+	err := controller.createSecret(ctx, cluster, target, &newSecret)
+	if err != nil {
+		cluster.UpdateSecretStatus(target, imv1.ConditionReasonFailedToCreateSecret, err)
+
+		return err
+	}
+
+	reason := imv1.ConditionReasonKubeconfigSecretRotated
+	if currentSecret == nil {
+		reason = imv1.ConditionReasonKubeconfigSecretCreated
+	}
+
+	cluster.UpdateSecretStatus(target, reason, nil)
+	cluster.SetCurrentSecretName(target, newSecret.Name)
+
+	message := fmt.Sprintf("Secret %s has been created in %s namespace.", newSecret.Name, newSecret.Namespace)
+	controller.log.Info(message, loggingContextFromCluster(cluster)...)
+
+	// The new version is already in place, failing the rotation now would only create another version.
+	err = controller.deleteOutdatedSecretVersions(ctx, cluster, target, version)
+	if err != nil {
+		controller.log.Error(err, "Failed to delete outdated secret versions.", loggingContextFromCluster(cluster)...)
+	}
+
+	return nil
+}
+
+func (controller *GardenerClusterController) deleteOutdatedSecretVersions(ctx context.Context, cluster *imv1.GardenerCluster, target imv1.Secret, currentVersion int) error {
+	versions, err := controller.listSecretVersions(ctx, cluster, target)
+	if err != nil {
+		return err
+	}
+
+	targetClient, err := controller.clientFor(ctx, cluster, target)
+	if err != nil {
+		return err
+	}
+
+	for i := range versions {
+		if secretVersion(&versions[i]) >= currentVersion-1 {
+			continue
+		}
+
+		err = targetClient.Delete(ctx, &versions[i])
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		if err == nil {
+			controller.auditSecretDeleted(cluster, &versions[i], audit.TriggerVersionPruned)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+
+	"filippo.io/age"
+	"github.com/pkg/errors"
+)
+
+// AgeKeyWrapper encrypts data keys to an age X25519 recipient, the holder of the identity decrypts them offline.
+type AgeKeyWrapper struct {
+	recipient *age.X25519Recipient
+}
+
+func NewAgeKeyWrapper(recipient string) (*AgeKeyWrapper, error) {
+	parsed, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid age recipient")
+	}
+
+	return &AgeKeyWrapper{recipient: parsed}, nil
+}
+
+func (wrapper *AgeKeyWrapper) Provider() string {
+	return ProviderAge
+}
+
+func (wrapper *AgeKeyWrapper) KeyID() string {
+	return wrapper.recipient.String()
+}
+
+func (wrapper *AgeKeyWrapper) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	var wrapped bytes.Buffer
+
+	writer, err := age.Encrypt(&wrapped, wrapper.recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = writer.Write(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return wrapped.Bytes(), nil
+}
@@ -0,0 +1,43 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+type kmsAPI interface {
+	Encrypt(ctx context.Context, params *kms.EncryptInput, optFns ...func(*kms.Options)) (*kms.EncryptOutput, error)
+}
+
+// AWSKMSKeyWrapper encrypts data keys with an AWS KMS key, the ciphertext blob is decrypted with the KMS Decrypt API.
+type AWSKMSKeyWrapper struct {
+	client kmsAPI
+	keyID  string
+}
+
+// NewAWSKMSKeyWrapper creates the wrapper for the key ID, ARN, or alias, the credentials are taken from the AWS configuration.
+func NewAWSKMSKeyWrapper(config aws.Config, keyID string) *AWSKMSKeyWrapper {
+	return &AWSKMSKeyWrapper{client: kms.NewFromConfig(config), keyID: keyID}
+}
+
+func (wrapper *AWSKMSKeyWrapper) Provider() string {
+	return ProviderAWSKMS
+}
+
+func (wrapper *AWSKMSKeyWrapper) KeyID() string {
+	return wrapper.keyID
+}
+
+func (wrapper *AWSKMSKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	output, err := wrapper.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(wrapper.keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.CiphertextBlob, nil
+}
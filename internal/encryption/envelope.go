@@ -0,0 +1,114 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ProviderAWSKMS = "aws-kms"
+	ProviderGCPKMS = "gcp-kms"
+	ProviderAge    = "age"
+)
+
+const (
+	ProviderAnnotation  = "operator.kyma-project.io/encryption-provider"
+	KeyAnnotation       = "operator.kyma-project.io/encryption-key"
+	DataKeyAnnotation   = "operator.kyma-project.io/encrypted-data-key"
+	AlgorithmAnnotation = "operator.kyma-project.io/encryption-algorithm"
+
+	AlgorithmAES256GCM = "AES-256-GCM"
+	dataKeySize        = 32
+)
+
+// Annotations lists all annotations set on encrypted secrets.
+//
+//nolint:gochecknoglobals
+var Annotations = []string{ProviderAnnotation, KeyAnnotation, DataKeyAnnotation, AlgorithmAnnotation}
+
+// KeyWrapper encrypts data keys with a key encryption key held by a key management system.
+type KeyWrapper interface {
+	// Provider identifies the key management system.
+	Provider() string
+
+	// KeyID identifies the key encryption key within the key management system.
+	KeyID() string
+
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+}
+
+// Envelope encrypts kubeconfigs with a random data key per kubeconfig using AES-256-GCM, the data key is encrypted
+// with the key encryption key. The ciphertext is prefixed with the nonce. The encrypted data key, and the reference
+// of the key encryption key are returned as annotations of the secret, so consumers can decrypt the kubeconfig.
+type Envelope struct {
+	keyWrapper KeyWrapper
+}
+
+func NewEnvelope(keyWrapper KeyWrapper) *Envelope {
+	return &Envelope{keyWrapper: keyWrapper}
+}
+
+func (envelope *Envelope) Encrypt(ctx context.Context, plaintext []byte) ([]byte, map[string]string, error) {
+	dataKey := make([]byte, dataKeySize)
+
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate data key")
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	wrappedKey, err := envelope.keyWrapper.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to encrypt data key with %s key %s", envelope.keyWrapper.Provider(), envelope.keyWrapper.KeyID())
+	}
+
+	annotations := map[string]string{
+		ProviderAnnotation:  envelope.keyWrapper.Provider(),
+		KeyAnnotation:       envelope.keyWrapper.KeyID(),
+		DataKeyAnnotation:   base64.StdEncoding.EncodeToString(wrappedKey),
+		AlgorithmAnnotation: AlgorithmAES256GCM,
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), annotations, nil
+}
+
+// Decrypt reverses Encrypt given the plaintext data key.
+func Decrypt(dataKey, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+func newGCM(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
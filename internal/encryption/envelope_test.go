@@ -0,0 +1,95 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainKeyWrapper returns the data key unencrypted, so that tests can decrypt the kubeconfig.
+type plainKeyWrapper struct{}
+
+func (plainKeyWrapper) Provider() string { return "plain" }
+
+func (plainKeyWrapper) KeyID() string { return "key" }
+
+func (plainKeyWrapper) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	return dataKey, nil
+}
+
+func TestEnvelope(t *testing.T) {
+	t.Run("should encrypt kubeconfig with a data key recorded in annotations", func(t *testing.T) {
+		// given
+		envelope := NewEnvelope(plainKeyWrapper{})
+
+		// when
+		ciphertext, annotations, err := envelope.Encrypt(context.Background(), []byte("kubeconfig"))
+		require.NoError(t, err)
+
+		// then
+		assert.NotContains(t, string(ciphertext), "kubeconfig")
+		assert.Equal(t, "plain", annotations[ProviderAnnotation])
+		assert.Equal(t, "key", annotations[KeyAnnotation])
+		assert.Equal(t, AlgorithmAES256GCM, annotations[AlgorithmAnnotation])
+
+		dataKey, err := base64.StdEncoding.DecodeString(annotations[DataKeyAnnotation])
+		require.NoError(t, err)
+
+		plaintext, err := Decrypt(dataKey, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "kubeconfig", string(plaintext))
+	})
+
+	t.Run("should use a new data key for every kubeconfig", func(t *testing.T) {
+		// given
+		envelope := NewEnvelope(plainKeyWrapper{})
+
+		// when
+		_, firstAnnotations, err := envelope.Encrypt(context.Background(), []byte("kubeconfig"))
+		require.NoError(t, err)
+
+		_, secondAnnotations, err := envelope.Encrypt(context.Background(), []byte("kubeconfig"))
+		require.NoError(t, err)
+
+		// then
+		assert.NotEqual(t, firstAnnotations[DataKeyAnnotation], secondAnnotations[DataKeyAnnotation])
+	})
+}
+
+func TestAgeKeyWrapper(t *testing.T) {
+	t.Run("should wrap data key for the recipient", func(t *testing.T) {
+		// given
+		identity, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+
+		wrapper, err := NewAgeKeyWrapper(identity.Recipient().String())
+		require.NoError(t, err)
+
+		// when
+		wrapped, err := wrapper.WrapKey(context.Background(), []byte("data key"))
+		require.NoError(t, err)
+
+		// then
+		reader, err := age.Decrypt(bytes.NewReader(wrapped), identity)
+		require.NoError(t, err)
+
+		dataKey, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "data key", string(dataKey))
+		assert.Equal(t, identity.Recipient().String(), wrapper.KeyID())
+	})
+
+	t.Run("should reject invalid recipient", func(t *testing.T) {
+		// when
+		_, err := NewAgeKeyWrapper("invalid")
+
+		// then
+		require.Error(t, err)
+	})
+}
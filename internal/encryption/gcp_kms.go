@@ -0,0 +1,78 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcpKMSEndpoint        = "https://cloudkms.googleapis.com"
+	gcpCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// GCPKMSKeyWrapper encrypts data keys with a Google Cloud KMS key using its REST API.
+type GCPKMSKeyWrapper struct {
+	httpClient *http.Client
+	endpoint   string
+	keyName    string
+}
+
+// NewGCPKMSKeyWrapper creates the wrapper for the key resource name, `projects/*/locations/*/keyRings/*/cryptoKeys/*`,
+// authenticating with the application default credentials.
+func NewGCPKMSKeyWrapper(ctx context.Context, keyName string) (*GCPKMSKeyWrapper, error) {
+	httpClient, err := google.DefaultClient(ctx, gcpCloudPlatformScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Google application default credentials")
+	}
+
+	return &GCPKMSKeyWrapper{httpClient: httpClient, endpoint: gcpKMSEndpoint, keyName: keyName}, nil
+}
+
+func (wrapper *GCPKMSKeyWrapper) Provider() string {
+	return ProviderGCPKMS
+}
+
+func (wrapper *GCPKMSKeyWrapper) KeyID() string {
+	return wrapper.keyName
+}
+
+func (wrapper *GCPKMSKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, wrapper.endpoint+"/v1/"+wrapper.keyName+":encrypt", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := wrapper.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Cloud KMS responded with status %d", response.StatusCode)
+	}
+
+	var encryptResponse struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&encryptResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(encryptResponse.Ciphertext)
+}
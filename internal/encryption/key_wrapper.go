@@ -0,0 +1,27 @@
+package encryption
+
+import (
+	"context"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/pkg/errors"
+)
+
+// NewKeyWrapper creates the key wrapper of the provider for the key, the credentials are taken from the environment.
+func NewKeyWrapper(ctx context.Context, provider, key string) (KeyWrapper, error) {
+	switch provider {
+	case ProviderAWSKMS:
+		config, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load AWS configuration")
+		}
+
+		return NewAWSKMSKeyWrapper(config, key), nil
+	case ProviderGCPKMS:
+		return NewGCPKMSKeyWrapper(ctx, key)
+	case ProviderAge:
+		return NewAgeKeyWrapper(key)
+	default:
+		return nil, errors.Errorf("unsupported encryption provider %q", provider)
+	}
+}
@@ -0,0 +1,85 @@
+// Package featuregates holds the features of the manager which can be enabled, or disabled at runtime with the
+// InfrastructureManagerConfig, the flags of the manager set their defaults.
+package featuregates
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the feature gates.
+const (
+	// KubeconfigValidation checks the kubeconfigs before they are written to the secrets.
+	KubeconfigValidation = "KubeconfigValidation"
+	// ShootMetadataPropagation copies the labels, and annotations of the GardenerClusters to their shoots.
+	ShootMetadataPropagation = "ShootMetadataPropagation"
+	// ShootAdmissionValidation rejects the GardenerClusters referencing a shoot which doesn't exist when they are created.
+	ShootAdmissionValidation = "ShootAdmissionValidation"
+)
+
+// Gates holds the state of the feature gates, the overrides set at runtime take precedence over the defaults.
+type Gates struct {
+	defaults map[string]bool
+
+	mu        sync.RWMutex
+	overrides map[string]bool
+}
+
+// New creates the gates with their defaults, only the gates with a default are known.
+func New(defaults map[string]bool) *Gates {
+	return &Gates{defaults: defaults, overrides: map[string]bool{}}
+}
+
+// Enabled reports whether the feature is enabled, unknown features are disabled.
+func (gates *Gates) Enabled(name string) bool {
+	gates.mu.RLock()
+	defer gates.mu.RUnlock()
+
+	if enabled, found := gates.overrides[name]; found {
+		return enabled
+	}
+
+	return gates.defaults[name]
+}
+
+// Set replaces the overrides, the gates not overridden return to their defaults. The overrides are not applied when
+// they contain an unknown gate.
+func (gates *Gates) Set(overrides map[string]bool) error {
+	err := gates.Validate(overrides)
+	if err != nil {
+		return err
+	}
+
+	applied := make(map[string]bool, len(overrides))
+	for name, enabled := range overrides {
+		applied[name] = enabled
+	}
+
+	gates.mu.Lock()
+	defer gates.mu.Unlock()
+
+	gates.overrides = applied
+
+	return nil
+}
+
+// Validate checks the overrides only contain known gates.
+func (gates *Gates) Validate(overrides map[string]bool) error {
+	var unknown []string
+
+	for name := range overrides {
+		if _, found := gates.defaults[name]; !found {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+
+		return errors.Errorf("unknown feature gates %v", unknown)
+	}
+
+	return nil
+}
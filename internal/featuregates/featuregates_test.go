@@ -0,0 +1,43 @@
+package featuregates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGates(t *testing.T) {
+	t.Run("should apply the overrides, and return to the defaults", func(t *testing.T) {
+		// given
+		gates := New(map[string]bool{KubeconfigValidation: true, ShootMetadataPropagation: false})
+
+		// when
+		err := gates.Set(map[string]bool{KubeconfigValidation: false})
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, gates.Enabled(KubeconfigValidation))
+		assert.False(t, gates.Enabled(ShootMetadataPropagation))
+
+		// when
+		err = gates.Set(nil)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, gates.Enabled(KubeconfigValidation))
+	})
+
+	t.Run("should reject unknown gates, and keep the overrides", func(t *testing.T) {
+		// given
+		gates := New(map[string]bool{KubeconfigValidation: true})
+		require.NoError(t, gates.Set(map[string]bool{KubeconfigValidation: false}))
+
+		// when
+		err := gates.Set(map[string]bool{"Unknown": true, KubeconfigValidation: true})
+
+		// then
+		assert.ErrorContains(t, err, "unknown feature gates [Unknown]")
+		assert.False(t, gates.Enabled(KubeconfigValidation))
+	})
+}
@@ -2,14 +2,22 @@ package gardener
 
 import (
 	"context"
+	"time"
 
 	authenticationv1alpha1 "github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
 	"github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
 	"github.com/pkg/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gardenerClient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Names of the shoot API server addresses advertised by Gardener.
+const (
+	externalAdvertisedAddress = "external"
+	internalAdvertisedAddress = "internal"
+)
+
 type KubeconfigProvider struct {
 	shootNamespace       string
 	shootClient          ShootClient
@@ -38,22 +46,61 @@ func NewKubeconfigProvider(
 	}
 }
 
-func (kp KubeconfigProvider) Fetch(shootName string) (string, error) {
-	shoot, err := kp.shootClient.Get(context.Background(), shootName, v1.GetOptions{})
+func (kp KubeconfigProvider) Fetch(ctx context.Context, shootName string) (string, time.Time, error) {
+	return kp.FetchWithExpiration(ctx, shootName, time.Duration(kp.expirationInSeconds)*time.Second)
+}
+
+// FetchWithExpiration requests an admin kubeconfig valid for the given duration instead of the default one.
+func (kp KubeconfigProvider) FetchWithExpiration(ctx context.Context, shootName string, expiration time.Duration) (string, time.Time, error) {
+	shoot, err := kp.shootClient.Get(ctx, shootName, v1.GetOptions{})
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get shoot")
+		return "", time.Time{}, errors.Wrap(err, "failed to get shoot")
 	}
 
+	expirationInSeconds := int64(expiration.Seconds())
 	adminKubeconfigRequest := authenticationv1alpha1.AdminKubeconfigRequest{
 		Spec: authenticationv1alpha1.AdminKubeconfigRequestSpec{
-			ExpirationSeconds: &kp.expirationInSeconds,
+			ExpirationSeconds: &expirationInSeconds,
 		},
 	}
 
-	err = kp.dynamicKubeconfigAPI.Create(context.Background(), shoot, &adminKubeconfigRequest)
+	err = kp.dynamicKubeconfigAPI.Create(ctx, shoot, &adminKubeconfigRequest)
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "failed to create AdminKubeconfigRequest")
+	}
+
+	return string(adminKubeconfigRequest.Status.Kubeconfig), adminKubeconfigRequest.Status.ExpirationTimestamp.Time, nil
+}
+
+func (kp KubeconfigProvider) FetchShootInfo(ctx context.Context, shootName string) (imv1.ShootInfo, error) {
+	shoot, err := kp.shootClient.Get(ctx, shootName, v1.GetOptions{})
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create AdminKubeconfigRequest")
+		return imv1.ShootInfo{}, errors.Wrap(err, "failed to get shoot")
+	}
+
+	shootInfo := imv1.ShootInfo{
+		UID:               shoot.UID,
+		ProviderType:      shoot.Spec.Provider.Type,
+		Region:            shoot.Spec.Region,
+		KubernetesVersion: shoot.Spec.Kubernetes.Version,
+	}
+
+	if shoot.Spec.Purpose != nil {
+		shootInfo.Purpose = imv1.Purpose(*shoot.Spec.Purpose)
+	}
+
+	if shoot.Spec.DNS != nil && shoot.Spec.DNS.Domain != nil {
+		shootInfo.Domain = *shoot.Spec.DNS.Domain
+	}
+
+	for _, address := range shoot.Status.AdvertisedAddresses {
+		switch address.Name {
+		case externalAdvertisedAddress:
+			shootInfo.ExternalEndpoint = address.URL
+		case internalAdvertisedAddress:
+			shootInfo.InternalEndpoint = address.URL
+		}
 	}
 
-	return string(adminKubeconfigRequest.Status.Kubeconfig), nil
+	return shootInfo, nil
 }
@@ -0,0 +1,79 @@
+package gardener
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authenticationv1alpha1 "github.com/gardener/gardener/pkg/apis/authentication/v1alpha1"
+	"github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gardenerClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type reconcileKey struct{}
+
+type recordingShootClient struct {
+	ctx context.Context
+}
+
+func (client *recordingShootClient) Get(ctx context.Context, name string, _ metav1.GetOptions) (*v1beta1.Shoot, error) {
+	client.ctx = ctx
+
+	return &v1beta1.Shoot{ObjectMeta: metav1.ObjectMeta{Name: name, UID: "shoot-uid"}}, nil
+}
+
+type recordingKubeconfigAPI struct {
+	ctx context.Context
+}
+
+func (api *recordingKubeconfigAPI) Create(ctx context.Context, _ gardenerClient.Object, subResource gardenerClient.Object, _ ...gardenerClient.SubResourceCreateOption) error {
+	api.ctx = ctx
+
+	request, ok := subResource.(*authenticationv1alpha1.AdminKubeconfigRequest)
+	if !ok {
+		return errors.New("unexpected subresource")
+	}
+
+	request.Status.Kubeconfig = []byte("kubeconfig")
+	request.Status.ExpirationTimestamp = metav1.NewTime(time.Now().Add(time.Hour))
+
+	return nil
+}
+
+func TestKubeconfigProvider(t *testing.T) {
+	t.Run("should send the kubeconfig requests with the context of the caller", func(t *testing.T) {
+		// given
+		shootClient := &recordingShootClient{}
+		kubeconfigAPI := &recordingKubeconfigAPI{}
+		provider := NewKubeconfigProvider(shootClient, kubeconfigAPI, "garden-test", 600)
+		ctx := context.WithValue(context.Background(), reconcileKey{}, "reconcile")
+
+		// when
+		kubeconfig, _, err := provider.Fetch(ctx, "shoot")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "kubeconfig", kubeconfig)
+		assert.Equal(t, "reconcile", shootClient.ctx.Value(reconcileKey{}))
+		assert.Equal(t, "reconcile", kubeconfigAPI.ctx.Value(reconcileKey{}))
+	})
+
+	t.Run("should get the shoot info with the context of the caller", func(t *testing.T) {
+		// given
+		shootClient := &recordingShootClient{}
+		provider := NewKubeconfigProvider(shootClient, &recordingKubeconfigAPI{}, "garden-test", 600)
+		ctx := context.WithValue(context.Background(), reconcileKey{}, "reconcile")
+
+		// when
+		shootInfo, err := provider.FetchShootInfo(ctx, "shoot")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "shoot-uid", string(shootInfo.UID))
+		assert.Equal(t, "reconcile", shootClient.ctx.Value(reconcileKey{}))
+	})
+}
@@ -1,23 +0,0 @@
-package gardener
-
-import (
-	"fmt"
-	"os"
-
-	restclient "k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-)
-
-func NewRestConfigFromFile(kubeconfigFilePath string) (*restclient.Config, error) {
-	rawKubeconfig, err := os.ReadFile(kubeconfigFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read Gardener Kubeconfig from path %s: %s", kubeconfigFilePath, err.Error())
-	}
-
-	restConfig, err := clientcmd.RESTConfigFromKubeConfig(rawKubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	return restConfig, err
-}
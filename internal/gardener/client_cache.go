@@ -0,0 +1,425 @@
+package gardener
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardener_apis "github.com/gardener/gardener/pkg/client/core/clientset/versioned/typed/core/v1beta1"
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/kubeconfig"
+	"github.com/kyma-project/infrastructure-manager/internal/metrics"
+	"github.com/pkg/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
+	gardenerClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClientCache keeps the Gardener clients for the lifetime of the process, and rebuilds them when the mounted
+// kubeconfig changes, e.g. after the token has been rotated. It implements ShootClient, and DynamicKubeconfigAPI.
+// The kubeconfig, and the project namespace can be replaced at runtime with Configure.
+type ClientCache struct {
+	kubeconfigPath   string
+	defaultNamespace string
+	rateLimiter      flowcontrol.RateLimiter
+	refreshInterval  time.Duration
+	log              logr.Logger
+
+	// refreshMu serializes the refreshes, and the configuration changes
+	refreshMu sync.Mutex
+
+	mu                   sync.RWMutex
+	rawKubeconfig        []byte
+	namespace            string
+	endpoint             string
+	configured           bool
+	shootClient          gardener_apis.ShootInterface
+	cloudProfileClient   gardener_apis.CloudProfileInterface
+	seedClient           gardener_apis.SeedInterface
+	dynamicKubeconfigAPI DynamicKubeconfigAPI
+	shootLister          shootLister
+
+	checkMu        sync.Mutex
+	lastCheckTime  time.Time
+	lastCheckError error
+}
+
+type shootLister interface {
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.ShootList, error)
+}
+
+// deletionConfirmationAnnotation must be set on a shoot before Gardener accepts its deletion.
+const deletionConfirmationAnnotation = "confirmation.gardener.cloud/deletion"
+
+// connectivityCheckInterval limits the requests sent to Gardener by the health probes.
+const connectivityCheckInterval = 30 * time.Second
+
+func NewClientCache(kubeconfigPath, namespace string, rateLimiter flowcontrol.RateLimiter, refreshInterval time.Duration, logger logr.Logger) (*ClientCache, error) {
+	cache := &ClientCache{
+		kubeconfigPath:   kubeconfigPath,
+		defaultNamespace: namespace,
+		namespace:        namespace,
+		rateLimiter:      rateLimiter,
+		refreshInterval:  refreshInterval,
+		log:              logger,
+	}
+
+	_, err := cache.Refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// Refresh rebuilds the clients when the kubeconfig file has changed since the last refresh, and reports whether it did.
+// The file is not read while a kubeconfig set with Configure is used.
+func (cache *ClientCache) Refresh() (bool, error) {
+	cache.refreshMu.Lock()
+	defer cache.refreshMu.Unlock()
+
+	cache.mu.RLock()
+	configured, endpoint, namespace := cache.configured, cache.endpoint, cache.namespace
+	cache.mu.RUnlock()
+
+	if configured {
+		return false, nil
+	}
+
+	rawKubeconfig, err := cache.readKubeconfigFile()
+	if err != nil {
+		return false, err
+	}
+
+	return cache.apply(rawKubeconfig, endpoint, namespace, false)
+}
+
+// Configure replaces the mounted kubeconfig, its server, and the project namespace, and reports whether the clients
+// were rebuilt. A nil kubeconfig returns to the mounted kubeconfig, an empty endpoint keeps the server of the
+// kubeconfig, and an empty namespace returns to the namespace the cache was created with.
+func (cache *ClientCache) Configure(rawKubeconfig []byte, endpoint, namespace string) (bool, error) {
+	cache.refreshMu.Lock()
+	defer cache.refreshMu.Unlock()
+
+	if namespace == "" {
+		namespace = cache.defaultNamespace
+	}
+
+	configured := rawKubeconfig != nil
+	if !configured {
+		var err error
+
+		rawKubeconfig, err = cache.readKubeconfigFile()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return cache.apply(rawKubeconfig, endpoint, namespace, configured)
+}
+
+// Namespace returns the namespace of the Gardener project the clients use.
+func (cache *ClientCache) Namespace() string {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	return cache.namespace
+}
+
+func (cache *ClientCache) readKubeconfigFile() ([]byte, error) {
+	rawKubeconfig, err := os.ReadFile(cache.kubeconfigPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read Gardener Kubeconfig from path %s", cache.kubeconfigPath)
+	}
+
+	return rawKubeconfig, nil
+}
+
+// apply rebuilds the clients when the kubeconfig, or the namespace have changed.
+func (cache *ClientCache) apply(rawKubeconfig []byte, endpoint, namespace string, configured bool) (bool, error) {
+	if endpoint != "" {
+		kubeconfigWithEndpoint, err := kubeconfig.WithServer(string(rawKubeconfig), endpoint)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to set the Gardener endpoint")
+		}
+
+		rawKubeconfig = []byte(kubeconfigWithEndpoint)
+	}
+
+	cache.mu.RLock()
+	unchanged := bytes.Equal(rawKubeconfig, cache.rawKubeconfig) && namespace == cache.namespace
+	cache.mu.RUnlock()
+
+	if unchanged {
+		cache.mu.Lock()
+		cache.configured = configured
+		cache.endpoint = endpoint
+		cache.mu.Unlock()
+
+		return false, nil
+	}
+
+	// the kubeconfig is provided by the operator, with the flags, or the InfrastructureManagerConfig, so it may run
+	// commands, or read files, e.g. to refresh its token
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(rawKubeconfig)
+	if err != nil {
+		return false, err
+	}
+
+	gardenerClientSet, dynamicKubeconfigAPI, err := newClients(restConfig, cache.rateLimiter)
+	if err != nil {
+		return false, err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.rawKubeconfig = rawKubeconfig
+	cache.namespace = namespace
+	cache.endpoint = endpoint
+	cache.configured = configured
+	cache.shootClient = gardenerClientSet.Shoots(namespace)
+	cache.shootLister = cache.shootClient
+	cache.cloudProfileClient = gardenerClientSet.CloudProfiles()
+	cache.seedClient = gardenerClientSet.Seeds()
+	cache.dynamicKubeconfigAPI = dynamicKubeconfigAPI
+
+	return true, nil
+}
+
+func newClients(restConfig *rest.Config, rateLimiter flowcontrol.RateLimiter) (gardener_apis.CoreV1beta1Interface, DynamicKubeconfigAPI, error) {
+	restConfig.RateLimiter = rateLimiter
+	if limiter, ok := rateLimiter.(*RateLimiter); ok {
+		restConfig.Wrap(limiter.WrapTransport)
+	}
+
+	gardenerClientSet, err := gardener_apis.NewForConfig(restConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := gardenerClient.New(restConfig, gardenerClient.Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = v1beta1.AddToScheme(client.Scheme())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to register Gardener schema")
+	}
+
+	return gardenerClientSet, client.SubResource("adminkubeconfig"), nil
+}
+
+// Start refreshes the clients every refresh interval until the context is cancelled. It is called by the manager, the
+// refresh interval must be positive.
+func (cache *ClientCache) Start(ctx context.Context) error {
+	ticker := time.NewTicker(cache.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			refreshed, err := cache.Refresh()
+			if err != nil {
+				cache.log.Error(err, "Failed to refresh Gardener clients")
+				continue
+			}
+
+			if refreshed {
+				cache.log.Info("Gardener kubeconfig changed, clients rebuilt")
+			}
+		}
+	}
+}
+
+// Check implements healthz.Checker, it fails when Gardener is not reachable, or the credentials are rejected.
+// The result is reused for the connectivity check interval.
+func (cache *ClientCache) Check(req *http.Request) error {
+	cache.checkMu.Lock()
+	defer cache.checkMu.Unlock()
+
+	if time.Since(cache.lastCheckTime) < connectivityCheckInterval {
+		return cache.lastCheckError
+	}
+
+	cache.mu.RLock()
+	lister := cache.shootLister
+	cache.mu.RUnlock()
+
+	_, err := lister.List(req.Context(), v1.ListOptions{Limit: 1})
+	if err != nil {
+		err = errors.Wrap(err, "failed to reach Gardener")
+	}
+
+	cache.lastCheckTime = time.Now()
+	cache.lastCheckError = err
+
+	return err
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable, the clients are used by all replicas.
+func (cache *ClientCache) NeedLeaderElection() bool {
+	return false
+}
+
+func (cache *ClientCache) shoots() gardener_apis.ShootInterface {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	return cache.shootClient
+}
+
+func (cache *ClientCache) Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.Shoot, error) {
+	start := time.Now()
+	shoot, err := cache.shoots().Get(ctx, name, opts)
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationGetShoot, start, err)
+
+	return shoot, err
+}
+
+// LookupShoot checks the shoot exists in the project, it fails with a NotFound error otherwise.
+func (cache *ClientCache) LookupShoot(ctx context.Context, name string) error {
+	_, err := cache.Get(ctx, name, v1.GetOptions{})
+
+	return err
+}
+
+func (cache *ClientCache) Create(ctx context.Context, obj gardenerClient.Object, subResource gardenerClient.Object, opts ...gardenerClient.SubResourceCreateOption) error {
+	cache.mu.RLock()
+	dynamicKubeconfigAPI := cache.dynamicKubeconfigAPI
+	cache.mu.RUnlock()
+
+	start := time.Now()
+	err := dynamicKubeconfigAPI.Create(ctx, obj, subResource, opts...)
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationCreateKubeconfig, start, err)
+
+	return err
+}
+
+// CreateShoot creates the shoot in the project namespace.
+func (cache *ClientCache) CreateShoot(ctx context.Context, shoot *v1beta1.Shoot) (*v1beta1.Shoot, error) {
+	start := time.Now()
+	created, err := cache.shoots().Create(ctx, shoot, v1.CreateOptions{})
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationCreateShoot, start, err)
+
+	return created, err
+}
+
+// UpdateShoot updates the spec, and the metadata of the shoot, the update fails on a conflicting resource version.
+func (cache *ClientCache) UpdateShoot(ctx context.Context, shoot *v1beta1.Shoot) (*v1beta1.Shoot, error) {
+	start := time.Now()
+	updated, err := cache.shoots().Update(ctx, shoot, v1.UpdateOptions{})
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationUpdateShoot, start, err)
+
+	return updated, err
+}
+
+// PatchShoot applies the JSON merge patch to the shoot, the patch is not checked against the resource version.
+func (cache *ClientCache) PatchShoot(ctx context.Context, name string, patch []byte) error {
+	start := time.Now()
+	_, err := cache.shoots().Patch(ctx, name, types.MergePatchType, patch, v1.PatchOptions{})
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationPatchShoot, start, err)
+
+	return err
+}
+
+func (cache *ClientCache) getCloudProfile(ctx context.Context, name string) (*v1beta1.CloudProfile, error) {
+	cache.mu.RLock()
+	cloudProfiles := cache.cloudProfileClient
+	cache.mu.RUnlock()
+
+	start := time.Now()
+	cloudProfile, err := cloudProfiles.Get(ctx, name, v1.GetOptions{})
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationGetCloudProfile, start, err)
+
+	return cloudProfile, errors.Wrapf(err, "failed to get cloud profile %s", name)
+}
+
+// CloudProfile returns the provider type, the regions, and the seed selector of the cloud profile.
+func (cache *ClientCache) CloudProfile(ctx context.Context, name string) (imv1.CloudProfileInfo, error) {
+	cloudProfile, err := cache.getCloudProfile(ctx, name)
+	if err != nil {
+		return imv1.CloudProfileInfo{}, err
+	}
+
+	info := imv1.CloudProfileInfo{ProviderType: cloudProfile.Spec.Type}
+
+	for _, region := range cloudProfile.Spec.Regions {
+		info.Regions = append(info.Regions, region.Name)
+	}
+
+	if selector := cloudProfile.Spec.SeedSelector; selector != nil {
+		info.SeedSelector = &imv1.RuntimeSeedSelector{LabelSelector: selector.LabelSelector, ProviderTypes: selector.ProviderTypes}
+	}
+
+	return info, nil
+}
+
+// Seed returns the provider type, the region, and the labels of the seed.
+func (cache *ClientCache) Seed(ctx context.Context, name string) (imv1.SeedInfo, error) {
+	cache.mu.RLock()
+	seeds := cache.seedClient
+	cache.mu.RUnlock()
+
+	start := time.Now()
+	seed, err := seeds.Get(ctx, name, v1.GetOptions{})
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationGetSeed, start, err)
+
+	if err != nil {
+		return imv1.SeedInfo{}, errors.Wrapf(err, "failed to get seed %s", name)
+	}
+
+	return imv1.SeedInfo{ProviderType: seed.Spec.Provider.Type, Region: seed.Spec.Provider.Region, Labels: seed.Labels}, nil
+}
+
+// RegionZones returns the names of the zones of the region in the cloud profile, none when the region is not defined.
+func (cache *ClientCache) RegionZones(ctx context.Context, cloudProfileName, region string) ([]string, error) {
+	cloudProfile, err := cache.getCloudProfile(ctx, cloudProfileName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profileRegion := range cloudProfile.Spec.Regions {
+		if profileRegion.Name != region {
+			continue
+		}
+
+		zones := make([]string, 0, len(profileRegion.Zones))
+		for _, zone := range profileRegion.Zones {
+			zones = append(zones, zone.Name)
+		}
+
+		return zones, nil
+	}
+
+	return []string{}, nil
+}
+
+// DeleteShoot confirms the deletion with the annotation required by Gardener, and deletes the shoot.
+func (cache *ClientCache) DeleteShoot(ctx context.Context, name string) error {
+	shoots := cache.shoots()
+
+	start := time.Now()
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:"true"}}}`, deletionConfirmationAnnotation))
+
+	_, err := shoots.Patch(ctx, name, types.MergePatchType, patch, v1.PatchOptions{})
+	if err == nil {
+		err = shoots.Delete(ctx, name, v1.DeleteOptions{})
+	}
+
+	metrics.ObserveGardenerRequest(metrics.GardenerOperationDeleteShoot, start, err)
+
+	return err
+}
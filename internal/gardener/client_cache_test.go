@@ -0,0 +1,154 @@
+package gardener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const testKubeconfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- name: garden
+  cluster:
+    server: https://gardener.example.com
+contexts:
+- name: garden
+  context:
+    cluster: garden
+    user: robot
+current-context: garden
+users:
+- name: robot
+  user:
+    token: %s
+`
+
+func TestClientCache(t *testing.T) {
+	t.Run("should rebuild clients only when the kubeconfig changes", func(t *testing.T) {
+		// given
+		kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+		writeTestKubeconfig(t, kubeconfigPath, "token1")
+
+		cache, err := NewClientCache(kubeconfigPath, "garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), time.Minute, logr.Discard())
+		require.NoError(t, err)
+
+		// when
+		refreshedUnchanged, err := cache.Refresh()
+		require.NoError(t, err)
+
+		writeTestKubeconfig(t, kubeconfigPath, "token2")
+		refreshedChanged, err := cache.Refresh()
+		require.NoError(t, err)
+
+		// then
+		assert.False(t, refreshedUnchanged)
+		assert.True(t, refreshedChanged)
+	})
+
+	t.Run("should keep the clients when the kubeconfig can't be read", func(t *testing.T) {
+		// given
+		kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+		writeTestKubeconfig(t, kubeconfigPath, "token1")
+
+		cache, err := NewClientCache(kubeconfigPath, "garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), time.Minute, logr.Discard())
+		require.NoError(t, err)
+
+		// when
+		require.NoError(t, os.Remove(kubeconfigPath))
+		_, err = cache.Refresh()
+
+		// then
+		require.Error(t, err)
+		assert.NotNil(t, cache.shootClient)
+		assert.NotNil(t, cache.dynamicKubeconfigAPI)
+	})
+
+	t.Run("should use the configured kubeconfig, and return to the mounted one", func(t *testing.T) {
+		// given
+		kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+		writeTestKubeconfig(t, kubeconfigPath, "token1")
+
+		cache, err := NewClientCache(kubeconfigPath, "garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), time.Minute, logr.Discard())
+		require.NoError(t, err)
+
+		// when
+		configured, err := cache.Configure([]byte(fmt.Sprintf(testKubeconfigTemplate, "token2")), "https://gardener.other.example.com", "garden-other")
+		require.NoError(t, err)
+
+		writeTestKubeconfig(t, kubeconfigPath, "token3")
+		refreshed, err := cache.Refresh()
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, configured)
+		assert.False(t, refreshed)
+		assert.Equal(t, "garden-other", cache.Namespace())
+		assert.Contains(t, string(cache.rawKubeconfig), "https://gardener.other.example.com")
+
+		// when
+		configured, err = cache.Configure(nil, "", "")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, configured)
+		assert.Equal(t, "garden-test", cache.Namespace())
+		assert.Contains(t, string(cache.rawKubeconfig), "token3")
+	})
+}
+
+func TestClientCacheCheck(t *testing.T) {
+	t.Run("should fail when Gardener can't be reached", func(t *testing.T) {
+		// given
+		lister := &fakeShootLister{err: errors.New("connection refused")}
+		cache := &ClientCache{shootLister: lister}
+
+		// when
+		err := cache.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should reuse the result within the check interval", func(t *testing.T) {
+		// given
+		lister := &fakeShootLister{}
+		cache := &ClientCache{shootLister: lister}
+
+		// when
+		require.NoError(t, cache.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil)))
+		require.NoError(t, cache.Check(httptest.NewRequest(http.MethodGet, "/readyz", nil)))
+
+		// then
+		assert.Equal(t, 1, lister.calls)
+	})
+}
+
+type fakeShootLister struct {
+	calls int
+	err   error
+}
+
+func (lister *fakeShootLister) List(_ context.Context, _ metav1.ListOptions) (*v1beta1.ShootList, error) {
+	lister.calls++
+
+	return &v1beta1.ShootList{}, lister.err
+}
+
+func writeTestKubeconfig(t *testing.T, path, token string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(testKubeconfigTemplate, token)), 0o600))
+}
@@ -0,0 +1,86 @@
+package gardener
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/kyma-project/infrastructure-manager/internal/kubeconfig"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// ProjectClients keeps the kubeconfig providers of the Gardener projects selected by the credentials of the
+// GardenerClusters, keyed by the secret with the kubeconfig. A provider is rebuilt when the kubeconfig in its secret
+// changes. The providers share the rate limiter of the controller, so the requests to Gardener stay limited.
+type ProjectClients struct {
+	defaultNamespace    string
+	rateLimiter         flowcontrol.RateLimiter
+	expirationInSeconds int64
+
+	mu        sync.Mutex
+	providers map[string]projectProvider
+}
+
+type projectProvider struct {
+	kubeconfigHash [sha256.Size]byte
+	provider       KubeconfigProvider
+}
+
+// NewProjectClients creates the cache, the default namespace is used for the kubeconfigs without a namespace in their
+// current context.
+func NewProjectClients(defaultNamespace string, rateLimiter flowcontrol.RateLimiter, expirationInSeconds int64) *ProjectClients {
+	return &ProjectClients{
+		defaultNamespace:    defaultNamespace,
+		rateLimiter:         rateLimiter,
+		expirationInSeconds: expirationInSeconds,
+		providers:           map[string]projectProvider{},
+	}
+}
+
+// KubeconfigProvider returns the provider of the Gardener project of the kubeconfig stored in the secret with the key.
+func (clients *ProjectClients) KubeconfigProvider(key string, rawKubeconfig []byte) (KubeconfigProvider, error) {
+	kubeconfigHash := sha256.Sum256(rawKubeconfig)
+
+	clients.mu.Lock()
+	defer clients.mu.Unlock()
+
+	if cached, found := clients.providers[key]; found && cached.kubeconfigHash == kubeconfigHash {
+		return cached.provider, nil
+	}
+
+	namespace, err := clients.projectNamespace(rawKubeconfig)
+	if err != nil {
+		return KubeconfigProvider{}, err
+	}
+
+	// the kubeconfig is provided by the tenant, so only inline credentials are accepted
+	restConfig, err := kubeconfig.RESTConfigFromUntrusted(rawKubeconfig)
+	if err != nil {
+		return KubeconfigProvider{}, errors.Wrapf(err, "invalid Gardener kubeconfig in %s", key)
+	}
+
+	gardenerClientSet, dynamicKubeconfigAPI, err := newClients(restConfig, clients.rateLimiter)
+	if err != nil {
+		return KubeconfigProvider{}, errors.Wrapf(err, "failed to create the Gardener clients for %s", key)
+	}
+
+	provider := NewKubeconfigProvider(gardenerClientSet.Shoots(namespace), dynamicKubeconfigAPI, namespace, clients.expirationInSeconds)
+	clients.providers[key] = projectProvider{kubeconfigHash: kubeconfigHash, provider: provider}
+
+	return provider, nil
+}
+
+// projectNamespace returns the namespace of the current context of the kubeconfig.
+func (clients *ProjectClients) projectNamespace(rawKubeconfig []byte) (string, error) {
+	config, err := clientcmd.Load(rawKubeconfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the Gardener kubeconfig")
+	}
+
+	if kubeconfigContext, found := config.Contexts[config.CurrentContext]; found && kubeconfigContext.Namespace != "" {
+		return kubeconfigContext.Namespace, nil
+	}
+
+	return clients.defaultNamespace, nil
+}
@@ -0,0 +1,83 @@
+package gardener
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func TestProjectClients(t *testing.T) {
+	t.Run("should reuse the provider until the kubeconfig changes", func(t *testing.T) {
+		// given
+		clients := NewProjectClients("garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), 600)
+
+		// when
+		first, err := clients.KubeconfigProvider("kcp-system/gardener-eu", []byte(fmt.Sprintf(testKubeconfigTemplate, "token1")))
+		require.NoError(t, err)
+
+		cached, err := clients.KubeconfigProvider("kcp-system/gardener-eu", []byte(fmt.Sprintf(testKubeconfigTemplate, "token1")))
+		require.NoError(t, err)
+
+		rebuilt, err := clients.KubeconfigProvider("kcp-system/gardener-eu", []byte(fmt.Sprintf(testKubeconfigTemplate, "token2")))
+		require.NoError(t, err)
+
+		// then
+		assert.Same(t, first.shootClient, cached.shootClient)
+		assert.NotSame(t, first.shootClient, rebuilt.shootClient)
+		assert.Equal(t, "garden-test", first.shootNamespace)
+	})
+
+	t.Run("should use the namespace of the current context of the kubeconfig", func(t *testing.T) {
+		// given
+		clients := NewProjectClients("garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), 600)
+		kubeconfig := strings.Replace(fmt.Sprintf(testKubeconfigTemplate, "token"), "    cluster: garden\n", "    cluster: garden\n    namespace: garden-eu\n", 1)
+
+		// when
+		provider, err := clients.KubeconfigProvider("kcp-system/gardener-eu", []byte(kubeconfig))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "garden-eu", provider.shootNamespace)
+	})
+
+	t.Run("should fail on an invalid kubeconfig", func(t *testing.T) {
+		// given
+		clients := NewProjectClients("garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), 600)
+
+		// when
+		_, err := clients.KubeconfigProvider("kcp-system/gardener-eu", []byte("invalid"))
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject a kubeconfig running a command in the controller", func(t *testing.T) {
+		// given
+		clients := NewProjectClients("garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), 600)
+		kubeconfig := strings.Replace(fmt.Sprintf(testKubeconfigTemplate, "token"), "token: token", "exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh", 1)
+
+		// when
+		_, err := clients.KubeconfigProvider("kcp-system/gardener-eu", []byte(kubeconfig))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exec plugin")
+	})
+
+	t.Run("should reject a kubeconfig reading the token of the controller", func(t *testing.T) {
+		// given
+		clients := NewProjectClients("garden-test", flowcontrol.NewFakeAlwaysRateLimiter(), 600)
+		kubeconfig := strings.Replace(fmt.Sprintf(testKubeconfigTemplate, "token"), "token: token", "tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", 1)
+
+		// when
+		_, err := clients.KubeconfigProvider("kcp-system/gardener-eu", []byte(kubeconfig))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token file")
+	})
+}
@@ -0,0 +1,84 @@
+package gardener
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/infrastructure-manager/internal/metrics"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// RateLimiter is a token bucket rate limiter shared by all clients of the Gardener API server, it reports throttled requests.
+// It optionally limits the requests in flight too, independently of the number of reconciliations running in parallel.
+type RateLimiter struct {
+	flowcontrol.RateLimiter
+
+	// inFlight holds a token for each request in flight, nil when the requests are not limited
+	inFlight chan struct{}
+}
+
+func NewRateLimiter(qps float32, burst int) *RateLimiter {
+	return &RateLimiter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+	}
+}
+
+// Wait blocks until the request is allowed by the rate limiter, or the context is cancelled.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if rl.TryAccept() {
+		return nil
+	}
+
+	start := time.Now()
+
+	err := rl.RateLimiter.Wait(ctx)
+	metrics.ObserveGardenerThrottling(start)
+
+	return err
+}
+
+// WithMaxInFlight limits the number of requests in flight, the requests above the limit wait for a free slot. Zero
+// disables the limit.
+func (rl *RateLimiter) WithMaxInFlight(limit int) *RateLimiter {
+	rl.inFlight = nil
+	if limit > 0 {
+		rl.inFlight = make(chan struct{}, limit)
+	}
+
+	return rl
+}
+
+// WrapTransport makes the requests sent through the transport wait for a free slot of the in-flight limit, a request
+// holds its slot until its response arrives.
+func (rl *RateLimiter) WrapTransport(transport http.RoundTripper) http.RoundTripper {
+	if rl.inFlight == nil {
+		return transport
+	}
+
+	return &inFlightTransport{transport: transport, inFlight: rl.inFlight}
+}
+
+type inFlightTransport struct {
+	transport http.RoundTripper
+	inFlight  chan struct{}
+}
+
+func (t *inFlightTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	select {
+	case t.inFlight <- struct{}{}:
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	}
+
+	metrics.GardenerRequestStarted(start)
+
+	defer func() {
+		metrics.GardenerRequestFinished()
+		<-t.inFlight
+	}()
+
+	return t.transport.RoundTrip(request)
+}
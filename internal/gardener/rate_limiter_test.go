@@ -0,0 +1,171 @@
+package gardener
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("should throttle requests exceeding the burst", func(t *testing.T) {
+		// given
+		rateLimiter := NewRateLimiter(100, 2)
+		defer rateLimiter.Stop()
+		throttledBefore := throttledRequests(t)
+
+		// when
+		for i := 0; i < 3; i++ {
+			require.NoError(t, rateLimiter.Wait(context.Background()))
+		}
+
+		// then
+		assert.Equal(t, throttledBefore+1, throttledRequests(t))
+	})
+
+	t.Run("should stop waiting when the context is cancelled", func(t *testing.T) {
+		// given
+		rateLimiter := NewRateLimiter(0.001, 1)
+		defer rateLimiter.Stop()
+		require.NoError(t, rateLimiter.Wait(context.Background()))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// when
+		err := rateLimiter.Wait(ctx)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+// throttledRequests reads the counter of the throttled requests from the registry of the controller.
+func throttledRequests(t *testing.T) float64 {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() == "im_gardener_requests_throttled_total" {
+			return family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+
+	t.Fatal("the counter of the throttled requests is not registered")
+
+	return 0
+}
+
+type blockingTransport struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (transport *blockingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	transport.started <- struct{}{}
+	<-transport.release
+
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestInFlightLimit(t *testing.T) {
+	t.Run("should hold requests above the limit until a request completes", func(t *testing.T) {
+		// given
+		rateLimiter := NewRateLimiter(100, 10).WithMaxInFlight(1)
+		defer rateLimiter.Stop()
+		blocking := &blockingTransport{started: make(chan struct{}, 2), release: make(chan struct{})}
+		transport := rateLimiter.WrapTransport(blocking)
+		request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://gardener.example.com", nil)
+		require.NoError(t, err)
+
+		// when
+		for i := 0; i < 2; i++ {
+			go func() { _, _ = transport.RoundTrip(request) }()
+		}
+
+		// then
+		<-blocking.started
+		select {
+		case <-blocking.started:
+			t.Fatal("the second request was sent above the in-flight limit")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		blocking.release <- struct{}{}
+		<-blocking.started
+		blocking.release <- struct{}{}
+	})
+
+	t.Run("should stop waiting for a slot when the context of the kubeconfig fetch is cancelled", func(t *testing.T) {
+		// given
+		started, release := make(chan struct{}, 1), make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			started <- struct{}{}
+			<-release
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		defer close(release)
+
+		rateLimiter := NewRateLimiter(100, 10).WithMaxInFlight(1)
+		defer rateLimiter.Stop()
+		provider := newTestKubeconfigProvider(t, server.URL, rateLimiter)
+
+		go func() { _, _ = provider.FetchShootInfo(context.Background(), "other") }()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		// when
+		fetched := make(chan error, 1)
+		go func() {
+			_, _, err := provider.Fetch(ctx, "shoot")
+			fetched <- err
+		}()
+
+		// then
+		select {
+		case err := <-fetched:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the fetch kept waiting for a slot after its context was cancelled")
+		}
+	})
+
+	t.Run("should not wrap the transport without a limit", func(t *testing.T) {
+		// given
+		rateLimiter := NewRateLimiter(100, 10)
+		defer rateLimiter.Stop()
+
+		// when
+		transport := rateLimiter.WrapTransport(http.DefaultTransport)
+
+		// then
+		assert.Equal(t, http.DefaultTransport, transport)
+	})
+}
+
+// newTestKubeconfigProvider returns a provider with the Gardener clients of the API server behind the rate limiter.
+func newTestKubeconfigProvider(t *testing.T, serverURL string, rateLimiter *RateLimiter) KubeconfigProvider {
+	t.Helper()
+
+	kubeconfig := strings.Replace(fmt.Sprintf(testKubeconfigTemplate, "token"), "https://gardener.example.com", serverURL, 1)
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfigPath, []byte(kubeconfig), 0o600))
+
+	cache, err := NewClientCache(kubeconfigPath, "garden-test", rateLimiter, time.Minute, logr.Discard())
+	require.NoError(t, err)
+
+	return NewKubeconfigProvider(cache, cache, "garden-test", 600)
+}
@@ -0,0 +1,69 @@
+package kubeconfig
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// WithCABundle appends the PEM encoded certificates to the certificate authority data of all clusters in the kubeconfig.
+func WithCABundle(kubeconfig string, caBundle []byte) (string, error) {
+	err := validateCertificates(caBundle)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	for _, cluster := range config.Clusters {
+		caData := bytes.TrimSpace(cluster.CertificateAuthorityData)
+		if len(caData) > 0 {
+			caData = append(caData, '\n')
+		}
+
+		cluster.CertificateAuthorityData = append(caData, caBundle...)
+	}
+
+	result, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize kubeconfig")
+	}
+
+	return string(result), nil
+}
+
+func validateCertificates(caBundle []byte) error {
+	found := false
+
+	for rest := caBundle; ; {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			return errors.Errorf("unexpected PEM block `%s` in CA bundle", block.Type)
+		}
+
+		_, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse certificate in CA bundle")
+		}
+
+		found = true
+	}
+
+	if !found {
+		return errors.New("CA bundle contains no PEM encoded certificate")
+	}
+
+	return nil
+}
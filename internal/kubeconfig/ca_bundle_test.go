@@ -0,0 +1,65 @@
+package kubeconfig
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestWithCABundle(t *testing.T) {
+	t.Run("should append CA bundle to certificate authority data", func(t *testing.T) {
+		// given
+		caBundle := fixCertificatePEM(t)
+
+		// when
+		kubeconfig, err := WithCABundle(adminKubeconfig, caBundle)
+
+		// then
+		require.NoError(t, err)
+
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		require.NoError(t, err)
+
+		caData := config.Clusters["shoot"].CertificateAuthorityData
+		assert.True(t, bytes.HasPrefix(caData, []byte("certificate\n")))
+		assert.True(t, bytes.HasSuffix(caData, caBundle))
+	})
+
+	t.Run("should reject CA bundle without certificates", func(t *testing.T) {
+		// when
+		_, err := WithCABundle(adminKubeconfig, []byte("not a certificate"))
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func fixCertificatePEM(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "proxy-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
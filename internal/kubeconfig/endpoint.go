@@ -0,0 +1,33 @@
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// WithServer points the cluster of the current context to the given API server URL.
+func WithServer(kubeconfig string, server string) (string, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	context, found := config.Contexts[config.CurrentContext]
+	if !found {
+		return "", errors.Errorf("current context `%s` not found in kubeconfig", config.CurrentContext)
+	}
+
+	cluster, found := config.Clusters[context.Cluster]
+	if !found {
+		return "", errors.Errorf("cluster `%s` not found in kubeconfig", context.Cluster)
+	}
+
+	cluster.Server = server
+
+	result, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize kubeconfig")
+	}
+
+	return string(result), nil
+}
@@ -0,0 +1,36 @@
+package kubeconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestWithServer(t *testing.T) {
+	t.Run("should replace server of the current context cluster", func(t *testing.T) {
+		// when
+		kubeconfig, err := WithServer(adminKubeconfig, "https://api.shoot.internal.example.com")
+
+		// then
+		require.NoError(t, err)
+
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.shoot.internal.example.com", config.Clusters["shoot"].Server)
+		assert.Equal(t, "admin-token", config.AuthInfos["admin"].Token)
+	})
+
+	t.Run("should fail without current context", func(t *testing.T) {
+		// given
+		kubeconfig := strings.Replace(adminKubeconfig, "current-context: shoot", "current-context: other", 1)
+
+		// when
+		_, err := WithServer(kubeconfig, "https://api.shoot.internal.example.com")
+
+		// then
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,75 @@
+package kubeconfig
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	gardenloginUserName       = "gardenlogin"
+	gardenloginInstallHint    = "Install the gardenlogin plugin: https://github.com/gardener/gardenlogin"
+	gardenloginCommand        = "kubectl"
+	gardenloginPluginCommand  = "gardenlogin"
+	gardenloginPluginArgument = "get-client-certificate"
+	execClusterExtensionName  = "client.authentication.k8s.io/exec"
+)
+
+// Gardenlogin identifies the shoot in the garden cluster, gardenlogin requests the client certificate for.
+type Gardenlogin struct {
+	GardenClusterIdentity string
+	ShootNamespace        string
+	ShootName             string
+}
+
+type gardenloginExtension struct {
+	ShootRef              shootRef `json:"shootRef"`
+	GardenClusterIdentity string   `json:"gardenClusterIdentity"`
+}
+
+type shootRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ToGardenlogin replaces the credentials of all users in the kubeconfig with the gardenlogin exec plugin, which requests
+// a client certificate from the garden cluster with the credentials of the operator.
+func ToGardenlogin(kubeconfig string, gardenlogin Gardenlogin) (string, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	extension, err := json.Marshal(gardenloginExtension{
+		ShootRef: shootRef{
+			Namespace: gardenlogin.ShootNamespace,
+			Name:      gardenlogin.ShootName,
+		},
+		GardenClusterIdentity: gardenlogin.GardenClusterIdentity,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize gardenlogin extension")
+	}
+
+	for _, cluster := range config.Clusters {
+		if cluster.Extensions == nil {
+			cluster.Extensions = map[string]runtime.Object{}
+		}
+
+		cluster.Extensions[execClusterExtensionName] = &runtime.Unknown{Raw: extension, ContentType: runtime.ContentTypeJSON}
+	}
+
+	return replaceUser(config, gardenloginUserName, &clientcmdapi.AuthInfo{
+		Exec: &clientcmdapi.ExecConfig{
+			APIVersion:         execCredentialAPIVersion,
+			Command:            gardenloginCommand,
+			Args:               []string{gardenloginPluginCommand, gardenloginPluginArgument},
+			InstallHint:        gardenloginInstallHint,
+			ProvideClusterInfo: true,
+			InteractiveMode:    clientcmdapi.IfAvailableExecInteractiveMode,
+		},
+	})
+}
@@ -0,0 +1,41 @@
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestToGardenlogin(t *testing.T) {
+	t.Run("should replace admin credentials with gardenlogin", func(t *testing.T) {
+		// given
+		gardenlogin := Gardenlogin{
+			GardenClusterIdentity: "landscape-dev",
+			ShootNamespace:        "garden-project",
+			ShootName:             "shoot",
+		}
+
+		// when
+		kubeconfig, err := ToGardenlogin(adminKubeconfig, gardenlogin)
+
+		// then
+		require.NoError(t, err)
+		assert.NotContains(t, kubeconfig, "admin-token")
+
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		require.NoError(t, err)
+		assert.Equal(t, gardenloginUserName, config.Contexts["shoot"].AuthInfo)
+
+		exec := config.AuthInfos[gardenloginUserName].Exec
+		require.NotNil(t, exec)
+		assert.Equal(t, []string{"gardenlogin", "get-client-certificate"}, exec.Args)
+		assert.True(t, exec.ProvideClusterInfo)
+
+		extension, ok := config.Clusters["shoot"].Extensions[execClusterExtensionName].(*runtime.Unknown)
+		require.True(t, ok)
+		assert.JSONEq(t, `{"shootRef":{"namespace":"garden-project","name":"shoot"},"gardenClusterIdentity":"landscape-dev"}`, string(extension.Raw))
+	})
+}
@@ -0,0 +1,65 @@
+package kubeconfig
+
+import (
+	"fmt"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	oidcUserName              = "oidc"
+	execCredentialAPIVersion  = "client.authentication.k8s.io/v1beta1"
+	oidcLoginInstallHint      = "Install the kubelogin plugin: https://github.com/int128/kubelogin"
+	oidcLoginCommand          = "kubectl"
+	oidcLoginGetTokenCommand  = "oidc-login"
+	oidcLoginGetTokenArgument = "get-token"
+)
+
+// ToOIDC replaces the credentials of all users in the kubeconfig with the kubelogin exec plugin authenticating with the OIDC provider.
+// Only the clusters, and the server certificate authorities of the source kubeconfig are kept.
+func ToOIDC(kubeconfig string, oidc imv1.OIDCConfig) (string, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	args := []string{
+		oidcLoginGetTokenCommand,
+		oidcLoginGetTokenArgument,
+		fmt.Sprintf("--oidc-issuer-url=%s", oidc.IssuerURL),
+		fmt.Sprintf("--oidc-client-id=%s", oidc.ClientID),
+	}
+
+	for _, scope := range oidc.ExtraScopes {
+		args = append(args, fmt.Sprintf("--oidc-extra-scope=%s", scope))
+	}
+
+	return replaceUser(config, oidcUserName, &clientcmdapi.AuthInfo{
+		Exec: &clientcmdapi.ExecConfig{
+			APIVersion:      execCredentialAPIVersion,
+			Command:         oidcLoginCommand,
+			Args:            args,
+			InstallHint:     oidcLoginInstallHint,
+			InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+		},
+	})
+}
+
+// replaceUser makes the given user the only one in the kubeconfig, and switches all contexts to it.
+func replaceUser(config *clientcmdapi.Config, userName string, authInfo *clientcmdapi.AuthInfo) (string, error) {
+	config.AuthInfos = map[string]*clientcmdapi.AuthInfo{userName: authInfo}
+
+	for _, context := range config.Contexts {
+		context.AuthInfo = userName
+	}
+
+	kubeconfig, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize kubeconfig")
+	}
+
+	return string(kubeconfig), nil
+}
@@ -0,0 +1,73 @@
+package kubeconfig
+
+import (
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const adminKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: shoot
+  cluster:
+    server: https://api.shoot.example.com
+    certificate-authority-data: Y2VydGlmaWNhdGU=
+contexts:
+- name: shoot
+  context:
+    cluster: shoot
+    user: admin
+current-context: shoot
+users:
+- name: admin
+  user:
+    token: admin-token
+`
+
+func TestToOIDC(t *testing.T) {
+	t.Run("should replace admin credentials with OIDC login", func(t *testing.T) {
+		// given
+		oidc := imv1.OIDCConfig{
+			IssuerURL:   "https://issuer.example.com",
+			ClientID:    "client",
+			ExtraScopes: []string{"email"},
+		}
+
+		// when
+		kubeconfig, err := ToOIDC(adminKubeconfig, oidc)
+
+		// then
+		require.NoError(t, err)
+		assert.NotContains(t, kubeconfig, "admin-token")
+
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.shoot.example.com", config.Clusters["shoot"].Server)
+		assert.Equal(t, []byte("certificate"), config.Clusters["shoot"].CertificateAuthorityData)
+		assert.Equal(t, oidcUserName, config.Contexts["shoot"].AuthInfo)
+
+		require.Len(t, config.AuthInfos, 1)
+		exec := config.AuthInfos[oidcUserName].Exec
+		require.NotNil(t, exec)
+		assert.Equal(t, oidcLoginCommand, exec.Command)
+		assert.Equal(t, []string{
+			"oidc-login",
+			"get-token",
+			"--oidc-issuer-url=https://issuer.example.com",
+			"--oidc-client-id=client",
+			"--oidc-extra-scope=email",
+		}, exec.Args)
+	})
+
+	t.Run("should fail for invalid kubeconfig", func(t *testing.T) {
+		// when
+		_, err := ToOIDC("not a kubeconfig", imv1.OIDCConfig{IssuerURL: "https://issuer.example.com", ClientID: "client"})
+
+		// then
+		require.Error(t, err)
+	})
+}
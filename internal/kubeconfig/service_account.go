@@ -0,0 +1,112 @@
+package kubeconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ServiceAccount identifies the service account created in the shoot, and the cluster role it is bound to.
+type ServiceAccount struct {
+	Name        string
+	Namespace   string
+	ClusterRole string
+}
+
+// ServiceAccountTokenGenerator issues kubeconfigs authenticating with the token of a service account in the shoot.
+// The admin kubeconfig is only used to set up the service account, and to request the token.
+type ServiceAccountTokenGenerator struct {
+	newClient func(config *clientcmdapi.Config) (kubernetes.Interface, error)
+}
+
+func NewServiceAccountTokenGenerator() *ServiceAccountTokenGenerator {
+	return &ServiceAccountTokenGenerator{newClient: newShootClient}
+}
+
+func newShootClient(config *clientcmdapi.Config) (kubernetes.Interface, error) {
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// Generate ensures the service account and its cluster role binding exist in the shoot, and returns a kubeconfig with a token
+// of the service account valid for the given duration, together with the token expiration time.
+func (generator *ServiceAccountTokenGenerator) Generate(ctx context.Context, adminKubeconfig string, serviceAccount ServiceAccount, expiration time.Duration) (string, time.Time, error) {
+	config, err := clientcmd.Load([]byte(adminKubeconfig))
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	shootClient, err := generator.newClient(config)
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "failed to create shoot client")
+	}
+
+	err = ensureServiceAccount(ctx, shootClient, serviceAccount)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expirationSeconds := int64(expiration.Seconds())
+
+	tokenRequest, err := shootClient.CoreV1().ServiceAccounts(serviceAccount.Namespace).CreateToken(ctx, serviceAccount.Name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", time.Time{}, errors.Wrapf(err, "failed to request token for service account %s", serviceAccount.Name)
+	}
+
+	kubeconfig, err := replaceUser(config, serviceAccount.Name, &clientcmdapi.AuthInfo{Token: tokenRequest.Status.Token})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return kubeconfig, tokenRequest.Status.ExpirationTimestamp.Time, nil
+}
+
+func ensureServiceAccount(ctx context.Context, shootClient kubernetes.Interface, serviceAccount ServiceAccount) error {
+	_, err := shootClient.CoreV1().ServiceAccounts(serviceAccount.Namespace).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceAccount.Name,
+			Namespace: serviceAccount.Namespace,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create service account %s", serviceAccount.Name)
+	}
+
+	_, err = shootClient.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: serviceAccount.Name,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     serviceAccount.ClusterRole,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccount.Name,
+			Namespace: serviceAccount.Namespace,
+		}},
+	}, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to bind service account %s to cluster role %s", serviceAccount.Name, serviceAccount.ClusterRole)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package kubeconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestServiceAccountTokenGenerator(t *testing.T) {
+	serviceAccount := ServiceAccount{Name: "viewer", Namespace: "kube-system", ClusterRole: "view"}
+
+	t.Run("should create service account, and issue kubeconfig with its token", func(t *testing.T) {
+		// given
+		expirationTime := time.Now().Add(time.Hour).Truncate(time.Second)
+		shootClient := fixShootClientWithToken("viewer-token", expirationTime)
+		generator := &ServiceAccountTokenGenerator{newClient: func(*clientcmdapi.Config) (kubernetes.Interface, error) {
+			return shootClient, nil
+		}}
+
+		// when
+		kubeconfig, tokenExpirationTime, err := generator.Generate(context.Background(), adminKubeconfig, serviceAccount, time.Hour)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, expirationTime, tokenExpirationTime)
+
+		_, err = shootClient.CoreV1().ServiceAccounts("kube-system").Get(context.Background(), "viewer", metav1.GetOptions{})
+		require.NoError(t, err)
+
+		binding, err := shootClient.RbacV1().ClusterRoleBindings().Get(context.Background(), "viewer", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "view", binding.RoleRef.Name)
+
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		require.NoError(t, err)
+		require.Len(t, config.AuthInfos, 1)
+		assert.Equal(t, "viewer-token", config.AuthInfos["viewer"].Token)
+		assert.Equal(t, "viewer", config.Contexts["shoot"].AuthInfo)
+		assert.Equal(t, "https://api.shoot.example.com", config.Clusters["shoot"].Server)
+	})
+
+	t.Run("should reuse existing service account", func(t *testing.T) {
+		// given
+		shootClient := fixShootClientWithToken("viewer-token", time.Now().Add(time.Hour))
+		generator := &ServiceAccountTokenGenerator{newClient: func(*clientcmdapi.Config) (kubernetes.Interface, error) {
+			return shootClient, nil
+		}}
+		_, _, err := generator.Generate(context.Background(), adminKubeconfig, serviceAccount, time.Hour)
+		require.NoError(t, err)
+
+		// when
+		_, _, err = generator.Generate(context.Background(), adminKubeconfig, serviceAccount, time.Hour)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func fixShootClientWithToken(token string, expirationTime time.Time) *fake.Clientset {
+	shootClient := fake.NewSimpleClientset()
+	shootClient.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+
+		return true, &authenticationv1.TokenRequest{
+			Status: authenticationv1.TokenRequestStatus{
+				Token:               token,
+				ExpirationTimestamp: metav1.Time{Time: expirationTime},
+			},
+		}, nil
+	})
+
+	return shootClient
+}
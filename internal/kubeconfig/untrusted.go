@@ -0,0 +1,52 @@
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// RESTConfigFromUntrusted builds the REST config of a kubeconfig provided by a tenant, e.g. in a secret referenced by a
+// GardenerCluster. Only the credentials inline in the kubeconfig are accepted, the exec plugins, and auth providers
+// would run commands in the controller pod, and the file references would send its files, e.g. its service account
+// token, to the server of the kubeconfig.
+func RESTConfigFromUntrusted(rawKubeconfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(rawKubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	err = ValidateUntrusted(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+// ValidateUntrusted rejects the kubeconfig when any of its clusters, or users runs a command, or reads a file.
+func ValidateUntrusted(config *clientcmdapi.Config) error {
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return errors.Errorf("cluster %s of the kubeconfig references the file %s, only inline certificate authority data is allowed", name, cluster.CertificateAuthority)
+		}
+	}
+
+	for name, user := range config.AuthInfos {
+		switch {
+		case user.Exec != nil:
+			return errors.Errorf("user %s of the kubeconfig uses an exec plugin, only inline credentials are allowed", name)
+		case user.AuthProvider != nil:
+			return errors.Errorf("user %s of the kubeconfig uses an auth provider, only inline credentials are allowed", name)
+		case user.TokenFile != "":
+			return errors.Errorf("user %s of the kubeconfig references the token file %s, only inline tokens are allowed", name, user.TokenFile)
+		case user.ClientCertificate != "":
+			return errors.Errorf("user %s of the kubeconfig references the file %s, only inline client certificate data is allowed", name, user.ClientCertificate)
+		case user.ClientKey != "":
+			return errors.Errorf("user %s of the kubeconfig references the file %s, only inline client key data is allowed", name, user.ClientKey)
+		}
+	}
+
+	return nil
+}
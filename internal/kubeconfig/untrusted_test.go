@@ -0,0 +1,97 @@
+package kubeconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTConfigFromUntrusted(t *testing.T) {
+	t.Run("should build the REST config of inline credentials", func(t *testing.T) {
+		// when
+		restConfig, err := RESTConfigFromUntrusted([]byte(adminKubeconfig))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.shoot.example.com", restConfig.Host)
+		assert.Equal(t, "admin-token", restConfig.BearerToken)
+	})
+
+	t.Run("should reject kubeconfig which cannot be parsed", func(t *testing.T) {
+		// when
+		_, err := RESTConfigFromUntrusted([]byte("not a kubeconfig"))
+
+		// then
+		require.Error(t, err)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		user     string
+		expected string
+	}{
+		{
+			name:     "should reject an exec plugin",
+			user:     "exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh\n      args: [\"-c\", \"id\"]",
+			expected: "exec plugin",
+		},
+		{
+			name:     "should reject an auth provider",
+			user:     "auth-provider:\n      name: oidc\n      config:\n        client-id: client",
+			expected: "auth provider",
+		},
+		{
+			name:     "should reject a token file",
+			user:     "tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token",
+			expected: "token file",
+		},
+		{
+			name:     "should reject a client certificate file",
+			user:     "client-certificate: /var/run/secrets/kubernetes.io/serviceaccount/token",
+			expected: "client certificate",
+		},
+		{
+			name:     "should reject a client key file",
+			user:     "client-key: /var/run/secrets/kubernetes.io/serviceaccount/token",
+			expected: "client key",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			kubeconfig := strings.Replace(adminKubeconfig, "token: admin-token", tc.user, 1)
+
+			// when
+			_, err := RESTConfigFromUntrusted([]byte(kubeconfig))
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+
+	t.Run("should reject a certificate authority file", func(t *testing.T) {
+		// given
+		kubeconfig := strings.Replace(adminKubeconfig, "certificate-authority-data: Y2VydGlmaWNhdGU=", "certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt", 1)
+
+		// when
+		_, err := RESTConfigFromUntrusted([]byte(kubeconfig))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate authority")
+	})
+
+	t.Run("should reject file references of users outside the current context", func(t *testing.T) {
+		// given
+		kubeconfig := adminKubeconfig + "- name: other\n  user:\n    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token\n"
+
+		// when
+		_, err := RESTConfigFromUntrusted([]byte(kubeconfig))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token file")
+	})
+}
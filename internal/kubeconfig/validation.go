@@ -0,0 +1,58 @@
+package kubeconfig
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Validate checks that the kubeconfig can be parsed, and that its current context references a cluster, and a user.
+// When the dial timeout is positive, it also checks that the API server of the current context accepts connections.
+func Validate(ctx context.Context, kubeconfig string, dialTimeout time.Duration) error {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse kubeconfig")
+	}
+
+	if config.CurrentContext == "" {
+		return errors.New("kubeconfig has no current context")
+	}
+
+	err = clientcmd.ConfirmUsable(*config, config.CurrentContext)
+	if err != nil {
+		return errors.Wrap(err, "kubeconfig is not usable")
+	}
+
+	if dialTimeout <= 0 {
+		return nil
+	}
+
+	cluster := config.Clusters[config.Contexts[config.CurrentContext].Cluster]
+
+	return dialAPIServer(ctx, cluster.Server, dialTimeout)
+}
+
+func dialAPIServer(ctx context.Context, server string, timeout time.Duration) error {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return errors.Wrapf(err, "invalid API server URL %s", server)
+	}
+
+	address := serverURL.Host
+	if serverURL.Port() == "" {
+		address = net.JoinHostPort(serverURL.Hostname(), "443")
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to API server %s", server)
+	}
+
+	return conn.Close()
+}
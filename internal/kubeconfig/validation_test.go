@@ -0,0 +1,82 @@
+package kubeconfig
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("should accept valid kubeconfig", func(t *testing.T) {
+		// when
+		err := Validate(context.Background(), adminKubeconfig, 0)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject kubeconfig which cannot be parsed", func(t *testing.T) {
+		// when
+		err := Validate(context.Background(), "not a kubeconfig", 0)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should reject kubeconfig without current context", func(t *testing.T) {
+		// given
+		kubeconfig := strings.Replace(adminKubeconfig, "current-context: shoot", "", 1)
+
+		// when
+		err := Validate(context.Background(), kubeconfig, 0)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should reject kubeconfig referencing missing user", func(t *testing.T) {
+		// given
+		kubeconfig := strings.Replace(adminKubeconfig, "user: admin", "user: missing", 1)
+
+		// when
+		err := Validate(context.Background(), kubeconfig, 0)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should connect to API server", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		kubeconfig := strings.Replace(adminKubeconfig, "api.shoot.example.com", listener.Addr().String(), 1)
+
+		// when
+		err = Validate(context.Background(), kubeconfig, time.Second)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject kubeconfig with unreachable API server", func(t *testing.T) {
+		// given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		kubeconfig := strings.Replace(adminKubeconfig, "api.shoot.example.com", address, 1)
+
+		// when
+		err = Validate(context.Background(), kubeconfig, time.Second)
+
+		// then
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,217 @@
+// Package loglevel changes the verbosity of the manager logs at runtime, globally, and per named logger.
+package loglevel
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// lowestLevel enables every logr verbosity on the wrapped core, the filtering is done by the levels.
+const lowestLevel = zapcore.Level(-127)
+
+// Levels holds the default level of the logs, and the levels overriding it for named loggers and their descendants,
+// e.g. "gardener-cluster-controller".
+type Levels struct {
+	mu        sync.RWMutex
+	level     zapcore.Level
+	overrides map[string]zapcore.Level
+}
+
+func NewLevels(level zapcore.Level) *Levels {
+	return &Levels{
+		level:     level,
+		overrides: map[string]zapcore.Level{},
+	}
+}
+
+// InitialLevel returns the lowest level enabled by the level configured with the zap flags.
+func InitialLevel(enabler zapcore.LevelEnabler, fallback zapcore.Level) zapcore.Level {
+	if enabler == nil {
+		return fallback
+	}
+
+	for level := lowestLevel; level <= zapcore.FatalLevel; level++ {
+		if enabler.Enabled(level) {
+			return level
+		}
+	}
+
+	return zapcore.FatalLevel
+}
+
+// ZapOptions make the logger filter the entries with the levels. The core of the logger must enable all levels.
+func (levels *Levels) ZapOptions() (zapcore.LevelEnabler, zap.Option) {
+	return lowestLevel, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &filteringCore{Core: core, levels: levels}
+	})
+}
+
+// Set replaces the default level, and all overrides.
+func (levels *Levels) Set(level zapcore.Level, overrides map[string]zapcore.Level) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	levels.level = level
+	levels.overrides = overrides
+}
+
+// enabled reports whether the entry of the logger with the given name is written, the most specific override wins.
+func (levels *Levels) enabled(loggerName string, level zapcore.Level) bool {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	minLevel := levels.level
+	matched := -1
+
+	for name, override := range levels.overrides {
+		if len(name) > matched && (loggerName == name || strings.HasPrefix(loggerName, name+".")) {
+			minLevel = override
+			matched = len(name)
+		}
+	}
+
+	return level >= minLevel
+}
+
+// anyEnabled reports whether the level is enabled for any logger.
+func (levels *Levels) anyEnabled(level zapcore.Level) bool {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	if level >= levels.level {
+		return true
+	}
+
+	for _, override := range levels.overrides {
+		if level >= override {
+			return true
+		}
+	}
+
+	return false
+}
+
+type filteringCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (core *filteringCore) Enabled(level zapcore.Level) bool {
+	return core.levels.anyEnabled(level)
+}
+
+func (core *filteringCore) With(fields []zapcore.Field) zapcore.Core {
+	return &filteringCore{Core: core.Core.With(fields), levels: core.levels}
+}
+
+func (core *filteringCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !core.levels.enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+
+	return core.Core.Check(entry, checked)
+}
+
+// config is the representation of the levels served by the handler. The levels are zap level names, or logr
+// verbosities, e.g. "debug", or "2".
+type config struct {
+	Level   string            `json:"level"`
+	Loggers map[string]string `json:"loggers,omitempty"`
+}
+
+// ServeHTTP returns the current levels on GET, and replaces them on PUT, e.g.
+// {"level": "info", "loggers": {"gardener-cluster-controller": "debug"}}.
+func (levels *Levels) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		err := levels.update(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		writer.Header().Set("Allow", "GET, PUT")
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(writer).Encode(levels.config())
+}
+
+func (levels *Levels) update(request *http.Request) error {
+	var desired config
+
+	err := json.NewDecoder(request.Body).Decode(&desired)
+	if err != nil {
+		return errors.Wrap(err, "invalid log level configuration")
+	}
+
+	level, err := parseLevel(desired.Level)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]zapcore.Level, len(desired.Loggers))
+
+	for name, loggerLevel := range desired.Loggers {
+		overrides[name], err = parseLevel(loggerLevel)
+		if err != nil {
+			return errors.Wrapf(err, "logger %s", name)
+		}
+	}
+
+	levels.Set(level, overrides)
+
+	return nil
+}
+
+func (levels *Levels) config() config {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+
+	current := config{Level: formatLevel(levels.level), Loggers: map[string]string{}}
+	for name, level := range levels.overrides {
+		current.Loggers[name] = formatLevel(level)
+	}
+
+	return current
+}
+
+func parseLevel(text string) (zapcore.Level, error) {
+	verbosity, err := strconv.Atoi(text)
+	if err == nil {
+		if verbosity < 0 {
+			return 0, errors.Errorf("verbosity %d must not be negative", verbosity)
+		}
+
+		return zapcore.Level(-verbosity), nil
+	}
+
+	var level zapcore.Level
+
+	err = level.UnmarshalText([]byte(text))
+	if err != nil {
+		return 0, errors.Errorf("invalid log level %q", text)
+	}
+
+	return level, nil
+}
+
+// formatLevel returns the zap level name, or the logr verbosity for levels below debug.
+func formatLevel(level zapcore.Level) string {
+	if level < zapcore.DebugLevel {
+		return strconv.Itoa(-int(level))
+	}
+
+	return level.String()
+}
@@ -0,0 +1,99 @@
+package loglevel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevels(t *testing.T) {
+	newLogger := func(levels *Levels) (*zap.Logger, *observer.ObservedLogs) {
+		enabler, filter := levels.ZapOptions()
+		core, logs := observer.New(enabler)
+
+		return zap.New(core, filter), logs
+	}
+
+	t.Run("should filter entries with the default level", func(t *testing.T) {
+		// given
+		levels := NewLevels(zapcore.InfoLevel)
+		logger, logs := newLogger(levels)
+
+		// when
+		logger.Debug("hidden")
+		logger.Info("visible")
+
+		// then
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, "visible", logs.All()[0].Message)
+	})
+
+	t.Run("should apply the most specific override to named loggers", func(t *testing.T) {
+		// given
+		levels := NewLevels(zapcore.InfoLevel)
+		levels.Set(zapcore.InfoLevel, map[string]zapcore.Level{
+			"controller":         zapcore.DebugLevel,
+			"controller.secrets": zapcore.ErrorLevel,
+		})
+		logger, logs := newLogger(levels)
+
+		// when
+		logger.Named("controller").Debug("controller debug")
+		logger.Named("controller").Named("secrets").Info("secrets info")
+		logger.Named("controllers").Debug("other debug")
+		logger.Named("gardener").Debug("gardener debug")
+
+		// then
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, "controller debug", logs.All()[0].Message)
+	})
+
+	t.Run("should change the levels with PUT, and return them with GET", func(t *testing.T) {
+		// given
+		levels := NewLevels(zapcore.InfoLevel)
+		logger, logs := newLogger(levels)
+
+		// when
+		response := httptest.NewRecorder()
+		levels.ServeHTTP(response, httptest.NewRequest(http.MethodPut, "/debug/loglevel", strings.NewReader(`{"level": "error", "loggers": {"controller": "2"}}`)))
+
+		// then
+		require.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"level": "error", "loggers": {"controller": "2"}}`, response.Body.String())
+
+		logger.Info("hidden")
+		logger.Named("controller").Check(zapcore.Level(-2), "verbose").Write()
+		require.Equal(t, 1, logs.Len())
+		assert.Equal(t, "verbose", logs.All()[0].Message)
+
+		response = httptest.NewRecorder()
+		levels.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/debug/loglevel", nil))
+		assert.JSONEq(t, `{"level": "error", "loggers": {"controller": "2"}}`, response.Body.String())
+	})
+
+	t.Run("should reject invalid levels", func(t *testing.T) {
+		// given
+		levels := NewLevels(zapcore.InfoLevel)
+
+		// when
+		response := httptest.NewRecorder()
+		levels.ServeHTTP(response, httptest.NewRequest(http.MethodPut, "/debug/loglevel", strings.NewReader(`{"level": "loud"}`)))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, zapcore.InfoLevel, levels.level)
+	})
+}
+
+func TestInitialLevel(t *testing.T) {
+	assert.Equal(t, zapcore.Level(-3), InitialLevel(zap.NewAtomicLevelAt(zapcore.Level(-3)), zapcore.InfoLevel))
+	assert.Equal(t, zapcore.ErrorLevel, InitialLevel(zapcore.ErrorLevel, zapcore.InfoLevel))
+	assert.Equal(t, zapcore.DebugLevel, InitialLevel(nil, zapcore.DebugLevel))
+}
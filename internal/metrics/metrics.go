@@ -0,0 +1,256 @@
+// Package metrics defines the Prometheus metrics of the kubeconfig management, registered with the controller-runtime registry.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+
+	GardenerOperationGetShoot         = "get_shoot"
+	GardenerOperationCreateKubeconfig = "create_admin_kubeconfig"
+	GardenerOperationCreateShoot      = "create_shoot"
+	GardenerOperationUpdateShoot      = "update_shoot"
+	GardenerOperationPatchShoot       = "patch_shoot"
+	GardenerOperationGetCloudProfile  = "get_cloud_profile"
+	GardenerOperationGetSeed          = "get_seed"
+	GardenerOperationDeleteShoot      = "delete_shoot"
+
+	// listing the managed secrets from the cache must not stall the scrape
+	managedSecretsListTimeout = 5 * time.Second
+)
+
+var (
+	rotations = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "im_kubeconfig_rotations_total",
+		Help: "Number of kubeconfigs written to the secrets, by reason, e.g. created, rotated, or restored after drift.",
+	}, []string{"reason"})
+	rotationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "im_kubeconfig_rotation_failures_total",
+		Help: "Number of failed kubeconfig rotations, by the reason of the failure.",
+	}, []string{"reason"})
+	gardenerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals
+		Name:    "im_gardener_request_duration_seconds",
+		Help:    "Latency of requests to the Gardener API server, by operation, and outcome.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12), //nolint:gomnd
+	}, []string{"operation", "outcome"})
+	clusterState = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "im_gardener_cluster_state",
+		Help: "State of the GardenerCluster, the series of the current state is 1, the series of the other states are 0.",
+	}, []string{"name", "namespace", "state"})
+	clusterInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "im_gardener_cluster_info",
+		Help: "Metadata of the GardenerCluster, always 1, joined with the other series to group them by the purpose of the shoot.",
+	}, []string{"name", "namespace", "purpose"})
+	kubeconfigExpiration = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "im_kubeconfig_expiration_timestamp_seconds",
+		Help: "Unix time when the kubeconfig stored in the managed secret expires.",
+	}, []string{"name", "namespace", "cluster", "cluster_namespace"})
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals
+		Name:    "im_gardener_cluster_reconcile_duration_seconds",
+		Help:    "Duration of the GardenerCluster reconciliations, by outcome.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12), //nolint:gomnd
+	}, []string{"outcome"})
+	gardenerCircuitOpen = prometheus.NewGauge(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "im_gardener_circuit_open",
+		Help: "Whether the requests for kubeconfigs are paused after repeated failures of Gardener, 1 while paused, 0 otherwise.",
+	})
+	gardenerThrottledRequests = prometheus.NewCounter(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "im_gardener_requests_throttled_total",
+		Help: "Number of requests to the Gardener API server delayed by the client-side rate limiter.",
+	})
+	gardenerThrottlingDelay = prometheus.NewHistogram(prometheus.HistogramOpts{ //nolint:gochecknoglobals
+		Name:    "im_gardener_requests_throttling_delay_seconds",
+		Help:    "Delay of requests to the Gardener API server caused by the client-side rate limiter.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12), //nolint:gomnd
+	})
+	gardenerInFlightRequests = prometheus.NewGauge(prometheus.GaugeOpts{ //nolint:gochecknoglobals
+		Name: "im_gardener_requests_in_flight",
+		Help: "Number of requests to the Gardener API server in flight.",
+	})
+	gardenerInFlightWait = prometheus.NewHistogram(prometheus.HistogramOpts{ //nolint:gochecknoglobals
+		Name:    "im_gardener_requests_queue_wait_seconds",
+		Help:    "Time the requests to the Gardener API server waited for a free slot of the in-flight limit.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12), //nolint:gomnd
+	})
+	orphanedSecretsFound = prometheus.NewCounter(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "im_orphaned_kubeconfig_secrets_found_total",
+		Help: "Number of kubeconfig secrets found without an owning GardenerCluster.",
+	})
+	orphanedSecretsDeleted = prometheus.NewCounter(prometheus.CounterOpts{ //nolint:gochecknoglobals
+		Name: "im_orphaned_kubeconfig_secrets_deleted_total",
+		Help: "Number of kubeconfig secrets deleted because their GardenerCluster no longer exists.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(rotations, rotationFailures, gardenerRequestDuration, clusterState, clusterInfo, kubeconfigExpiration, reconcileDuration, gardenerCircuitOpen,
+		gardenerThrottledRequests, gardenerThrottlingDelay, gardenerInFlightRequests, gardenerInFlightWait, orphanedSecretsFound, orphanedSecretsDeleted)
+}
+
+//nolint:gochecknoglobals
+var clusterStates = []imv1.State{imv1.ReadyState, imv1.ProcessingState, imv1.ErrorState, imv1.DegradedState, imv1.DeletingState}
+
+// SetClusterState records the current state of the GardenerCluster.
+func SetClusterState(name, namespace string, state imv1.State) {
+	for _, knownState := range clusterStates {
+		value := 0.0
+		if knownState == state {
+			value = 1
+		}
+
+		clusterState.WithLabelValues(name, namespace, string(knownState)).Set(value)
+	}
+}
+
+// SetClusterPurpose records the purpose of the shoot of the GardenerCluster, empty when it is not known yet.
+func SetClusterPurpose(name, namespace string, purpose imv1.Purpose) {
+	clusterInfo.DeletePartialMatch(prometheus.Labels{"name": name, "namespace": namespace})
+	clusterInfo.WithLabelValues(name, namespace, string(purpose)).Set(1)
+}
+
+// SetKubeconfigExpiration records the expiration of the kubeconfig in each secret of the GardenerCluster. A secret keeps
+// the expiration of the last written kubeconfig when a rotation fails, so a stalled rotation shows as an approaching expiration.
+func SetKubeconfigExpiration(cluster *imv1.GardenerCluster) {
+	kubeconfigExpiration.DeletePartialMatch(prometheus.Labels{"cluster": cluster.Name, "cluster_namespace": cluster.Namespace})
+
+	if cluster.Status.KubeconfigExpirationTime == nil {
+		return
+	}
+
+	for _, secret := range cluster.Status.Secrets {
+		// the secrets at the previous locations of moved targets are about to be deleted
+		if secret.Reason == imv1.ConditionReasonSecretRelocationPending {
+			continue
+		}
+
+		name := secret.Name
+		if secret.CurrentSecret != "" {
+			name = secret.CurrentSecret
+		}
+
+		kubeconfigExpiration.WithLabelValues(name, secret.Namespace, cluster.Name, cluster.Namespace).
+			Set(float64(cluster.Status.KubeconfigExpirationTime.Unix()))
+	}
+}
+
+// DeleteCluster removes the series of a GardenerCluster which no longer exists.
+func DeleteCluster(name, namespace string) {
+	clusterState.DeletePartialMatch(prometheus.Labels{"name": name, "namespace": namespace})
+	clusterInfo.DeletePartialMatch(prometheus.Labels{"name": name, "namespace": namespace})
+	kubeconfigExpiration.DeletePartialMatch(prometheus.Labels{"cluster": name, "cluster_namespace": namespace})
+}
+
+func RecordRotation(reason string) {
+	rotations.WithLabelValues(reason).Inc()
+}
+
+func RecordRotationFailure(reason string) {
+	rotationFailures.WithLabelValues(reason).Inc()
+}
+
+// ObserveGardenerRequest records the latency of a request to Gardener started at the given time.
+func ObserveGardenerRequest(operation string, start time.Time, err error) {
+	gardenerRequestDuration.WithLabelValues(operation, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// SetGardenerCircuitOpen records whether the requests for kubeconfigs are paused.
+func SetGardenerCircuitOpen(open bool) {
+	value := 0.0
+	if open {
+		value = 1
+	}
+
+	gardenerCircuitOpen.Set(value)
+}
+
+// ObserveGardenerThrottling records a request to Gardener delayed by the rate limiter since the given time.
+func ObserveGardenerThrottling(start time.Time) {
+	gardenerThrottledRequests.Inc()
+	gardenerThrottlingDelay.Observe(time.Since(start).Seconds())
+}
+
+// GardenerRequestStarted records a request to Gardener which waited for a slot of the in-flight limit since the given time.
+func GardenerRequestStarted(waitStart time.Time) {
+	gardenerInFlightWait.Observe(time.Since(waitStart).Seconds())
+	gardenerInFlightRequests.Inc()
+}
+
+// GardenerRequestFinished records the response to a request started with GardenerRequestStarted.
+func GardenerRequestFinished() {
+	gardenerInFlightRequests.Dec()
+}
+
+func RecordOrphanedSecretFound() {
+	orphanedSecretsFound.Inc()
+}
+
+func RecordOrphanedSecretDeleted() {
+	orphanedSecretsDeleted.Inc()
+}
+
+// ObserveReconcile records the duration of a reconciliation started at the given time.
+func ObserveReconcile(start time.Time, err error) {
+	reconcileDuration.WithLabelValues(outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+
+	return OutcomeSuccess
+}
+
+// managedSecretsCollector counts the managed secrets at scrape time, so that the gauge is correct also for secrets
+// written by previous leaders, or removed by other actors.
+type managedSecretsCollector struct {
+	reader      client.Reader
+	selector    client.MatchingLabels
+	description *prometheus.Desc
+}
+
+// RegisterManagedSecrets exports the number of secrets matching the selector, listed with the reader.
+// Registering again keeps the first registration.
+func RegisterManagedSecrets(reader client.Reader, selector client.MatchingLabels) error {
+	err := metrics.Registry.Register(&managedSecretsCollector{
+		reader:      reader,
+		selector:    selector,
+		description: prometheus.NewDesc("im_managed_kubeconfig_secrets", "Number of kubeconfig secrets managed by the controller.", nil, nil),
+	})
+
+	if errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		return nil
+	}
+
+	return err
+}
+
+func (collector *managedSecretsCollector) Describe(descriptions chan<- *prometheus.Desc) {
+	descriptions <- collector.description
+}
+
+func (collector *managedSecretsCollector) Collect(metrics chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), managedSecretsListTimeout)
+	defer cancel()
+
+	var secrets corev1.SecretList
+
+	err := collector.reader.List(ctx, &secrets, collector.selector)
+	if err != nil {
+		metrics <- prometheus.NewInvalidMetric(collector.description, err)
+		return
+	}
+
+	metrics <- prometheus.MustNewConstMetric(collector.description, prometheus.GaugeValue, float64(len(secrets.Items)))
+}
@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRotationMetrics(t *testing.T) {
+	t.Run("should count rotations, and failures by reason", func(t *testing.T) {
+		// given
+		rotatedBefore := testutil.ToFloat64(rotations.WithLabelValues("KubeconfigSecretRotated"))
+		failedBefore := testutil.ToFloat64(rotationFailures.WithLabelValues("FailedToGetKubeconfig"))
+
+		// when
+		RecordRotation("KubeconfigSecretRotated")
+		RecordRotationFailure("FailedToGetKubeconfig")
+		RecordRotationFailure("FailedToGetKubeconfig")
+
+		// then
+		assert.Equal(t, rotatedBefore+1, testutil.ToFloat64(rotations.WithLabelValues("KubeconfigSecretRotated")))
+		assert.Equal(t, failedBefore+2, testutil.ToFloat64(rotationFailures.WithLabelValues("FailedToGetKubeconfig")))
+	})
+
+	t.Run("should observe durations by outcome", func(t *testing.T) {
+		// when
+		ObserveGardenerRequest(GardenerOperationGetShoot, time.Now(), errors.New("unavailable"))
+		ObserveReconcile(time.Now(), nil)
+
+		// then
+		assert.Equal(t, 1, testutil.CollectAndCount(gardenerRequestDuration, "im_gardener_request_duration_seconds"))
+		assert.Equal(t, 1, testutil.CollectAndCount(reconcileDuration, "im_gardener_cluster_reconcile_duration_seconds"))
+	})
+
+	t.Run("should count throttled, and in-flight Gardener requests", func(t *testing.T) {
+		// given
+		throttledBefore := testutil.ToFloat64(gardenerThrottledRequests)
+		inFlightBefore := testutil.ToFloat64(gardenerInFlightRequests)
+
+		// when
+		ObserveGardenerThrottling(time.Now())
+		GardenerRequestStarted(time.Now())
+		GardenerRequestStarted(time.Now())
+		GardenerRequestFinished()
+
+		// then
+		assert.Equal(t, throttledBefore+1, testutil.ToFloat64(gardenerThrottledRequests))
+		assert.Equal(t, inFlightBefore+1, testutil.ToFloat64(gardenerInFlightRequests))
+	})
+
+	t.Run("should count orphaned secrets", func(t *testing.T) {
+		// given
+		foundBefore := testutil.ToFloat64(orphanedSecretsFound)
+		deletedBefore := testutil.ToFloat64(orphanedSecretsDeleted)
+
+		// when
+		RecordOrphanedSecretFound()
+		RecordOrphanedSecretFound()
+		RecordOrphanedSecretDeleted()
+
+		// then
+		assert.Equal(t, foundBefore+2, testutil.ToFloat64(orphanedSecretsFound))
+		assert.Equal(t, deletedBefore+1, testutil.ToFloat64(orphanedSecretsDeleted))
+	})
+}
+
+func TestManagedSecretsCollector(t *testing.T) {
+	t.Run("should count secrets matching the selector", func(t *testing.T) {
+		// given
+		selector := client.MatchingLabels{"managed": "true"}
+		reader := fake.NewClientBuilder().WithObjects(
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "kcp-system", Labels: selector}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "kcp-system"}},
+		).Build()
+
+		collector := &managedSecretsCollector{
+			reader:      reader,
+			selector:    selector,
+			description: prometheus.NewDesc("im_managed_kubeconfig_secrets", "Number of kubeconfig secrets managed by the controller.", nil, nil),
+		}
+
+		// when
+		err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP im_managed_kubeconfig_secrets Number of kubeconfig secrets managed by the controller.
+# TYPE im_managed_kubeconfig_secrets gauge
+im_managed_kubeconfig_secrets 1
+`))
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestClusterStateMetric(t *testing.T) {
+	t.Run("should mark the current state of the cluster", func(t *testing.T) {
+		// when
+		SetClusterState("cluster", "kcp-system", imv1.ProcessingState)
+		SetClusterState("cluster", "kcp-system", imv1.ErrorState)
+
+		// then
+		assert.Equal(t, 1.0, testutil.ToFloat64(clusterState.WithLabelValues("cluster", "kcp-system", "Error")))
+		assert.Equal(t, 0.0, testutil.ToFloat64(clusterState.WithLabelValues("cluster", "kcp-system", "Processing")))
+		assert.Equal(t, 0.0, testutil.ToFloat64(clusterState.WithLabelValues("cluster", "kcp-system", "Ready")))
+	})
+
+	t.Run("should remove the series of deleted cluster", func(t *testing.T) {
+		// given
+		SetClusterState("deleted", "kcp-system", imv1.ReadyState)
+		SetClusterState("other", "kcp-system", imv1.ReadyState)
+		before := testutil.CollectAndCount(clusterState)
+
+		// when
+		DeleteCluster("deleted", "kcp-system")
+
+		// then
+		assert.Equal(t, before-len(clusterStates), testutil.CollectAndCount(clusterState))
+	})
+}
+
+func TestClusterInfoMetric(t *testing.T) {
+	t.Run("should replace the purpose of the cluster", func(t *testing.T) {
+		// when
+		SetClusterPurpose("purpose", "kcp-system", "")
+		SetClusterPurpose("purpose", "kcp-system", imv1.PurposeProduction)
+
+		// then
+		assert.Equal(t, 1.0, testutil.ToFloat64(clusterInfo.WithLabelValues("purpose", "kcp-system", "production")))
+		assert.Equal(t, 1, testutil.CollectAndCount(clusterInfo))
+
+		DeleteCluster("purpose", "kcp-system")
+		assert.Equal(t, 0, testutil.CollectAndCount(clusterInfo))
+	})
+}
+
+func TestKubeconfigExpirationMetric(t *testing.T) {
+	t.Run("should export expiration of every secret", func(t *testing.T) {
+		// given
+		expirationTime := time.Unix(1700000000, 0)
+		cluster := &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+			Status: imv1.GardenerClusterStatus{
+				KubeconfigExpirationTime: &metav1.Time{Time: expirationTime},
+				Secrets: []imv1.SecretStatus{
+					{Name: "kubeconfig", Namespace: "kcp-system"},
+					{Name: "copy", Namespace: "other", CurrentSecret: "copy-v2"},
+				},
+			},
+		}
+
+		// when
+		SetKubeconfigExpiration(cluster)
+
+		// then
+		assert.Equal(t, 1700000000.0, testutil.ToFloat64(kubeconfigExpiration.WithLabelValues("kubeconfig", "kcp-system", "cluster", "kcp-system")))
+		assert.Equal(t, 1700000000.0, testutil.ToFloat64(kubeconfigExpiration.WithLabelValues("copy-v2", "other", "cluster", "kcp-system")))
+	})
+
+	t.Run("should remove series of secrets no longer managed", func(t *testing.T) {
+		// given
+		cluster := &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "kcp-system"},
+			Status: imv1.GardenerClusterStatus{
+				KubeconfigExpirationTime: &metav1.Time{Time: time.Now()},
+				Secrets:                  []imv1.SecretStatus{{Name: "kubeconfig", Namespace: "kcp-system"}},
+			},
+		}
+		SetKubeconfigExpiration(cluster)
+		before := testutil.CollectAndCount(kubeconfigExpiration)
+
+		// when
+		cluster.Status.Secrets = nil
+		SetKubeconfigExpiration(cluster)
+
+		// then
+		assert.Equal(t, before-1, testutil.CollectAndCount(kubeconfigExpiration))
+	})
+}
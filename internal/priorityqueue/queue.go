@@ -0,0 +1,190 @@
+// Package priorityqueue provides a work queue handing out the items with the highest priority first, so the important
+// objects are reconciled before the others when the queue is deep.
+package priorityqueue
+
+import (
+	"container/heap"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// PriorityFunc returns the priority of an item, the items with a higher priority are handed out first.
+type PriorityFunc func(item any) int
+
+// Queue is a workqueue.Interface ordering the items by their priority, and the items with the same priority in the
+// order they were added. Like the queue of client-go, an item is queued only once, and an item added while it is
+// processed is queued again when it is done. The priority is read when the item is queued.
+type Queue struct {
+	priority PriorityFunc
+
+	cond         *sync.Cond
+	queue        itemHeap
+	dirty        map[any]struct{}
+	processing   map[any]struct{}
+	sequence     uint64
+	shuttingDown bool
+	drain        bool
+}
+
+var _ workqueue.Interface = &Queue{}
+
+// New creates a queue ordering the items with the priority function.
+func New(priority PriorityFunc) *Queue {
+	return &Queue{
+		priority:   priority,
+		cond:       sync.NewCond(&sync.Mutex{}),
+		dirty:      map[any]struct{}{},
+		processing: map[any]struct{}{},
+	}
+}
+
+// NewRateLimiting creates a rate limiting queue on top of the priority queue, the delayed, and rate limited items are
+// ordered by their priority once they are due.
+func NewRateLimiting(name string, rateLimiter workqueue.RateLimiter, priority PriorityFunc) workqueue.RateLimitingInterface {
+	delayingQueue := workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{Name: name, Queue: New(priority)})
+
+	return workqueue.NewRateLimitingQueueWithConfig(rateLimiter, workqueue.RateLimitingQueueConfig{Name: name, DelayingQueue: delayingQueue})
+}
+
+// Add marks the item as needing processing.
+func (queue *Queue) Add(item any) {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	if queue.shuttingDown {
+		return
+	}
+
+	if _, found := queue.dirty[item]; found {
+		return
+	}
+
+	queue.dirty[item] = struct{}{}
+
+	if _, found := queue.processing[item]; found {
+		return
+	}
+
+	queue.push(item)
+	queue.cond.Signal()
+}
+
+// Len returns the number of items waiting to be processed.
+func (queue *Queue) Len() int {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	return queue.queue.Len()
+}
+
+// Get blocks until it can return the item with the highest priority. Done has to be called with the item when it is
+// processed.
+func (queue *Queue) Get() (any, bool) {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	for queue.queue.Len() == 0 && !queue.shuttingDown {
+		queue.cond.Wait()
+	}
+
+	if queue.queue.Len() == 0 {
+		return nil, true
+	}
+
+	item := heap.Pop(&queue.queue).(queuedItem).item
+
+	queue.processing[item] = struct{}{}
+	delete(queue.dirty, item)
+
+	return item, false
+}
+
+// Done marks the item as processed, it is queued again when it was added while it was processed.
+func (queue *Queue) Done(item any) {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	delete(queue.processing, item)
+
+	if _, found := queue.dirty[item]; found {
+		queue.push(item)
+		queue.cond.Signal()
+	} else if len(queue.processing) == 0 {
+		queue.cond.Signal()
+	}
+}
+
+// ShutDown makes the queue ignore the added items, and the workers return once the queue is empty.
+func (queue *Queue) ShutDown() {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	queue.drain = false
+	queue.shuttingDown = true
+	queue.cond.Broadcast()
+}
+
+// ShutDownWithDrain shuts the queue down, and waits until all the items being processed are done.
+func (queue *Queue) ShutDownWithDrain() {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	queue.drain = true
+	queue.shuttingDown = true
+	queue.cond.Broadcast()
+
+	for len(queue.processing) != 0 && queue.drain {
+		queue.cond.Wait()
+	}
+}
+
+// ShuttingDown reports whether the queue is shut down.
+func (queue *Queue) ShuttingDown() bool {
+	queue.cond.L.Lock()
+	defer queue.cond.L.Unlock()
+
+	return queue.shuttingDown
+}
+
+func (queue *Queue) push(item any) {
+	queue.sequence++
+	heap.Push(&queue.queue, queuedItem{item: item, priority: queue.priority(item), sequence: queue.sequence})
+}
+
+type queuedItem struct {
+	item     any
+	priority int
+	sequence uint64
+}
+
+// itemHeap orders the items by their priority, and the items with the same priority by their sequence.
+type itemHeap []queuedItem
+
+func (items itemHeap) Len() int {
+	return len(items)
+}
+
+func (items itemHeap) Less(i, j int) bool {
+	if items[i].priority != items[j].priority {
+		return items[i].priority > items[j].priority
+	}
+
+	return items[i].sequence < items[j].sequence
+}
+
+func (items itemHeap) Swap(i, j int) {
+	items[i], items[j] = items[j], items[i]
+}
+
+func (items *itemHeap) Push(item any) {
+	*items = append(*items, item.(queuedItem))
+}
+
+func (items *itemHeap) Pop() any {
+	old := *items
+	last := old[len(old)-1]
+	*items = old[:len(old)-1]
+
+	return last
+}
@@ -0,0 +1,99 @@
+package priorityqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestQueue(t *testing.T) {
+	priorities := map[string]int{"production": 100, "staging": 10}
+	priority := func(item any) int {
+		return priorities[item.(string)]
+	}
+
+	t.Run("should hand out the items with the highest priority first", func(t *testing.T) {
+		// given
+		queue := New(priority)
+
+		// when
+		queue.Add("dev1")
+		queue.Add("staging")
+		queue.Add("dev2")
+		queue.Add("production")
+
+		// then
+		assert.Equal(t, []any{"production", "staging", "dev1", "dev2"}, getAll(queue))
+	})
+
+	t.Run("should queue an item only once", func(t *testing.T) {
+		// given
+		queue := New(priority)
+
+		// when
+		queue.Add("dev1")
+		queue.Add("dev1")
+
+		// then
+		assert.Equal(t, 1, queue.Len())
+	})
+
+	t.Run("should queue an item added while processed again when it is done", func(t *testing.T) {
+		// given
+		queue := New(priority)
+		queue.Add("dev1")
+		item, _ := queue.Get()
+
+		// when
+		queue.Add("dev1")
+
+		// then
+		assert.Equal(t, 0, queue.Len())
+
+		// when
+		queue.Done(item)
+
+		// then
+		assert.Equal(t, 1, queue.Len())
+	})
+
+	t.Run("should return from get when shut down", func(t *testing.T) {
+		// given
+		queue := New(priority)
+		go queue.ShutDown()
+
+		// when
+		_, shutdown := queue.Get()
+
+		// then
+		assert.True(t, shutdown)
+	})
+
+	t.Run("should order the rate limited items by their priority", func(t *testing.T) {
+		// given
+		queue := NewRateLimiting("", workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Millisecond), priority)
+		defer queue.ShutDown()
+
+		// when
+		queue.AddRateLimited("dev1")
+		queue.AddRateLimited("production")
+
+		// then
+		assert.Eventually(t, func() bool { return queue.Len() == 2 }, time.Second, time.Millisecond)
+		assert.Equal(t, []any{"production", "dev1"}, getAll(queue))
+	})
+}
+
+func getAll(queue workqueue.Interface) []any {
+	var items []any
+
+	for queue.Len() > 0 {
+		item, _ := queue.Get()
+		items = append(items, item)
+		queue.Done(item)
+	}
+
+	return items
+}
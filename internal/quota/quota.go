@@ -0,0 +1,132 @@
+// Package quota enforces the ClusterQuotas, which limit the number of GardenerClusters, and Runtimes in a namespace, so
+// a tenant can't exhaust the Gardener project.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-infrastructuremanager-kyma-project-io-v1-clusterquota-usage,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructuremanager.kyma-project.io,resources=gardenerclusters;runtimes,verbs=create,versions=v1,name=vclusterquota.kb.io,admissionReviewVersions=v1
+
+//+kubebuilder:rbac:groups=infrastructuremanager.kyma-project.io,resources=clusterquotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+
+// WebhookPath is the path the quota webhook is served at.
+const WebhookPath = "/validate-infrastructuremanager-kyma-project-io-v1-clusterquota-usage"
+
+// quotaAnnotations are the annotations of the namespaces limiting the objects of the kinds.
+var quotaAnnotations = map[string]string{ //nolint:gochecknoglobals
+	"GardenerCluster": imv1.GardenerClustersQuotaAnnotation,
+	"Runtime":         imv1.RuntimesQuotaAnnotation,
+}
+
+// Validator rejects the creation of GardenerClusters, and Runtimes exceeding the quotas of their namespace, set by the
+// ClusterQuotas, or the quota annotations of the namespace. The objects are counted with uncached reads, so concurrent
+// creations can't exceed the quota by the lag of the cache.
+type Validator struct {
+	reader client.Reader
+}
+
+var _ admission.Handler = &Validator{}
+
+func NewValidator(reader client.Reader) *Validator {
+	return &Validator{reader: reader}
+}
+
+// Handle admits the creation when the number of objects of the kind in the namespace is below all the limits.
+func (validator *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	limit, limited, err := validator.limit(ctx, req.Namespace, req.Kind.Kind)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if !limited {
+		return admission.Allowed("")
+	}
+
+	count, err := validator.count(ctx, req.Namespace, req.Kind.Kind)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if count >= int(limit) {
+		return admission.Denied(fmt.Sprintf("quota of %d %ss in namespace %s is exhausted", limit, req.Kind.Kind, req.Namespace))
+	}
+
+	return admission.Allowed("")
+}
+
+// limit returns the lowest limit of the kind set by the quotas, and the annotations of the namespace.
+func (validator *Validator) limit(ctx context.Context, namespace, kind string) (int32, bool, error) {
+	lowest, limited, err := validator.annotationLimit(ctx, namespace, kind)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var quotas imv1.ClusterQuotaList
+
+	err = validator.reader.List(ctx, &quotas, client.InNamespace(namespace))
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to list cluster quotas")
+	}
+
+	for i := range quotas.Items {
+		limit, ok := quotas.Items[i].Limit(kind)
+		if ok && (!limited || limit < lowest) {
+			lowest = limit
+			limited = true
+		}
+	}
+
+	return lowest, limited, nil
+}
+
+// annotationLimit returns the limit of the kind set by the quota annotation of the namespace.
+func (validator *Validator) annotationLimit(ctx context.Context, namespace, kind string) (int32, bool, error) {
+	annotation, ok := quotaAnnotations[kind]
+	if !ok {
+		return 0, false, nil
+	}
+
+	var ns corev1.Namespace
+
+	err := validator.reader.Get(ctx, client.ObjectKey{Name: namespace}, &ns)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to get namespace")
+	}
+
+	value, ok := ns.Annotations[annotation]
+	if !ok {
+		return 0, false, nil
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || limit < 0 {
+		return 0, false, errors.Errorf("invalid value %q of the annotation %s of namespace %s", value, annotation, namespace)
+	}
+
+	return int32(limit), true, nil
+}
+
+// count returns the number of objects of the kind in the namespace, only their metadata is read.
+func (validator *Validator) count(ctx context.Context, namespace, kind string) (int, error) {
+	var objects metav1.PartialObjectMetadataList
+	objects.SetGroupVersionKind(imv1.GroupVersion.WithKind(kind + "List"))
+
+	err := validator.reader.List(ctx, &objects, client.InNamespace(namespace))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list %ss", kind)
+	}
+
+	return len(objects.Items), nil
+}
@@ -0,0 +1,146 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const testNamespace = "kcp-system"
+
+func TestValidator(t *testing.T) {
+	t.Run("should allow the creation without quotas", func(t *testing.T) {
+		// given
+		validator := NewValidator(fakeClient(t, namespace(nil), runtimeObject("runtime1")))
+
+		// when
+		response := validator.Handle(context.Background(), createRequest("Runtime"))
+
+		// then
+		assert.True(t, response.Allowed)
+	})
+
+	t.Run("should allow the creation below the quota", func(t *testing.T) {
+		// given
+		validator := NewValidator(fakeClient(t, namespace(nil), clusterQuota("quota", nil, limit(2)), runtimeObject("runtime1")))
+
+		// when
+		response := validator.Handle(context.Background(), createRequest("Runtime"))
+
+		// then
+		assert.True(t, response.Allowed)
+	})
+
+	t.Run("should deny the creation when the quota is exhausted", func(t *testing.T) {
+		// given
+		validator := NewValidator(fakeClient(t, namespace(nil), clusterQuota("quota", nil, limit(1)), runtimeObject("runtime1")))
+
+		// when
+		response := validator.Handle(context.Background(), createRequest("Runtime"))
+
+		// then
+		assert.False(t, response.Allowed)
+		assert.Contains(t, response.Result.Message, "quota of 1 Runtimes")
+	})
+
+	t.Run("should apply the lowest limit of several quotas", func(t *testing.T) {
+		// given
+		validator := NewValidator(fakeClient(t, namespace(nil),
+			clusterQuota("quota1", nil, limit(5)),
+			clusterQuota("quota2", nil, limit(1)),
+			runtimeObject("runtime1")))
+
+		// when
+		response := validator.Handle(context.Background(), createRequest("Runtime"))
+
+		// then
+		assert.False(t, response.Allowed)
+	})
+
+	t.Run("should only limit the kinds set in the quota", func(t *testing.T) {
+		// given
+		validator := NewValidator(fakeClient(t, namespace(nil), clusterQuota("quota", limit(0), nil)))
+
+		// when
+		runtimeResponse := validator.Handle(context.Background(), createRequest("Runtime"))
+		clusterResponse := validator.Handle(context.Background(), createRequest("GardenerCluster"))
+
+		// then
+		assert.True(t, runtimeResponse.Allowed)
+		assert.False(t, clusterResponse.Allowed)
+	})
+
+	t.Run("should deny the creation when the quota annotation of the namespace is exhausted", func(t *testing.T) {
+		// given
+		validator := NewValidator(fakeClient(t,
+			namespace(map[string]string{imv1.RuntimesQuotaAnnotation: "1"}),
+			clusterQuota("quota", nil, limit(5)),
+			runtimeObject("runtime1")))
+
+		// when
+		response := validator.Handle(context.Background(), createRequest("Runtime"))
+
+		// then
+		assert.False(t, response.Allowed)
+	})
+
+	t.Run("should fail on an invalid quota annotation", func(t *testing.T) {
+		// given
+		validator := NewValidator(fakeClient(t, namespace(map[string]string{imv1.GardenerClustersQuotaAnnotation: "many"})))
+
+		// when
+		response := validator.Handle(context.Background(), createRequest("GardenerCluster"))
+
+		// then
+		assert.False(t, response.Allowed)
+		assert.Contains(t, response.Result.Message, "invalid value")
+	})
+}
+
+func fakeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, imv1.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func createRequest(kind string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Namespace: testNamespace,
+		Kind:      metav1.GroupVersionKind{Group: imv1.GroupVersion.Group, Version: imv1.GroupVersion.Version, Kind: kind},
+	}}
+}
+
+func namespace(annotations map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Annotations: annotations}}
+}
+
+func clusterQuota(name string, gardenerClusters, runtimes *int32) *imv1.ClusterQuota {
+	return &imv1.ClusterQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       imv1.ClusterQuotaSpec{GardenerClusters: gardenerClusters, Runtimes: runtimes},
+	}
+}
+
+func runtimeObject(name string) *imv1.Runtime {
+	return &imv1.Runtime{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace}}
+}
+
+func limit(value int32) *int32 {
+	return &value
+}
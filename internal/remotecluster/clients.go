@@ -0,0 +1,63 @@
+// Package remotecluster builds the clients of the clusters the kubeconfig secrets are delivered to, when the secrets
+// are not written to the cluster of the GardenerClusters.
+package remotecluster
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/kyma-project/infrastructure-manager/internal/kubeconfig"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Clients keeps the clients of the target clusters, keyed by the secret with the kubeconfig of the cluster. A client is
+// rebuilt when the kubeconfig in its secret changes.
+type Clients struct {
+	scheme *runtime.Scheme
+
+	mu      sync.Mutex
+	clients map[string]cachedClient
+}
+
+type cachedClient struct {
+	kubeconfigHash [sha256.Size]byte
+	client         client.Client
+}
+
+// NewClients creates the cache, the clients read, and write the objects of the scheme.
+func NewClients(scheme *runtime.Scheme) *Clients {
+	return &Clients{
+		scheme:  scheme,
+		clients: map[string]cachedClient{},
+	}
+}
+
+// Client returns the client of the cluster of the kubeconfig stored in the secret with the key. The client reads from
+// the API server without an informer cache, the target clusters are only accessed on the reconciliation of their
+// GardenerClusters. The kubeconfig is provided by the tenant, so only inline credentials are accepted.
+func (clients *Clients) Client(key string, rawKubeconfig []byte) (client.Client, error) {
+	kubeconfigHash := sha256.Sum256(rawKubeconfig)
+
+	clients.mu.Lock()
+	defer clients.mu.Unlock()
+
+	if cached, found := clients.clients[key]; found && cached.kubeconfigHash == kubeconfigHash {
+		return cached.client, nil
+	}
+
+	restConfig, err := kubeconfig.RESTConfigFromUntrusted(rawKubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the kubeconfig of the target cluster %s", key)
+	}
+
+	targetClient, err := client.New(restConfig, client.Options{Scheme: clients.scheme})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the client of the target cluster %s", key)
+	}
+
+	clients.clients[key] = cachedClient{kubeconfigHash: kubeconfigHash, client: targetClient}
+
+	return targetClient, nil
+}
@@ -0,0 +1,88 @@
+package remotecluster
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+const testKubeconfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- name: target
+  cluster:
+    server: https://target.example.com
+contexts:
+- name: target
+  context:
+    cluster: target
+    user: robot
+current-context: target
+users:
+- name: robot
+  user:
+    token: %s
+`
+
+func TestClients(t *testing.T) {
+	t.Run("should reuse the client until the kubeconfig changes", func(t *testing.T) {
+		// given
+		clients := NewClients(clientgoscheme.Scheme)
+
+		// when
+		first, err := clients.Client("kcp-system/target-cluster", []byte(fmt.Sprintf(testKubeconfigTemplate, "token1")))
+		require.NoError(t, err)
+
+		cached, err := clients.Client("kcp-system/target-cluster", []byte(fmt.Sprintf(testKubeconfigTemplate, "token1")))
+		require.NoError(t, err)
+
+		rebuilt, err := clients.Client("kcp-system/target-cluster", []byte(fmt.Sprintf(testKubeconfigTemplate, "token2")))
+		require.NoError(t, err)
+
+		// then
+		assert.Same(t, first, cached)
+		assert.NotSame(t, first, rebuilt)
+	})
+
+	t.Run("should fail on an invalid kubeconfig", func(t *testing.T) {
+		// given
+		clients := NewClients(clientgoscheme.Scheme)
+
+		// when
+		_, err := clients.Client("kcp-system/target-cluster", []byte("not a kubeconfig"))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "kcp-system/target-cluster")
+	})
+
+	t.Run("should reject a kubeconfig running a command in the controller", func(t *testing.T) {
+		// given
+		clients := NewClients(clientgoscheme.Scheme)
+		kubeconfig := strings.Replace(fmt.Sprintf(testKubeconfigTemplate, "token1"), "token: token1", "exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh", 1)
+
+		// when
+		_, err := clients.Client("kcp-system/target-cluster", []byte(kubeconfig))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exec plugin")
+	})
+
+	t.Run("should reject a kubeconfig reading the token of the controller", func(t *testing.T) {
+		// given
+		clients := NewClients(clientgoscheme.Scheme)
+		kubeconfig := strings.Replace(fmt.Sprintf(testKubeconfigTemplate, "token1"), "token: token1", "tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", 1)
+
+		// when
+		_, err := clients.Client("kcp-system/target-cluster", []byte(kubeconfig))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token file")
+	})
+}
@@ -0,0 +1,77 @@
+package sealing
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const sessionKeySize = 32
+
+// ParseCertificate returns the public key of the PEM encoded sealed-secrets controller certificate.
+func ParseCertificate(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate")
+	}
+
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("unexpected public key type %T, RSA expected", certificate.PublicKey)
+	}
+
+	return publicKey, nil
+}
+
+// Seal encrypts the value of a secret for the sealed-secrets controller with the strict scope, the sealed value
+// can only be unsealed into the secret with the given name, and namespace. It returns the base64 encoded value
+// of the SealedSecret encrypted data.
+//
+// The value is encrypted with a random AES-256-GCM session key, the session key is encrypted with RSA-OAEP using
+// the scope as the label, and prepended with its length.
+func Seal(publicKey *rsa.PublicKey, namespace, name string, plaintext []byte) (string, error) {
+	sessionKey := make([]byte, sessionKeySize)
+
+	_, err := rand.Read(sessionKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate session key")
+	}
+
+	label := []byte(fmt.Sprintf("%s/%s", namespace, name))
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, sessionKey, label)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encrypt session key")
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext := binary.BigEndian.AppendUint16(nil, uint16(len(encryptedKey)))
+	ciphertext = append(ciphertext, encryptedKey...)
+	// the session key is used only once, so the nonce can be fixed
+	ciphertext = gcm.Seal(ciphertext, make([]byte, gcm.NonceSize()), plaintext, nil)
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
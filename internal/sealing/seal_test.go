@@ -0,0 +1,105 @@
+package sealing
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unseal mirrors the decryption of the sealed-secrets controller.
+func unseal(t *testing.T, privateKey *rsa.PrivateKey, namespace, name, sealed string) ([]byte, error) {
+	t.Helper()
+
+	ciphertext, err := base64.StdEncoding.DecodeString(sealed)
+	require.NoError(t, err)
+
+	keyLength := int(binary.BigEndian.Uint16(ciphertext))
+	encryptedKey, sealedValue := ciphertext[2:2+keyLength], ciphertext[2+keyLength:]
+
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, encryptedKey, []byte(namespace+"/"+name))
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	require.NoError(t, err)
+
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	return gcm.Open(nil, make([]byte, gcm.NonceSize()), sealedValue, nil)
+}
+
+func TestSeal(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("should seal value for the secret", func(t *testing.T) {
+		// when
+		sealed, err := Seal(&privateKey.PublicKey, "kcp-system", "kubeconfig", []byte("kubeconfig"))
+		require.NoError(t, err)
+
+		// then
+		plaintext, err := unseal(t, privateKey, "kcp-system", "kubeconfig", sealed)
+		require.NoError(t, err)
+		assert.Equal(t, "kubeconfig", string(plaintext))
+	})
+
+	t.Run("should not unseal into another secret", func(t *testing.T) {
+		// given
+		sealed, err := Seal(&privateKey.PublicKey, "kcp-system", "kubeconfig", []byte("kubeconfig"))
+		require.NoError(t, err)
+
+		// when
+		_, err = unseal(t, privateKey, "kcp-system", "other", sealed)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestParseCertificate(t *testing.T) {
+	t.Run("should return the public key of the certificate", func(t *testing.T) {
+		// given
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "sealed-secrets"},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+
+		certificate, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+		require.NoError(t, err)
+
+		// when
+		publicKey, err := ParseCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, publicKey.Equal(&privateKey.PublicKey))
+	})
+
+	t.Run("should reject data without certificate", func(t *testing.T) {
+		// when
+		_, err := ParseCertificate([]byte("invalid"))
+
+		// then
+		require.Error(t, err)
+	})
+}
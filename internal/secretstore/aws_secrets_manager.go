@@ -0,0 +1,123 @@
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// deleted secrets can be restored within the recovery window
+	awsSecretRecoveryWindowInDays = 7
+)
+
+type secretsManagerAPI interface {
+	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
+	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+}
+
+// AWSSecretsManagerStore writes kubeconfigs to AWS Secrets Manager. Every rotation stores a new version of the secret,
+// Secrets Manager moves the AWSCURRENT label to it, and keeps the previous version labelled AWSPREVIOUS.
+type AWSSecretsManagerStore struct {
+	client secretsManagerAPI
+}
+
+// NewAWSSecretsManagerStore creates the store from the AWS configuration, which picks up the IRSA web identity credentials
+// injected into the pod.
+func NewAWSSecretsManagerStore(config aws.Config) *AWSSecretsManagerStore {
+	return &AWSSecretsManagerStore{client: secretsmanager.NewFromConfig(config)}
+}
+
+func (store *AWSSecretsManagerStore) Location(cluster *imv1.GardenerCluster) string {
+	return cluster.Spec.Kubeconfig.ExternalStores.AWSSecretsManager.SecretName
+}
+
+// Write stores a new version of the secret, an existing secret must be tagged as managed for the cluster.
+func (store *AWSSecretsManagerStore) Write(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, error) {
+	secretName := store.Location(cluster)
+
+	found, err := store.verifyManaged(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	if found {
+		output, err := store.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+			SecretId:     aws.String(secretName),
+			SecretString: aws.String(kubeconfig),
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to write kubeconfig to AWS secret %s", secretName)
+		}
+
+		return aws.ToString(output.VersionId), nil
+	}
+
+	created, err := store.client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String(secretName),
+		SecretString: aws.String(kubeconfig),
+		Description:  aws.String(fmt.Sprintf("Kubeconfig of shoot %s", cluster.Spec.Shoot.Name)),
+		Tags: []types.Tag{
+			{Key: aws.String(managedByTag), Value: aws.String(managedByTagValue)},
+			{Key: aws.String(clusterTag), Value: aws.String(clusterTagValue(cluster))},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create AWS secret %s", secretName)
+	}
+
+	return aws.ToString(created.VersionId), nil
+}
+
+// Delete schedules the deletion of the secret, only when it is tagged as managed for the cluster.
+func (store *AWSSecretsManagerStore) Delete(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	secretName := store.Location(cluster)
+
+	found, err := store.verifyManaged(ctx, cluster)
+	if err != nil || !found {
+		return err
+	}
+
+	_, err = store.client.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{
+		SecretId:             aws.String(secretName),
+		RecoveryWindowInDays: aws.Int64(awsSecretRecoveryWindowInDays),
+	})
+
+	var notFound *types.ResourceNotFoundException
+	if err != nil && !errors.As(err, &notFound) {
+		return errors.Wrapf(err, "failed to delete AWS secret %s", secretName)
+	}
+
+	return nil
+}
+
+// verifyManaged reads the tags of the secret, and returns whether it exists. An existing secret which is not tagged as
+// managed for the cluster is an error.
+func (store *AWSSecretsManagerStore) verifyManaged(ctx context.Context, cluster *imv1.GardenerCluster) (bool, error) {
+	secretName := store.Location(cluster)
+
+	output, err := store.client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(secretName)})
+
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to describe AWS secret %s", secretName)
+	}
+
+	tags := make(map[string]string, len(output.Tags))
+	for _, tag := range output.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return true, verifyManaged(secretName, tags, clusterTag, clusterTagValue(cluster))
+}
@@ -0,0 +1,139 @@
+package secretstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeSecretsManager struct {
+	secrets map[string][]string
+	tags    map[string][]types.Tag
+	deleted []string
+}
+
+func (fake *fakeSecretsManager) DescribeSecret(_ context.Context, params *secretsmanager.DescribeSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+	if _, found := fake.secrets[*params.SecretId]; !found {
+		return nil, &types.ResourceNotFoundException{}
+	}
+
+	return &secretsmanager.DescribeSecretOutput{Name: params.SecretId, Tags: fake.tags[*params.SecretId]}, nil
+}
+
+func (fake *fakeSecretsManager) CreateSecret(_ context.Context, params *secretsmanager.CreateSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	fake.secrets[*params.Name] = []string{*params.SecretString}
+	fake.tags[*params.Name] = params.Tags
+
+	return &secretsmanager.CreateSecretOutput{VersionId: aws.String("v1")}, nil
+}
+
+func (fake *fakeSecretsManager) PutSecretValue(_ context.Context, params *secretsmanager.PutSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	versions, found := fake.secrets[*params.SecretId]
+	if !found {
+		return nil, &types.ResourceNotFoundException{}
+	}
+
+	fake.secrets[*params.SecretId] = append(versions, *params.SecretString)
+
+	return &secretsmanager.PutSecretValueOutput{VersionId: aws.String("v2")}, nil
+}
+
+func (fake *fakeSecretsManager) DeleteSecret(_ context.Context, params *secretsmanager.DeleteSecretInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	if _, found := fake.secrets[*params.SecretId]; !found {
+		return nil, &types.ResourceNotFoundException{}
+	}
+
+	delete(fake.secrets, *params.SecretId)
+	fake.deleted = append(fake.deleted, *params.SecretId)
+
+	return &secretsmanager.DeleteSecretOutput{}, nil
+}
+
+func TestAWSSecretsManagerStore(t *testing.T) {
+	t.Run("should create secret, and add new version on rotation", func(t *testing.T) {
+		// given
+		fake := &fakeSecretsManager{secrets: map[string][]string{}, tags: map[string][]types.Tag{}}
+		store := &AWSSecretsManagerStore{client: fake}
+		cluster := fixClusterWithAWSSecret("kyma/cluster")
+
+		// when
+		createdVersion, err := store.Write(context.Background(), cluster, "kubeconfig1")
+		require.NoError(t, err)
+		rotatedVersion, err := store.Write(context.Background(), cluster, "kubeconfig2")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "v1", createdVersion)
+		assert.Equal(t, "v2", rotatedVersion)
+		assert.Equal(t, []string{"kubeconfig1", "kubeconfig2"}, fake.secrets["kyma/cluster"])
+		assert.Equal(t, fixAWSManagedTags("kcp-system/cluster"), fake.tags["kyma/cluster"])
+	})
+
+	t.Run("should refuse to write a secret not created for the cluster", func(t *testing.T) {
+		for name, tags := range map[string][]types.Tag{
+			"unmanaged":     nil,
+			"other cluster": fixAWSManagedTags("kcp-system/other"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				fake := &fakeSecretsManager{secrets: map[string][]string{"kyma/cluster": {"foreign"}}, tags: map[string][]types.Tag{"kyma/cluster": tags}}
+				store := &AWSSecretsManagerStore{client: fake}
+
+				// when
+				_, writeErr := store.Write(context.Background(), fixClusterWithAWSSecret("kyma/cluster"), "kubeconfig")
+				deleteErr := store.Delete(context.Background(), fixClusterWithAWSSecret("kyma/cluster"))
+
+				// then
+				assert.Error(t, writeErr)
+				assert.Error(t, deleteErr)
+				assert.Equal(t, []string{"foreign"}, fake.secrets["kyma/cluster"])
+				assert.Empty(t, fake.deleted)
+			})
+		}
+	})
+
+	t.Run("should delete secret and ignore missing secret", func(t *testing.T) {
+		// given
+		fake := &fakeSecretsManager{
+			secrets: map[string][]string{"kyma/cluster": {"kubeconfig"}},
+			tags:    map[string][]types.Tag{"kyma/cluster": fixAWSManagedTags("kcp-system/cluster")},
+		}
+		store := &AWSSecretsManagerStore{client: fake}
+		cluster := fixClusterWithAWSSecret("kyma/cluster")
+
+		// when
+		require.NoError(t, store.Delete(context.Background(), cluster))
+		err := store.Delete(context.Background(), cluster)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"kyma/cluster"}, fake.deleted)
+	})
+}
+
+func fixClusterWithAWSSecret(secretName string) *imv1.GardenerCluster {
+	return &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+		Spec: imv1.GardenerClusterSpec{
+			Kubeconfig: imv1.Kubeconfig{
+				ExternalStores: &imv1.ExternalStores{
+					AWSSecretsManager: &imv1.AWSSecretsManagerTarget{SecretName: secretName},
+				},
+			},
+		},
+	}
+}
+
+func fixAWSManagedTags(cluster string) []types.Tag {
+	return []types.Tag{
+		{Key: aws.String(managedByTag), Value: aws.String(managedByTagValue)},
+		{Key: aws.String(clusterTag), Value: aws.String(cluster)},
+	}
+}
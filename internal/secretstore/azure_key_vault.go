@@ -0,0 +1,321 @@
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultAzureKeyVaultDNSSuffix = "vault.azure.net"
+
+	azureKeyVaultAPIVersion   = "7.4"
+	azureIMDSTokenEndpoint    = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureIMDSAPIVersion       = "2018-02-01"
+	azureKeyVaultResource     = "https://vault.azure.net"
+	azureDeletedButRecovering = "ObjectIsDeletedButRecoverable"
+	// azureClusterTag records the cluster of the secret, the secrets created before carry it already
+	azureClusterTag = "cluster"
+
+	// recovering a soft-deleted secret is asynchronous, the secret is polled until it becomes available
+	azureRecoveryPollInterval = 2 * time.Second
+	azureRecoveryTimeout      = time.Minute
+)
+
+// AzureKeyVaultConfig defines how the key vaults are addressed, and the managed identity the controller authenticates with.
+type AzureKeyVaultConfig struct {
+	DNSSuffix string
+
+	// ManagedIdentityClientID selects a user-assigned managed identity, the system-assigned identity is used when empty.
+	ManagedIdentityClientID string
+}
+
+// AzureKeyVaultStore writes kubeconfigs to Azure Key Vault using its REST API, authenticated with a managed identity.
+// Every rotation creates a new version of the secret. A secret soft-deleted before is recovered, and then updated.
+type AzureKeyVaultStore struct {
+	config        AzureKeyVaultConfig
+	httpClient    *http.Client
+	tokenEndpoint string
+	vaultURL      func(vaultName string) string
+
+	mu              sync.Mutex
+	token           string
+	tokenExpiration time.Time
+}
+
+func NewAzureKeyVaultStore(config AzureKeyVaultConfig, httpClient *http.Client) *AzureKeyVaultStore {
+	return &AzureKeyVaultStore{
+		config:        config,
+		httpClient:    httpClient,
+		tokenEndpoint: azureIMDSTokenEndpoint,
+		vaultURL: func(vaultName string) string {
+			return fmt.Sprintf("https://%s.%s", vaultName, config.DNSSuffix)
+		},
+	}
+}
+
+func (store *AzureKeyVaultStore) Location(cluster *imv1.GardenerCluster) string {
+	target := cluster.Spec.Kubeconfig.ExternalStores.AzureKeyVault
+
+	return fmt.Sprintf("%s/secrets/%s", store.vaultURL(target.VaultName), target.SecretName)
+}
+
+// Write creates a new version of the secret, an existing, or soft-deleted secret must be tagged as managed for the cluster.
+func (store *AzureKeyVaultStore) Write(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, error) {
+	found, err := store.verifyManaged(ctx, cluster, store.Location(cluster))
+	if err != nil {
+		return "", err
+	}
+
+	version, err := store.setSecret(ctx, cluster, kubeconfig)
+
+	var apiErr azureError
+	if !found && errors.As(err, &apiErr) && apiErr.code == azureDeletedButRecovering {
+		err = store.recoverSecret(ctx, cluster)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to recover soft-deleted Azure secret %s", store.Location(cluster))
+		}
+
+		version, err = store.setSecret(ctx, cluster, kubeconfig)
+	}
+
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write kubeconfig to Azure secret %s", store.Location(cluster))
+	}
+
+	return version, nil
+}
+
+// Delete soft-deletes the secret, only when it is tagged as managed for the cluster.
+func (store *AzureKeyVaultStore) Delete(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	found, err := store.verifyManaged(ctx, cluster, store.Location(cluster))
+	if err != nil || !found {
+		return err
+	}
+
+	err = store.do(ctx, http.MethodDelete, store.Location(cluster), nil, nil)
+
+	var apiErr azureError
+	if err != nil && !(errors.As(err, &apiErr) && apiErr.statusCode == http.StatusNotFound) {
+		return errors.Wrapf(err, "failed to delete Azure secret %s", store.Location(cluster))
+	}
+
+	return nil
+}
+
+// verifyManaged reads the tags of the secret, or of the soft-deleted secret, and returns whether it exists. An existing
+// secret which is not tagged as managed for the cluster is an error.
+func (store *AzureKeyVaultStore) verifyManaged(ctx context.Context, cluster *imv1.GardenerCluster, secretURL string) (bool, error) {
+	var secret struct {
+		Tags map[string]string `json:"tags"`
+	}
+
+	err := store.do(ctx, http.MethodGet, secretURL, nil, &secret)
+
+	var apiErr azureError
+	if errors.As(err, &apiErr) && apiErr.statusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get Azure secret %s", secretURL)
+	}
+
+	return true, verifyManaged(secretURL, secret.Tags, azureClusterTag, clusterTagValue(cluster))
+}
+
+func (store *AzureKeyVaultStore) setSecret(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, error) {
+	body := map[string]any{
+		"value":       kubeconfig,
+		"contentType": "application/yaml",
+		"tags": map[string]string{
+			managedByTag:    managedByTagValue,
+			azureClusterTag: clusterTagValue(cluster),
+		},
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+
+	err := store.do(ctx, http.MethodPut, store.Location(cluster), body, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Base(response.ID), nil
+}
+
+func (store *AzureKeyVaultStore) recoverSecret(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	target := cluster.Spec.Kubeconfig.ExternalStores.AzureKeyVault
+	deletedSecretURL := fmt.Sprintf("%s/deletedsecrets/%s", store.vaultURL(target.VaultName), target.SecretName)
+
+	found, err := store.verifyManaged(ctx, cluster, deletedSecretURL)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return errors.Errorf("soft-deleted secret %s not found", deletedSecretURL)
+	}
+
+	err = store.do(ctx, http.MethodPost, deletedSecretURL+"/recover", nil, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, azureRecoveryTimeout)
+	defer cancel()
+
+	for {
+		err = store.do(ctx, http.MethodGet, store.Location(cluster), nil, nil)
+
+		var apiErr azureError
+		if !errors.As(err, &apiErr) || apiErr.statusCode != http.StatusNotFound {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "secret has not been recovered in time")
+		case <-time.After(azureRecoveryPollInterval):
+		}
+	}
+}
+
+type azureError struct {
+	statusCode int
+	code       string
+	message    string
+}
+
+func (err azureError) Error() string {
+	return fmt.Sprintf("Key Vault responded with status %d: %s %s", err.statusCode, err.code, err.message)
+}
+
+func (store *AzureKeyVaultStore) do(ctx context.Context, method, resourceURL string, body any, response any) error {
+	token, err := store.getToken(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get managed identity token")
+	}
+
+	var requestBody io.Reader
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		requestBody = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, resourceURL+"?api-version="+azureKeyVaultAPIVersion, requestBody)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := store.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode >= http.StatusBadRequest {
+		var errorResponse struct {
+			Error struct {
+				Code       string `json:"code"`
+				Message    string `json:"message"`
+				InnerError *struct {
+					Code string `json:"code"`
+				} `json:"innererror"`
+			} `json:"error"`
+		}
+
+		_ = json.NewDecoder(httpResponse.Body).Decode(&errorResponse)
+
+		code := errorResponse.Error.Code
+		if errorResponse.Error.InnerError != nil {
+			code = errorResponse.Error.InnerError.Code
+		}
+
+		return azureError{statusCode: httpResponse.StatusCode, code: code, message: errorResponse.Error.Message}
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.NewDecoder(httpResponse.Body).Decode(response)
+}
+
+// getToken returns the cached managed identity token, and requests a new one from the instance metadata service shortly before it expires.
+func (store *AzureKeyVaultStore) getToken(ctx context.Context) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.token != "" && time.Now().Before(store.tokenExpiration) {
+		return store.token, nil
+	}
+
+	query := url.Values{}
+	query.Set("api-version", azureIMDSAPIVersion)
+	query.Set("resource", azureKeyVaultResource)
+
+	if store.config.ManagedIdentityClientID != "" {
+		query.Set("client_id", store.config.ManagedIdentityClientID)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, store.tokenEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Metadata", "true")
+
+	httpResponse, err := store.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return "", errors.Errorf("instance metadata service responded with status %d", httpResponse.StatusCode)
+	}
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"`
+	}
+
+	err = json.NewDecoder(httpResponse.Body).Decode(&response)
+	if err != nil {
+		return "", err
+	}
+
+	expiresIn, err := strconv.Atoi(response.ExpiresIn)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid token expiration")
+	}
+
+	store.token = response.AccessToken
+	store.tokenExpiration = time.Now().Add(time.Duration(vaultTokenRenewalRatio * float64(time.Duration(expiresIn)*time.Second)))
+
+	return store.token, nil
+}
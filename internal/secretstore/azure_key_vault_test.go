@@ -0,0 +1,223 @@
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeKeyVault serves the instance metadata token endpoint, and the subset of the Key Vault REST API used by the store.
+type fakeKeyVault struct {
+	exists       bool
+	softDeleted  bool
+	tags         map[string]string
+	recovered    bool
+	deleted      bool
+	values       []string
+	tokenQueries int
+}
+
+func (fake *fakeKeyVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const secretPath = "/secrets/cluster"
+
+	if r.URL.Path == "/token" {
+		fake.tokenQueries++
+		_, _ = w.Write([]byte(`{"access_token":"token","expires_in":"3600"}`))
+
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPut && r.URL.Path == secretPath:
+		if fake.softDeleted {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"code":"Conflict","message":"Secret is currently in a deleted but recoverable state","innererror":{"code":"ObjectIsDeletedButRecoverable"}}}`))
+
+			return
+		}
+
+		var body struct {
+			Value string            `json:"value"`
+			Tags  map[string]string `json:"tags"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		fake.values = append(fake.values, body.Value)
+		fake.exists, fake.tags = true, body.Tags
+
+		_, _ = fmt.Fprintf(w, `{"id":"https://vault.vault.azure.net/secrets/cluster/version%d"}`, len(fake.values))
+	case r.Method == http.MethodPost && r.URL.Path == "/deletedsecrets/cluster/recover":
+		fake.softDeleted, fake.exists = false, true
+		fake.recovered = true
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodGet && r.URL.Path == secretPath:
+		fake.writeTags(w, fake.exists)
+	case r.Method == http.MethodGet && r.URL.Path == "/deletedsecrets/cluster":
+		fake.writeTags(w, fake.softDeleted)
+	case r.Method == http.MethodDelete:
+		fake.deleted = true
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (fake *fakeKeyVault) writeTags(w http.ResponseWriter, found bool) {
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":"SecretNotFound"}}`))
+
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"tags": fake.tags})
+}
+
+func TestAzureKeyVaultStore(t *testing.T) {
+	newStore := func(serverURL string, client *http.Client) *AzureKeyVaultStore {
+		store := NewAzureKeyVaultStore(AzureKeyVaultConfig{DNSSuffix: DefaultAzureKeyVaultDNSSuffix}, client)
+		store.tokenEndpoint = serverURL + "/token"
+		store.vaultURL = func(string) string { return serverURL }
+
+		return store
+	}
+
+	cluster := &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+		Spec: imv1.GardenerClusterSpec{
+			Kubeconfig: imv1.Kubeconfig{
+				ExternalStores: &imv1.ExternalStores{
+					AzureKeyVault: &imv1.AzureKeyVaultTarget{VaultName: "vault", SecretName: "cluster"},
+				},
+			},
+		},
+	}
+
+	managedTags := map[string]string{managedByTag: managedByTagValue, azureClusterTag: "kcp-system/cluster"}
+
+	t.Run("should write new versions, and reuse the managed identity token", func(t *testing.T) {
+		// given
+		fake := &fakeKeyVault{}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		store := newStore(server.URL, server.Client())
+
+		// when
+		firstVersion, err := store.Write(context.Background(), cluster, "kubeconfig1")
+		require.NoError(t, err)
+
+		secondVersion, err := store.Write(context.Background(), cluster, "kubeconfig2")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, "version1", firstVersion)
+		assert.Equal(t, "version2", secondVersion)
+		assert.Equal(t, []string{"kubeconfig1", "kubeconfig2"}, fake.values)
+		assert.Equal(t, 1, fake.tokenQueries)
+		assert.Equal(t, managedTags, fake.tags)
+	})
+
+	t.Run("should refuse to write, and delete a secret not created for the cluster", func(t *testing.T) {
+		for name, tags := range map[string]map[string]string{
+			"unmanaged":     {"owner": "team-a"},
+			"other cluster": {managedByTag: managedByTagValue, azureClusterTag: "kcp-system/other"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				fake := &fakeKeyVault{exists: true, tags: tags}
+				server := httptest.NewServer(fake)
+				defer server.Close()
+
+				store := newStore(server.URL, server.Client())
+
+				// when
+				_, writeErr := store.Write(context.Background(), cluster, "kubeconfig")
+				deleteErr := store.Delete(context.Background(), cluster)
+
+				// then
+				assert.Error(t, writeErr)
+				assert.Error(t, deleteErr)
+				assert.Empty(t, fake.values)
+				assert.False(t, fake.deleted)
+			})
+		}
+	})
+
+	t.Run("should refuse to recover a soft-deleted secret not managed by the controller", func(t *testing.T) {
+		// given
+		fake := &fakeKeyVault{softDeleted: true, tags: map[string]string{"owner": "team-a"}}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		store := newStore(server.URL, server.Client())
+
+		// when
+		_, err := store.Write(context.Background(), cluster, "kubeconfig")
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not managed by the infrastructure manager")
+		assert.False(t, fake.recovered)
+	})
+
+	t.Run("should delete secret created for the cluster", func(t *testing.T) {
+		// given
+		fake := &fakeKeyVault{exists: true, tags: managedTags}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		store := newStore(server.URL, server.Client())
+
+		// when
+		err := store.Delete(context.Background(), cluster)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, fake.deleted)
+	})
+
+	t.Run("should recover soft-deleted secret before writing", func(t *testing.T) {
+		// given
+		fake := &fakeKeyVault{softDeleted: true, tags: managedTags}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		store := newStore(server.URL, server.Client())
+
+		// when
+		version, err := store.Write(context.Background(), cluster, "kubeconfig")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, fake.recovered)
+		assert.Equal(t, "version1", version)
+		assert.Equal(t, []string{"kubeconfig"}, fake.values)
+	})
+
+	t.Run("should ignore missing secret on delete", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(&fakeKeyVault{})
+		defer server.Close()
+
+		store := newStore(server.URL, server.Client())
+
+		// when
+		err := store.Delete(context.Background(), cluster)
+
+		// then
+		require.NoError(t, err)
+	})
+}
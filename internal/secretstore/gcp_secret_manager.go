@@ -0,0 +1,279 @@
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+	gcpCloudPlatformScope    = "https://www.googleapis.com/auth/cloud-platform"
+	gcpVersionStateEnabled   = "ENABLED"
+	// gcpClusterLabel records the cluster of the secret, hashed, the label values can't hold its namespace, and name
+	gcpClusterLabel          = "gardener-cluster"
+	gcpClusterLabelHashBytes = 20
+)
+
+// GCPSecretManagerStore writes kubeconfigs to Google Secret Manager using its REST API. Every rotation adds a new version of
+// the secret, the superseded versions are disabled once the grace period has passed, at the next rotation.
+type GCPSecretManagerStore struct {
+	httpClient  *http.Client
+	endpoint    string
+	gracePeriod time.Duration
+	now         func() time.Time
+}
+
+// NewGCPSecretManagerStore creates the store authenticating with the application default credentials, e.g. the workload identity
+// of the pod.
+func NewGCPSecretManagerStore(ctx context.Context, gracePeriod time.Duration) (*GCPSecretManagerStore, error) {
+	httpClient, err := google.DefaultClient(ctx, gcpCloudPlatformScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Google application default credentials")
+	}
+
+	return &GCPSecretManagerStore{
+		httpClient:  httpClient,
+		endpoint:    gcpSecretManagerEndpoint,
+		gracePeriod: gracePeriod,
+		now:         time.Now,
+	}, nil
+}
+
+type gcpSecretVersion struct {
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"createTime"`
+	State      string    `json:"state"`
+}
+
+func (store *GCPSecretManagerStore) Location(cluster *imv1.GardenerCluster) string {
+	target := cluster.Spec.Kubeconfig.ExternalStores.GCPSecretManager
+
+	return fmt.Sprintf("projects/%s/secrets/%s", target.Project, target.SecretID)
+}
+
+// Write adds a new version of the secret, an existing secret must be labelled as managed by the controller.
+func (store *GCPSecretManagerStore) Write(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, error) {
+	secretName := store.Location(cluster)
+
+	found, err := store.verifyManaged(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		err = store.createSecret(ctx, cluster)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create GCP secret %s", secretName)
+		}
+	}
+
+	version, err := store.addVersion(ctx, secretName, kubeconfig)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write kubeconfig to GCP secret %s", secretName)
+	}
+
+	err = store.disableSupersededVersions(ctx, secretName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to disable superseded versions of GCP secret %s", secretName)
+	}
+
+	return path.Base(version.Name), nil
+}
+
+// Delete removes the secret with all its versions, only when it is labelled as managed by the controller.
+func (store *GCPSecretManagerStore) Delete(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	secretName := store.Location(cluster)
+
+	found, err := store.verifyManaged(ctx, cluster)
+	if err != nil || !found {
+		return err
+	}
+
+	err = store.do(ctx, http.MethodDelete, "/v1/"+secretName, nil, nil)
+	if err != nil && !isGCPNotFound(err) {
+		return errors.Wrapf(err, "failed to delete GCP secret %s", secretName)
+	}
+
+	return nil
+}
+
+// verifyManaged reads the labels of the secret, and returns whether it exists. An existing secret which is not labelled
+// as managed for the cluster is an error.
+func (store *GCPSecretManagerStore) verifyManaged(ctx context.Context, cluster *imv1.GardenerCluster) (bool, error) {
+	secretName := store.Location(cluster)
+
+	var secret struct {
+		Labels map[string]string `json:"labels"`
+	}
+
+	err := store.do(ctx, http.MethodGet, "/v1/"+secretName, nil, &secret)
+	if isGCPNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get GCP secret %s", secretName)
+	}
+
+	return true, verifyManaged(secretName, secret.Labels, gcpClusterLabel, gcpClusterLabelValue(cluster))
+}
+
+func (store *GCPSecretManagerStore) createSecret(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	target := cluster.Spec.Kubeconfig.ExternalStores.GCPSecretManager
+
+	body := map[string]any{
+		"replication": map[string]any{"automatic": map[string]any{}},
+		"labels": map[string]string{
+			managedByTag:    managedByTagValue,
+			gcpClusterLabel: gcpClusterLabelValue(cluster),
+		},
+	}
+
+	return store.do(ctx, http.MethodPost, fmt.Sprintf("/v1/projects/%s/secrets?secretId=%s", target.Project, url.QueryEscape(target.SecretID)), body, nil)
+}
+
+func (store *GCPSecretManagerStore) addVersion(ctx context.Context, secretName, kubeconfig string) (gcpSecretVersion, error) {
+	body := map[string]any{
+		"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(kubeconfig))},
+	}
+
+	var version gcpSecretVersion
+	err := store.do(ctx, http.MethodPost, fmt.Sprintf("/v1/%s:addVersion", secretName), body, &version)
+
+	return version, err
+}
+
+// disableSupersededVersions disables the enabled versions which have been superseded by a newer version for longer than the grace period.
+func (store *GCPSecretManagerStore) disableSupersededVersions(ctx context.Context, secretName string) error {
+	versions, err := store.listVersions(ctx, secretName)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].CreateTime.After(versions[j].CreateTime)
+	})
+
+	for i := 1; i < len(versions); i++ {
+		supersededAt := versions[i-1].CreateTime
+		if versions[i].State != gcpVersionStateEnabled || store.now().Sub(supersededAt) < store.gracePeriod {
+			continue
+		}
+
+		err = store.do(ctx, http.MethodPost, fmt.Sprintf("/v1/%s:disable", versions[i].Name), map[string]any{}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (store *GCPSecretManagerStore) listVersions(ctx context.Context, secretName string) ([]gcpSecretVersion, error) {
+	var versions []gcpSecretVersion
+	pageToken := ""
+
+	for {
+		var response struct {
+			Versions      []gcpSecretVersion `json:"versions"`
+			NextPageToken string             `json:"nextPageToken"`
+		}
+
+		err := store.do(ctx, http.MethodGet, fmt.Sprintf("/v1/%s/versions?pageToken=%s", secretName, url.QueryEscape(pageToken)), nil, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, response.Versions...)
+
+		if response.NextPageToken == "" {
+			return versions, nil
+		}
+
+		pageToken = response.NextPageToken
+	}
+}
+
+// gcpClusterLabelValue identifies the cluster within the limits of the label values, lowercase hexadecimal digits, at
+// most 63 characters.
+func gcpClusterLabelValue(cluster *imv1.GardenerCluster) string {
+	hash := sha256.Sum256([]byte(clusterTagValue(cluster)))
+
+	return hex.EncodeToString(hash[:gcpClusterLabelHashBytes])
+}
+
+type gcpError struct {
+	statusCode int
+	message    string
+}
+
+func (err gcpError) Error() string {
+	return fmt.Sprintf("Secret Manager responded with status %d: %s", err.statusCode, err.message)
+}
+
+func isGCPNotFound(err error) bool {
+	var apiErr gcpError
+
+	return errors.As(err, &apiErr) && apiErr.statusCode == http.StatusNotFound
+}
+
+func (store *GCPSecretManagerStore) do(ctx context.Context, method, requestPath string, body any, response any) error {
+	var requestBody io.Reader
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		requestBody = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, store.endpoint+requestPath, requestBody)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := store.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode >= http.StatusBadRequest {
+		var errorResponse struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+
+		_ = json.NewDecoder(httpResponse.Body).Decode(&errorResponse)
+
+		return gcpError{statusCode: httpResponse.StatusCode, message: errorResponse.Error.Message}
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.NewDecoder(httpResponse.Body).Decode(response)
+}
@@ -0,0 +1,198 @@
+package secretstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeSecretManager serves the subset of the Secret Manager REST API used by the store.
+type fakeSecretManager struct {
+	secretExists bool
+	labels       map[string]string
+	deleted      bool
+	versions     []gcpSecretVersion
+	payloads     []string
+	disabled     []string
+	now          time.Time
+}
+
+func (fake *fakeSecretManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const secretName = "projects/project/secrets/cluster"
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/projects/project/secrets":
+		var body struct {
+			Labels map[string]string `json:"labels"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		fake.secretExists, fake.labels = true, body.Labels
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/"+secretName:
+		if !fake.secretExists {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"message":"secret not found"}}`))
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": secretName, "labels": fake.labels})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/"+secretName+":addVersion":
+		if !fake.secretExists {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"message":"secret not found"}}`))
+
+			return
+		}
+
+		var body struct {
+			Payload struct {
+				Data string `json:"data"`
+			} `json:"payload"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		payload, _ := base64.StdEncoding.DecodeString(body.Payload.Data)
+		fake.payloads = append(fake.payloads, string(payload))
+
+		version := gcpSecretVersion{
+			Name:       fmt.Sprintf("%s/versions/%d", secretName, len(fake.versions)+1),
+			CreateTime: fake.now,
+			State:      gcpVersionStateEnabled,
+		}
+		fake.versions = append(fake.versions, version)
+		_ = json.NewEncoder(w).Encode(version)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/"+secretName+"/versions":
+		_ = json.NewEncoder(w).Encode(map[string]any{"versions": fake.versions})
+	case r.Method == http.MethodPost:
+		fake.disabled = append(fake.disabled, r.URL.Path)
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodDelete:
+		fake.deleted = true
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func TestGCPSecretManagerStore(t *testing.T) {
+	t.Run("should create secret, and disable superseded versions after grace period", func(t *testing.T) {
+		// given
+		now := time.Now()
+		fake := &fakeSecretManager{now: now.Add(-3 * time.Hour)}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		store := &GCPSecretManagerStore{
+			httpClient:  server.Client(),
+			endpoint:    server.URL,
+			gracePeriod: time.Hour,
+			now:         func() time.Time { return now },
+		}
+		cluster := fixClusterWithGCPSecret()
+
+		// when
+		firstVersion, err := store.Write(context.Background(), cluster, "kubeconfig1")
+		require.NoError(t, err)
+
+		fake.now = now.Add(-2 * time.Hour)
+		_, err = store.Write(context.Background(), cluster, "kubeconfig2")
+		require.NoError(t, err)
+
+		fake.now = now
+		lastVersion, err := store.Write(context.Background(), cluster, "kubeconfig3")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "1", firstVersion)
+		assert.Equal(t, "3", lastVersion)
+		assert.Equal(t, []string{"kubeconfig1", "kubeconfig2", "kubeconfig3"}, fake.payloads)
+		assert.Contains(t, fake.disabled, "/v1/projects/project/secrets/cluster/versions/1:disable")
+		assert.NotContains(t, fake.disabled, "/v1/projects/project/secrets/cluster/versions/2:disable")
+		assert.Equal(t, fixGCPManagedLabels("kcp-system", "cluster"), fake.labels)
+	})
+
+	t.Run("should refuse to write, and delete a secret not created for the cluster", func(t *testing.T) {
+		for name, labels := range map[string]map[string]string{
+			"unmanaged":               {"owner": "team-a"},
+			"other cluster":           fixGCPManagedLabels("kcp-system", "other"),
+			"cluster of other tenant": fixGCPManagedLabels("tenant", "cluster"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				fake := &fakeSecretManager{secretExists: true, labels: labels}
+				server := httptest.NewServer(fake)
+				defer server.Close()
+
+				store := &GCPSecretManagerStore{httpClient: server.Client(), endpoint: server.URL, now: time.Now}
+
+				// when
+				_, writeErr := store.Write(context.Background(), fixClusterWithGCPSecret(), "kubeconfig")
+				deleteErr := store.Delete(context.Background(), fixClusterWithGCPSecret())
+
+				// then
+				assert.Error(t, writeErr)
+				assert.Error(t, deleteErr)
+				assert.Empty(t, fake.payloads)
+				assert.Empty(t, fake.disabled)
+				assert.False(t, fake.deleted)
+			})
+		}
+	})
+
+	t.Run("should delete secret managed by the controller", func(t *testing.T) {
+		// given
+		fake := &fakeSecretManager{secretExists: true, labels: fixGCPManagedLabels("kcp-system", "cluster")}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		store := &GCPSecretManagerStore{httpClient: server.Client(), endpoint: server.URL, now: time.Now}
+
+		// when
+		err := store.Delete(context.Background(), fixClusterWithGCPSecret())
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, fake.deleted)
+	})
+
+	t.Run("should ignore missing secret on delete", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(&fakeSecretManager{})
+		defer server.Close()
+
+		store := &GCPSecretManagerStore{httpClient: server.Client(), endpoint: server.URL, now: time.Now}
+
+		// when
+		err := store.Delete(context.Background(), fixClusterWithGCPSecret())
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func fixClusterWithGCPSecret() *imv1.GardenerCluster {
+	return &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+		Spec: imv1.GardenerClusterSpec{
+			Kubeconfig: imv1.Kubeconfig{
+				ExternalStores: &imv1.ExternalStores{
+					GCPSecretManager: &imv1.GCPSecretManagerTarget{Project: "project", SecretID: "cluster"},
+				},
+			},
+		},
+	}
+}
+
+func fixGCPManagedLabels(namespace, name string) map[string]string {
+	cluster := &imv1.GardenerCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+
+	return map[string]string{managedByTag: managedByTagValue, gcpClusterLabel: gcpClusterLabelValue(cluster)}
+}
@@ -0,0 +1,144 @@
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+//nolint:gochecknoglobals
+var pushSecretGVK = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1alpha1", Kind: "PushSecret"}
+
+const (
+	pushSecretSourceKey   = "config"
+	pushSecretDefaultKind = "SecretStore"
+	pushSecretNameSuffix  = "kubeconfig-push"
+)
+
+// PushSecretStore hands the kubeconfigs over to the External Secrets Operator. For every cluster it maintains
+// a PushSecret together with the source secret it pushes, so distribution is independent of the kubeconfig secrets,
+// and works in the immutable mode as well. The PushSecret owns the source secret, and deletes the provider secret when deleted.
+// The operator, and its CRDs are not required unless a GardenerCluster requests the store.
+type PushSecretStore struct {
+	client client.Client
+}
+
+func NewPushSecretStore(k8sClient client.Client) *PushSecretStore {
+	return &PushSecretStore{client: k8sClient}
+}
+
+func (store *PushSecretStore) Location(cluster *imv1.GardenerCluster) string {
+	target := cluster.Spec.Kubeconfig.ExternalStores.PushSecret
+
+	location := fmt.Sprintf("%s/%s/%s", secretStoreKind(target), target.SecretStoreRef.Name, target.RemoteKey)
+	if target.Property != "" {
+		location = fmt.Sprintf("%s#%s", location, target.Property)
+	}
+
+	return location
+}
+
+// Write updates the PushSecret, and its source secret. It returns the resource version of the source secret,
+// the operator pushes it asynchronously.
+func (store *PushSecretStore) Write(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, error) {
+	pushSecret := newPushSecret(cluster)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, store.client, pushSecret, func() error {
+		return unstructured.SetNestedField(pushSecret.Object, pushSecretSpec(cluster), "spec")
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write PushSecret %s/%s", pushSecret.GetNamespace(), pushSecret.GetName())
+	}
+
+	source := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pushSecret.GetName(),
+			Namespace: pushSecret.GetNamespace(),
+		},
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, store.client, &source, func() error {
+		source.Labels = pushSecret.GetLabels()
+		source.Data = map[string][]byte{pushSecretSourceKey: []byte(kubeconfig)}
+		source.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(pushSecret, pushSecretGVK)}
+
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write PushSecret source secret %s/%s", source.Namespace, source.Name)
+	}
+
+	return source.ResourceVersion, nil
+}
+
+// Delete removes the PushSecret, the operator deletes the provider secret, and the source secret is garbage collected.
+func (store *PushSecretStore) Delete(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	pushSecret := newPushSecret(cluster)
+
+	err := store.client.Delete(ctx, pushSecret)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete PushSecret %s/%s", pushSecret.GetNamespace(), pushSecret.GetName())
+	}
+
+	return nil
+}
+
+func newPushSecret(cluster *imv1.GardenerCluster) *unstructured.Unstructured {
+	pushSecret := &unstructured.Unstructured{}
+	pushSecret.SetGroupVersionKind(pushSecretGVK)
+	pushSecret.SetName(fmt.Sprintf("%s-%s", cluster.Name, pushSecretNameSuffix))
+	pushSecret.SetNamespace(cluster.Namespace)
+	pushSecret.SetLabels(map[string]string{
+		"app.kubernetes.io/managed-by":          managedByTagValue,
+		"operator.kyma-project.io/cluster-name": cluster.Name,
+	})
+
+	return pushSecret
+}
+
+func pushSecretSpec(cluster *imv1.GardenerCluster) map[string]any {
+	target := cluster.Spec.Kubeconfig.ExternalStores.PushSecret
+
+	remoteRef := map[string]any{"remoteKey": target.RemoteKey}
+	if target.Property != "" {
+		remoteRef["property"] = target.Property
+	}
+
+	return map[string]any{
+		"deletionPolicy": "Delete",
+		"secretStoreRefs": []any{
+			map[string]any{
+				"name": target.SecretStoreRef.Name,
+				"kind": secretStoreKind(target),
+			},
+		},
+		"selector": map[string]any{
+			"secret": map[string]any{"name": fmt.Sprintf("%s-%s", cluster.Name, pushSecretNameSuffix)},
+		},
+		"data": []any{
+			map[string]any{
+				"match": map[string]any{
+					"secretKey": pushSecretSourceKey,
+					"remoteRef": remoteRef,
+				},
+			},
+		},
+	}
+}
+
+func secretStoreKind(target *imv1.PushSecretTarget) string {
+	if target.SecretStoreRef.Kind == "" {
+		return pushSecretDefaultKind
+	}
+
+	return target.SecretStoreRef.Kind
+}
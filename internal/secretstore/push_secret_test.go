@@ -0,0 +1,86 @@
+package secretstore
+
+import (
+	"context"
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPushSecretStore(t *testing.T) {
+	newCluster := func() *imv1.GardenerCluster {
+		return &imv1.GardenerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+			Spec: imv1.GardenerClusterSpec{
+				Kubeconfig: imv1.Kubeconfig{
+					ExternalStores: &imv1.ExternalStores{
+						PushSecret: &imv1.PushSecretTarget{
+							SecretStoreRef: imv1.SecretStoreRef{Name: "vault", Kind: "ClusterSecretStore"},
+							RemoteKey:      "clusters/cluster",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	newScheme := func() *runtime.Scheme {
+		scheme := runtime.NewScheme()
+		_ = corev1.AddToScheme(scheme)
+		scheme.AddKnownTypeWithName(pushSecretGVK, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(pushSecretGVK.GroupVersion().WithKind("PushSecretList"), &unstructured.UnstructuredList{})
+
+		return scheme
+	}
+
+	t.Run("should create PushSecret with its source secret", func(t *testing.T) {
+		// given
+		k8sClient := fake.NewClientBuilder().WithScheme(newScheme()).Build()
+		store := NewPushSecretStore(k8sClient)
+		cluster := newCluster()
+
+		// when
+		_, err := store.Write(context.Background(), cluster, "kubeconfig1")
+		require.NoError(t, err)
+
+		_, err = store.Write(context.Background(), cluster, "kubeconfig2")
+		require.NoError(t, err)
+
+		// then
+		key := types.NamespacedName{Name: "cluster-kubeconfig-push", Namespace: "kcp-system"}
+
+		pushSecret := &unstructured.Unstructured{}
+		pushSecret.SetGroupVersionKind(pushSecretGVK)
+		require.NoError(t, k8sClient.Get(context.Background(), key, pushSecret))
+
+		data, _, _ := unstructured.NestedSlice(pushSecret.Object, "spec", "data")
+		require.Len(t, data, 1)
+		remoteKey, _, _ := unstructured.NestedString(data[0].(map[string]any), "match", "remoteRef", "remoteKey")
+		assert.Equal(t, "clusters/cluster", remoteKey)
+
+		var source corev1.Secret
+		require.NoError(t, k8sClient.Get(context.Background(), key, &source))
+		assert.Equal(t, "kubeconfig2", string(source.Data[pushSecretSourceKey]))
+		assert.Equal(t, "PushSecret", source.OwnerReferences[0].Kind)
+		assert.Equal(t, "ClusterSecretStore/vault/clusters/cluster", store.Location(cluster))
+	})
+
+	t.Run("should ignore missing PushSecret on delete", func(t *testing.T) {
+		// given
+		store := NewPushSecretStore(fake.NewClientBuilder().WithScheme(newScheme()).Build())
+
+		// when
+		err := store.Delete(context.Background(), newCluster())
+
+		// then
+		require.NoError(t, err)
+	})
+}
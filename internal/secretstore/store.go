@@ -0,0 +1,47 @@
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	managedByTag      = "managed-by"
+	managedByTagValue = "infrastructure-manager"
+	clusterTag        = "kyma-project.io/gardener-cluster"
+)
+
+// Store writes kubeconfigs to a secret management system outside of the cluster.
+type Store interface {
+	// Location identifies the entry of the cluster in the store.
+	Location(cluster *imv1.GardenerCluster) string
+
+	// Write stores the kubeconfig as the current value of the entry, and returns its version, empty if the store keeps no versions.
+	Write(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, error)
+
+	// Delete removes the entry with all its versions, a missing entry is not an error.
+	Delete(ctx context.Context, cluster *imv1.GardenerCluster) error
+}
+
+// clusterTagValue identifies the GardenerCluster an entry of a store has been created for.
+func clusterTagValue(cluster *imv1.GardenerCluster) string {
+	return fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name)
+}
+
+// verifyManaged refuses to write, or delete an entry of a store the controller hasn't created for the cluster, so that a
+// GardenerCluster can't overwrite the secrets of other clusters, or of other systems sharing the store. The tags are
+// the metadata of the entry, the store records the cluster in the given tag, with the value it has for the cluster.
+func verifyManaged(location string, tags map[string]string, clusterTagKey, expectedCluster string) error {
+	if tags[managedByTag] != managedByTagValue {
+		return errors.Errorf("%s is not managed by the infrastructure manager", location)
+	}
+
+	if tags[clusterTagKey] != expectedCluster {
+		return errors.Errorf("%s is managed for another GardenerCluster", location)
+	}
+
+	return nil
+}
@@ -0,0 +1,361 @@
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultVaultKubernetesAuthMount = "kubernetes"
+	DefaultServiceAccountTokenPath  = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec
+	DefaultVaultMount               = "secret"
+	DefaultVaultPathPrefix          = "infrastructure-manager"
+
+	vaultTokenHeader = "X-Vault-Token"
+
+	// the token is renewed before its lease expires, so that requests in flight do not fail
+	vaultTokenRenewalRatio = 0.8
+)
+
+// VaultConfig defines the Vault server, and how the controller authenticates against it.
+type VaultConfig struct {
+	Address string
+
+	// Mount is the only KV version 2 secrets engine the clusters may write to.
+	Mount string
+
+	// PathPrefix confines the secrets of the clusters, the secret of a cluster is written below <prefix>/<namespace>/<name>/.
+	PathPrefix string
+
+	// Token authenticates the controller directly, when empty the controller logs in with the Kubernetes auth method.
+	Token string
+
+	KubernetesAuthMount     string
+	KubernetesAuthRole      string
+	ServiceAccountTokenPath string
+}
+
+// VaultStore writes kubeconfigs to Vault KV version 2 secrets engines using the Vault HTTP API.
+type VaultStore struct {
+	config     VaultConfig
+	httpClient *http.Client
+
+	mu              sync.Mutex
+	token           string
+	tokenExpiration time.Time
+}
+
+func NewVaultStore(config VaultConfig, httpClient *http.Client) *VaultStore {
+	if config.Mount == "" {
+		config.Mount = DefaultVaultMount
+	}
+
+	if config.PathPrefix == "" {
+		config.PathPrefix = DefaultVaultPathPrefix
+	}
+
+	return &VaultStore{
+		config:     config,
+		httpClient: httpClient,
+		token:      config.Token,
+	}
+}
+
+func (store *VaultStore) Location(cluster *imv1.GardenerCluster) string {
+	target := cluster.Spec.Kubeconfig.ExternalStores.Vault
+
+	return fmt.Sprintf("%s/%s/%s/%s/%s#%s", vaultMount(target, store.config), strings.Trim(store.config.PathPrefix, "/"),
+		cluster.Namespace, cluster.Name, strings.Trim(target.Path, "/"), vaultKey(target))
+}
+
+func (store *VaultStore) Write(ctx context.Context, cluster *imv1.GardenerCluster, kubeconfig string) (string, error) {
+	target := cluster.Spec.Kubeconfig.ExternalStores.Vault
+
+	secretPath, err := store.secretPath(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, found, err := store.readCustomMetadata(ctx, secretPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read metadata of Vault secret %s", store.Location(cluster))
+	}
+
+	if found {
+		err = verifyManaged(store.Location(cluster), metadata, clusterTag, clusterTagValue(cluster))
+		if err != nil {
+			return "", err
+		}
+	} else {
+		// the metadata is written before the data, so that the secret is never left without the marker of the controller
+		body := map[string]any{
+			"custom_metadata": map[string]string{
+				managedByTag: managedByTagValue,
+				clusterTag:   clusterTagValue(cluster),
+			},
+		}
+
+		_, err = store.do(ctx, http.MethodPost, secretPath.endpoint("metadata"), body, nil)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to write metadata of Vault secret %s", store.Location(cluster))
+		}
+	}
+
+	body := map[string]any{
+		"data": map[string]string{vaultKey(target): kubeconfig},
+	}
+
+	var response struct {
+		Data struct {
+			Version int `json:"version"`
+		} `json:"data"`
+	}
+
+	_, err = store.do(ctx, http.MethodPost, secretPath.endpoint("data"), body, &response)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write kubeconfig to Vault secret %s", store.Location(cluster))
+	}
+
+	return strconv.Itoa(response.Data.Version), nil
+}
+
+// Delete removes the secret with all its versions, only when its custom metadata marks it as created for the cluster.
+func (store *VaultStore) Delete(ctx context.Context, cluster *imv1.GardenerCluster) error {
+	secretPath, err := store.secretPath(cluster)
+	if err != nil {
+		return err
+	}
+
+	metadata, found, err := store.readCustomMetadata(ctx, secretPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read metadata of Vault secret %s", store.Location(cluster))
+	}
+
+	if !found {
+		return nil
+	}
+
+	err = verifyManaged(store.Location(cluster), metadata, clusterTag, clusterTagValue(cluster))
+	if err != nil {
+		return err
+	}
+
+	_, err = store.do(ctx, http.MethodDelete, secretPath.endpoint("metadata"), nil, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete Vault secret %s", store.Location(cluster))
+	}
+
+	return nil
+}
+
+// vaultSecretPath is the path of a secret within the mount of the controller.
+type vaultSecretPath struct {
+	mount string
+	path  string
+}
+
+func (secretPath vaultSecretPath) endpoint(endpoint string) string {
+	return fmt.Sprintf("/v1/%s/%s/%s", secretPath.mount, endpoint, secretPath.path)
+}
+
+// secretPath confines the secret of the cluster to the mount of the controller, and to the prefix of the cluster, the
+// path in the spec is relative to it.
+func (store *VaultStore) secretPath(cluster *imv1.GardenerCluster) (vaultSecretPath, error) {
+	target := cluster.Spec.Kubeconfig.ExternalStores.Vault
+
+	mount := vaultMount(target, store.config)
+	if mount != strings.Trim(store.config.Mount, "/") {
+		return vaultSecretPath{}, errors.Errorf("Vault mount %s is not allowed, the kubeconfigs are written to %s", mount, store.config.Mount)
+	}
+
+	relativePath := strings.Trim(target.Path, "/")
+	for _, segment := range strings.Split(relativePath, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return vaultSecretPath{}, errors.Errorf("invalid Vault secret path %s, empty, and relative segments are not allowed", target.Path)
+		}
+	}
+
+	return vaultSecretPath{
+		mount: mount,
+		path:  strings.Join([]string{strings.Trim(store.config.PathPrefix, "/"), cluster.Namespace, cluster.Name, relativePath}, "/"),
+	}, nil
+}
+
+// readCustomMetadata returns the custom metadata of the secret, and whether the secret exists.
+func (store *VaultStore) readCustomMetadata(ctx context.Context, secretPath vaultSecretPath) (map[string]string, bool, error) {
+	var response struct {
+		Data struct {
+			CustomMetadata map[string]string `json:"custom_metadata"`
+		} `json:"data"`
+	}
+
+	statusCode, err := store.do(ctx, http.MethodGet, secretPath.endpoint("metadata"), nil, &response)
+	if statusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return response.Data.CustomMetadata, true, nil
+}
+
+func vaultMount(target *imv1.VaultTarget, config VaultConfig) string {
+	if target.Mount == "" {
+		return strings.Trim(config.Mount, "/")
+	}
+
+	return strings.Trim(target.Mount, "/")
+}
+
+func vaultKey(target *imv1.VaultTarget) string {
+	if target.Key == "" {
+		return imv1.DefaultKubeconfigSecretKey
+	}
+
+	return target.Key
+}
+
+// do sends an authenticated request, and logs in again once when the token has been rejected. It returns the status code
+// of the response, so that a missing secret can be told apart from the other errors.
+func (store *VaultStore) do(ctx context.Context, method, path string, body any, response any) (int, error) {
+	token, err := store.getToken(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	statusCode, err := store.send(ctx, method, path, token, body, response)
+	if statusCode == http.StatusForbidden && store.config.Token == "" {
+		store.invalidateToken()
+
+		token, err = store.getToken(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		statusCode, err = store.send(ctx, method, path, token, body, response)
+	}
+
+	return statusCode, err
+}
+
+func (store *VaultStore) send(ctx context.Context, method, path, token string, body any, response any) (int, error) {
+	var requestBody io.Reader
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+
+		requestBody = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(store.config.Address, "/")+path, requestBody)
+	if err != nil {
+		return 0, err
+	}
+
+	if token != "" {
+		request.Header.Set(vaultTokenHeader, token)
+	}
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := store.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode == http.StatusNotFound && method == http.MethodDelete {
+		return httpResponse.StatusCode, nil
+	}
+
+	if httpResponse.StatusCode >= http.StatusBadRequest {
+		return httpResponse.StatusCode, vaultError(httpResponse)
+	}
+
+	if response == nil || httpResponse.StatusCode == http.StatusNoContent {
+		return httpResponse.StatusCode, nil
+	}
+
+	return httpResponse.StatusCode, json.NewDecoder(httpResponse.Body).Decode(response)
+}
+
+func vaultError(httpResponse *http.Response) error {
+	var response struct {
+		Errors []string `json:"errors"`
+	}
+
+	_ = json.NewDecoder(httpResponse.Body).Decode(&response)
+
+	return errors.Errorf("Vault responded with status %d: %s", httpResponse.StatusCode, strings.Join(response.Errors, ", "))
+}
+
+func (store *VaultStore) getToken(ctx context.Context) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.config.Token != "" || (store.token != "" && time.Now().Before(store.tokenExpiration)) {
+		return store.token, nil
+	}
+
+	token, leaseDuration, err := store.login(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to log in to Vault")
+	}
+
+	store.token = token
+	store.tokenExpiration = time.Now().Add(time.Duration(vaultTokenRenewalRatio * float64(leaseDuration)))
+
+	return token, nil
+}
+
+func (store *VaultStore) invalidateToken() {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.token = ""
+}
+
+func (store *VaultStore) login(ctx context.Context) (string, time.Duration, error) {
+	jwt, err := os.ReadFile(store.config.ServiceAccountTokenPath)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "failed to read service account token")
+	}
+
+	body := map[string]string{
+		"role": store.config.KubernetesAuthRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}
+
+	var response struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+
+	_, err = store.send(ctx, http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", strings.Trim(store.config.KubernetesAuthMount, "/")), "", body, &response)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return response.Auth.ClientToken, time.Duration(response.Auth.LeaseDuration) * time.Second, nil
+}
@@ -0,0 +1,274 @@
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVaultStore(t *testing.T) {
+	t.Run("should write kubeconfig with static token", func(t *testing.T) {
+		// given
+		var written map[string]map[string]string
+		var metadata map[string]map[string]string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "static-token", r.Header.Get(vaultTokenHeader))
+
+			switch {
+			case r.Method == http.MethodGet:
+				assert.Equal(t, "/v1/kv/metadata/infrastructure-manager/kcp-system/cluster/kyma/cluster", r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			case r.URL.Path == "/v1/kv/metadata/infrastructure-manager/kcp-system/cluster/kyma/cluster":
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&metadata))
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/v1/kv/data/infrastructure-manager/kcp-system/cluster/kyma/cluster", r.URL.Path)
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&written))
+
+				_, _ = w.Write([]byte(`{"data":{"version":3}}`))
+			}
+		}))
+		defer server.Close()
+
+		store := NewVaultStore(VaultConfig{Address: server.URL, Mount: "kv", Token: "static-token"}, server.Client())
+
+		// when
+		version, err := store.Write(context.Background(), fixClusterWithVault("kv", "kyma/cluster"), "kubeconfig")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "3", version)
+		assert.Equal(t, "kubeconfig", written["data"]["config"])
+		assert.Equal(t, map[string]string{managedByTag: managedByTagValue, clusterTag: "kcp-system/cluster"}, metadata["custom_metadata"])
+	})
+
+	t.Run("should update the secret created for the cluster", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"data":{"custom_metadata":{"managed-by":"infrastructure-manager","kyma-project.io/gardener-cluster":"kcp-system/cluster"}}}`))
+
+				return
+			}
+
+			assert.Equal(t, "/v1/secret/data/infrastructure-manager/kcp-system/cluster/kyma/cluster", r.URL.Path)
+			_, _ = w.Write([]byte(`{"data":{"version":4}}`))
+		}))
+		defer server.Close()
+
+		store := NewVaultStore(VaultConfig{Address: server.URL, Token: "static-token"}, server.Client())
+
+		// when
+		version, err := store.Write(context.Background(), fixClusterWithVault("", "kyma/cluster"), "kubeconfig")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "4", version)
+	})
+
+	t.Run("should refuse to write a secret not created for the cluster", func(t *testing.T) {
+		for name, customMetadata := range map[string]string{
+			"unmanaged":     `null`,
+			"other cluster": `{"managed-by":"infrastructure-manager","kyma-project.io/gardener-cluster":"kcp-system/other"}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, http.MethodGet, r.Method)
+					_, _ = w.Write([]byte(`{"data":{"custom_metadata":` + customMetadata + `}}`))
+				}))
+				defer server.Close()
+
+				store := NewVaultStore(VaultConfig{Address: server.URL, Token: "static-token"}, server.Client())
+
+				// when
+				_, err := store.Write(context.Background(), fixClusterWithVault("", "kyma/cluster"), "kubeconfig")
+
+				// then
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("should refuse paths outside of the cluster, and other mounts", func(t *testing.T) {
+		for name, cluster := range map[string]*imv1.GardenerCluster{
+			"parent segment":  fixClusterWithVault("", "../other/cluster"),
+			"current segment": fixClusterWithVault("", "kyma/./cluster"),
+			"empty segment":   fixClusterWithVault("", "kyma//cluster"),
+			"empty path":      fixClusterWithVault("", ""),
+			"other mount":     fixClusterWithVault("kv", "kyma/cluster"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}))
+				defer server.Close()
+
+				store := NewVaultStore(VaultConfig{Address: server.URL, Token: "static-token"}, server.Client())
+
+				// when
+				_, writeErr := store.Write(context.Background(), cluster, "kubeconfig")
+				deleteErr := store.Delete(context.Background(), cluster)
+
+				// then
+				assert.Error(t, writeErr)
+				assert.Error(t, deleteErr)
+			})
+		}
+	})
+
+	t.Run("should log in with Kubernetes auth method", func(t *testing.T) {
+		// given
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenPath, []byte("service-account-jwt\n"), 0600))
+
+		logins := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/auth/kubernetes/login" {
+				var login map[string]string
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&login))
+				assert.Equal(t, "service-account-jwt", login["jwt"])
+				assert.Equal(t, "infrastructure-manager", login["role"])
+
+				logins++
+				_, _ = w.Write([]byte(`{"auth":{"client_token":"login-token","lease_duration":3600}}`))
+
+				return
+			}
+
+			assert.Equal(t, "login-token", r.Header.Get(vaultTokenHeader))
+			_, _ = w.Write([]byte(`{"data":{"version":1,"custom_metadata":{"managed-by":"infrastructure-manager","kyma-project.io/gardener-cluster":"kcp-system/cluster"}}}`))
+		}))
+		defer server.Close()
+
+		store := NewVaultStore(VaultConfig{
+			Address:                 server.URL,
+			KubernetesAuthMount:     DefaultVaultKubernetesAuthMount,
+			KubernetesAuthRole:      "infrastructure-manager",
+			ServiceAccountTokenPath: tokenPath,
+		}, server.Client())
+
+		// when
+		_, err := store.Write(context.Background(), fixClusterWithVault("", "kyma/cluster"), "kubeconfig")
+		require.NoError(t, err)
+		_, err = store.Write(context.Background(), fixClusterWithVault("", "kyma/cluster"), "kubeconfig")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, logins)
+	})
+
+	t.Run("should delete secret metadata of the secret created for the cluster", func(t *testing.T) {
+		// given
+		deleted := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/secret/metadata/infrastructure-manager/kcp-system/cluster/kyma/cluster", r.URL.Path)
+
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"data":{"custom_metadata":{"managed-by":"infrastructure-manager","kyma-project.io/gardener-cluster":"kcp-system/cluster"}}}`))
+
+				return
+			}
+
+			assert.Equal(t, http.MethodDelete, r.Method)
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		store := NewVaultStore(VaultConfig{Address: server.URL, Token: "static-token"}, server.Client())
+
+		// when
+		err := store.Delete(context.Background(), fixClusterWithVault("", "kyma/cluster"))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("should ignore missing secret on delete", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		store := NewVaultStore(VaultConfig{Address: server.URL, Token: "static-token"}, server.Client())
+
+		// when
+		err := store.Delete(context.Background(), fixClusterWithVault("", "kyma/cluster"))
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should refuse to delete a secret not created by the controller", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			_, _ = w.Write([]byte(`{"data":{"custom_metadata":{"owner":"team-a"}}}`))
+		}))
+		defer server.Close()
+
+		store := NewVaultStore(VaultConfig{Address: server.URL, Token: "static-token"}, server.Client())
+
+		// when
+		err := store.Delete(context.Background(), fixClusterWithVault("", "kyma/cluster"))
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not managed by the infrastructure manager")
+	})
+
+	t.Run("should return Vault errors", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+		}))
+		defer server.Close()
+
+		store := NewVaultStore(VaultConfig{Address: server.URL, Token: "static-token"}, server.Client())
+
+		// when
+		_, err := store.Write(context.Background(), fixClusterWithVault("", "kyma/cluster"), "kubeconfig")
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "permission denied")
+	})
+
+	t.Run("should describe location", func(t *testing.T) {
+		// given
+		store := NewVaultStore(VaultConfig{}, http.DefaultClient)
+
+		// then
+		assert.Equal(t, "secret/infrastructure-manager/kcp-system/cluster/kyma/cluster#config", store.Location(fixClusterWithVault("", "/kyma/cluster/")))
+	})
+}
+
+func fixClusterWithVault(mount, path string) *imv1.GardenerCluster {
+	return &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "kcp-system"},
+		Spec: imv1.GardenerClusterSpec{
+			Kubeconfig: imv1.Kubeconfig{
+				ExternalStores: &imv1.ExternalStores{
+					Vault: &imv1.VaultTarget{Mount: mount, Path: path},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,124 @@
+// Package sharding splits the GardenerClusters between the active replicas of the controller. The clusters are
+// assigned to the shards by rendezvous hashing of their UID, so changing the number of shards only moves the clusters
+// of the added, or removed shards.
+package sharding
+
+import (
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Environment variables the shard ID is read from, the pod name of a StatefulSet ends with the ordinal of the pod.
+const (
+	ShardIDEnv = "SHARD_ID"
+	PodNameEnv = "POD_NAME"
+)
+
+// Shard identifies the clusters reconciled by a replica. The zero value is the single shard owning all clusters.
+type Shard struct {
+	ID    int
+	Count int
+}
+
+func New(id, count int) (Shard, error) {
+	if count < 1 {
+		return Shard{}, errors.Errorf("invalid number of shards %d", count)
+	}
+
+	if id < 0 || id >= count {
+		return Shard{}, errors.Errorf("shard ID %d is not in the range of the %d shards", id, count)
+	}
+
+	return Shard{ID: id, Count: count}, nil
+}
+
+// Sharded reports whether the clusters are split between several shards.
+func (shard Shard) Sharded() bool {
+	return shard.Count > 1
+}
+
+// Primary reports whether the shard runs the work which isn't split between the shards, e.g. the controllers of the
+// other resources, it is the first shard.
+func (shard Shard) Primary() bool {
+	return shard.ID == 0
+}
+
+// Owns reports whether the object with the UID is reconciled by the shard.
+func (shard Shard) Owns(uid types.UID) bool {
+	if !shard.Sharded() {
+		return true
+	}
+
+	return Assign(uid, shard.Count) == shard.ID
+}
+
+// Assign returns the shard of the object with the UID, the shard with the highest weight for the UID.
+func Assign(uid types.UID, count int) int {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(uid))
+	uidHash := hash.Sum64()
+
+	assigned := 0
+	highestWeight := uint64(0)
+
+	for id := 0; id < count; id++ {
+		if weight := mix(uidHash ^ mix(uint64(id)+1)); id == 0 || weight > highestWeight {
+			assigned = id
+			highestWeight = weight
+		}
+	}
+
+	return assigned
+}
+
+// Constants of the SplitMix64 finalizer.
+const (
+	mixShift1      = 30
+	mixMultiplier1 = 0xbf58476d1ce4e5b9
+	mixShift2      = 27
+	mixMultiplier2 = 0x94d049bb133111eb
+	mixShift3      = 31
+)
+
+// mix is the finalizer of SplitMix64, it spreads the weights of the similar hashes of the shards evenly.
+func mix(value uint64) uint64 {
+	value ^= value >> mixShift1
+	value *= mixMultiplier1
+	value ^= value >> mixShift2
+	value *= mixMultiplier2
+	value ^= value >> mixShift3
+
+	return value
+}
+
+// IDFromEnvironment returns the shard ID set in the SHARD_ID environment variable, or the ordinal of the StatefulSet
+// pod in the POD_NAME environment variable.
+func IDFromEnvironment() (int, error) {
+	if value, found := os.LookupEnv(ShardIDEnv); found {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid shard ID %q in %s", value, ShardIDEnv)
+		}
+
+		return id, nil
+	}
+
+	podName := os.Getenv(PodNameEnv)
+
+	separator := strings.LastIndex(podName, "-")
+	if separator < 0 {
+		return 0, errors.Errorf("neither %s, nor the StatefulSet pod name in %s are set", ShardIDEnv, PodNameEnv)
+	}
+
+	id, err := strconv.Atoi(podName[separator+1:])
+	if err != nil {
+		return 0, errors.Wrapf(err, "pod name %q does not end with the ordinal of a StatefulSet pod", podName)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,118 @@
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestShard(t *testing.T) {
+	t.Run("should assign each object to exactly one shard", func(t *testing.T) {
+		// given
+		shards := make([]Shard, 3)
+		for id := range shards {
+			shard, err := New(id, len(shards))
+			require.NoError(t, err)
+
+			shards[id] = shard
+		}
+
+		// when
+		owners := map[int]int{}
+
+		for i := 0; i < 300; i++ {
+			uid := types.UID(fmt.Sprintf("uid-%d", i))
+
+			for _, shard := range shards {
+				if shard.Owns(uid) {
+					owners[i]++
+				}
+			}
+		}
+
+		// then
+		for i := 0; i < 300; i++ {
+			assert.Equal(t, 1, owners[i])
+		}
+	})
+
+	t.Run("should only move the objects of the added shard", func(t *testing.T) {
+		// given
+		moved := 0
+
+		// when
+		for i := 0; i < 1000; i++ {
+			uid := types.UID(fmt.Sprintf("uid-%d", i))
+			before, after := Assign(uid, 4), Assign(uid, 5)
+
+			if before != after {
+				assert.Equal(t, 4, after)
+				moved++
+			}
+		}
+
+		// then
+		assert.InDelta(t, 200, moved, 60)
+	})
+
+	t.Run("should own all objects without sharding", func(t *testing.T) {
+		assert.True(t, Shard{}.Owns("uid"))
+	})
+
+	t.Run("should make only the first shard primary", func(t *testing.T) {
+		first, err := New(0, 3)
+		require.NoError(t, err)
+		second, err := New(1, 3)
+		require.NoError(t, err)
+
+		assert.True(t, Shard{}.Primary())
+		assert.True(t, first.Primary())
+		assert.False(t, second.Primary())
+	})
+
+	t.Run("should reject a shard ID out of range", func(t *testing.T) {
+		_, err := New(3, 3)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestIDFromEnvironment(t *testing.T) {
+	t.Run("should read the shard ID", func(t *testing.T) {
+		// given
+		t.Setenv(ShardIDEnv, "2")
+
+		// when
+		id, err := IDFromEnvironment()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, id)
+	})
+
+	t.Run("should read the ordinal of the StatefulSet pod", func(t *testing.T) {
+		// given
+		t.Setenv(PodNameEnv, "infrastructure-manager-1")
+
+		// when
+		id, err := IDFromEnvironment()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, id)
+	})
+
+	t.Run("should fail without the shard ID", func(t *testing.T) {
+		// given
+		t.Setenv(PodNameEnv, "infrastructure-manager-7d9f8")
+
+		// when
+		_, err := IDFromEnvironment()
+
+		// then
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,98 @@
+package shoot
+
+import (
+	"strings"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// setExtensions applies the extensions listed in the Runtime, and removes the previously listed extensions which
+// are no longer listed. The other extensions of the shoot are kept.
+func setExtensions(shoot *gardener.Shoot, runtimeExtensions []imv1.RuntimeExtension) {
+	listed := sets.New[string]()
+
+	for _, runtimeExtension := range runtimeExtensions {
+		extension := gardener.Extension{Type: runtimeExtension.Type, ProviderConfig: runtimeExtension.ProviderConfig.DeepCopy()}
+
+		if runtimeExtension.Disabled != nil {
+			disabled := *runtimeExtension.Disabled
+			extension.Disabled = &disabled
+		}
+
+		shoot.Spec.Extensions = setExtension(shoot.Spec.Extensions, extension)
+		listed.Insert(runtimeExtension.Type)
+	}
+
+	for _, previous := range strings.Split(shoot.Annotations[ExtensionsAnnotation], ",") {
+		if previous != "" && !listed.Has(previous) {
+			shoot.Spec.Extensions = removeExtension(shoot.Spec.Extensions, previous)
+		}
+	}
+
+	if listed.Len() == 0 {
+		delete(shoot.Annotations, ExtensionsAnnotation)
+
+		return
+	}
+
+	if shoot.Annotations == nil {
+		shoot.Annotations = map[string]string{}
+	}
+
+	shoot.Annotations[ExtensionsAnnotation] = strings.Join(sets.List(listed), ",")
+}
+
+// setExtension replaces the extension of the same type in place, or appends it, the order of the other extensions
+// is kept. The provider config is kept when Gardener has only re-encoded it.
+func setExtension(existing []gardener.Extension, extension gardener.Extension) []gardener.Extension {
+	for i := range existing {
+		if existing[i].Type != extension.Type {
+			continue
+		}
+
+		result := append([]gardener.Extension{}, existing...)
+		extension.ProviderConfig = keepEquivalent(extension.ProviderConfig, existing[i].ProviderConfig)
+		result[i] = extension
+
+		return result
+	}
+
+	return append(existing, extension)
+}
+
+// removeExtension returns the extensions without the extension of the given type, nil when none is left.
+func removeExtension(existing []gardener.Extension, extensionType string) []gardener.Extension {
+	var result []gardener.Extension
+
+	for _, extension := range existing {
+		if extension.Type != extensionType {
+			result = append(result, extension)
+		}
+	}
+
+	return result
+}
+
+// renameExtension changes the type of an extension in place, an extension of the new type is dropped.
+func renameExtension(existing []gardener.Extension, oldType, newType string) []gardener.Extension {
+	found := false
+
+	for _, extension := range existing {
+		found = found || extension.Type == oldType
+	}
+
+	if !found {
+		return existing
+	}
+
+	result := removeExtension(existing, newType)
+	for i := range result {
+		if result[i].Type == oldType {
+			result[i].Type = newType
+		}
+	}
+
+	return result
+}
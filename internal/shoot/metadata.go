@@ -0,0 +1,86 @@
+package shoot
+
+import (
+	"strings"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reservedMetadataKeys are set on the shoots by the controllers, they are never propagated from the custom resources.
+var reservedMetadataKeys = map[string]bool{ //nolint:gochecknoglobals
+	RuntimeNameLabel:            true,
+	RuntimeNamespaceLabel:       true,
+	AuditLogExtensionAnnotation: true,
+	ExtensionsAnnotation:        true,
+}
+
+// MetadataPolicy selects the labels, and annotations of the custom resources propagated to their shoots by key prefix.
+// The keys matching a prefix are owned by the custom resource, they are removed from the shoot once removed from the
+// resource. The zero value propagates nothing.
+type MetadataPolicy struct {
+	LabelPrefixes      []string
+	AnnotationPrefixes []string
+}
+
+// Enabled reports whether any labels, or annotations are propagated.
+func (policy MetadataPolicy) Enabled() bool {
+	return len(policy.LabelPrefixes) > 0 || len(policy.AnnotationPrefixes) > 0
+}
+
+// Apply propagates the metadata of the source to the shoot, and reports whether the shoot changed.
+func (policy MetadataPolicy) Apply(shoot *gardener.Shoot, source metav1.Object) bool {
+	labels, labelsChanged := propagateMetadata(shoot.Labels, source.GetLabels(), policy.LabelPrefixes)
+	annotations, annotationsChanged := propagateMetadata(shoot.Annotations, source.GetAnnotations(), policy.AnnotationPrefixes)
+
+	shoot.Labels = labels
+	shoot.Annotations = annotations
+
+	return labelsChanged || annotationsChanged
+}
+
+// propagateMetadata copies the keys of the source matching the prefixes to the target, and removes the matching keys
+// the source doesn't have.
+func propagateMetadata(target, source map[string]string, prefixes []string) (map[string]string, bool) {
+	changed := false
+
+	for key := range target {
+		if _, ok := source[key]; !ok && propagated(key, prefixes) {
+			delete(target, key)
+			changed = true
+		}
+	}
+
+	for key, value := range source {
+		if !propagated(key, prefixes) {
+			continue
+		}
+
+		if existing, ok := target[key]; ok && existing == value {
+			continue
+		}
+
+		if target == nil {
+			target = map[string]string{}
+		}
+
+		target[key] = value
+		changed = true
+	}
+
+	return target, changed
+}
+
+func propagated(key string, prefixes []string) bool {
+	if reservedMetadataKeys[key] {
+		return false
+	}
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
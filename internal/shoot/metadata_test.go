@@ -0,0 +1,91 @@
+package shoot
+
+import (
+	"testing"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMetadataPolicy(t *testing.T) {
+	policy := MetadataPolicy{
+		LabelPrefixes:      []string{"kyma-project.io/", "operator.kyma-project.io/"},
+		AnnotationPrefixes: []string{"kyma-project.io/"},
+	}
+
+	t.Run("should copy the keys matching the prefixes", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Labels = map[string]string{"kyma-project.io/region": "eu", "app": "kcp"}
+		runtime.Annotations = map[string]string{"kyma-project.io/owner": "team-a", "kyma-project.io/region": "ignored"}
+		shoot := &gardener.Shoot{}
+
+		// when
+		changed := policy.Apply(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, map[string]string{"kyma-project.io/region": "eu"}, shoot.Labels)
+		assert.Equal(t, map[string]string{"kyma-project.io/owner": "team-a", "kyma-project.io/region": "ignored"}, shoot.Annotations)
+	})
+
+	t.Run("should remove the keys matching the prefixes the resource doesn't have", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Labels = map[string]string{"kyma-project.io/region": "us"}
+		shoot := &gardener.Shoot{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"kyma-project.io/region": "eu", "kyma-project.io/tier": "free", "shoot.gardener.cloud/status": "healthy"},
+		}}
+
+		// when
+		changed := policy.Apply(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, map[string]string{"kyma-project.io/region": "us", "shoot.gardener.cloud/status": "healthy"}, shoot.Labels)
+	})
+
+	t.Run("should not touch the keys set by the controller", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Labels = map[string]string{RuntimeNameLabel: "other"}
+		shoot := New(fixRuntime(), "garden-project")
+
+		// when
+		changed := policy.Apply(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, "runtime", shoot.Labels[RuntimeNameLabel])
+		assert.Equal(t, "kcp-system", shoot.Labels[RuntimeNamespaceLabel])
+	})
+
+	t.Run("should report no change when the shoot is up to date", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Labels = map[string]string{"kyma-project.io/region": "eu"}
+		shoot := &gardener.Shoot{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"kyma-project.io/region": "eu"}}}
+
+		// when
+		changed := policy.Apply(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+	})
+
+	t.Run("should propagate nothing with the zero value", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Labels = map[string]string{"kyma-project.io/region": "eu"}
+		shoot := &gardener.Shoot{}
+
+		// when
+		changed := MetadataPolicy{}.Apply(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.False(t, MetadataPolicy{}.Enabled())
+		assert.Empty(t, shoot.Labels)
+	})
+}
@@ -0,0 +1,240 @@
+package shoot
+
+import (
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mirrorConfig is the provider config of the registry mirror extension, in the format of the extension.
+type mirrorConfig struct {
+	typeMeta `json:",inline"`
+	Mirrors  []registryMirror `json:"mirrors"`
+}
+
+type registryMirror struct {
+	Upstream string       `json:"upstream"`
+	Hosts    []mirrorHost `json:"hosts"`
+}
+
+type mirrorHost struct {
+	Host         string   `json:"host"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// cri returns the container runtime of the pool, the provider configs Gardener set for the container runtimes are
+// kept. Without a CRI in the Runtime the container runtime of the shoot is kept.
+func cri(runtimeCRI *imv1.RuntimeWorkerCRI, existing *gardener.CRI) *gardener.CRI {
+	if runtimeCRI == nil {
+		return existing
+	}
+
+	result := &gardener.CRI{Name: gardener.CRINameContainerD}
+	if runtimeCRI.Name != "" {
+		result.Name = gardener.CRIName(runtimeCRI.Name)
+	}
+
+	existingRuntimes := map[string]gardener.ContainerRuntime{}
+
+	if existing != nil {
+		for _, containerRuntime := range existing.ContainerRuntimes {
+			existingRuntimes[containerRuntime.Type] = containerRuntime
+		}
+	}
+
+	for _, runtimeType := range runtimeCRI.ContainerRuntimes {
+		containerRuntime, found := existingRuntimes[runtimeType]
+		if !found {
+			containerRuntime = gardener.ContainerRuntime{Type: runtimeType}
+		}
+
+		result.ContainerRuntimes = append(result.ContainerRuntimes, containerRuntime)
+	}
+
+	return result
+}
+
+// setKubelet applies the kubelet settings of the pool, the settings not specified keep the values defaulted by
+// Gardener.
+func setKubelet(worker *gardener.Worker, kubelet *imv1.RuntimeKubelet) {
+	if kubelet == nil {
+		return
+	}
+
+	if worker.Kubernetes == nil {
+		worker.Kubernetes = &gardener.WorkerKubernetes{}
+	}
+
+	if worker.Kubernetes.Kubelet == nil {
+		worker.Kubernetes.Kubelet = &gardener.KubeletConfig{}
+	}
+
+	result := worker.Kubernetes.Kubelet
+
+	if kubelet.MaxPods != nil {
+		maxPods := *kubelet.MaxPods
+		result.MaxPods = &maxPods
+	}
+
+	result.EvictionHard = eviction(kubelet.EvictionHard, result.EvictionHard)
+	result.EvictionSoft = eviction(kubelet.EvictionSoft, result.EvictionSoft)
+
+	// the webhook requires the grace period with the soft thresholds
+	if kubelet.EvictionSoft != nil && kubelet.EvictionSoftGracePeriod != nil {
+		result.EvictionSoftGracePeriod = evictionSoftGracePeriod(kubelet.EvictionSoft, *kubelet.EvictionSoftGracePeriod, result.EvictionSoftGracePeriod)
+	}
+}
+
+// eviction returns the eviction thresholds with the thresholds of the Runtime applied.
+func eviction(runtimeEviction *imv1.RuntimeKubeletEviction, existing *gardener.KubeletConfigEviction) *gardener.KubeletConfigEviction {
+	if runtimeEviction == nil {
+		return existing
+	}
+
+	result := &gardener.KubeletConfigEviction{}
+	if existing != nil {
+		result = existing.DeepCopy()
+	}
+
+	setOptionalString(&result.MemoryAvailable, runtimeEviction.MemoryAvailable)
+	setOptionalString(&result.ImageFSAvailable, runtimeEviction.ImageFSAvailable)
+	setOptionalString(&result.ImageFSInodesFree, runtimeEviction.ImageFSInodesFree)
+	setOptionalString(&result.NodeFSAvailable, runtimeEviction.NodeFSAvailable)
+	setOptionalString(&result.NodeFSInodesFree, runtimeEviction.NodeFSInodesFree)
+
+	return result
+}
+
+// evictionSoftGracePeriod sets the grace period of each soft threshold of the Runtime.
+func evictionSoftGracePeriod(soft *imv1.RuntimeKubeletEviction, gracePeriod metav1.Duration, existing *gardener.KubeletConfigEvictionSoftGracePeriod) *gardener.KubeletConfigEvictionSoftGracePeriod {
+	result := &gardener.KubeletConfigEvictionSoftGracePeriod{}
+	if existing != nil {
+		result = existing.DeepCopy()
+	}
+
+	for _, threshold := range []struct {
+		value  string
+		target **metav1.Duration
+	}{
+		{soft.MemoryAvailable, &result.MemoryAvailable},
+		{soft.ImageFSAvailable, &result.ImageFSAvailable},
+		{soft.ImageFSInodesFree, &result.ImageFSInodesFree},
+		{soft.NodeFSAvailable, &result.NodeFSAvailable},
+		{soft.NodeFSInodesFree, &result.NodeFSInodesFree},
+	} {
+		if threshold.value != "" {
+			duration := gracePeriod
+			*threshold.target = &duration
+		}
+	}
+
+	return result
+}
+
+func setOptionalString(target **string, value string) {
+	if value != "" {
+		*target = &value
+	}
+}
+
+// setRegistryMirrors configures the registry mirror extension, the extension is removed with the last mirror.
+func setRegistryMirrors(shoot *gardener.Shoot, mirrors []imv1.RuntimeRegistryMirror) {
+	if len(mirrors) == 0 {
+		shoot.Spec.Extensions = removeExtension(shoot.Spec.Extensions, imv1.RegistryMirrorExtensionType)
+
+		return
+	}
+
+	config := mirrorConfig{typeMeta: typeMeta{APIVersion: "mirror.extensions.gardener.cloud/v1alpha1", Kind: "MirrorConfig"}}
+
+	for _, mirror := range mirrors {
+		hosts := make([]mirrorHost, 0, len(mirror.Hosts))
+		for _, host := range mirror.Hosts {
+			hosts = append(hosts, mirrorHost{Host: host, Capabilities: []string{"pull"}})
+		}
+
+		config.Mirrors = append(config.Mirrors, registryMirror{Upstream: mirror.Upstream, Hosts: hosts})
+	}
+
+	shoot.Spec.Extensions = setExtension(shoot.Spec.Extensions, gardener.Extension{
+		Type:           imv1.RegistryMirrorExtensionType,
+		ProviderConfig: rawExtension(config),
+	})
+}
+
+// machineImage returns the image of the pool. The version Gardener defaulted is kept when the Runtime has none, and a
+// higher version applied by the maintenance is kept when the images are updated automatically.
+func machineImage(runtimeImage *imv1.RuntimeWorkerImage, existing *gardener.ShootMachineImage, autoUpdate bool) *gardener.ShootMachineImage {
+	if runtimeImage == nil {
+		return existing
+	}
+
+	result := &gardener.ShootMachineImage{Name: runtimeImage.Name}
+	if existing != nil && existing.Name == runtimeImage.Name {
+		result = existing.DeepCopy()
+	}
+
+	switch {
+	case runtimeImage.Version == "":
+		// the version Gardener defaulted is kept
+	case autoUpdate && result.Version != nil:
+		imageVersion := minimumVersion(runtimeImage.Version, *result.Version)
+		result.Version = &imageVersion
+	default:
+		imageVersion := runtimeImage.Version
+		result.Version = &imageVersion
+	}
+
+	return result
+}
+
+// machineImageAutoUpdate reports whether Gardener updates the machine images of the shoot. The pools setting the auto
+// update take precedence over the maintenance of the Runtime, Gardener updates the images by default.
+func machineImageAutoUpdate(runtime *imv1.Runtime, existing *gardener.Maintenance) bool {
+	if autoUpdate := workersImageAutoUpdate(runtime.Spec.Shoot.Workers); autoUpdate != nil {
+		return *autoUpdate
+	}
+
+	if maintenance := runtime.Spec.Maintenance; maintenance != nil && maintenance.AutoUpdate != nil {
+		return maintenance.AutoUpdate.MachineImageVersion
+	}
+
+	if existing != nil && existing.AutoUpdate != nil && existing.AutoUpdate.MachineImageVersion != nil {
+		return *existing.AutoUpdate.MachineImageVersion
+	}
+
+	return true
+}
+
+// workersImageAutoUpdate returns the auto update of the images set by the pools, the webhook rejects pools which don't
+// agree on it. Nil when no pool sets it.
+func workersImageAutoUpdate(runtimeWorkers []imv1.RuntimeWorker) *bool {
+	for _, worker := range runtimeWorkers {
+		if worker.Image != nil && worker.Image.AutoUpdate != nil {
+			return worker.Image.AutoUpdate
+		}
+	}
+
+	return nil
+}
+
+// setMachineImageAutoUpdate applies the auto update of the images set by the pools to the maintenance of the shoot,
+// Gardener updates the images of all pools together.
+func setMachineImageAutoUpdate(shoot *gardener.Shoot, runtimeWorkers []imv1.RuntimeWorker) {
+	autoUpdate := workersImageAutoUpdate(runtimeWorkers)
+	if autoUpdate == nil {
+		return
+	}
+
+	if shoot.Spec.Maintenance == nil {
+		shoot.Spec.Maintenance = &gardener.Maintenance{}
+	}
+
+	if shoot.Spec.Maintenance.AutoUpdate == nil {
+		// Gardener updates the Kubernetes version by default
+		shoot.Spec.Maintenance.AutoUpdate = &gardener.MaintenanceAutoUpdate{KubernetesVersion: true}
+	}
+
+	machineImageVersion := *autoUpdate
+	shoot.Spec.Maintenance.AutoUpdate.MachineImageVersion = &machineImageVersion
+}
@@ -0,0 +1,189 @@
+package shoot
+
+import (
+	"testing"
+	"time"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCRI(t *testing.T) {
+	t.Run("should configure the container runtimes, and keep their provider configs", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+		gvisorConfig := &k8sruntime.RawExtension{Raw: []byte(`{"configFlags":{"debug":"true"}}`)}
+		shoot.Spec.Provider.Workers[0].CRI = &gardener.CRI{
+			Name:              gardener.CRINameContainerD,
+			ContainerRuntimes: []gardener.ContainerRuntime{{Type: "gvisor", ProviderConfig: gvisorConfig}},
+		}
+		runtime.Spec.Shoot.Workers[0].CRI = &imv1.RuntimeWorkerCRI{ContainerRuntimes: []string{"gvisor", "kata"}}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, &gardener.CRI{
+			Name: gardener.CRINameContainerD,
+			ContainerRuntimes: []gardener.ContainerRuntime{
+				{Type: "gvisor", ProviderConfig: gvisorConfig},
+				{Type: "kata"},
+			},
+		}, shoot.Spec.Provider.Workers[0].CRI)
+	})
+
+	t.Run("should keep the container runtime of the shoot without a CRI", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+		shoot.Spec.Provider.Workers[0].CRI = &gardener.CRI{Name: gardener.CRINameContainerD}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+	})
+}
+
+func TestKubelet(t *testing.T) {
+	t.Run("should apply the kubelet settings, and keep the defaults of the other ones", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+		memoryHard := "100Mi"
+		failSwapOn := false
+		shoot.Spec.Provider.Workers[0].Kubernetes = &gardener.WorkerKubernetes{Kubelet: &gardener.KubeletConfig{
+			FailSwapOn:   &failSwapOn,
+			EvictionHard: &gardener.KubeletConfigEviction{MemoryAvailable: &memoryHard},
+		}}
+
+		maxPods := int32(64)
+		runtime.Spec.Shoot.Workers[0].Kubelet = &imv1.RuntimeKubelet{
+			MaxPods:                 &maxPods,
+			EvictionHard:            &imv1.RuntimeKubeletEviction{NodeFSAvailable: "5%"},
+			EvictionSoft:            &imv1.RuntimeKubeletEviction{MemoryAvailable: "200Mi"},
+			EvictionSoftGracePeriod: &metav1.Duration{Duration: time.Minute},
+		}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+
+		kubelet := shoot.Spec.Provider.Workers[0].Kubernetes.Kubelet
+		assert.Equal(t, int32(64), *kubelet.MaxPods)
+		assert.False(t, *kubelet.FailSwapOn)
+		assert.Equal(t, "100Mi", *kubelet.EvictionHard.MemoryAvailable)
+		assert.Equal(t, "5%", *kubelet.EvictionHard.NodeFSAvailable)
+		assert.Equal(t, "200Mi", *kubelet.EvictionSoft.MemoryAvailable)
+		assert.Equal(t, time.Minute, kubelet.EvictionSoftGracePeriod.MemoryAvailable.Duration)
+		assert.Nil(t, kubelet.EvictionSoftGracePeriod.NodeFSAvailable)
+		assert.False(t, Update(shoot, runtime))
+	})
+}
+
+func TestRegistryMirrors(t *testing.T) {
+	t.Run("should configure, and remove the registry mirror extension", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.RegistryMirrors = []imv1.RuntimeRegistryMirror{
+			{Upstream: "docker.io", Hosts: []string{"https://mirror.example.com"}},
+		}
+
+		// when
+		shoot := New(runtime, "garden-project")
+
+		// then
+		require.Len(t, shoot.Spec.Extensions, 1)
+		assert.Equal(t, "registry-mirror", shoot.Spec.Extensions[0].Type)
+		assert.JSONEq(t, `{"apiVersion":"mirror.extensions.gardener.cloud/v1alpha1","kind":"MirrorConfig",`+
+			`"mirrors":[{"upstream":"docker.io","hosts":[{"host":"https://mirror.example.com","capabilities":["pull"]}]}]}`,
+			string(shoot.Spec.Extensions[0].ProviderConfig.Raw))
+
+		// when
+		runtime.Spec.Shoot.RegistryMirrors = nil
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Empty(t, shoot.Spec.Extensions)
+	})
+}
+
+func TestMachineImage(t *testing.T) {
+	t.Run("should pin the image version, and disable the auto update of the images", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		autoUpdate := false
+		runtime.Spec.Shoot.Workers[0].Image = &imv1.RuntimeWorkerImage{Name: "gardenlinux", Version: "1312.3.0", AutoUpdate: &autoUpdate}
+		shoot := New(runtime, "garden-project")
+		updatedVersion := "1312.4.0"
+		shoot.Spec.Provider.Workers[0].Machine.Image.Version = &updatedVersion
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, "gardenlinux", shoot.Spec.Provider.Workers[0].Machine.Image.Name)
+		assert.Equal(t, "1312.3.0", *shoot.Spec.Provider.Workers[0].Machine.Image.Version)
+		assert.False(t, *shoot.Spec.Maintenance.AutoUpdate.MachineImageVersion)
+		assert.True(t, shoot.Spec.Maintenance.AutoUpdate.KubernetesVersion)
+	})
+
+	t.Run("should keep the version applied by the auto update", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		autoUpdate := true
+		runtime.Spec.Shoot.Workers[0].Image = &imv1.RuntimeWorkerImage{Name: "gardenlinux", Version: "1312.3.0", AutoUpdate: &autoUpdate}
+		shoot := New(runtime, "garden-project")
+		updatedVersion := "1312.4.0"
+		shoot.Spec.Provider.Workers[0].Machine.Image.Version = &updatedVersion
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, "1312.4.0", *shoot.Spec.Provider.Workers[0].Machine.Image.Version)
+	})
+
+	t.Run("should keep the version defaulted by Gardener for a floating image", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.Workers[0].Image = &imv1.RuntimeWorkerImage{Name: "gardenlinux"}
+		shoot := New(runtime, "garden-project")
+		defaultedVersion := "1312.4.0"
+		shoot.Spec.Provider.Workers[0].Machine.Image.Version = &defaultedVersion
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.Nil(t, shoot.Spec.Maintenance)
+	})
+
+	t.Run("should report the running image in the worker status", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.Workers[0].Image = &imv1.RuntimeWorkerImage{Name: "gardenlinux", Version: "1312.3.0"}
+		shoot := New(runtime, "garden-project")
+
+		// when
+		statuses := WorkerStatuses(shoot, imv1.ReadyState)
+
+		// then
+		require.Len(t, statuses, 1)
+		assert.Equal(t, "gardenlinux", statuses[0].MachineImage)
+		assert.Equal(t, "1312.3.0", statuses[0].MachineImageVersion)
+	})
+}
@@ -0,0 +1,207 @@
+package shoot
+
+import (
+	"encoding/json"
+	"reflect"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// The provider configs are serialized in the format of the Gardener provider extensions, their API modules are not
+// imported to keep the dependencies of the manager small.
+
+type typeMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+func providerTypeMeta(providerType, kind string) typeMeta {
+	return typeMeta{APIVersion: providerType + ".provider.extensions.gardener.cloud/v1alpha1", Kind: kind}
+}
+
+type awsInfrastructureConfig struct {
+	typeMeta `json:",inline"`
+	Networks struct {
+		VPC struct {
+			ID   string `json:"id,omitempty"`
+			CIDR string `json:"cidr,omitempty"`
+		} `json:"vpc"`
+		Zones []imv1.AWSZone `json:"zones"`
+	} `json:"networks"`
+}
+
+type awsWorkerConfig struct {
+	typeMeta                `json:",inline"`
+	InstanceMetadataOptions *imv1.AWSInstanceMetadataOptions `json:"instanceMetadataOptions,omitempty"`
+}
+
+type gcpInfrastructureConfig struct {
+	typeMeta `json:",inline"`
+	Networks struct {
+		VPC *gcpVPC `json:"vpc,omitempty"`
+
+		Workers  string `json:"workers"`
+		Internal string `json:"internal,omitempty"`
+	} `json:"networks"`
+}
+
+type gcpVPC struct {
+	Name        string          `json:"name"`
+	CloudRouter *gcpCloudRouter `json:"cloudRouter,omitempty"`
+}
+
+type gcpCloudRouter struct {
+	Name string `json:"name"`
+}
+
+type gcpControlPlaneConfig struct {
+	typeMeta `json:",inline"`
+	Zone     string `json:"zone"`
+}
+
+type azureInfrastructureConfig struct {
+	typeMeta `json:",inline"`
+	Networks struct {
+		VNet    imv1.AzureVNet `json:"vnet"`
+		Workers string         `json:"workers"`
+	} `json:"networks"`
+	Zoned bool `json:"zoned"`
+}
+
+type openStackInfrastructureConfig struct {
+	typeMeta         `json:",inline"`
+	FloatingPoolName string `json:"floatingPoolName"`
+	Networks         struct {
+		ID     string           `json:"id,omitempty"`
+		Router *openStackRouter `json:"router,omitempty"`
+
+		Workers string `json:"workers"`
+	} `json:"networks"`
+}
+
+type openStackRouter struct {
+	ID string `json:"id"`
+}
+
+type openStackControlPlaneConfig struct {
+	typeMeta             `json:",inline"`
+	LoadBalancerProvider string `json:"loadBalancerProvider"`
+}
+
+// providerConfigs returns the infrastructure, and the control plane config of the provider extension, the raw configs
+// are passed as is without a typed configuration.
+func providerConfigs(provider imv1.RuntimeProvider) (*runtime.RawExtension, *runtime.RawExtension) {
+	switch {
+	case provider.AWS != nil:
+		infrastructureConfig := awsInfrastructureConfig{typeMeta: providerTypeMeta(imv1.ProviderTypeAWS, "InfrastructureConfig")}
+		infrastructureConfig.Networks.VPC.ID = provider.AWS.VPC.ID
+		infrastructureConfig.Networks.VPC.CIDR = provider.AWS.VPC.CIDR
+		infrastructureConfig.Networks.Zones = provider.AWS.Zones
+
+		return rawExtensions(infrastructureConfig, providerTypeMeta(imv1.ProviderTypeAWS, "ControlPlaneConfig"))
+	case provider.GCP != nil:
+		infrastructureConfig := gcpInfrastructureConfig{typeMeta: providerTypeMeta(imv1.ProviderTypeGCP, "InfrastructureConfig")}
+		infrastructureConfig.Networks.Workers = provider.GCP.Workers
+		infrastructureConfig.Networks.Internal = provider.GCP.Internal
+
+		if provider.GCP.VPC != nil {
+			infrastructureConfig.Networks.VPC = &gcpVPC{Name: provider.GCP.VPC.Name}
+			if provider.GCP.VPC.CloudRouterName != "" {
+				infrastructureConfig.Networks.VPC.CloudRouter = &gcpCloudRouter{Name: provider.GCP.VPC.CloudRouterName}
+			}
+		}
+
+		return rawExtensions(infrastructureConfig, gcpControlPlaneConfig{
+			typeMeta: providerTypeMeta(imv1.ProviderTypeGCP, "ControlPlaneConfig"),
+			Zone:     provider.GCP.Zone,
+		})
+	case provider.Azure != nil:
+		infrastructureConfig := azureInfrastructureConfig{typeMeta: providerTypeMeta(imv1.ProviderTypeAzure, "InfrastructureConfig")}
+		infrastructureConfig.Networks.VNet = provider.Azure.VNet
+		infrastructureConfig.Networks.Workers = provider.Azure.Workers
+		infrastructureConfig.Zoned = provider.Azure.Zoned
+
+		return rawExtensions(infrastructureConfig, providerTypeMeta(imv1.ProviderTypeAzure, "ControlPlaneConfig"))
+	case provider.OpenStack != nil:
+		infrastructureConfig := openStackInfrastructureConfig{
+			typeMeta:         providerTypeMeta(imv1.ProviderTypeOpenStack, "InfrastructureConfig"),
+			FloatingPoolName: provider.OpenStack.FloatingPoolName,
+		}
+		infrastructureConfig.Networks.ID = provider.OpenStack.NetworkID
+		infrastructureConfig.Networks.Workers = provider.OpenStack.Workers
+
+		if provider.OpenStack.RouterID != "" {
+			infrastructureConfig.Networks.Router = &openStackRouter{ID: provider.OpenStack.RouterID}
+		}
+
+		return rawExtensions(infrastructureConfig, openStackControlPlaneConfig{
+			typeMeta:             providerTypeMeta(imv1.ProviderTypeOpenStack, "ControlPlaneConfig"),
+			LoadBalancerProvider: provider.OpenStack.LoadBalancerProvider,
+		})
+	}
+
+	return provider.InfrastructureConfig, provider.ControlPlaneConfig
+}
+
+// workerConfig returns the provider config of the worker pools, nil when the provider doesn't configure the nodes.
+func workerConfig(provider imv1.RuntimeProvider) *runtime.RawExtension {
+	if provider.AWS == nil || provider.AWS.InstanceMetadataOptions == nil {
+		return nil
+	}
+
+	return rawExtension(awsWorkerConfig{
+		typeMeta:                providerTypeMeta(imv1.ProviderTypeAWS, "WorkerConfig"),
+		InstanceMetadataOptions: provider.AWS.InstanceMetadataOptions,
+	})
+}
+
+func rawExtensions(infrastructureConfig, controlPlaneConfig any) (*runtime.RawExtension, *runtime.RawExtension) {
+	return rawExtension(infrastructureConfig), rawExtension(controlPlaneConfig)
+}
+
+// rawExtension serializes the config, the configs consist of strings, and numbers only, which can't fail to marshal.
+func rawExtension(config any) *runtime.RawExtension {
+	raw, _ := json.Marshal(config) //nolint:errchkjson
+
+	return &runtime.RawExtension{Raw: raw}
+}
+
+// keepEquivalent returns the existing config when it holds the same JSON document as the desired one, Gardener
+// re-encodes the configs, which must not be reported as a change of the shoot.
+func keepEquivalent(desired, existing *runtime.RawExtension) *runtime.RawExtension {
+	if desired == nil || existing == nil {
+		return desired
+	}
+
+	var desiredDocument, existingDocument any
+
+	if json.Unmarshal(desired.Raw, &desiredDocument) != nil || json.Unmarshal(existing.Raw, &existingDocument) != nil {
+		return desired
+	}
+
+	if reflect.DeepEqual(desiredDocument, existingDocument) {
+		return existing
+	}
+
+	return desired
+}
+
+// setProviderConfigs applies the provider configs of the Runtime to the shoot.
+func setProviderConfigs(shoot *gardener.Shoot, provider imv1.RuntimeProvider) {
+	infrastructureConfig, controlPlaneConfig := providerConfigs(provider)
+
+	shoot.Spec.Provider.InfrastructureConfig = keepEquivalent(infrastructureConfig, shoot.Spec.Provider.InfrastructureConfig)
+	shoot.Spec.Provider.ControlPlaneConfig = keepEquivalent(controlPlaneConfig, shoot.Spec.Provider.ControlPlaneConfig)
+
+	workerProviderConfig := workerConfig(provider)
+	if workerProviderConfig == nil {
+		return
+	}
+
+	for i := range shoot.Spec.Provider.Workers {
+		worker := &shoot.Spec.Provider.Workers[i]
+		worker.ProviderConfig = keepEquivalent(workerProviderConfig, worker.ProviderConfig)
+	}
+}
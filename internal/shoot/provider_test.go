@@ -0,0 +1,128 @@
+package shoot
+
+import (
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestProviderConfigs(t *testing.T) {
+	hopLimit := int64(1)
+
+	for _, tc := range []struct {
+		name                 string
+		provider             imv1.RuntimeProvider
+		infrastructureConfig string
+		controlPlaneConfig   string
+	}{
+		{
+			name: "aws",
+			provider: imv1.RuntimeProvider{Type: "aws", AWS: &imv1.AWSProviderConfig{
+				VPC:   imv1.AWSVPC{ID: "vpc-0a1b2c3d"},
+				Zones: []imv1.AWSZone{{Name: "eu-central-1a", Workers: "10.250.0.0/19", Public: "10.250.32.0/20", Internal: "10.250.48.0/20"}},
+				InstanceMetadataOptions: &imv1.AWSInstanceMetadataOptions{
+					HTTPTokens:              "required",
+					HTTPPutResponseHopLimit: &hopLimit,
+				},
+			}},
+			infrastructureConfig: `{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig",
+				"networks":{"vpc":{"id":"vpc-0a1b2c3d"},"zones":[{"name":"eu-central-1a","workers":"10.250.0.0/19","public":"10.250.32.0/20","internal":"10.250.48.0/20"}]}}`,
+			controlPlaneConfig: `{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"ControlPlaneConfig"}`,
+		},
+		{
+			name: "gcp",
+			provider: imv1.RuntimeProvider{Type: "gcp", GCP: &imv1.GCPProviderConfig{
+				VPC:     &imv1.GCPVPC{Name: "shared", CloudRouterName: "router"},
+				Workers: "10.250.0.0/16",
+				Zone:    "europe-west3-a",
+			}},
+			infrastructureConfig: `{"apiVersion":"gcp.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig",
+				"networks":{"vpc":{"name":"shared","cloudRouter":{"name":"router"}},"workers":"10.250.0.0/16"}}`,
+			controlPlaneConfig: `{"apiVersion":"gcp.provider.extensions.gardener.cloud/v1alpha1","kind":"ControlPlaneConfig","zone":"europe-west3-a"}`,
+		},
+		{
+			name: "azure",
+			provider: imv1.RuntimeProvider{Type: "azure", Azure: &imv1.AzureProviderConfig{
+				VNet:    imv1.AzureVNet{CIDR: "10.250.0.0/16"},
+				Workers: "10.250.0.0/19",
+				Zoned:   true,
+			}},
+			infrastructureConfig: `{"apiVersion":"azure.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig",
+				"networks":{"vnet":{"cidr":"10.250.0.0/16"},"workers":"10.250.0.0/19"},"zoned":true}`,
+			controlPlaneConfig: `{"apiVersion":"azure.provider.extensions.gardener.cloud/v1alpha1","kind":"ControlPlaneConfig"}`,
+		},
+		{
+			name: "openstack",
+			provider: imv1.RuntimeProvider{Type: "openstack", OpenStack: &imv1.OpenStackProviderConfig{
+				FloatingPoolName:     "FloatingIP-external",
+				RouterID:             "router-id",
+				Workers:              "10.250.0.0/16",
+				LoadBalancerProvider: "f5",
+			}},
+			infrastructureConfig: `{"apiVersion":"openstack.provider.extensions.gardener.cloud/v1alpha1","kind":"InfrastructureConfig",
+				"floatingPoolName":"FloatingIP-external","networks":{"router":{"id":"router-id"},"workers":"10.250.0.0/16"}}`,
+			controlPlaneConfig: `{"apiVersion":"openstack.provider.extensions.gardener.cloud/v1alpha1","kind":"ControlPlaneConfig","loadBalancerProvider":"f5"}`,
+		},
+	} {
+		t.Run("should convert the "+tc.name+" configuration", func(t *testing.T) {
+			// when
+			infrastructureConfig, controlPlaneConfig := providerConfigs(tc.provider)
+
+			// then
+			assert.JSONEq(t, tc.infrastructureConfig, string(infrastructureConfig.Raw))
+			assert.JSONEq(t, tc.controlPlaneConfig, string(controlPlaneConfig.Raw))
+		})
+	}
+
+	t.Run("should pass the raw configuration as is", func(t *testing.T) {
+		// given
+		raw := &runtime.RawExtension{Raw: []byte(`{"kind":"InfrastructureConfig"}`)}
+
+		// when
+		infrastructureConfig, controlPlaneConfig := providerConfigs(imv1.RuntimeProvider{Type: "alicloud", InfrastructureConfig: raw})
+
+		// then
+		assert.Same(t, raw, infrastructureConfig)
+		assert.Nil(t, controlPlaneConfig)
+	})
+}
+
+func TestSetProviderConfigs(t *testing.T) {
+	t.Run("should configure the instance metadata of the nodes", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		rt.Spec.Shoot.Provider.AWS = &imv1.AWSProviderConfig{
+			VPC:                     imv1.AWSVPC{CIDR: "10.250.0.0/16"},
+			InstanceMetadataOptions: &imv1.AWSInstanceMetadataOptions{HTTPTokens: "required"},
+		}
+
+		// when
+		shoot := New(rt, "garden-project")
+
+		// then
+		require.Len(t, shoot.Spec.Provider.Workers, 1)
+		assert.JSONEq(t, `{"apiVersion":"aws.provider.extensions.gardener.cloud/v1alpha1","kind":"WorkerConfig",
+			"instanceMetadataOptions":{"httpTokens":"required"}}`, string(shoot.Spec.Provider.Workers[0].ProviderConfig.Raw))
+	})
+
+	t.Run("should keep the configuration re-encoded by Gardener", func(t *testing.T) {
+		// given
+		rt := fixRuntime()
+		rt.Spec.Shoot.Provider.GCP = &imv1.GCPProviderConfig{Workers: "10.250.0.0/16", Zone: "europe-west3-a"}
+		rt.Spec.Shoot.Provider.Type = "gcp"
+		shoot := New(rt, "garden-project")
+
+		reencoded := []byte(`{"kind": "ControlPlaneConfig", "zone": "europe-west3-a", "apiVersion": "gcp.provider.extensions.gardener.cloud/v1alpha1"}`)
+		shoot.Spec.Provider.ControlPlaneConfig = &runtime.RawExtension{Raw: reencoded}
+
+		// when
+		changed := Update(shoot, rt)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, reencoded, shoot.Spec.Provider.ControlPlaneConfig.Raw)
+	})
+}
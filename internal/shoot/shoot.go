@@ -0,0 +1,453 @@
+// Package shoot translates the Runtime spec into the Gardener shoot spec.
+package shoot
+
+import (
+	"strconv"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	RuntimeNameLabel      = "operator.kyma-project.io/runtime-name"
+	RuntimeNamespaceLabel = "operator.kyma-project.io/runtime-namespace"
+
+	// AuditLogExtensionAnnotation records the type of the audit log extension managed for the Runtime, so the extension
+	// is removed together with the backend.
+	AuditLogExtensionAnnotation = "operator.kyma-project.io/audit-log-extension"
+
+	// ExtensionsAnnotation records the types of the extensions listed in the Runtime, so the extensions removed from the
+	// list are removed from the shoot.
+	ExtensionsAnnotation = "operator.kyma-project.io/extensions"
+
+	// defaultNetworkingType is the CNI of the shoots.
+	defaultNetworkingType = "calico"
+)
+
+// New returns the shoot of the Runtime in the project namespace.
+func New(runtime *imv1.Runtime, namespace string) *gardener.Shoot {
+	secretBindingName := runtime.Spec.Shoot.SecretBindingName
+
+	shoot := &gardener.Shoot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      runtime.Spec.Shoot.Name,
+			Namespace: namespace,
+		},
+		Spec: gardener.ShootSpec{
+			CloudProfileName:  runtime.Spec.Shoot.CloudProfileName,
+			Region:            runtime.Spec.Shoot.Region,
+			SecretBindingName: &secretBindingName,
+			Provider: gardener.Provider{
+				Type: runtime.Spec.Shoot.Provider.Type,
+			},
+			Networking: networking(runtime.Spec.Shoot.Networking),
+		},
+	}
+
+	if runtime.Spec.Shoot.Purpose != "" {
+		purpose := gardener.ShootPurpose(runtime.Spec.Shoot.Purpose)
+		shoot.Spec.Purpose = &purpose
+	}
+
+	setSeedSelection(shoot, runtime.Spec.Shoot)
+
+	Update(shoot, runtime)
+
+	return shoot
+}
+
+// setSeedSelection pins the shoot to the seed, and sets the seed selector of the Runtime. Both are immutable, and only
+// applied on creation, the seed Gardener schedules the shoot to is kept.
+func setSeedSelection(shoot *gardener.Shoot, runtimeShoot imv1.RuntimeShoot) {
+	shoot.Spec.SeedName = optionalString(runtimeShoot.SeedName)
+
+	if selector := runtimeShoot.SeedSelector; selector != nil {
+		shoot.Spec.SeedSelector = &gardener.SeedSelector{
+			LabelSelector: *selector.LabelSelector.DeepCopy(),
+			ProviderTypes: selector.ProviderTypes,
+		}
+	}
+}
+
+// networking returns the networking of the shoot, the CNI defaults to calico. It is immutable, and only applied on
+// creation.
+func networking(runtimeNetworking *imv1.RuntimeNetworking) *gardener.Networking {
+	networkingType := defaultNetworkingType
+	result := &gardener.Networking{Type: &networkingType}
+
+	if runtimeNetworking == nil {
+		return result
+	}
+
+	if runtimeNetworking.Type != "" {
+		networkingType = runtimeNetworking.Type
+	}
+
+	result.Nodes = optionalString(runtimeNetworking.Nodes)
+	result.Pods = optionalString(runtimeNetworking.Pods)
+	result.Services = optionalString(runtimeNetworking.Services)
+
+	return result
+}
+
+// Update applies the mutable part of the Runtime spec to the shoot, and reports whether the shoot changed.
+// Fields not managed by the Runtime, e.g. defaulted by Gardener, are kept.
+func Update(shoot *gardener.Shoot, runtime *imv1.Runtime) bool {
+	original := shoot.DeepCopy()
+
+	if shoot.Labels == nil {
+		shoot.Labels = map[string]string{}
+	}
+
+	shoot.Labels[RuntimeNameLabel] = runtime.Name
+	shoot.Labels[RuntimeNamespaceLabel] = runtime.Namespace
+
+	shoot.Spec.Kubernetes.Version = minimumVersion(runtime.Spec.Shoot.Kubernetes.Version, shoot.Spec.Kubernetes.Version)
+	setOIDCConfig(shoot, runtime.Spec.Shoot.Kubernetes.OIDC)
+	setClusterAutoscaler(shoot, runtime.Spec.Shoot.Kubernetes.ClusterAutoscaler)
+	setControlPlane(shoot, runtime.Spec.Shoot.ControlPlane)
+	// the listed extensions are applied first, they can't conflict with the extensions managed by the other fields
+	setExtensions(shoot, runtime.Spec.Shoot.Extensions)
+	setAuditLog(shoot, runtime.Spec.Shoot.AuditLog)
+	setDNS(shoot, runtime.Spec.Shoot.DNS)
+	setRegistryMirrors(shoot, runtime.Spec.Shoot.RegistryMirrors)
+	imageAutoUpdate := machineImageAutoUpdate(runtime, shoot.Spec.Maintenance)
+	shoot.Spec.Provider.Workers = workers(runtime.Spec.Shoot.Workers, shoot.Spec.Provider.Workers, imageAutoUpdate)
+	setProviderConfigs(shoot, runtime.Spec.Shoot.Provider)
+	shoot.Spec.Hibernation = hibernation(runtime.Spec.Hibernation, shoot.Spec.Hibernation)
+
+	if runtime.Spec.Maintenance != nil {
+		shoot.Spec.Maintenance = maintenance(runtime.Spec.Maintenance, shoot.Spec.Maintenance)
+	}
+
+	setMachineImageAutoUpdate(shoot, runtime.Spec.Shoot.Workers)
+
+	return !equality.Semantic.DeepEqual(original, shoot)
+}
+
+// minimumVersion returns the version of the Runtime, unless the shoot runs a higher version, e.g. a version applied by
+// the maintenance of Gardener, which rejects Kubernetes downgrades anyway.
+func minimumVersion(runtimeVersion, existing string) string {
+	desired, err := version.ParseGeneric(runtimeVersion)
+	if err != nil {
+		return runtimeVersion
+	}
+
+	current, err := version.ParseGeneric(existing)
+	if err != nil || !desired.LessThan(current) {
+		return runtimeVersion
+	}
+
+	return existing
+}
+
+// setOIDCConfig applies the OIDC provider of the Runtime to the kube-apiserver, the other settings of the
+// kube-apiserver are kept.
+func setOIDCConfig(shoot *gardener.Shoot, oidc *imv1.RuntimeOIDC) {
+	if oidc == nil {
+		if shoot.Spec.Kubernetes.KubeAPIServer != nil {
+			shoot.Spec.Kubernetes.KubeAPIServer.OIDCConfig = nil
+		}
+
+		return
+	}
+
+	if shoot.Spec.Kubernetes.KubeAPIServer == nil {
+		shoot.Spec.Kubernetes.KubeAPIServer = &gardener.KubeAPIServerConfig{}
+	}
+
+	shoot.Spec.Kubernetes.KubeAPIServer.OIDCConfig = &gardener.OIDCConfig{
+		IssuerURL:      optionalString(oidc.IssuerURL),
+		ClientID:       optionalString(oidc.ClientID),
+		UsernameClaim:  optionalString(oidc.UsernameClaim),
+		UsernamePrefix: optionalString(oidc.UsernamePrefix),
+		GroupsClaim:    optionalString(oidc.GroupsClaim),
+		GroupsPrefix:   optionalString(oidc.GroupsPrefix),
+		SigningAlgs:    oidc.SigningAlgs,
+		RequiredClaims: oidc.RequiredClaims,
+	}
+}
+
+// setAuditLog applies the audit policy to the kube-apiserver, and the backend as an extension of the shoot.
+func setAuditLog(shoot *gardener.Shoot, auditLog *imv1.RuntimeAuditLog) {
+	var policyConfigMapName string
+	var backend *imv1.RuntimeAuditLogBackend
+
+	if auditLog != nil {
+		policyConfigMapName = auditLog.PolicyConfigMapName
+		backend = auditLog.Backend
+	}
+
+	switch {
+	case policyConfigMapName != "":
+		if shoot.Spec.Kubernetes.KubeAPIServer == nil {
+			shoot.Spec.Kubernetes.KubeAPIServer = &gardener.KubeAPIServerConfig{}
+		}
+
+		shoot.Spec.Kubernetes.KubeAPIServer.AuditConfig = &gardener.AuditConfig{
+			AuditPolicy: &gardener.AuditPolicy{ConfigMapRef: &corev1.ObjectReference{Name: policyConfigMapName}},
+		}
+	case shoot.Spec.Kubernetes.KubeAPIServer != nil:
+		shoot.Spec.Kubernetes.KubeAPIServer.AuditConfig = nil
+	}
+
+	shoot.Spec.Extensions = auditLogExtensions(shoot.Spec.Extensions, shoot.Annotations[AuditLogExtensionAnnotation], backend)
+
+	if backend == nil {
+		delete(shoot.Annotations, AuditLogExtensionAnnotation)
+
+		return
+	}
+
+	if shoot.Annotations == nil {
+		shoot.Annotations = map[string]string{}
+	}
+
+	shoot.Annotations[AuditLogExtensionAnnotation] = backend.Type
+}
+
+// auditLogExtensions replaces the previously managed extension with the backend.
+func auditLogExtensions(existing []gardener.Extension, managedType string, backend *imv1.RuntimeAuditLogBackend) []gardener.Extension {
+	if backend == nil {
+		return removeExtension(existing, managedType)
+	}
+
+	if managedType != backend.Type {
+		existing = renameExtension(existing, managedType, backend.Type)
+	}
+
+	return setExtension(existing, gardener.Extension{Type: backend.Type, ProviderConfig: backend.ProviderConfig})
+}
+
+// dnsConfig is the provider config of the DNS extension, in the format of the extension.
+type dnsConfig struct {
+	typeMeta                      `json:",inline"`
+	SyncProvidersFromShootSpecDNS bool `json:"syncProvidersFromShootSpecDNS"`
+}
+
+// setDNS applies the custom domain, and its primary DNS provider, the DNS extension takes the provider over from the
+// DNS spec. Without a custom domain the default domain, and provider of Gardener are kept.
+func setDNS(shoot *gardener.Shoot, dns *imv1.RuntimeDNS) {
+	if dns == nil {
+		return
+	}
+
+	primary := true
+	shoot.Spec.DNS = &gardener.DNS{
+		Domain: optionalString(dns.Domain),
+		Providers: []gardener.DNSProvider{{
+			Primary:    &primary,
+			Type:       optionalString(dns.Provider.Type),
+			SecretName: optionalString(dns.Provider.SecretName),
+			Domains:    &gardener.DNSIncludeExclude{Include: []string{dns.Domain}},
+		}},
+	}
+
+	shoot.Spec.Extensions = setExtension(shoot.Spec.Extensions, gardener.Extension{
+		Type: imv1.DNSExtensionType,
+		ProviderConfig: rawExtension(dnsConfig{
+			typeMeta:                      typeMeta{APIVersion: "service.dns.extensions.gardener.cloud/v1alpha1", Kind: "DNSConfig"},
+			SyncProvidersFromShootSpecDNS: true,
+		}),
+	})
+}
+
+// setClusterAutoscaler applies the autoscaler settings of the Runtime, the settings not specified keep the values
+// defaulted by Gardener.
+func setClusterAutoscaler(shoot *gardener.Shoot, autoscaler *imv1.RuntimeClusterAutoscaler) {
+	if autoscaler == nil {
+		return
+	}
+
+	if shoot.Spec.Kubernetes.ClusterAutoscaler == nil {
+		shoot.Spec.Kubernetes.ClusterAutoscaler = &gardener.ClusterAutoscaler{}
+	}
+
+	result := shoot.Spec.Kubernetes.ClusterAutoscaler
+
+	if autoscaler.ScaleDownDelayAfterAdd != nil {
+		result.ScaleDownDelayAfterAdd = autoscaler.ScaleDownDelayAfterAdd.DeepCopy()
+	}
+
+	if autoscaler.ScaleDownUnneededTime != nil {
+		result.ScaleDownUnneededTime = autoscaler.ScaleDownUnneededTime.DeepCopy()
+	}
+
+	if autoscaler.MaxNodeProvisionTime != nil {
+		result.MaxNodeProvisionTime = autoscaler.MaxNodeProvisionTime.DeepCopy()
+	}
+
+	// the webhook validates the threshold
+	if threshold, err := strconv.ParseFloat(autoscaler.ScaleDownUtilizationThreshold, 64); err == nil {
+		result.ScaleDownUtilizationThreshold = &threshold
+	}
+}
+
+// maintenance returns the maintenance of the Runtime, the settings Gardener defaulted are kept for the ones not specified.
+func maintenance(runtimeMaintenance *imv1.RuntimeMaintenance, existing *gardener.Maintenance) *gardener.Maintenance {
+	result := &gardener.Maintenance{}
+	if existing != nil {
+		result = existing.DeepCopy()
+	}
+
+	if runtimeMaintenance.TimeWindow != nil {
+		result.TimeWindow = &gardener.MaintenanceTimeWindow{
+			Begin: runtimeMaintenance.TimeWindow.Begin,
+			End:   runtimeMaintenance.TimeWindow.End,
+		}
+	}
+
+	if runtimeMaintenance.AutoUpdate != nil {
+		machineImageVersion := runtimeMaintenance.AutoUpdate.MachineImageVersion
+		result.AutoUpdate = &gardener.MaintenanceAutoUpdate{
+			KubernetesVersion:   runtimeMaintenance.AutoUpdate.KubernetesVersion,
+			MachineImageVersion: &machineImageVersion,
+		}
+	}
+
+	return result
+}
+
+// workers returns the worker pools of the Runtime, the settings Gardener defaulted on existing pools are kept.
+// Pools missing in the Runtime are removed, Gardener drains their nodes before deleting them.
+func workers(runtimeWorkers []imv1.RuntimeWorker, existing []gardener.Worker, imageAutoUpdate bool) []gardener.Worker {
+	existingByName := make(map[string]gardener.Worker, len(existing))
+	for _, worker := range existing {
+		existingByName[worker.Name] = worker
+	}
+
+	result := make([]gardener.Worker, 0, len(runtimeWorkers))
+
+	for _, runtimeWorker := range runtimeWorkers {
+		worker := existingByName[runtimeWorker.Name]
+		worker.Name = runtimeWorker.Name
+		worker.Machine.Type = runtimeWorker.MachineType
+		worker.Minimum = runtimeWorker.Minimum
+		worker.Maximum = runtimeWorker.Maximum
+		worker.Zones = runtimeWorker.Zones
+		worker.Labels = runtimeWorker.Labels
+		worker.Taints = runtimeWorker.Taints
+		worker.Machine.Image = machineImage(runtimeWorker.Image, worker.Machine.Image, imageAutoUpdate)
+		worker.CRI = cri(runtimeWorker.CRI, worker.CRI)
+		setKubelet(&worker, runtimeWorker.Kubelet)
+
+		if runtimeWorker.Volume != nil {
+			worker.Volume = volume(runtimeWorker.Volume, worker.Volume)
+		}
+
+		result = append(result, worker)
+	}
+
+	return result
+}
+
+// volume returns the root disk of the pool, the settings Gardener defaulted, e.g. the encryption, are kept.
+func volume(runtimeVolume *imv1.RuntimeWorkerVolume, existing *gardener.Volume) *gardener.Volume {
+	result := &gardener.Volume{}
+	if existing != nil {
+		result = existing.DeepCopy()
+	}
+
+	result.VolumeSize = runtimeVolume.Size
+	result.Type = optionalString(runtimeVolume.Type)
+
+	return result
+}
+
+// WorkerStatuses reports the worker pools of the shoot, the pools are in the given state once Gardener has observed
+// the current generation of the shoot, and in the Processing state before.
+func WorkerStatuses(shoot *gardener.Shoot, state imv1.State) []imv1.RuntimeWorkerStatus {
+	if shoot.Status.ObservedGeneration < shoot.Generation {
+		state = imv1.ProcessingState
+	}
+
+	result := make([]imv1.RuntimeWorkerStatus, 0, len(shoot.Spec.Provider.Workers))
+
+	for _, worker := range shoot.Spec.Provider.Workers {
+		status := imv1.RuntimeWorkerStatus{
+			Name:        worker.Name,
+			State:       state,
+			MachineType: worker.Machine.Type,
+			Minimum:     worker.Minimum,
+			Maximum:     worker.Maximum,
+			Zones:       worker.Zones,
+		}
+
+		if worker.Machine.Image != nil {
+			status.MachineImage = worker.Machine.Image.Name
+			if worker.Machine.Image.Version != nil {
+				status.MachineImageVersion = *worker.Machine.Image.Version
+			}
+		}
+
+		result = append(result, status)
+	}
+
+	return result
+}
+
+// hibernation returns the hibernation of the Runtime. Without the manual toggle the shoot keeps the state set by the
+// hibernation schedules of Gardener.
+func hibernation(runtimeHibernation *imv1.RuntimeHibernation, existing *gardener.Hibernation) *gardener.Hibernation {
+	if runtimeHibernation == nil {
+		return nil
+	}
+
+	result := &gardener.Hibernation{Enabled: runtimeHibernation.Enabled}
+	if result.Enabled == nil && existing != nil {
+		result.Enabled = existing.Enabled
+	}
+
+	for _, schedule := range runtimeHibernation.Schedules {
+		result.Schedules = append(result.Schedules, gardener.HibernationSchedule{
+			Start:    optionalString(schedule.Start),
+			End:      optionalString(schedule.End),
+			Location: optionalString(schedule.Location),
+		})
+	}
+
+	return result
+}
+
+// HibernationEnabled reports whether the desired state of the shoot is hibernated.
+func HibernationEnabled(shoot *gardener.Shoot) bool {
+	return shoot.Spec.Hibernation != nil && shoot.Spec.Hibernation.Enabled != nil && *shoot.Spec.Hibernation.Enabled
+}
+
+// setControlPlane makes the control plane highly available, Gardener doesn't allow to disable the high availability, it
+// is kept when the Runtime doesn't configure it.
+func setControlPlane(shoot *gardener.Shoot, controlPlane *imv1.RuntimeControlPlane) {
+	if controlPlane == nil {
+		return
+	}
+
+	if shoot.Spec.ControlPlane == nil {
+		shoot.Spec.ControlPlane = &gardener.ControlPlane{}
+	}
+
+	shoot.Spec.ControlPlane.HighAvailability = &gardener.HighAvailability{
+		FailureTolerance: gardener.FailureTolerance{Type: gardener.FailureToleranceType(controlPlane.FailureTolerance)},
+	}
+}
+
+// ControlPlaneFailureTolerance returns the failure tolerance of the control plane of the shoot, empty when it is not
+// highly available.
+func ControlPlaneFailureTolerance(shoot *gardener.Shoot) imv1.FailureToleranceType {
+	if shoot.Spec.ControlPlane == nil || shoot.Spec.ControlPlane.HighAvailability == nil {
+		return ""
+	}
+
+	return imv1.FailureToleranceType(shoot.Spec.ControlPlane.HighAvailability.FailureTolerance.Type)
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
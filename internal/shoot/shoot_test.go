@@ -0,0 +1,625 @@
+package shoot
+
+import (
+	"testing"
+	"time"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func fixRuntime() *imv1.Runtime {
+	return &imv1.Runtime{
+		ObjectMeta: metav1.ObjectMeta{Name: "runtime", Namespace: "kcp-system"},
+		Spec: imv1.RuntimeSpec{
+			Shoot: imv1.RuntimeShoot{
+				Name:              "shoot",
+				Purpose:           "production",
+				Region:            "eu-central-1",
+				CloudProfileName:  "aws",
+				SecretBindingName: "aws-credentials",
+				Provider:          imv1.RuntimeProvider{Type: "aws"},
+				Kubernetes:        imv1.RuntimeKubernetes{Version: "1.27.5"},
+				Workers: []imv1.RuntimeWorker{
+					{Name: "cpu-worker", MachineType: "m5.xlarge", Minimum: 1, Maximum: 3, Zones: []string{"eu-central-1a"}},
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	// when
+	shoot := New(fixRuntime(), "garden-project")
+
+	// then
+	assert.Equal(t, "shoot", shoot.Name)
+	assert.Equal(t, "garden-project", shoot.Namespace)
+	assert.Equal(t, map[string]string{RuntimeNameLabel: "runtime", RuntimeNamespaceLabel: "kcp-system"}, shoot.Labels)
+	assert.Equal(t, "aws", shoot.Spec.CloudProfileName)
+	assert.Equal(t, "eu-central-1", shoot.Spec.Region)
+	assert.Equal(t, "aws-credentials", *shoot.Spec.SecretBindingName)
+	assert.Equal(t, gardener.ShootPurpose("production"), *shoot.Spec.Purpose)
+	assert.Equal(t, "calico", *shoot.Spec.Networking.Type)
+	assert.Equal(t, "1.27.5", shoot.Spec.Kubernetes.Version)
+
+	require.Len(t, shoot.Spec.Provider.Workers, 1)
+	assert.Equal(t, "m5.xlarge", shoot.Spec.Provider.Workers[0].Machine.Type)
+	assert.Equal(t, int32(3), shoot.Spec.Provider.Workers[0].Maximum)
+}
+
+func TestNewNetworking(t *testing.T) {
+	// given
+	runtime := fixRuntime()
+	runtime.Spec.Shoot.Networking = &imv1.RuntimeNetworking{
+		Type:     "cilium",
+		Nodes:    "10.250.0.0/16",
+		Pods:     "100.64.0.0/12",
+		Services: "100.104.0.0/13",
+	}
+
+	// when
+	shoot := New(runtime, "garden-project")
+
+	// then
+	assert.Equal(t, "cilium", *shoot.Spec.Networking.Type)
+	assert.Equal(t, "10.250.0.0/16", *shoot.Spec.Networking.Nodes)
+	assert.Equal(t, "100.64.0.0/12", *shoot.Spec.Networking.Pods)
+	assert.Equal(t, "100.104.0.0/13", *shoot.Spec.Networking.Services)
+}
+
+func TestNewSeedSelection(t *testing.T) {
+	// given
+	runtime := fixRuntime()
+	runtime.Spec.Shoot.SeedName = "aws-eu1"
+	runtime.Spec.Shoot.SeedSelector = &imv1.RuntimeSeedSelector{
+		LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"seed.gardener.cloud/eu-access": "true"}},
+		ProviderTypes: []string{"aws"},
+	}
+
+	// when
+	shoot := New(runtime, "garden-project")
+
+	// then
+	assert.Equal(t, "aws-eu1", *shoot.Spec.SeedName)
+	assert.Equal(t, map[string]string{"seed.gardener.cloud/eu-access": "true"}, shoot.Spec.SeedSelector.MatchLabels)
+	assert.Equal(t, []string{"aws"}, shoot.Spec.SeedSelector.ProviderTypes)
+
+	// when
+	scheduledSeed := "aws-eu2"
+	shoot.Spec.SeedName = &scheduledSeed
+	Update(shoot, runtime)
+
+	// then
+	assert.Equal(t, "aws-eu2", *shoot.Spec.SeedName)
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("should report no change for an up-to-date shoot", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+	})
+
+	t.Run("should apply the spec, and keep the defaults of Gardener", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		imageName := "gardenlinux"
+		shoot.Spec.Provider.Workers[0].Machine.Image = &gardener.ShootMachineImage{Name: imageName}
+		shoot.Spec.Maintenance = &gardener.Maintenance{}
+
+		runtime.Spec.Shoot.Kubernetes.Version = "1.28.2"
+		runtime.Spec.Shoot.Workers[0].Maximum = 5
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, "1.28.2", shoot.Spec.Kubernetes.Version)
+		assert.Equal(t, int32(5), shoot.Spec.Provider.Workers[0].Maximum)
+		assert.Equal(t, imageName, shoot.Spec.Provider.Workers[0].Machine.Image.Name)
+		assert.NotNil(t, shoot.Spec.Maintenance)
+	})
+
+	t.Run("should keep the hibernation state set by the schedules without the manual toggle", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Hibernation = &imv1.RuntimeHibernation{
+			Schedules: []imv1.HibernationSchedule{{Start: "00 20 * * 1-5", End: "00 07 * * 1-5", Location: "Europe/Berlin"}},
+		}
+		shoot := New(runtime, "garden-project")
+
+		hibernated := true
+		shoot.Spec.Hibernation.Enabled = &hibernated
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.True(t, HibernationEnabled(shoot))
+		require.Len(t, shoot.Spec.Hibernation.Schedules, 1)
+		assert.Equal(t, "00 20 * * 1-5", *shoot.Spec.Hibernation.Schedules[0].Start)
+		assert.Equal(t, "Europe/Berlin", *shoot.Spec.Hibernation.Schedules[0].Location)
+	})
+
+	t.Run("should apply the manual toggle over the schedules", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Hibernation = &imv1.RuntimeHibernation{
+			Schedules: []imv1.HibernationSchedule{{Start: "00 20 * * 1-5"}},
+		}
+		shoot := New(runtime, "garden-project")
+
+		hibernated := true
+		shoot.Spec.Hibernation.Enabled = &hibernated
+
+		awake := false
+		runtime.Spec.Hibernation.Enabled = &awake
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.False(t, HibernationEnabled(shoot))
+		assert.Nil(t, shoot.Spec.Hibernation.Schedules[0].End)
+	})
+
+	t.Run("should remove the hibernation of the shoot", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		hibernated := true
+		runtime.Spec.Hibernation = &imv1.RuntimeHibernation{Enabled: &hibernated}
+		shoot := New(runtime, "garden-project")
+
+		runtime.Spec.Hibernation = nil
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Nil(t, shoot.Spec.Hibernation)
+	})
+
+	t.Run("should add, and remove worker pools", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		runtime.Spec.Shoot.Workers = []imv1.RuntimeWorker{{
+			Name:        "gpu-worker",
+			MachineType: "p3.2xlarge",
+			Minimum:     0,
+			Maximum:     2,
+			Volume:      &imv1.RuntimeWorkerVolume{Type: "gp3", Size: "100Gi"},
+			Labels:      map[string]string{"pool": "gpu"},
+			Taints:      []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}},
+		}}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		require.Len(t, shoot.Spec.Provider.Workers, 1)
+
+		worker := shoot.Spec.Provider.Workers[0]
+		assert.Equal(t, "gpu-worker", worker.Name)
+		assert.Equal(t, "100Gi", worker.Volume.VolumeSize)
+		assert.Equal(t, "gp3", *worker.Volume.Type)
+		assert.Equal(t, map[string]string{"pool": "gpu"}, worker.Labels)
+		assert.Len(t, worker.Taints, 1)
+	})
+
+	t.Run("should resize the volume, and keep its defaults", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		encrypted := true
+		shoot.Spec.Provider.Workers[0].Volume = &gardener.Volume{VolumeSize: "50Gi", Encrypted: &encrypted}
+		runtime.Spec.Shoot.Workers[0].Volume = &imv1.RuntimeWorkerVolume{Size: "80Gi"}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, "80Gi", shoot.Spec.Provider.Workers[0].Volume.VolumeSize)
+		assert.True(t, *shoot.Spec.Provider.Workers[0].Volume.Encrypted)
+	})
+}
+
+func TestMaintenance(t *testing.T) {
+	t.Run("should apply the maintenance, and keep the defaults of Gardener", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+		shoot.Spec.Maintenance = &gardener.Maintenance{
+			AutoUpdate: &gardener.MaintenanceAutoUpdate{KubernetesVersion: true},
+			TimeWindow: &gardener.MaintenanceTimeWindow{Begin: "010000+0000", End: "020000+0000"},
+		}
+
+		runtime.Spec.Maintenance = &imv1.RuntimeMaintenance{
+			TimeWindow: &imv1.RuntimeMaintenanceTimeWindow{Begin: "220000+0100", End: "230000+0100"},
+		}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, "220000+0100", shoot.Spec.Maintenance.TimeWindow.Begin)
+		assert.True(t, shoot.Spec.Maintenance.AutoUpdate.KubernetesVersion)
+	})
+
+	t.Run("should apply the auto update settings", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Maintenance = &imv1.RuntimeMaintenance{
+			AutoUpdate: &imv1.RuntimeMaintenanceAutoUpdate{KubernetesVersion: false, MachineImageVersion: true},
+		}
+
+		// when
+		shoot := New(runtime, "garden-project")
+
+		// then
+		assert.False(t, shoot.Spec.Maintenance.AutoUpdate.KubernetesVersion)
+		assert.True(t, *shoot.Spec.Maintenance.AutoUpdate.MachineImageVersion)
+	})
+
+	t.Run("should keep the patch version applied by the maintenance", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+		shoot.Spec.Kubernetes.Version = "1.27.8"
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, "1.27.8", shoot.Spec.Kubernetes.Version)
+	})
+}
+
+func TestOIDC(t *testing.T) {
+	t.Run("should configure the OIDC provider, and keep the other kube-apiserver settings", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		enableAnonymousAuthentication := false
+		shoot.Spec.Kubernetes.KubeAPIServer = &gardener.KubeAPIServerConfig{EnableAnonymousAuthentication: &enableAnonymousAuthentication}
+
+		runtime.Spec.Shoot.Kubernetes.OIDC = &imv1.RuntimeOIDC{
+			IssuerURL:     "https://issuer.example.com",
+			ClientID:      "kyma",
+			GroupsClaim:   "groups",
+			UsernameClaim: "email",
+		}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+
+		oidcConfig := shoot.Spec.Kubernetes.KubeAPIServer.OIDCConfig
+		assert.Equal(t, "https://issuer.example.com", *oidcConfig.IssuerURL)
+		assert.Equal(t, "kyma", *oidcConfig.ClientID)
+		assert.Equal(t, "groups", *oidcConfig.GroupsClaim)
+		assert.Nil(t, oidcConfig.GroupsPrefix)
+		assert.NotNil(t, shoot.Spec.Kubernetes.KubeAPIServer.EnableAnonymousAuthentication)
+	})
+
+	t.Run("should remove the OIDC provider", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.Kubernetes.OIDC = &imv1.RuntimeOIDC{IssuerURL: "https://issuer.example.com", ClientID: "kyma"}
+		shoot := New(runtime, "garden-project")
+
+		runtime.Spec.Shoot.Kubernetes.OIDC = nil
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Nil(t, shoot.Spec.Kubernetes.KubeAPIServer.OIDCConfig)
+	})
+}
+
+func TestClusterAutoscaler(t *testing.T) {
+	t.Run("should apply the autoscaler settings, and keep the defaults of the other ones", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		scanInterval := metav1.Duration{Duration: 10 * time.Second}
+		shoot.Spec.Kubernetes.ClusterAutoscaler = &gardener.ClusterAutoscaler{ScanInterval: &scanInterval}
+
+		runtime.Spec.Shoot.Kubernetes.ClusterAutoscaler = &imv1.RuntimeClusterAutoscaler{
+			ScaleDownDelayAfterAdd:        &metav1.Duration{Duration: time.Hour},
+			ScaleDownUtilizationThreshold: "0.4",
+			MaxNodeProvisionTime:          &metav1.Duration{Duration: 30 * time.Minute},
+		}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+
+		autoscaler := shoot.Spec.Kubernetes.ClusterAutoscaler
+		assert.Equal(t, time.Hour, autoscaler.ScaleDownDelayAfterAdd.Duration)
+		assert.Equal(t, 0.4, *autoscaler.ScaleDownUtilizationThreshold)
+		assert.Equal(t, 30*time.Minute, autoscaler.MaxNodeProvisionTime.Duration)
+		assert.Nil(t, autoscaler.ScaleDownUnneededTime)
+		assert.Equal(t, scanInterval, *autoscaler.ScanInterval)
+	})
+
+	t.Run("should keep the autoscaler of the shoot without settings", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		threshold := 0.5
+		shoot.Spec.Kubernetes.ClusterAutoscaler = &gardener.ClusterAutoscaler{ScaleDownUtilizationThreshold: &threshold}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, 0.5, *shoot.Spec.Kubernetes.ClusterAutoscaler.ScaleDownUtilizationThreshold)
+	})
+}
+
+func TestControlPlane(t *testing.T) {
+	t.Run("should make the control plane highly available", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+		assert.Empty(t, ControlPlaneFailureTolerance(shoot))
+
+		runtime.Spec.Shoot.ControlPlane = &imv1.RuntimeControlPlane{FailureTolerance: imv1.FailureToleranceZone}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, gardener.FailureToleranceTypeZone, shoot.Spec.ControlPlane.HighAvailability.FailureTolerance.Type)
+		assert.Equal(t, imv1.FailureToleranceZone, ControlPlaneFailureTolerance(shoot))
+	})
+
+	t.Run("should keep the high availability Gardener doesn't allow to disable", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.ControlPlane = &imv1.RuntimeControlPlane{FailureTolerance: imv1.FailureToleranceNode}
+		shoot := New(runtime, "garden-project")
+
+		runtime.Spec.Shoot.ControlPlane = nil
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, imv1.FailureToleranceNode, ControlPlaneFailureTolerance(shoot))
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Run("should reference the audit policy, and add the backend extension", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.AuditLog = &imv1.RuntimeAuditLog{
+			PolicyConfigMapName: "audit-policy",
+			Backend:             &imv1.RuntimeAuditLogBackend{Type: "shoot-auditlog-service"},
+		}
+
+		// when
+		shoot := New(runtime, "garden-project")
+
+		// then
+		assert.Equal(t, "audit-policy", shoot.Spec.Kubernetes.KubeAPIServer.AuditConfig.AuditPolicy.ConfigMapRef.Name)
+		assert.Equal(t, []gardener.Extension{{Type: "shoot-auditlog-service"}}, shoot.Spec.Extensions)
+		assert.Equal(t, "shoot-auditlog-service", shoot.Annotations[AuditLogExtensionAnnotation])
+		assert.False(t, Update(shoot, runtime))
+	})
+
+	t.Run("should replace the managed extension, and keep the other extensions", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.AuditLog = &imv1.RuntimeAuditLog{Backend: &imv1.RuntimeAuditLogBackend{Type: "shoot-auditlog-service"}}
+		shoot := New(runtime, "garden-project")
+		shoot.Spec.Extensions = append([]gardener.Extension{{Type: "shoot-dns-service"}}, shoot.Spec.Extensions...)
+		shoot.Spec.Extensions = append(shoot.Spec.Extensions, gardener.Extension{Type: "shoot-cert-service"})
+
+		runtime.Spec.Shoot.AuditLog.Backend.Type = "shoot-auditlog-forwarder"
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, []gardener.Extension{
+			{Type: "shoot-dns-service"},
+			{Type: "shoot-auditlog-forwarder"},
+			{Type: "shoot-cert-service"},
+		}, shoot.Spec.Extensions)
+	})
+
+	t.Run("should remove the audit log", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.AuditLog = &imv1.RuntimeAuditLog{
+			PolicyConfigMapName: "audit-policy",
+			Backend:             &imv1.RuntimeAuditLogBackend{Type: "shoot-auditlog-service"},
+		}
+		shoot := New(runtime, "garden-project")
+
+		runtime.Spec.Shoot.AuditLog = nil
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Nil(t, shoot.Spec.Kubernetes.KubeAPIServer.AuditConfig)
+		assert.Empty(t, shoot.Spec.Extensions)
+		assert.NotContains(t, shoot.Annotations, AuditLogExtensionAnnotation)
+	})
+}
+
+func TestExtensions(t *testing.T) {
+	t.Run("should enable, and disable the listed extensions, and keep the other extensions", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+		shoot.Spec.Extensions = []gardener.Extension{{Type: "shoot-cert-service"}}
+
+		disabled := true
+		filterConfig := &k8sruntime.RawExtension{Raw: []byte(`{"egressFilter":{"blackholingEnabled":true}}`)}
+		runtime.Spec.Shoot.Extensions = []imv1.RuntimeExtension{
+			{Type: "shoot-networking-filter", ProviderConfig: filterConfig},
+			{Type: "shoot-oidc-service", Disabled: &disabled},
+		}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, []gardener.Extension{
+			{Type: "shoot-cert-service"},
+			{Type: "shoot-networking-filter", ProviderConfig: filterConfig},
+			{Type: "shoot-oidc-service", Disabled: &disabled},
+		}, shoot.Spec.Extensions)
+		assert.Equal(t, "shoot-networking-filter,shoot-oidc-service", shoot.Annotations[ExtensionsAnnotation])
+		assert.False(t, Update(shoot, runtime))
+	})
+
+	t.Run("should remove the extensions removed from the list", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.Extensions = []imv1.RuntimeExtension{{Type: "shoot-networking-filter"}, {Type: "shoot-oidc-service"}}
+		shoot := New(runtime, "garden-project")
+		shoot.Spec.Extensions = append(shoot.Spec.Extensions, gardener.Extension{Type: "shoot-cert-service"})
+
+		runtime.Spec.Shoot.Extensions = runtime.Spec.Shoot.Extensions[1:]
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, []gardener.Extension{{Type: "shoot-oidc-service"}, {Type: "shoot-cert-service"}}, shoot.Spec.Extensions)
+
+		// when
+		runtime.Spec.Shoot.Extensions = nil
+		Update(shoot, runtime)
+
+		// then
+		assert.Equal(t, []gardener.Extension{{Type: "shoot-cert-service"}}, shoot.Spec.Extensions)
+		assert.NotContains(t, shoot.Annotations, ExtensionsAnnotation)
+	})
+}
+
+func TestDNS(t *testing.T) {
+	t.Run("should configure the custom domain, and the DNS extension", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		runtime.Spec.Shoot.DNS = &imv1.RuntimeDNS{
+			Domain:   "cluster.example.com",
+			Provider: imv1.RuntimeDNSProvider{Type: "aws-route53", SecretName: "route53-credentials"},
+		}
+
+		// when
+		shoot := New(runtime, "garden-project")
+
+		// then
+		assert.Equal(t, "cluster.example.com", *shoot.Spec.DNS.Domain)
+		require.Len(t, shoot.Spec.DNS.Providers, 1)
+		assert.True(t, *shoot.Spec.DNS.Providers[0].Primary)
+		assert.Equal(t, "aws-route53", *shoot.Spec.DNS.Providers[0].Type)
+		assert.Equal(t, "route53-credentials", *shoot.Spec.DNS.Providers[0].SecretName)
+		assert.Equal(t, []string{"cluster.example.com"}, shoot.Spec.DNS.Providers[0].Domains.Include)
+
+		require.Len(t, shoot.Spec.Extensions, 1)
+		assert.Equal(t, "shoot-dns-service", shoot.Spec.Extensions[0].Type)
+		assert.JSONEq(t, `{"apiVersion":"service.dns.extensions.gardener.cloud/v1alpha1","kind":"DNSConfig","syncProvidersFromShootSpecDNS":true}`,
+			string(shoot.Spec.Extensions[0].ProviderConfig.Raw))
+		assert.False(t, Update(shoot, runtime))
+	})
+
+	t.Run("should keep the default domain of Gardener", func(t *testing.T) {
+		// given
+		runtime := fixRuntime()
+		shoot := New(runtime, "garden-project")
+
+		domain := "shoot.project.default.example.com"
+		shoot.Spec.DNS = &gardener.DNS{Domain: &domain}
+
+		// when
+		changed := Update(shoot, runtime)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, domain, *shoot.Spec.DNS.Domain)
+	})
+}
+
+func TestWorkerStatuses(t *testing.T) {
+	t.Run("should report the pools in the given state once Gardener observed the shoot", func(t *testing.T) {
+		// given
+		shoot := New(fixRuntime(), "garden-project")
+		shoot.Generation = 2
+		shoot.Status.ObservedGeneration = 2
+
+		// when
+		statuses := WorkerStatuses(shoot, imv1.ReadyState)
+
+		// then
+		assert.Equal(t, []imv1.RuntimeWorkerStatus{{
+			Name:        "cpu-worker",
+			State:       imv1.ReadyState,
+			MachineType: "m5.xlarge",
+			Minimum:     1,
+			Maximum:     3,
+			Zones:       []string{"eu-central-1a"},
+		}}, statuses)
+	})
+
+	t.Run("should report the pools processing before Gardener observed the shoot", func(t *testing.T) {
+		// given
+		shoot := New(fixRuntime(), "garden-project")
+		shoot.Generation = 3
+		shoot.Status.ObservedGeneration = 2
+
+		// when
+		statuses := WorkerStatuses(shoot, imv1.ReadyState)
+
+		// then
+		require.Len(t, statuses, 1)
+		assert.Equal(t, imv1.ProcessingState, statuses[0].State)
+	})
+}
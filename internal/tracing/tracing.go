@@ -0,0 +1,75 @@
+// Package tracing exports OpenTelemetry spans of the kubeconfig management to an OTLP collector.
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "infrastructure-manager"
+	tracerName  = "github.com/kyma-project/infrastructure-manager"
+)
+
+// Setup installs a global tracer provider exporting the spans to the OTLP gRPC endpoint, with the given fraction of the
+// traces sampled. The returned function flushes the pending spans, and must be called before the process exits.
+func Setup(ctx context.Context, endpoint string, insecure bool, samplingRatio float64) (func(context.Context) error, error) {
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create OTLP trace exporter")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span of the infrastructure manager, it is a no-op when tracing is not set up.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records the error on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// ClusterAttributes identify the GardenerCluster a span belongs to.
+func ClusterAttributes(name, namespace string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("gardenercluster.name", name),
+		attribute.String("gardenercluster.namespace", namespace),
+	}
+}
+
+// SetShoot adds the shoot to the current span, it is known only once the GardenerCluster has been fetched.
+func SetShoot(ctx context.Context, shootName string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("shoot.name", shootName))
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	t.Run("should nest the spans, and record the errors", func(t *testing.T) {
+		// given
+		ctx, parent := StartSpan(context.Background(), "GardenerCluster.Reconcile", ClusterAttributes("cluster", "kcp-system")...)
+		SetShoot(ctx, "shoot")
+
+		// when
+		_, child := StartSpan(ctx, "Gardener.RequestKubeconfig")
+		End(child, errors.New("shoot not found"))
+		End(parent, nil)
+
+		// then
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+
+		assert.Equal(t, "Gardener.RequestKubeconfig", spans[0].Name())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+		assert.Equal(t, codes.Unset, spans[1].Status().Code)
+		assert.ElementsMatch(t, []attribute.KeyValue{
+			attribute.String("gardenercluster.name", "cluster"),
+			attribute.String("gardenercluster.namespace", "kcp-system"),
+			attribute.String("shoot.name", "shoot"),
+		}, spans[1].Attributes())
+	})
+}
@@ -0,0 +1,285 @@
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates;issuers,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;patch
+
+const (
+	injectCAAnnotation = "cert-manager.io/inject-ca-from"
+	selfSignedKind     = "Issuer"
+)
+
+//nolint:gochecknoglobals
+var (
+	certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+	issuerGVK      = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}
+
+	validatingWebhookConfigurationGVK = schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"}
+	mutatingWebhookConfigurationGVK   = schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"}
+	crdGVK                            = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+)
+
+// Config defines the cert-manager resources of the webhook serving certificate, and the webhooks it is injected into.
+type Config struct {
+	Namespace       string
+	ServiceName     string
+	CertificateName string
+	SecretName      string
+
+	// IssuerName references the issuer of the certificate, a self-signed issuer with the name is created when IssuerKind is empty.
+	IssuerName string
+	IssuerKind string
+
+	// CertDir is the directory the webhook server reads the certificate from.
+	CertDir string
+
+	ValidatingWebhookConfigurations []string
+	MutatingWebhookConfigurations   []string
+	ConversionCRDs                  []string
+}
+
+// CertManager sources the webhook serving certificate from cert-manager. It maintains the Certificate, makes cert-manager
+// inject its CA into the webhook configurations, and copies the issued certificate into the certificate directory, where
+// the webhook server picks up every renewal.
+type CertManager struct {
+	client   client.Client
+	config   Config
+	interval time.Duration
+	log      logr.Logger
+}
+
+func NewCertManager(k8sClient client.Client, config Config, interval time.Duration, logger logr.Logger) *CertManager {
+	return &CertManager{
+		client:   k8sClient,
+		config:   config,
+		interval: interval,
+		log:      logger,
+	}
+}
+
+// Bootstrap creates the cert-manager resources, and waits until the certificate has been issued and written to the certificate
+// directory. It must complete before the webhook server starts.
+func (manager *CertManager) Bootstrap(ctx context.Context, timeout time.Duration) error {
+	err := manager.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		_, err = manager.syncCertificateFiles(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Errorf("certificate %s/%s has not been issued in time", manager.config.Namespace, manager.config.CertificateName)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Start reconciles the cert-manager resources, and syncs the renewed certificates every interval until the context
+// is cancelled. It is called by the manager.
+func (manager *CertManager) Start(ctx context.Context) error {
+	ticker := time.NewTicker(manager.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := manager.Reconcile(ctx)
+			if err == nil {
+				_, err = manager.syncCertificateFiles(ctx)
+			}
+
+			if err != nil {
+				manager.log.Error(err, "Failed to reconcile webhook serving certificate")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable, every replica serves the webhooks.
+func (manager *CertManager) NeedLeaderElection() bool {
+	return false
+}
+
+// Reconcile creates, or restores the issuer, the Certificate, and the CA injection annotations.
+func (manager *CertManager) Reconcile(ctx context.Context) error {
+	issuerKind := manager.config.IssuerKind
+
+	if issuerKind == "" {
+		issuerKind = selfSignedKind
+
+		err := manager.reconcileSelfSignedIssuer(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to reconcile self-signed issuer")
+		}
+	}
+
+	err := manager.reconcileCertificate(ctx, issuerKind)
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile certificate")
+	}
+
+	return manager.injectCA(ctx)
+}
+
+func (manager *CertManager) reconcileSelfSignedIssuer(ctx context.Context) error {
+	issuer := newObject(issuerGVK, manager.config.Namespace, manager.config.IssuerName)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, manager.client, issuer, func() error {
+		return unstructured.SetNestedMap(issuer.Object, map[string]any{"selfSigned": map[string]any{}}, "spec")
+	})
+
+	return err
+}
+
+func (manager *CertManager) reconcileCertificate(ctx context.Context, issuerKind string) error {
+	certificate := newObject(certificateGVK, manager.config.Namespace, manager.config.CertificateName)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, manager.client, certificate, func() error {
+		return unstructured.SetNestedMap(certificate.Object, map[string]any{
+			"dnsNames": []any{
+				fmt.Sprintf("%s.%s.svc", manager.config.ServiceName, manager.config.Namespace),
+				fmt.Sprintf("%s.%s.svc.cluster.local", manager.config.ServiceName, manager.config.Namespace),
+			},
+			"issuerRef": map[string]any{
+				"kind": issuerKind,
+				"name": manager.config.IssuerName,
+			},
+			"secretName": manager.config.SecretName,
+		}, "spec")
+	})
+
+	return err
+}
+
+// injectCA annotates the webhook configurations, and the CRDs with conversion webhooks, so that the cert-injector of cert-manager
+// keeps their CA bundle in sync with the certificate.
+func (manager *CertManager) injectCA(ctx context.Context) error {
+	certificateRef := fmt.Sprintf("%s/%s", manager.config.Namespace, manager.config.CertificateName)
+
+	for gvk, names := range map[schema.GroupVersionKind][]string{
+		validatingWebhookConfigurationGVK: manager.config.ValidatingWebhookConfigurations,
+		mutatingWebhookConfigurationGVK:   manager.config.MutatingWebhookConfigurations,
+		crdGVK:                            manager.config.ConversionCRDs,
+	} {
+		for _, name := range names {
+			err := manager.annotate(ctx, gvk, name, certificateRef)
+			if err != nil {
+				return errors.Wrapf(err, "failed to request CA injection into %s %s", gvk.Kind, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (manager *CertManager) annotate(ctx context.Context, gvk schema.GroupVersionKind, name, certificateRef string) error {
+	object := &metav1.PartialObjectMetadata{}
+	object.SetGroupVersionKind(gvk)
+
+	err := manager.client.Get(ctx, types.NamespacedName{Name: name}, object)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if object.GetAnnotations()[injectCAAnnotation] == certificateRef {
+		return nil
+	}
+
+	patch := client.MergeFrom(object.DeepCopy())
+
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[injectCAAnnotation] = certificateRef
+	object.SetAnnotations(annotations)
+
+	return manager.client.Patch(ctx, object, patch)
+}
+
+// syncCertificateFiles writes the certificate, and the key of the issued certificate to the certificate directory,
+// and reports whether the files have changed.
+func (manager *CertManager) syncCertificateFiles(ctx context.Context) (bool, error) {
+	var secret corev1.Secret
+
+	err := manager.client.Get(ctx, types.NamespacedName{Name: manager.config.SecretName, Namespace: manager.config.Namespace}, &secret)
+	if err != nil {
+		return false, err
+	}
+
+	certificate, key := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certificate) == 0 || len(key) == 0 {
+		return false, k8serrors.NewNotFound(corev1.Resource("secrets"), secret.Name)
+	}
+
+	certificatePath := filepath.Join(manager.config.CertDir, corev1.TLSCertKey)
+
+	existing, err := os.ReadFile(certificatePath)
+	if err == nil && bytes.Equal(existing, certificate) {
+		return false, nil
+	}
+
+	err = os.MkdirAll(manager.config.CertDir, 0o700) //nolint:gomnd
+	if err != nil {
+		return false, err
+	}
+
+	// the key is written first, the webhook server reloads when the certificate changes
+	err = os.WriteFile(filepath.Join(manager.config.CertDir, corev1.TLSPrivateKeyKey), key, 0o600) //nolint:gomnd
+	if err != nil {
+		return false, err
+	}
+
+	err = os.WriteFile(certificatePath, certificate, 0o600) //nolint:gomnd
+	if err != nil {
+		return false, err
+	}
+
+	manager.log.Info("Webhook serving certificate updated", "secret", secret.Name)
+
+	return true, nil
+}
+
+func newObject(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+
+	return object
+}
@@ -0,0 +1,111 @@
+package webhookcert
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCertManager(t *testing.T) {
+	newConfig := func(certDir string) Config {
+		return Config{
+			Namespace:                       "kcp-system",
+			ServiceName:                     "webhook-service",
+			CertificateName:                 "serving-cert",
+			SecretName:                      "webhook-server-cert",
+			IssuerName:                      "selfsigned-issuer",
+			CertDir:                         certDir,
+			ValidatingWebhookConfigurations: []string{"validating-webhook-configuration"},
+		}
+	}
+
+	newClient := func(objects ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		_ = clientgoscheme.AddToScheme(scheme)
+
+		for _, gvk := range []struct{ kind, list string }{{"Certificate", "CertificateList"}, {"Issuer", "IssuerList"}} {
+			scheme.AddKnownTypeWithName(certificateGVK.GroupVersion().WithKind(gvk.kind), &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(certificateGVK.GroupVersion().WithKind(gvk.list), &unstructured.UnstructuredList{})
+		}
+
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	}
+
+	t.Run("should create certificate, and request CA injection", func(t *testing.T) {
+		// given
+		k8sClient := newClient(&admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "validating-webhook-configuration"}})
+		manager := NewCertManager(k8sClient, newConfig(t.TempDir()), time.Minute, logr.Discard())
+
+		// when
+		err := manager.Reconcile(context.Background())
+
+		// then
+		require.NoError(t, err)
+
+		certificate := newObject(certificateGVK, "", "")
+		require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "serving-cert", Namespace: "kcp-system"}, certificate))
+
+		dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+		assert.Contains(t, dnsNames, "webhook-service.kcp-system.svc")
+
+		issuerKind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+		assert.Equal(t, "Issuer", issuerKind)
+
+		issuer := newObject(issuerGVK, "", "")
+		require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "selfsigned-issuer", Namespace: "kcp-system"}, issuer))
+
+		var webhookConfiguration admissionregistrationv1.ValidatingWebhookConfiguration
+		require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "validating-webhook-configuration"}, &webhookConfiguration))
+		assert.Equal(t, "kcp-system/serving-cert", webhookConfiguration.Annotations[injectCAAnnotation])
+	})
+
+	t.Run("should write issued certificate to the certificate directory", func(t *testing.T) {
+		// given
+		certDir := t.TempDir()
+		k8sClient := newClient(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-server-cert", Namespace: "kcp-system"},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("certificate"), corev1.TLSPrivateKeyKey: []byte("key")},
+		})
+		manager := NewCertManager(k8sClient, newConfig(certDir), time.Minute, logr.Discard())
+
+		// when
+		err := manager.Bootstrap(context.Background(), time.Second)
+
+		// then
+		require.NoError(t, err)
+
+		certificate, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+		require.NoError(t, err)
+		assert.Equal(t, "certificate", string(certificate))
+
+		written, err := manager.syncCertificateFiles(context.Background())
+		require.NoError(t, err)
+		assert.False(t, written)
+	})
+
+	t.Run("should fail when certificate is not issued in time", func(t *testing.T) {
+		// given
+		manager := NewCertManager(newClient(), newConfig(t.TempDir()), time.Minute, logr.Discard())
+
+		// when
+		err := manager.Bootstrap(context.Background(), time.Second)
+
+		// then
+		require.Error(t, err)
+	})
+}
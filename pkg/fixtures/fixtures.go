@@ -0,0 +1,226 @@
+// Package fixtures generates the manifests of synthetic clusters for the scale tests, and the demo environments. The
+// clusters vary in their namespaces, rotation settings, and secrets, and the shapes are derived from the index of the
+// cluster, so the same options always generate the same manifests.
+package fixtures
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultNamespace of the clusters when no namespaces are given.
+	DefaultNamespace = "kcp-system"
+	// DefaultPrefix of the names of the clusters, and their shoots.
+	DefaultPrefix = "fx"
+
+	// maxShootNameLength is the limit of Gardener for the shoot names, enforced by the Runtime CRD.
+	maxShootNameLength = 21
+	// the intervals of the shapes are primes, so that the combinations of the shapes repeat rarely
+	immutableEvery         = 5
+	additionalSecretsEvery = 7
+)
+
+// rotationIntervals are cycled through, nil keeps the rotation period of the controller.
+var rotationIntervals = []*metav1.Duration{ //nolint:gochecknoglobals
+	nil,
+	{Duration: 6 * time.Hour},
+	{Duration: 12 * time.Hour},
+	nil,
+	{Duration: 48 * time.Hour},
+}
+
+// purposes are cycled through, they select the default rotation interval of the clusters by purpose.
+var purposes = []imv1.Purpose{imv1.PurposeEvaluation, imv1.PurposeDevelopment, imv1.PurposeProduction} //nolint:gochecknoglobals
+
+// providers are cycled through for the Runtimes, with a region of the provider.
+var providers = []struct { //nolint:gochecknoglobals
+	providerType string
+	region       string
+	machineType  string
+}{
+	{imv1.ProviderTypeAWS, "eu-central-1", "m5.xlarge"},
+	{imv1.ProviderTypeGCP, "europe-west3", "n2-standard-4"},
+	{imv1.ProviderTypeAzure, "westeurope", "Standard_D4s_v5"},
+}
+
+// Options select the clusters to generate.
+type Options struct {
+	// Count of the clusters.
+	Count int
+	// Namespaces the clusters are spread across, DefaultNamespace when empty.
+	Namespaces []string
+	// Prefix of the names of the clusters, and their shoots, DefaultPrefix when empty.
+	Prefix string
+	// Runtimes generates a Runtime for each cluster, with the shoot of the cluster.
+	Runtimes bool
+}
+
+// Fixture is a generated cluster, and its Runtime if requested.
+type Fixture struct {
+	Cluster *imv1.GardenerCluster
+	Runtime *imv1.Runtime
+}
+
+// Generate returns the fixtures of the clusters.
+func Generate(options Options) ([]Fixture, error) {
+	if options.Count < 0 {
+		return nil, errors.Errorf("count must not be negative, got %d", options.Count)
+	}
+
+	namespaces := options.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{DefaultNamespace}
+	}
+
+	prefix := options.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	fixtures := make([]Fixture, 0, options.Count)
+
+	for i := 0; i < options.Count; i++ {
+		name := fmt.Sprintf("%s-%05d", prefix, i)
+		if len(name) > maxShootNameLength {
+			return nil, errors.Errorf("shoot name %s is longer than %d characters, use a shorter prefix", name, maxShootNameLength)
+		}
+
+		if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+			return nil, errors.Errorf("invalid name %s: %v", name, msgs)
+		}
+
+		fixture := Fixture{Cluster: newCluster(i, name, namespaces[i%len(namespaces)])}
+		if options.Runtimes {
+			fixture.Runtime = newRuntime(i, name, fixture.Cluster.Namespace)
+		}
+
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+// Write writes the manifests of the fixtures as a multi-document YAML, the Runtime before its cluster.
+func Write(out io.Writer, fixtures []Fixture) error {
+	for _, fixture := range fixtures {
+		objects := []runtime.Object{fixture.Cluster}
+		if fixture.Runtime != nil {
+			objects = []runtime.Object{fixture.Runtime, fixture.Cluster}
+		}
+
+		for _, object := range objects {
+			manifest, err := toManifest(object)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(out, "---\n%s", manifest); err != nil {
+				return errors.Wrap(err, "failed to write the manifest")
+			}
+		}
+	}
+
+	return nil
+}
+
+// toManifest returns the YAML of the object without the status, and the fields set by the API server.
+func toManifest(object runtime.Object) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the object")
+	}
+
+	unstructured.RemoveNestedField(content, "status")
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+
+	manifest, err := yaml.Marshal(content)
+
+	return manifest, errors.Wrap(err, "failed to marshal the object")
+}
+
+func newCluster(index int, name, namespace string) *imv1.GardenerCluster {
+	cluster := &imv1.GardenerCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: imv1.GroupVersion.String(),
+			Kind:       "GardenerCluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"kyma-project.io/runtime-id": name,
+				"kyma-project.io/shoot-name": name,
+			},
+		},
+		Spec: imv1.GardenerClusterSpec{
+			Shoot: imv1.Shoot{
+				Name: name,
+			},
+			Kubeconfig: imv1.Kubeconfig{
+				Secret: imv1.Secret{
+					Name:      "kubeconfig-" + name,
+					Namespace: namespace,
+					Key:       "config",
+				},
+				RotationInterval: rotationIntervals[index%len(rotationIntervals)],
+				Immutable:        index%immutableEvery == immutableEvery-1,
+			},
+		},
+	}
+
+	if index%additionalSecretsEvery == additionalSecretsEvery-1 {
+		cluster.Spec.Kubeconfig.AdditionalSecrets = []imv1.Secret{{
+			Name:      "kubeconfig-" + name + "-replica",
+			Namespace: namespace,
+			Key:       "config",
+		}}
+	}
+
+	return cluster
+}
+
+func newRuntime(index int, name, namespace string) *imv1.Runtime {
+	provider := providers[index%len(providers)]
+
+	return &imv1.Runtime{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: imv1.GroupVersion.String(),
+			Kind:       "Runtime",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: imv1.RuntimeSpec{
+			Shoot: imv1.RuntimeShoot{
+				Name:              name,
+				Purpose:           purposes[index%len(purposes)],
+				Region:            provider.region,
+				CloudProfileName:  provider.providerType,
+				SecretBindingName: provider.providerType + "-credentials",
+				Provider: imv1.RuntimeProvider{
+					Type: provider.providerType,
+				},
+				Kubernetes: imv1.RuntimeKubernetes{
+					Version: "1.27.6",
+				},
+				Workers: []imv1.RuntimeWorker{{
+					Name:        "cpu-worker",
+					MachineType: provider.machineType,
+					Minimum:     1,
+					Maximum:     3, //nolint:gomnd
+				}},
+			},
+		},
+	}
+}
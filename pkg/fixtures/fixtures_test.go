@@ -0,0 +1,98 @@
+package fixtures
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("should spread the clusters across the namespaces", func(t *testing.T) {
+		// when
+		fixtures, err := Generate(Options{Count: 4, Namespaces: []string{"a", "b"}})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, fixtures, 4)
+		assert.Equal(t, "a", fixtures[0].Cluster.Namespace)
+		assert.Equal(t, "b", fixtures[1].Cluster.Namespace)
+		assert.Equal(t, "a", fixtures[2].Cluster.Namespace)
+		assert.Nil(t, fixtures[0].Runtime)
+	})
+
+	t.Run("should vary the shapes of the clusters", func(t *testing.T) {
+		// when
+		fixtures, err := Generate(Options{Count: 35})
+
+		// then
+		require.NoError(t, err)
+		var rotated, immutable, replicated int
+		for _, fixture := range fixtures {
+			if fixture.Cluster.Spec.Kubeconfig.RotationInterval != nil {
+				rotated++
+			}
+			if fixture.Cluster.Spec.Kubeconfig.Immutable {
+				immutable++
+			}
+			if len(fixture.Cluster.Spec.Kubeconfig.AdditionalSecrets) > 0 {
+				replicated++
+			}
+		}
+		assert.Equal(t, 21, rotated)
+		assert.Equal(t, 7, immutable)
+		assert.Equal(t, 5, replicated)
+	})
+
+	t.Run("should generate valid clusters, and Runtimes", func(t *testing.T) {
+		// when
+		fixtures, err := Generate(Options{Count: 6, Runtimes: true})
+
+		// then
+		require.NoError(t, err)
+		for _, fixture := range fixtures {
+			_, err := fixture.Cluster.ValidateCreate()
+			assert.NoError(t, err)
+			require.NotNil(t, fixture.Runtime)
+			_, err = fixture.Runtime.ValidateCreate()
+			assert.NoError(t, err)
+			assert.Equal(t, fixture.Cluster.Spec.Shoot.Name, fixture.Runtime.Spec.Shoot.Name)
+		}
+	})
+
+	t.Run("should reject a prefix making the shoot names too long", func(t *testing.T) {
+		// when
+		_, err := Generate(Options{Count: 1, Prefix: "a-very-long-prefix"})
+
+		// then
+		assert.ErrorContains(t, err, "longer than 21 characters")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("should write the manifests without the status", func(t *testing.T) {
+		// given
+		fixtures, err := Generate(Options{Count: 2, Runtimes: true})
+		require.NoError(t, err)
+		var out bytes.Buffer
+
+		// when
+		err = Write(&out, fixtures)
+
+		// then
+		require.NoError(t, err)
+		documents := strings.Split(strings.TrimPrefix(out.String(), "---\n"), "---\n")
+		require.Len(t, documents, 4)
+		assert.NotContains(t, out.String(), "status:")
+		assert.NotContains(t, out.String(), "creationTimestamp")
+
+		var cluster imv1.GardenerCluster
+		require.NoError(t, yaml.UnmarshalStrict([]byte(documents[1]), &cluster))
+		assert.Equal(t, "GardenerCluster", cluster.Kind)
+		assert.Equal(t, *fixtures[0].Cluster, cluster)
+	})
+}
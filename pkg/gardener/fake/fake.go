@@ -0,0 +1,291 @@
+// Package fake provides an in-memory Gardener for the tests of the code built on the kubeconfig providers of the
+// infrastructure manager, so that they run without a Gardener landscape. The shoots, the validity of the issued
+// kubeconfigs, and the failures of the requests are programmable.
+package fake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// AnyShoot injects a failure into the requests for all shoots.
+const AnyShoot = ""
+
+// DefaultTTL is the validity of the kubeconfigs requested without an expiration, like the default of the controller.
+const DefaultTTL = 24 * time.Hour
+
+// Operation identifies a request sent to Gardener.
+type Operation string
+
+const (
+	// OperationRequestKubeconfig is the request for an admin kubeconfig of a shoot.
+	OperationRequestKubeconfig Operation = "RequestKubeconfig"
+	// OperationGetShoot is the request for the shoot information.
+	OperationGetShoot Operation = "GetShoot"
+)
+
+// Request is a request received by the fake Gardener.
+type Request struct {
+	Operation Operation
+	ShootName string
+	// Expiration is the validity requested for the kubeconfig, zero for the other operations.
+	Expiration time.Duration
+}
+
+// Gardener implements the kubeconfig provider of the controller, the Fetch, FetchWithExpiration, and FetchShootInfo
+// methods, with shoots kept in memory. It is safe for concurrent use.
+type Gardener struct {
+	mu sync.Mutex
+
+	ttl    time.Duration
+	maxTTL time.Duration
+	now    func() time.Time
+
+	shoots         map[string]imv1.ShootInfo
+	shootsOnDemand bool
+	failures       map[string]*failure
+	requests       []Request
+	issued         int
+}
+
+// failure is returned for the given number of requests, or until it is cleared when the number is not positive.
+type failure struct {
+	err   error
+	times int
+}
+
+// New creates a Gardener without shoots, issuing the kubeconfigs for DefaultTTL when no expiration is requested.
+func New() *Gardener {
+	return &Gardener{
+		ttl:      DefaultTTL,
+		now:      time.Now,
+		shoots:   map[string]imv1.ShootInfo{},
+		failures: map[string]*failure{},
+	}
+}
+
+// WithTTL sets the validity of the kubeconfigs requested without an expiration.
+func (gardener *Gardener) WithTTL(ttl time.Duration) *Gardener {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	gardener.ttl = ttl
+
+	return gardener
+}
+
+// WithMaxTTL caps the validity of the issued kubeconfigs, like a Gardener issuing shorter kubeconfigs than requested.
+// Zero removes the cap.
+func (gardener *Gardener) WithMaxTTL(maxTTL time.Duration) *Gardener {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	gardener.maxTTL = maxTTL
+
+	return gardener
+}
+
+// WithClock replaces the clock the expiration of the issued kubeconfigs is computed with.
+func (gardener *Gardener) WithClock(now func() time.Time) *Gardener {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	gardener.now = now
+
+	return gardener
+}
+
+// WithShootsOnDemand makes the requests for a missing shoot create it with the information of DefaultShootInfo, so that
+// any cluster gets a kubeconfig without setting up its shoot first, e.g. when running the manager locally.
+func (gardener *Gardener) WithShootsOnDemand() *Gardener {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	gardener.shootsOnDemand = true
+
+	return gardener
+}
+
+// DefaultShootInfo returns the information of the shoots created on demand. It depends on the name of the shoot only, so
+// the UID is kept when the fake Gardener is created again, e.g. after a restart of the manager.
+func DefaultShootInfo(name string) imv1.ShootInfo {
+	hash := sha256.Sum256([]byte(name))
+
+	return imv1.ShootInfo{
+		UID:               types.UID(hex.EncodeToString(hash[:16])),
+		ProviderType:      "local",
+		Region:            "local",
+		KubernetesVersion: "1.27.6",
+		Purpose:           imv1.PurposeEvaluation,
+		Domain:            fmt.Sprintf("%s.example.com", name),
+		ExternalEndpoint:  fmt.Sprintf("https://api.%s.example.com", name),
+	}
+}
+
+// AddShoot creates the shoot, or replaces it. A shoot without a UID gets a new one, so that adding a deleted shoot
+// again looks like a shoot recreated with the same name.
+func (gardener *Gardener) AddShoot(name string, info imv1.ShootInfo) {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	if info.UID == "" {
+		gardener.issued++
+		info.UID = types.UID(fmt.Sprintf("%s-%d", name, gardener.issued))
+	}
+
+	gardener.shoots[name] = info
+}
+
+// DeleteShoot removes the shoot, the requests for it fail with a NotFound error.
+func (gardener *Gardener) DeleteShoot(name string) {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	delete(gardener.shoots, name)
+}
+
+// HasShoot reports whether the shoot exists, without recording a request.
+func (gardener *Gardener) HasShoot(name string) bool {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	_, found := gardener.shoots[name]
+
+	return found
+}
+
+// Fail makes the requests for the shoot, or for all shoots with AnyShoot, return the error. The error is returned for
+// the given number of requests, or until Recover is called when the number is not positive.
+func (gardener *Gardener) Fail(shootName string, err error, times int) {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	gardener.failures[shootName] = &failure{err: err, times: times}
+}
+
+// Recover clears the failure injected for the shoot, or for all shoots with AnyShoot.
+func (gardener *Gardener) Recover(shootName string) {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	delete(gardener.failures, shootName)
+}
+
+// Requests returns the requests received so far, in the order they were received.
+func (gardener *Gardener) Requests() []Request {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	return append([]Request(nil), gardener.requests...)
+}
+
+// Fetch issues a kubeconfig of the shoot valid for the default TTL.
+func (gardener *Gardener) Fetch(ctx context.Context, shootName string) (string, time.Time, error) {
+	return gardener.FetchWithExpiration(ctx, shootName, 0)
+}
+
+// FetchWithExpiration issues a kubeconfig of the shoot valid for the given duration, capped by the maximal TTL. Each
+// kubeconfig carries a new token, so that the rotations can be told apart.
+func (gardener *Gardener) FetchWithExpiration(_ context.Context, shootName string, expiration time.Duration) (string, time.Time, error) {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	info, err := gardener.receive(Request{Operation: OperationRequestKubeconfig, ShootName: shootName, Expiration: expiration})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if expiration <= 0 {
+		expiration = gardener.ttl
+	}
+
+	if gardener.maxTTL > 0 && expiration > gardener.maxTTL {
+		expiration = gardener.maxTTL
+	}
+
+	gardener.issued++
+
+	kubeconfig, err := newKubeconfig(shootName, info, fmt.Sprintf("%s-token-%d", shootName, gardener.issued))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return kubeconfig, gardener.now().Add(expiration), nil
+}
+
+// FetchShootInfo returns the information of the shoot.
+func (gardener *Gardener) FetchShootInfo(_ context.Context, shootName string) (imv1.ShootInfo, error) {
+	gardener.mu.Lock()
+	defer gardener.mu.Unlock()
+
+	return gardener.receive(Request{Operation: OperationGetShoot, ShootName: shootName})
+}
+
+// LookupShoot returns an error when the shoot doesn't exist, like the lookup of the shoot admission validation.
+func (gardener *Gardener) LookupShoot(ctx context.Context, shootName string) error {
+	_, err := gardener.FetchShootInfo(ctx, shootName)
+
+	return err
+}
+
+// receive records the request, and returns the shoot, or the failure injected for it.
+func (gardener *Gardener) receive(request Request) (imv1.ShootInfo, error) {
+	gardener.requests = append(gardener.requests, request)
+
+	for _, name := range []string{request.ShootName, AnyShoot} {
+		injected, found := gardener.failures[name]
+		if !found {
+			continue
+		}
+
+		if injected.times > 0 {
+			injected.times--
+			if injected.times == 0 {
+				delete(gardener.failures, name)
+			}
+		}
+
+		return imv1.ShootInfo{}, injected.err
+	}
+
+	info, found := gardener.shoots[request.ShootName]
+	if !found && gardener.shootsOnDemand {
+		info, found = DefaultShootInfo(request.ShootName), true
+		gardener.shoots[request.ShootName] = info
+	}
+
+	if !found {
+		return imv1.ShootInfo{}, apierrors.NewNotFound(schema.GroupResource{Group: "core.gardener.cloud", Resource: "shoots"}, request.ShootName)
+	}
+
+	return info, nil
+}
+
+// newKubeconfig returns a kubeconfig of the shoot API server authenticated with the token.
+func newKubeconfig(shootName string, info imv1.ShootInfo, token string) (string, error) {
+	server := info.ExternalEndpoint
+	if server == "" {
+		server = fmt.Sprintf("https://api.%s.example.com", shootName)
+	}
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters[shootName] = &clientcmdapi.Cluster{Server: server}
+	config.AuthInfos[shootName] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[shootName] = &clientcmdapi.Context{Cluster: shootName, AuthInfo: shootName}
+	config.CurrentContext = shootName
+
+	kubeconfig, err := clientcmd.Write(*config)
+
+	return string(kubeconfig), err
+}
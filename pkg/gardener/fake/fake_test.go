@@ -0,0 +1,178 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/controller"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	_ controller.KubeconfigProvider = &Gardener{}
+	_ imv1.ShootLookup              = &Gardener{}
+)
+
+func TestGardener(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("should issue a kubeconfig of the shoot valid for the default TTL", func(t *testing.T) {
+		// given
+		gardener := New().WithClock(clock)
+		gardener.AddShoot("shoot", imv1.ShootInfo{ExternalEndpoint: "https://api.shoot.example.org"})
+
+		// when
+		kubeconfig, expiration, err := gardener.Fetch(context.Background(), "shoot")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(DefaultTTL), expiration)
+		config, err := clientcmd.Load([]byte(kubeconfig))
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.shoot.example.org", config.Clusters[config.CurrentContext].Server)
+	})
+
+	t.Run("should issue a new token with each kubeconfig", func(t *testing.T) {
+		// given
+		gardener := New()
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+
+		// when
+		first, _, err := gardener.Fetch(context.Background(), "shoot")
+		require.NoError(t, err)
+		second, _, err := gardener.Fetch(context.Background(), "shoot")
+		require.NoError(t, err)
+
+		// then
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("should cap the requested expiration with the maximal TTL", func(t *testing.T) {
+		// given
+		gardener := New().WithClock(clock).WithMaxTTL(time.Hour)
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+
+		// when
+		_, expiration, err := gardener.FetchWithExpiration(context.Background(), "shoot", 12*time.Hour)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(time.Hour), expiration)
+	})
+
+	t.Run("should return NotFound for a missing shoot", func(t *testing.T) {
+		// given
+		gardener := New()
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+		gardener.DeleteShoot("shoot")
+
+		// when
+		_, _, err := gardener.Fetch(context.Background(), "shoot")
+		_, infoErr := gardener.FetchShootInfo(context.Background(), "shoot")
+
+		// then
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.True(t, apierrors.IsNotFound(infoErr))
+	})
+
+	t.Run("should give a recreated shoot a new UID", func(t *testing.T) {
+		// given
+		gardener := New()
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+		before, err := gardener.FetchShootInfo(context.Background(), "shoot")
+		require.NoError(t, err)
+
+		// when
+		gardener.DeleteShoot("shoot")
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+
+		// then
+		after, err := gardener.FetchShootInfo(context.Background(), "shoot")
+		require.NoError(t, err)
+		assert.NotEmpty(t, before.UID)
+		assert.NotEqual(t, before.UID, after.UID)
+	})
+
+	t.Run("should fail the requests for the given number of times", func(t *testing.T) {
+		// given
+		gardener := New()
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+		unavailable := errors.New("connection refused")
+
+		// when
+		gardener.Fail("shoot", unavailable, 2)
+
+		// then
+		_, _, err := gardener.Fetch(context.Background(), "shoot")
+		assert.ErrorIs(t, err, unavailable)
+		_, err = gardener.FetchShootInfo(context.Background(), "shoot")
+		assert.ErrorIs(t, err, unavailable)
+		_, _, err = gardener.Fetch(context.Background(), "shoot")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail the requests for all shoots until recovered", func(t *testing.T) {
+		// given
+		gardener := New()
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+		unavailable := errors.New("connection refused")
+		gardener.Fail(AnyShoot, unavailable, 0)
+
+		// when
+		_, _, failed := gardener.Fetch(context.Background(), "shoot")
+		gardener.Recover(AnyShoot)
+		_, _, recovered := gardener.Fetch(context.Background(), "shoot")
+
+		// then
+		assert.ErrorIs(t, failed, unavailable)
+		assert.NoError(t, recovered)
+	})
+
+	t.Run("should create the missing shoots on demand", func(t *testing.T) {
+		// given
+		gardener := New().WithShootsOnDemand()
+
+		// when
+		_, _, err := gardener.Fetch(context.Background(), "shoot")
+		require.NoError(t, err)
+		info, infoErr := gardener.FetchShootInfo(context.Background(), "shoot")
+
+		// then
+		require.NoError(t, infoErr)
+		assert.Equal(t, DefaultShootInfo("shoot"), info)
+		assert.NotEqual(t, DefaultShootInfo("shoot").UID, DefaultShootInfo("other").UID)
+	})
+
+	t.Run("should look the shoots up for the admission validation", func(t *testing.T) {
+		// given
+		gardener := New()
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+
+		// then
+		assert.NoError(t, gardener.LookupShoot(context.Background(), "shoot"))
+		assert.True(t, apierrors.IsNotFound(gardener.LookupShoot(context.Background(), "missing")))
+	})
+
+	t.Run("should record the requests", func(t *testing.T) {
+		// given
+		gardener := New()
+		gardener.AddShoot("shoot", imv1.ShootInfo{})
+
+		// when
+		_, _, _ = gardener.FetchWithExpiration(context.Background(), "shoot", time.Hour)
+		_, _ = gardener.FetchShootInfo(context.Background(), "other")
+
+		// then
+		assert.Equal(t, []Request{
+			{Operation: OperationRequestKubeconfig, ShootName: "shoot", Expiration: time.Hour},
+			{Operation: OperationGetShoot, ShootName: "other"},
+		}, gardener.Requests())
+	})
+}
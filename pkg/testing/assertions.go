@@ -0,0 +1,115 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DefaultSecretKey is the key of the kubeconfig in the secrets of the clusters created with NewCluster.
+const DefaultSecretKey = "config"
+
+// NewCluster returns a cluster of the shoot with its kubeconfig in a secret named after the cluster.
+func NewCluster(name, namespace, shootName string) *imv1.GardenerCluster {
+	return &imv1.GardenerCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: imv1.GardenerClusterSpec{
+			Shoot: imv1.Shoot{
+				Name: shootName,
+			},
+			Kubeconfig: imv1.Kubeconfig{
+				Secret: imv1.Secret{
+					Name:      name,
+					Namespace: namespace,
+					Key:       DefaultSecretKey,
+				},
+			},
+		},
+	}
+}
+
+// CreateCluster creates the cluster, and adds its shoot to the fake Gardener unless it is already there.
+func (env *Environment) CreateCluster(t T, cluster *imv1.GardenerCluster) {
+	t.Helper()
+
+	if !env.Gardener.HasShoot(cluster.Spec.Shoot.Name) {
+		env.Gardener.AddShoot(cluster.Spec.Shoot.Name, imv1.ShootInfo{})
+	}
+
+	if err := env.Client.Create(context.Background(), cluster); err != nil {
+		t.Fatalf("failed to create the cluster %s/%s: %v", cluster.Namespace, cluster.Name, err)
+	}
+}
+
+// EventuallyState waits for the cluster to reach the state, and returns it.
+func (env *Environment) EventuallyState(t T, key types.NamespacedName, state imv1.State) imv1.GardenerCluster {
+	t.Helper()
+
+	return env.eventuallyCluster(t, key, fmt.Sprintf("state %s", state), func(cluster imv1.GardenerCluster) bool {
+		return cluster.Status.State == state
+	})
+}
+
+// EventuallyCondition waits for the condition of the cluster to have the status, and returns the cluster.
+func (env *Environment) EventuallyCondition(t T, key types.NamespacedName, conditionType imv1.ConditionType, status metav1.ConditionStatus) imv1.GardenerCluster {
+	t.Helper()
+
+	return env.eventuallyCluster(t, key, fmt.Sprintf("condition %s %s", conditionType, status), func(cluster imv1.GardenerCluster) bool {
+		return meta.IsStatusConditionPresentAndEqual(cluster.Status.Conditions, string(conditionType), status)
+	})
+}
+
+// EventuallySecret waits for the secret to hold a kubeconfig under the key, and returns the kubeconfig.
+func (env *Environment) EventuallySecret(t T, key types.NamespacedName, dataKey string) string {
+	t.Helper()
+
+	var secret corev1.Secret
+
+	err := env.poll(func(ctx context.Context) (bool, error) {
+		err := env.Client.Get(ctx, key, &secret)
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return len(secret.Data[dataKey]) > 0, err
+	})
+	if err != nil {
+		t.Fatalf("the secret %s has no kubeconfig under %s: %v", key, dataKey, err)
+	}
+
+	return string(secret.Data[dataKey])
+}
+
+func (env *Environment) eventuallyCluster(t T, key types.NamespacedName, expected string, matches func(imv1.GardenerCluster) bool) imv1.GardenerCluster {
+	t.Helper()
+
+	var cluster imv1.GardenerCluster
+
+	err := env.poll(func(ctx context.Context) (bool, error) {
+		err := env.Client.Get(ctx, key, &cluster)
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return err == nil && matches(cluster), err
+	})
+	if err != nil {
+		t.Fatalf("the cluster %s didn't reach the %s, state %q, conditions %v: %v", key, expected, cluster.Status.State, cluster.Status.Conditions, err)
+	}
+
+	return cluster
+}
+
+func (env *Environment) poll(condition wait.ConditionWithContextFunc) error {
+	return wait.PollUntilContextTimeout(context.Background(), env.pollInterval, env.timeout, true, condition)
+}
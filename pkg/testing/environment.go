@@ -0,0 +1,210 @@
+// Package testing provides an integration harness for the GardenerCluster controller, so that the features built on
+// it, and the forks of the infrastructure manager can be tested against a real API server in a few lines. The harness
+// starts envtest with the CRDs installed, and runs the controller against the fake Gardener of pkg/gardener/fake.
+//
+// The API server, and etcd binaries are found with KUBEBUILDER_ASSETS, as set by `make test`. The tests using the
+// harness are skipped when the binaries are missing, install them with `make envtest`, and point KUBEBUILDER_ASSETS to
+// them, e.g. with `bin/setup-envtest use -p path`.
+package testing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/kyma-project/infrastructure-manager/internal/controller"
+	"github.com/kyma-project/infrastructure-manager/pkg/gardener/fake"
+	"github.com/pkg/errors"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+const (
+	// DefaultRotationPeriod is the rotation period of the controller when none is given.
+	DefaultRotationPeriod = 24 * time.Hour
+	// DefaultTimeout is how long the assertions wait for the controller.
+	DefaultTimeout = 30 * time.Second
+	// DefaultPollInterval is how often the assertions check the cluster.
+	DefaultPollInterval = 250 * time.Millisecond
+
+	// defaultAssetsDirectory is where envtest looks for the binaries when KUBEBUILDER_ASSETS is not set.
+	defaultAssetsDirectory = "/usr/local/kubebuilder/bin"
+)
+
+// assets are the binaries envtest starts.
+var assets = []string{"kube-apiserver", "etcd"} //nolint:gochecknoglobals
+
+// T is the part of testing.TB the harness uses, it is implemented by GinkgoT() as well.
+type T interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+	Cleanup(cleanup func())
+	Skipf(format string, args ...any)
+}
+
+// Options configure the environment, the zero value starts the controller with its defaults.
+type Options struct {
+	// CRDDirectoryPaths are installed in addition to the CRDs of the infrastructure manager.
+	CRDDirectoryPaths []string
+	// RotationPeriod of the controller, DefaultRotationPeriod when zero.
+	RotationPeriod time.Duration
+	// Logger of the controller, the logs are discarded when not set.
+	Logger logr.Logger
+	// Configure is called with the controller before it is started, e.g. to enable optional features with its With
+	// options.
+	Configure func(*controller.GardenerClusterController)
+}
+
+// Environment is an API server with the GardenerCluster controller running against a fake Gardener.
+type Environment struct {
+	// Config of the API server, e.g. to start additional controllers.
+	Config *rest.Config
+	// Client reads from the API server directly, the assertions never see stale objects.
+	Client client.Client
+	// Gardener serves the kubeconfigs, add the shoots of the clusters to it.
+	Gardener *fake.Gardener
+	// Controller is the running GardenerCluster controller.
+	Controller *controller.GardenerClusterController
+
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// Start starts the environment, it is stopped with the cleanup of the test. The test is skipped when the envtest
+// binaries are missing.
+func Start(t T, options Options) *Environment {
+	t.Helper()
+
+	if err := verifyAssets(); err != nil {
+		t.Skipf("envtest binaries not available, install them with `make envtest`, and set KUBEBUILDER_ASSETS: %v", err)
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     append([]string{crdDirectory()}, options.CRDDirectoryPaths...),
+		ErrorIfCRDPathMissing: true,
+	}
+
+	config, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("failed to start the test environment: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("failed to stop the test environment: %v", err)
+		}
+	})
+
+	scheme := k8sruntime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build the scheme: %v", err)
+	}
+
+	if err := imv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build the scheme: %v", err)
+	}
+
+	mgr, err := ctrl.NewManager(config, ctrl.Options{Scheme: scheme, MetricsBindAddress: "0"})
+	if err != nil {
+		t.Fatalf("failed to create the manager: %v", err)
+	}
+
+	rotationPeriod := options.RotationPeriod
+	if rotationPeriod == 0 {
+		rotationPeriod = DefaultRotationPeriod
+	}
+
+	logger := options.Logger
+	if logger.GetSink() == nil {
+		logger = logr.Discard()
+	}
+
+	gardener := fake.New()
+	gardenerClusterController := controller.NewGardenerClusterController(mgr, gardener, logger, rotationPeriod)
+
+	if options.Configure != nil {
+		options.Configure(gardenerClusterController)
+	}
+
+	if err := gardenerClusterController.SetupWithManager(mgr); err != nil {
+		t.Fatalf("failed to set up the controller: %v", err)
+	}
+
+	k8sClient, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatalf("failed to create the client: %v", err)
+	}
+
+	startManager(t, mgr)
+
+	return &Environment{
+		Config:       config,
+		Client:       k8sClient,
+		Gardener:     gardener,
+		Controller:   gardenerClusterController,
+		timeout:      DefaultTimeout,
+		pollInterval: DefaultPollInterval,
+	}
+}
+
+// WithTimeout changes how long the assertions wait for the controller.
+func (env *Environment) WithTimeout(timeout time.Duration) *Environment {
+	env.timeout = timeout
+
+	return env
+}
+
+// startManager runs the manager until the cleanup of the test, which waits for it to stop.
+func startManager(t T, mgr ctrl.Manager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+
+	go func() {
+		stopped <- mgr.Start(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-stopped; err != nil {
+			t.Errorf("the manager failed: %v", err)
+		}
+	})
+}
+
+// verifyAssets checks that envtest finds its binaries, unless the tests run against an existing cluster.
+func verifyAssets() error {
+	if os.Getenv("USE_EXISTING_CLUSTER") == "true" {
+		return nil
+	}
+
+	directory := os.Getenv("KUBEBUILDER_ASSETS")
+	if directory == "" {
+		directory = defaultAssetsDirectory
+	}
+
+	for _, asset := range assets {
+		if _, err := os.Stat(filepath.Join(directory, asset)); err != nil {
+			return errors.Wrapf(err, "%s not found in %s", asset, directory)
+		}
+	}
+
+	return nil
+}
+
+// crdDirectory returns the CRDs of the infrastructure manager, found relative to the sources of this package, so that
+// they are found from the module cache as well.
+func crdDirectory() string {
+	_, file, _, _ := runtime.Caller(0) //nolint:dogsled
+
+	return filepath.Join(filepath.Dir(file), "..", "..", "config", "crd", "bases")
+}
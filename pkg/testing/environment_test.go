@@ -0,0 +1,44 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestEnvironment(t *testing.T) {
+	env := Start(t, Options{}).WithTimeout(10 * time.Second)
+
+	t.Run("should write the kubeconfig issued by Gardener to the secret", func(t *testing.T) {
+		// given
+		cluster := NewCluster("ready", "default", "shoot-ready")
+		key := types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}
+
+		// when
+		env.CreateCluster(t, cluster)
+
+		// then
+		kubeconfig := env.EventuallySecret(t, key, DefaultSecretKey)
+		assert.Contains(t, kubeconfig, "shoot-ready")
+		env.EventuallyState(t, key, imv1.ReadyState)
+	})
+
+	t.Run("should report the failures of Gardener in the conditions", func(t *testing.T) {
+		// given
+		cluster := NewCluster("failing", "default", "shoot-failing")
+		key := types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}
+		env.Gardener.AddShoot("shoot-failing", imv1.ShootInfo{})
+		env.Gardener.Fail("shoot-failing", errors.New("connection refused"), 0)
+
+		// when
+		env.CreateCluster(t, cluster)
+
+		// then
+		env.EventuallyCondition(t, key, imv1.ConditionTypeGardenerAccess, metav1.ConditionFalse)
+	})
+}